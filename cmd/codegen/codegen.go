@@ -55,6 +55,8 @@ func Generate(ctx context.Context, cfg generator.Config, genFunc GenFunc) (err e
 		}
 	}
 
+	introspectionSchema.ScrubStreamingFields()
+
 	// Set the parent schema
 	generator.SetSchemaParents(introspectionSchema)
 
@@ -13,6 +13,7 @@ var (
 	modulePath string
 	moduleName string
 	isInit     bool
+	withMock   bool
 )
 
 var generateModuleCmd = &cobra.Command{
@@ -39,6 +40,7 @@ func GenerateModule(cmd *cobra.Command, args []string) error {
 
 	moduleConfig := &generator.ModuleGeneratorConfig{
 		IsInit: isInit,
+		Mock:   withMock,
 	}
 
 	moduleConfig.ModuleName = moduleName
@@ -75,4 +77,5 @@ func init() {
 	generateModuleCmd.MarkFlagRequired("module-source-path")
 
 	generateModuleCmd.Flags().BoolVar(&isInit, "is-init", false, "whether this command is initializing a new module")
+	generateModuleCmd.Flags().BoolVar(&withMock, "mock", false, "whether to generate a mock client to unit test the module")
 }
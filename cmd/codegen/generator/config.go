@@ -59,6 +59,10 @@ type ModuleGeneratorConfig struct {
 	// where a pre-existing go.mod file is checked during dagger init for whether its module
 	// name is the expected value.
 	IsInit bool
+
+	// Whether to generate a mock client, to unit test the module without an
+	// engine.
+	Mock bool
 }
 
 type ModuleSourceDependency struct {
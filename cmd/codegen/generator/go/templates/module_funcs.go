@@ -46,6 +46,28 @@ func (ps *parseState) parseGoFunc(parentType *types.Named, fn *types.Func) (*fun
 		}
 	}
 
+	if v, ok := docPragmas["prompt"]; ok {
+		if v == nil {
+			spec.isPrompt = true
+		} else {
+			spec.isPrompt, ok = v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("prompt pragma %q, must be a valid boolean", v)
+			}
+		}
+	}
+
+	if v, ok := docPragmas["paginated"]; ok {
+		if v == nil {
+			spec.isPaginated = true
+		} else {
+			spec.isPaginated, ok = v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("paginated pragma %q, must be a valid boolean", v)
+			}
+		}
+	}
+
 	if v, ok := docPragmas["deprecated"]; ok {
 		if v == nil {
 			spec.deprecated = nil
@@ -126,6 +148,8 @@ type funcTypeSpec struct {
 	sourceMap   *sourceMap
 	cachePolicy string
 	isCheck     bool
+	isPrompt    bool
+	isPaginated bool
 
 	argSpecs []paramSpec
 
@@ -191,6 +215,12 @@ func (spec *funcTypeSpec) TypeDefFunc(dag *dagger.Client) (*dagger.Function, err
 	if spec.isCheck {
 		fnTypeDef = fnTypeDef.WithCheck()
 	}
+	if spec.isPrompt {
+		fnTypeDef = fnTypeDef.WithPrompt()
+	}
+	if spec.isPaginated {
+		fnTypeDef = fnTypeDef.WithPaginated()
+	}
 
 	for _, argSpec := range spec.argSpecs {
 		if argSpec.isContext {
@@ -416,6 +446,9 @@ func (ps *parseState) parseParamSpecVar(field *types.Var, astField *ast.Field, d
 		if err != nil {
 			return paramSpec{}, fmt.Errorf("failed to parse type reference: %w", err)
 		}
+		if _, ok := typeSpec.(*parsedUnionTypeReference); ok {
+			return paramSpec{}, fmt.Errorf("unions are only supported as return values, not arguments")
+		}
 	}
 
 	name := field.Name()
@@ -0,0 +1,38 @@
+package templates
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoFuncPaginated(t *testing.T) {
+	ps := parseTestModule(t, `package main
+
+type Test struct{}
+
+// Some items
+//
+// +paginated
+func (*Test) Items() []string { return nil }
+
+func (*Test) Plain() []string { return nil }
+
+// +paginated=false
+func (*Test) Off() []string { return nil }
+`)
+	testObj := lookupNamed(t, ps, "Test")
+	spec, err := ps.parseGoStruct(testObj.Underlying().(*types.Struct), testObj)
+	require.NoError(t, err)
+
+	paginated := map[string]bool{}
+	for _, method := range spec.methods {
+		paginated[method.name] = method.isPaginated
+	}
+	require.Equal(t, map[string]bool{
+		"Items": true,
+		"Plain": false,
+		"Off":   false,
+	}, paginated)
+}
@@ -76,6 +76,9 @@ func (ps *parseState) parseGoIface(t *types.Interface, named *types.Named) (*par
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse method %s: %w", goFuncType.Name(), err)
 		}
+		if _, ok := funcTypeSpec.returnSpec.(*parsedUnionTypeReference); ok {
+			return nil, fmt.Errorf("failed to parse method %s: interface methods cannot return unions", goFuncType.Name())
+		}
 		spec.methods = append(spec.methods, funcTypeSpec)
 	}
 
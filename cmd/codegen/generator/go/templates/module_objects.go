@@ -113,6 +113,9 @@ func (ps *parseState) parseGoStruct(t *types.Struct, named *types.Named) (*parse
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse field type: %w", err)
 		}
+		if _, ok := fieldSpec.typeSpec.(*parsedUnionTypeReference); ok {
+			return nil, fmt.Errorf("field %s: unions are only supported as return values, not fields", field.Name())
+		}
 
 		fieldSpec.goName = field.Name()
 		fieldSpec.name = fieldSpec.goName
@@ -79,11 +79,30 @@ func (ps *parseState) parseGoTypeReference(typ types.Type, named *types.Named, i
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse slice element type: %w", err)
 		}
+		if _, ok := elemTypeSpec.(*parsedUnionTypeReference); ok {
+			return nil, fmt.Errorf("lists of unions are not supported")
+		}
 		return &parsedSliceType{
 			goType:     t,
 			underlying: elemTypeSpec,
 		}, nil
 
+	case *types.Map:
+		if key, ok := t.Key().Underlying().(*types.Basic); !ok || key.Info()&types.IsString == 0 {
+			return nil, fmt.Errorf("map keys must be strings, got %s", t.Key())
+		}
+		valueTypeSpec, err := ps.parseGoTypeReference(t.Elem(), nil, isPtr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse map value type: %w", err)
+		}
+		if _, ok := valueTypeSpec.(*parsedUnionTypeReference); ok {
+			return nil, fmt.Errorf("maps of unions are not supported")
+		}
+		return &parsedMapType{
+			goType:     t,
+			underlying: valueTypeSpec,
+		}, nil
+
 	case *types.Basic:
 		enumType, err := ps.parseGoEnumReference(t, named, isPtr)
 		if err != nil {
@@ -132,6 +151,16 @@ func (ps *parseState) parseGoTypeReference(typ types.Type, named *types.Named, i
 		if typeName == "" {
 			return nil, fmt.Errorf("interface types must be named")
 		}
+		if ps.isUnionIface(named) {
+			if isPtr {
+				return nil, fmt.Errorf("union %s must not be referred to by pointer", typeName)
+			}
+			return &parsedUnionTypeReference{
+				name:       typeName,
+				moduleName: ps.moduleName,
+				goType:     named,
+			}, nil
+		}
 		moduleName := ""
 		if !ps.isDaggerGenerated(named.Obj()) {
 			moduleName = ps.moduleName
@@ -230,6 +259,30 @@ func (spec *parsedSliceType) GoSubTypes() []types.Type {
 	return spec.underlying.GoSubTypes()
 }
 
+// parsedMapType is a parsed type that is a map from strings to other types
+type parsedMapType struct {
+	goType     *types.Map
+	underlying ParsedType // the value TypeSpec
+}
+
+var _ ParsedType = &parsedMapType{}
+
+func (spec *parsedMapType) TypeDef(dag *dagger.Client) (*dagger.TypeDef, error) {
+	underlyingTypeDef, err := spec.underlying.TypeDef(dag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate underlying typedef: %w", err)
+	}
+	return dag.TypeDef().WithMapOf(underlyingTypeDef), nil
+}
+
+func (spec *parsedMapType) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedMapType) GoSubTypes() []types.Type {
+	return spec.underlying.GoSubTypes()
+}
+
 // parsedObjectTypeReference is a parsed object type that is referred to just by name rather
 // than with the full type definition
 type parsedObjectTypeReference struct {
@@ -0,0 +1,243 @@
+package templates
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"dagger.io/dagger"
+	. "github.com/dave/jennifer/jen" //nolint:staticcheck
+	"github.com/iancoleman/strcase"
+)
+
+// The keys of the JSON object that values of a union are returned as, tagging
+// the member's value with the name of its type.
+const (
+	unionTypeNameKey = "typeName"
+	unionValueKey    = "value"
+)
+
+// isUnionIface returns whether the named type is an interface declaring a
+// union, i.e. it only has unexported methods, which its members implement:
+//
+//	type Pet interface{ isPet() }
+//
+//	func (*Cat) isPet() {}
+//	func (*Dog) isPet() {}
+func (ps *parseState) isUnionIface(named *types.Named) bool {
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 || ps.isDaggerGenerated(named.Obj()) {
+		return false
+	}
+	for i := range iface.NumMethods() {
+		if iface.Method(i).Exported() {
+			return false
+		}
+	}
+	return true
+}
+
+// unionOf returns the union declared by the given type, if any.
+func (ps *parseState) unionOf(t types.Type) (*types.Named, bool) {
+	named, ok := dealias(t).(*types.Named)
+	if !ok || !ps.isUnionIface(named) {
+		return nil, false
+	}
+	return named, true
+}
+
+// parseUnionMembers finds the objects in the module that implement the union.
+func (ps *parseState) parseUnionMembers(named *types.Named) ([]*parsedUnionMember, error) {
+	iface := named.Underlying().(*types.Interface)
+
+	var members []*parsedUnionMember
+	for _, obj := range ps.objs {
+		typeName, ok := obj.(*types.TypeName)
+		if !ok || typeName.IsAlias() || !typeName.Exported() {
+			continue
+		}
+		memberType, ok := typeName.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		if _, ok := memberType.Underlying().(*types.Struct); !ok {
+			continue
+		}
+		if !types.Implements(types.NewPointer(memberType), iface) {
+			continue
+		}
+		members = append(members, &parsedUnionMember{
+			name:   typeName.Name(),
+			goType: memberType,
+			// members implementing the union with value receivers can be
+			// returned as values as well as pointers
+			byValue: types.Implements(memberType, iface),
+		})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("union %s has no members, no object in the module implements it", named.Obj().Name())
+	}
+	return members, nil
+}
+
+func (ps *parseState) parseGoUnion(t *types.Interface, named *types.Named) (*parsedUnionType, error) {
+	spec := &parsedUnionType{
+		name:       named.Obj().Name(),
+		moduleName: ps.moduleName,
+		goType:     t,
+	}
+
+	var err error
+	spec.members, err = ps.parseUnionMembers(named)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the comment above the interface (if any)
+	astSpec, err := ps.astSpecForObj(named.Obj())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find decl for named type %s: %w", spec.name, err)
+	}
+	if doc := docForAstSpec(astSpec); doc != nil {
+		spec.doc = doc.Text()
+	}
+	spec.sourceMap = ps.sourceMap(astSpec)
+
+	return spec, nil
+}
+
+// parsedUnionTypeReference is a parsed union type that is referred to just by
+// name rather than with the full type definition
+type parsedUnionTypeReference struct {
+	name       string
+	moduleName string
+	goType     *types.Named
+}
+
+var _ NamedParsedType = &parsedUnionTypeReference{}
+
+func (spec *parsedUnionTypeReference) TypeDef(dag *dagger.Client) (*dagger.TypeDef, error) {
+	return dag.TypeDef().WithUnion(spec.name), nil
+}
+
+func (spec *parsedUnionTypeReference) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedUnionTypeReference) GoSubTypes() []types.Type {
+	// because this is a *reference* to a named type, we return the goType itself as a subtype too
+	return []types.Type{spec.goType}
+}
+
+func (spec *parsedUnionTypeReference) Name() string {
+	return spec.name
+}
+
+func (spec *parsedUnionTypeReference) ModuleName() string {
+	return spec.moduleName
+}
+
+type parsedUnionType struct {
+	name       string
+	moduleName string
+	doc        string
+	sourceMap  *sourceMap
+
+	members []*parsedUnionMember
+
+	goType *types.Interface
+}
+
+type parsedUnionMember struct {
+	name    string
+	goType  *types.Named
+	byValue bool
+}
+
+var _ NamedParsedType = &parsedUnionType{}
+
+func (spec *parsedUnionType) TypeDef(dag *dagger.Client) (*dagger.TypeDef, error) {
+	opts := dagger.TypeDefWithUnionOpts{}
+	if spec.doc != "" {
+		opts.Description = strings.TrimSpace(spec.doc)
+	}
+	if spec.sourceMap != nil {
+		opts.SourceMap = spec.sourceMap.TypeDef(dag)
+	}
+	typeDefObject := dag.TypeDef().WithUnion(spec.name, opts)
+
+	for _, member := range spec.members {
+		typeDefObject = typeDefObject.WithUnionMember(dag.TypeDef().WithObject(member.name))
+	}
+	return typeDefObject, nil
+}
+
+func (spec *parsedUnionType) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedUnionType) GoSubTypes() []types.Type {
+	subTypes := make([]types.Type, 0, len(spec.members))
+	for _, member := range spec.members {
+		subTypes = append(subTypes, member.goType)
+	}
+	return subTypes
+}
+
+func (spec *parsedUnionType) Name() string {
+	return spec.name
+}
+
+func (spec *parsedUnionType) ModuleName() string {
+	return spec.moduleName
+}
+
+// Extra generated code needed for the union implementation.
+func (spec *parsedUnionType) ImplementationCode() (*Statement, error) {
+	return spec.resultFuncCode(), nil
+}
+
+// resultFuncCode generates the func converting a value of the union returned
+// by a function to the form the engine expects, tagging it with the name of
+// the member's type:
+//
+//	func petResult(v Pet) (any, error) {
+//		switch v := v.(type) {
+//		case nil:
+//			return nil, nil
+//		case *Cat:
+//			return map[string]any{"typeName": "Cat", "value": v}, nil
+//		...
+func (spec *parsedUnionType) resultFuncCode() *Statement {
+	tagged := func(member *parsedUnionMember) Code {
+		return Return(
+			Map(String()).Any().Values(Dict{
+				Lit(unionTypeNameKey): Lit(member.name),
+				Lit(unionValueKey):    Id("v"),
+			}),
+			Nil(),
+		)
+	}
+
+	cases := []Code{
+		Case(Nil()).Block(Return(Nil(), Nil())),
+	}
+	for _, member := range spec.members {
+		cases = append(cases, Case(Op("*").Id(member.name)).Block(tagged(member)))
+		if member.byValue {
+			cases = append(cases, Case(Id(member.name)).Block(tagged(member)))
+		}
+	}
+	cases = append(cases, Default().Block(
+		Return(Nil(), Qual("fmt", "Errorf").Call(Lit("%T is not a member of union "+spec.name), Id("v"))),
+	))
+
+	return Func().Id(formatUnionResultFuncName(spec.name)).
+		Params(Id("v").Id(spec.name)).
+		Params(Any(), Error()).
+		Block(Switch(Id("v").Op(":=").Id("v").Assert(Type())).Block(cases...))
+}
+
+func formatUnionResultFuncName(s string) string {
+	return strcase.ToLowerCamel(s) + "Result"
+}
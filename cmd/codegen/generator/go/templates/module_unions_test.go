@@ -0,0 +1,199 @@
+package templates
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/dave/jennifer/jen" //nolint:staticcheck
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+const unionModuleSrc = `package main
+
+import (
+	"context"
+	"fmt"
+)
+
+type Test struct{}
+
+func (*Test) Adopt(ctx context.Context, cat bool) (Pet, error) {
+	if cat {
+		return &Cat{Name: "Tom"}, nil
+	}
+	return Dog{Name: "Rex"}, nil
+}
+
+func (*Test) Stray() Pet {
+	return nil
+}
+
+// Either a cat or a dog
+type Pet interface {
+	isPet()
+}
+
+type Cat struct {
+	Name string
+}
+
+func (*Cat) isPet() {}
+
+type Dog struct {
+	Name string
+}
+
+func (Dog) isPet() {}
+
+// not a member, as it doesn't implement Pet
+type Bird struct {
+	Name string
+}
+
+var _ = fmt.Errorf
+`
+
+// parseTestModule type-checks the source of a module, returning the state
+// used to parse it.
+func parseTestModule(t *testing.T, src string) *parseState {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	ps := &parseState{
+		pkg: &packages.Package{
+			Types:  pkg,
+			Syntax: []*ast.File{file},
+			Module: &packages.Module{Dir: t.TempDir()},
+		},
+		fset:       fset,
+		moduleName: "test",
+		methods:    make(map[string][]method),
+	}
+	for _, name := range pkg.Scope().Names() {
+		ps.objs = append(ps.objs, pkg.Scope().Lookup(name))
+	}
+	sort.Slice(ps.objs, func(i, j int) bool {
+		return ps.objs[i].Pos() < ps.objs[j].Pos()
+	})
+	return ps
+}
+
+func lookupNamed(t *testing.T, ps *parseState, name string) *types.Named {
+	t.Helper()
+	obj := ps.pkg.Types.Scope().Lookup(name)
+	require.NotNil(t, obj, name)
+	return obj.Type().(*types.Named)
+}
+
+func TestParseGoUnion(t *testing.T) {
+	ps := parseTestModule(t, unionModuleSrc)
+	pet := lookupNamed(t, ps, "Pet")
+
+	require.True(t, ps.isUnionIface(pet))
+	require.False(t, ps.isUnionIface(lookupNamed(t, ps, "Cat")))
+
+	ref, err := ps.parseGoTypeReference(pet, nil, false)
+	require.NoError(t, err)
+	require.IsType(t, &parsedUnionTypeReference{}, ref)
+	require.Equal(t, "Pet", ref.(*parsedUnionTypeReference).Name())
+
+	spec, err := ps.parseGoUnion(pet.Underlying().(*types.Interface), pet)
+	require.NoError(t, err)
+	require.Equal(t, "Either a cat or a dog\n", spec.doc)
+	var members []string
+	for _, member := range spec.members {
+		members = append(members, fmt.Sprintf("%s byValue=%v", member.name, member.byValue))
+	}
+	require.Equal(t, []string{"Cat byValue=false", "Dog byValue=true"}, members)
+	require.Equal(t, []types.Type{lookupNamed(t, ps, "Cat"), lookupNamed(t, ps, "Dog")}, spec.GoSubTypes())
+}
+
+func TestGoUnionReturns(t *testing.T) {
+	ps := parseTestModule(t, unionModuleSrc)
+
+	testObj := lookupNamed(t, ps, "Test")
+	_, err := ps.parseGoStruct(testObj.Underlying().(*types.Struct), testObj)
+	require.NoError(t, err)
+	cases := map[string][]Code{}
+	require.NoError(t, ps.fillObjectFunctionCases(testObj, cases))
+
+	pet := lookupNamed(t, ps, "Pet")
+	spec, err := ps.parseGoUnion(pet.Underlying().(*types.Interface), pet)
+	require.NoError(t, err)
+	implCode, err := spec.ImplementationCode()
+	require.NoError(t, err)
+
+	// the generated code must compile along with the module
+	generated := fmt.Sprintf("%#v\n\n%#v", implCode, Func().Id("invoke").Params(
+		Id("ctx").Qual("context", "Context"),
+		Id(parentJSONVar).Index().Byte(),
+		Id(fnNameVar).String(),
+		Id(inputArgsVar).Map(String()).Index().Byte(),
+	).Params(Id("_").Any(), Err().Error()).Block(
+		Switch(Id(fnNameVar)).Block(cases["Test"]...),
+	))
+	require.Contains(t, generated, "return petResult(res)")
+	require.Contains(t, generated, "return petResult((*Test).Stray(&parent))")
+
+	src := strings.Replace(unionModuleSrc, "import (", "import (\n\t\"encoding/json\"", 1) + "\n" + generated
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", src, 0)
+	require.NoError(t, err, src)
+	_, err = (&types.Config{Importer: importer.Default()}).Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err, src)
+}
+
+func TestGoUnionUnsupported(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "argument",
+			src:  "func (*Test) Pat(pet Pet) {}",
+			err:  "unions are only supported as return values, not arguments",
+		},
+		{
+			name: "list",
+			src:  "func (*Test) All() []Pet { return nil }",
+			err:  "lists of unions are not supported",
+		},
+		{
+			name: "pointer",
+			src:  "func (*Test) Maybe() *Pet { return nil }",
+			err:  "must not be referred to by pointer",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := parseTestModule(t, unionModuleSrc+tc.src)
+			testObj := lookupNamed(t, ps, "Test")
+			_, err := ps.parseGoStruct(testObj.Underlying().(*types.Struct), testObj)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	t.Run("no members", func(t *testing.T) {
+		ps := parseTestModule(t, `package main
+
+type Empty interface {
+	isEmpty()
+}
+`)
+		empty := lookupNamed(t, ps, "Empty")
+		_, err := ps.parseGoUnion(empty.Underlying().(*types.Interface), empty)
+		require.ErrorContains(t, err, "union Empty has no members")
+	})
+}
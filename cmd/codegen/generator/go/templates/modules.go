@@ -32,6 +32,10 @@ func (funcs goTemplateFuncs) isModuleCode() bool {
 	return funcs.cfg.ModuleConfig != nil && funcs.cfg.ModuleConfig.ModuleName != ""
 }
 
+func (funcs goTemplateFuncs) hasMock() bool {
+	return funcs.isModuleCode() && funcs.cfg.ModuleConfig.Mock
+}
+
 func (funcs goTemplateFuncs) isStandaloneClient() bool {
 	return funcs.cfg.ClientConfig != nil
 }
@@ -181,6 +185,16 @@ func (funcs goTemplateFuncs) moduleMainSrc() (string, error) {
 				}
 				implementationCode.Add(implCode).Line()
 
+				return nil
+			},
+			UnionVisitor: func(ps *parseState, named *types.Named, obj *types.TypeName, unionTypeSpec *parsedUnionType, iface *types.Interface) error {
+				// Add the union to the module
+				implCode, err := unionTypeSpec.ImplementationCode()
+				if err != nil {
+					return fmt.Errorf("failed to generate union code for %s: %w", obj.Name(), err)
+				}
+				implementationCode.Add(implCode).Line()
+
 				return nil
 			},
 		},
@@ -393,6 +407,9 @@ func (ps *parseState) renderNameOrStruct(t types.Type) string {
 	if sl, ok := t.(*types.Slice); ok {
 		return "[]" + ps.renderNameOrStruct(sl.Elem())
 	}
+	if m, ok := t.(*types.Map); ok {
+		return "map[" + ps.renderNameOrStruct(m.Key()) + "]" + ps.renderNameOrStruct(m.Elem())
+	}
 	if st, ok := t.(*types.Struct); ok {
 		result := "struct {\n"
 		for i := range st.NumFields() {
@@ -664,7 +681,16 @@ func (ps *parseState) fillObjectFunctionCase(
 			return fmt.Errorf("second return value must be error, have %s", results.At(1).Type().String())
 		}
 
-		statements = append(statements, Return(callStatement))
+		if union, ok := ps.unionOf(results.At(0).Type()); ok {
+			// tag the union's value with the name of its member's type
+			statements = append(statements,
+				List(Id("res"), Err()).Op(":=").Add(callStatement),
+				If(Err().Op("!=").Nil()).Block(Return(Nil(), Err())),
+				Return(Id(formatUnionResultFuncName(union.Obj().Name())).Call(Id("res"))),
+			)
+		} else {
+			statements = append(statements, Return(callStatement))
+		}
 		cases[objName] = append(cases[objName], Case(Lit(caseName)).Block(statements...))
 
 		if err := ps.fillObjectFunctionCases(results.At(0).Type(), cases); err != nil {
@@ -682,7 +708,12 @@ func (ps *parseState) fillObjectFunctionCase(
 		} else {
 			// non-error return
 
-			statements = append(statements, Return(callStatement, Nil()))
+			if union, ok := ps.unionOf(results.At(0).Type()); ok {
+				// tag the union's value with the name of its member's type
+				statements = append(statements, Return(Id(formatUnionResultFuncName(union.Obj().Name())).Call(callStatement)))
+			} else {
+				statements = append(statements, Return(callStatement, Nil()))
+			}
 			cases[objName] = append(cases[objName], Case(Lit(caseName)).Block(statements...))
 
 			if err := ps.fillObjectFunctionCases(results.At(0).Type(), cases); err != nil {
@@ -13,12 +13,14 @@ type (
 	structVisitor func(*parseState, *types.Named, *types.TypeName, *parsedObjectType, *types.Struct) error
 	ifaceVisitor  func(*parseState, *types.Named, *types.TypeName, *parsedIfaceType, *types.Interface) error
 	enumVisitor   func(*parseState, *types.Named, *types.TypeName, *parsedEnumType, *types.Basic) error
+	unionVisitor  func(*parseState, *types.Named, *types.TypeName, *parsedUnionType, *types.Interface) error
 
 	visitorFuncs struct {
 		RootVisitor   rootVisitor
 		StructVisitor structVisitor
 		IfaceVisitor  ifaceVisitor
 		EnumVisitor   enumVisitor
+		UnionVisitor  unionVisitor
 	}
 )
 
@@ -26,7 +28,8 @@ func (v *visitorFuncs) isValid() bool {
 	return v.RootVisitor != nil &&
 		v.StructVisitor != nil &&
 		v.IfaceVisitor != nil &&
-		v.EnumVisitor != nil
+		v.EnumVisitor != nil &&
+		v.UnionVisitor != nil
 }
 
 var (
@@ -141,6 +144,23 @@ func (funcs goTemplateFuncs) visitTypes(
 				nextTps = append(nextTps, objTypeSpec.GoSubTypes()...)
 			case *types.Interface:
 				iface := underlyingObj
+				if ps.isUnionIface(named) {
+					unionTypeSpec, err := ps.parseGoUnion(iface, named)
+					if err != nil {
+						return err
+					}
+
+					if err = visitorFuncs.UnionVisitor(ps, named, obj, unionTypeSpec, iface); err != nil {
+						return err
+					}
+
+					added[obj.Pkg().Path()+"/"+obj.Name()] = struct{}{}
+
+					// add the union's members to the list of types to process
+					nextTps = append(nextTps, unionTypeSpec.GoSubTypes()...)
+					break
+				}
+
 				ifaceTypeSpec, err := ps.parseGoIface(iface, named)
 				if err != nil {
 					return err
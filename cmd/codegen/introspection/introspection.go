@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"slices"
 )
 
 // Query is the query generated by graphiql to determine type information
@@ -68,6 +69,16 @@ func (s *Schema) ScrubType(typeName string) {
 	s.Types = filteredTypes
 }
 
+// Remove all fields that stream their values, since they can only be
+// selected in a subscription, which generated clients don't support.
+func (s *Schema) ScrubStreamingFields() {
+	for _, t := range s.Types {
+		t.Fields = slices.DeleteFunc(t.Fields, func(f *Field) bool {
+			return f.Directives.IsStreaming()
+		})
+	}
+}
+
 type DirectiveDef struct {
 	Name        string   `json:"name,omitempty"`
 	Description string   `json:"description,omitempty"`
@@ -333,6 +344,10 @@ func (t Directives) IsExperimental() bool {
 	return t.Directive("experimental") != nil
 }
 
+func (t Directives) IsStreaming() bool {
+	return t.Directive("streaming") != nil
+}
+
 func (t Directives) ExperimentalReason() string {
 	return fromJSON[string](t.Directive("experimental").Arg("reason"))
 }
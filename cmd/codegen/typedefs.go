@@ -22,6 +22,7 @@ func TypeDefs(ctx context.Context, cfg generator.Config, typedefFunc TypeDefFunc
 		}
 		introspectionSchema = resp.Schema
 		introspectionSchemaVersion = resp.SchemaVersion
+		introspectionSchema.ScrubStreamingFields()
 
 		// Set the parent schema
 		generator.SetSchemaParents(introspectionSchema)
@@ -120,6 +120,10 @@ func withEngine(
 
 		params.WithTerminal = withTerminal
 
+		if params.Command == "" {
+			params.Command = spanName(os.Args)
+		}
+
 		params.Interactive = interactive
 		params.InteractiveCommand = interactiveCommandParsed
 
@@ -161,6 +165,11 @@ func initEngineTelemetry(ctx context.Context) (context.Context, func(error)) {
 		telemetryCfg.LiveLogExporters = append(telemetryCfg.LiveLogExporters, logs)
 		telemetryCfg.LiveMetricExporters = append(telemetryCfg.LiveMetricExporters, metrics)
 	}
+	if traceFile != nil {
+		telemetryCfg.LiveTraceExporters = append(telemetryCfg.LiveTraceExporters, traceFile.SpanExporter())
+		telemetryCfg.LiveLogExporters = append(telemetryCfg.LiveLogExporters, traceFile.LogExporter())
+		telemetryCfg.LiveMetricExporters = append(telemetryCfg.LiveMetricExporters, traceFile.MetricExporter())
+	}
 	ctx = telemetry.Init(ctx, telemetryCfg)
 
 	// Set the full command string as the name of the root span.
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -258,6 +260,106 @@ func (v *enumValue) Set(s string) error {
 	return fmt.Errorf("value should be one of %s", v.Type())
 }
 
+func newMapValue(typedef *modMap, defaultValue map[string]any) *mapValue {
+	return &mapValue{
+		value:   defaultValue,
+		typedef: typedef,
+	}
+}
+
+// mapValue is a pflag.Value that builds a map from comma-separated key=value
+// pairs, e.g., "--labels foo=bar,baz=qux". Like slices, the flag can be
+// repeated to add more pairs.
+type mapValue struct {
+	value   map[string]any
+	changed bool
+	typedef *modMap
+}
+
+var _ DaggerValue = &mapValue{}
+
+func (v *mapValue) Type() string {
+	return "map[string]" + v.typedef.ValueTypeDef.String()
+}
+
+func (v *mapValue) String() string {
+	keys := make([]string, 0, len(v.value))
+	for k := range v.value {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v.value[k]))
+	}
+	out, _ := writeAsCSV(pairs)
+	return "[" + out + "]"
+}
+
+func (v *mapValue) Get(ctx context.Context, dag *dagger.Client, modSrc *dagger.ModuleSource, _ *modFunctionArg) (any, error) {
+	value := v.value
+	if value == nil {
+		value = map[string]any{}
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return dagger.JSON(out), nil
+}
+
+func (v *mapValue) Set(s string) error {
+	pairs, err := readAsCSV(s)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("%q must be formatted as key=value", pair)
+		}
+		parsed, err := parseMapFlagValue(v.typedef.ValueTypeDef, val)
+		if err != nil {
+			return fmt.Errorf("value for key %q: %w", key, err)
+		}
+		out[key] = parsed
+	}
+
+	if !v.changed || v.value == nil {
+		v.value = out
+	} else {
+		for k, val := range out {
+			v.value[k] = val
+		}
+	}
+
+	v.changed = true
+	return nil
+}
+
+// parseMapFlagValue parses the value of a key=value pair for a map flag
+// according to the map's value type.
+func parseMapFlagValue(typeDef *modTypeDef, s string) (any, error) {
+	switch typeDef.Kind {
+	case dagger.TypeDefKindIntegerKind:
+		return strconv.Atoi(s)
+	case dagger.TypeDefKindFloatKind:
+		return strconv.ParseFloat(s, 64)
+	case dagger.TypeDefKindBooleanKind:
+		return strconv.ParseBool(s)
+	case dagger.TypeDefKindEnumKind:
+		val := newEnumValue(typeDef.AsEnum, "")
+		if err := val.Set(s); err != nil {
+			return nil, err
+		}
+		return val.value, nil
+	default:
+		return s, nil
+	}
+}
+
 // containerValue is a pflag.Value that builds a dagger.Container from a
 // base image name.
 type containerValue struct {
@@ -838,6 +940,26 @@ func (r *modFunctionArg) AddFlag(flags *pflag.FlagSet) error {
 				Type: "list of lists",
 			}
 		}
+
+	case dagger.TypeDefKindMapKind:
+		valueType := r.TypeDef.AsMap.ValueTypeDef
+
+		if valueType.Kind == dagger.TypeDefKindListKind {
+			return &UnsupportedFlagError{
+				Name: name,
+				Type: "map of lists",
+			}
+		}
+
+		defVal, _ := getDefaultValue[map[string]any](r)
+		flags.Var(newMapValue(r.TypeDef.AsMap, defVal), name, usage)
+		return nil
+
+	case dagger.TypeDefKindUnionKind:
+		return &UnsupportedFlagError{
+			Name: name,
+			Type: fmt.Sprintf("%q union", r.TypeDef.AsUnion.Name),
+		}
 	}
 
 	return &UnsupportedFlagError{Name: name}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"dagger.io/dagger"
+)
+
+func TestMapFlag(t *testing.T) {
+	newArg := func(valueType *modTypeDef, defVal dagger.JSON) *modFunctionArg {
+		return &modFunctionArg{
+			Name: "labels",
+			TypeDef: &modTypeDef{
+				Kind:  dagger.TypeDefKindMapKind,
+				AsMap: &modMap{ValueTypeDef: valueType},
+			},
+			DefaultValue: defVal,
+		}
+	}
+	get := func(t *testing.T, arg *modFunctionArg, args ...string) dagger.JSON {
+		t.Helper()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		require.NoError(t, arg.AddFlag(flags))
+		require.NoError(t, flags.Parse(args))
+		flag, err := arg.GetFlag(flags)
+		require.NoError(t, err)
+		val, err := flag.Value.(DaggerValue).Get(t.Context(), nil, nil, arg)
+		require.NoError(t, err)
+		return val.(dagger.JSON)
+	}
+
+	t.Run("strings", func(t *testing.T) {
+		arg := newArg(&modTypeDef{Kind: dagger.TypeDefKindStringKind}, "")
+		got := get(t, arg, "--labels", "foo=bar,baz=a=b", "--labels", "qux=")
+		require.JSONEq(t, `{"foo":"bar","baz":"a=b","qux":""}`, string(got))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		arg := newArg(&modTypeDef{Kind: dagger.TypeDefKindStringKind}, `{"foo":"bar"}`)
+		require.JSONEq(t, `{"foo":"bar"}`, string(get(t, arg)))
+		require.JSONEq(t, `{"baz":"qux"}`, string(get(t, arg, "--labels", "baz=qux")))
+	})
+
+	t.Run("integers", func(t *testing.T) {
+		arg := newArg(&modTypeDef{Kind: dagger.TypeDefKindIntegerKind}, "")
+		require.JSONEq(t, `{"a":1,"b":2}`, string(get(t, arg, "--labels", "a=1,b=2")))
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		require.NoError(t, arg.AddFlag(flags))
+		require.ErrorContains(t, flags.Parse([]string{"--labels", "a=x"}), `value for key "a"`)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		arg := newArg(&modTypeDef{Kind: dagger.TypeDefKindStringKind}, "")
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		require.NoError(t, arg.AddFlag(flags))
+		require.ErrorContains(t, flags.Parse([]string{"--labels", "foo"}), "key=value")
+	})
+
+	t.Run("lists unsupported", func(t *testing.T) {
+		arg := newArg(&modTypeDef{
+			Kind:   dagger.TypeDefKindListKind,
+			AsList: &modList{ElementTypeDef: &modTypeDef{Kind: dagger.TypeDefKindStringKind}},
+		}, "")
+		require.True(t, arg.IsUnsupportedFlag())
+	})
+}
@@ -11,6 +11,7 @@ func initModuleParams(a []string) client.Params {
 		ExecCmd:      a,
 		Function:     functionName(a),
 		EagerRuntime: eagerRuntime,
+		ExplainCache: explainCache,
 	}
 
 	if !moduleNoURL {
@@ -34,6 +34,9 @@ var (
 
 	// outputPath is the parsed value of the `--output` flag.
 	outputPath string
+
+	// explainCache is true if the `--explain-cache` flag is used.
+	explainCache bool
 )
 
 const (
@@ -226,6 +229,8 @@ func (fc *FuncCommand) Command() *cobra.Command {
 		fc.cmd.PersistentFlags().StringVarP(&outputPath, "output", "o", "", "Save the result to a local file or directory")
 
 		fc.cmd.PersistentFlags().BoolVarP(&jsonOutput, "json", "j", false, "Present result as JSON")
+
+		fc.cmd.PersistentFlags().BoolVar(&explainCache, "explain-cache", false, "Explain why each call that missed the cache had to run, compared to the previous run")
 	}
 	return fc.cmd
 }
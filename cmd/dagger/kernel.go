@@ -0,0 +1,627 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/client"
+	"github.com/dagger/dagger/engine/slog"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+var (
+	kernelConnectionFile string
+	kernelSpec           bool
+)
+
+func init() {
+	kernelCmd.Flags().StringVarP(&kernelConnectionFile, "connection-file", "f", "", "Path to the connection file written by the Jupyter client")
+	kernelCmd.Flags().BoolVar(&kernelSpec, "kernelspec", false, "Print the Jupyter kernel spec for installing the kernel")
+}
+
+var kernelCmd = &cobra.Command{
+	Use:   "kernel [options]",
+	Short: "Run a Jupyter kernel for dagger shell",
+	Long: `Run a Jupyter kernel for dagger shell.
+
+Notebook cells run dagger shell code, in a single session. Directories,
+files and containers returned by a cell are displayed as a tree, with
+syntax highlighting and with their configuration, respectively.
+
+To install the kernel, save its spec in Jupyter's kernels directory:
+
+  mkdir -p ~/.local/share/jupyter/kernels/dagger
+  dagger kernel --kernelspec > ~/.local/share/jupyter/kernels/dagger/kernel.json
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if kernelSpec {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{
+				"argv":           []string{"dagger", "kernel", "--connection-file", "{connection_file}"},
+				"display_name":   "Dagger",
+				"language":       "dagger",
+				"interrupt_mode": "message",
+			})
+		}
+		if kernelConnectionFile == "" {
+			return errors.New("missing --connection-file")
+		}
+		conn, err := readKernelConnection(kernelConnectionFile)
+		if err != nil {
+			return err
+		}
+		return withEngine(cmd.Context(), initModuleParams(nil), func(ctx context.Context, engineClient *client.Client) error {
+			handler := newShellCallHandler(engineClient.Dagger(), Frontend)
+			return handler.ServeKernel(ctx, conn)
+		})
+	},
+	Hidden: true,
+	Annotations: map[string]string{
+		"experimental": "true",
+	},
+}
+
+// kernelConnection is the connection file that a Jupyter client writes
+// before starting a kernel.
+type kernelConnection struct {
+	Transport       string `json:"transport"`
+	IP              string `json:"ip"`
+	ShellPort       int    `json:"shell_port"`
+	ControlPort     int    `json:"control_port"`
+	StdinPort       int    `json:"stdin_port"`
+	IOPubPort       int    `json:"iopub_port"`
+	HBPort          int    `json:"hb_port"`
+	SignatureScheme string `json:"signature_scheme"`
+	Key             string `json:"key"`
+}
+
+func readKernelConnection(path string) (*kernelConnection, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var conn kernelConnection
+	if err := json.Unmarshal(b, &conn); err != nil {
+		return nil, fmt.Errorf("invalid connection file: %w", err)
+	}
+	if conn.Transport != "tcp" {
+		return nil, fmt.Errorf("unsupported transport %q", conn.Transport)
+	}
+	if conn.Key != "" && conn.SignatureScheme != "hmac-sha256" {
+		return nil, fmt.Errorf("unsupported signature scheme %q", conn.SignatureScheme)
+	}
+	return &conn, nil
+}
+
+const (
+	kernelProtocolVersion = "5.3"
+
+	// kernelDelimiter separates the routing identities from the message
+	kernelDelimiter = "<IDS|MSG>"
+)
+
+// ServeKernel is the entry point for `dagger kernel`
+//
+// It implements the Jupyter messaging protocol, so that notebooks can run
+// cells with dagger shell code, until the client requests a shutdown.
+func (h *shellCallHandler) ServeKernel(ctx context.Context, conn *kernelConnection) error {
+	if err := h.Initialize(ctx); err != nil {
+		return err
+	}
+	k, err := newShellKernel(h, conn)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	return k.serve(ctx)
+}
+
+// shellKernel handles the messages from a Jupyter client.
+//
+// Requests on the shell channel are handled one at a time, while the
+// control channel can interrupt a running cell or shut down the kernel.
+type shellKernel struct {
+	h *shellCallHandler
+
+	// session identifies the messages sent by the kernel
+	session string
+	key     []byte
+
+	shell   *zmtpSocket
+	control *zmtpSocket
+	stdin   *zmtpSocket
+	iopub   *zmtpSocket
+	hb      *zmtpSocket
+
+	mu             sync.Mutex
+	executionCount int
+
+	// interrupt cancels the running cell
+	interrupt func()
+
+	// stop shuts down the kernel
+	stop func()
+}
+
+type kernelHeader struct {
+	MsgID    string `json:"msg_id"`
+	Session  string `json:"session"`
+	Username string `json:"username"`
+	Date     string `json:"date"`
+	MsgType  string `json:"msg_type"`
+	Version  string `json:"version"`
+}
+
+type kernelMessage struct {
+	// Identities route replies back to the client
+	Identities [][]byte
+
+	Header  kernelHeader
+	Content json.RawMessage
+
+	// rawHeader is the header as received, to use as the parent of
+	// messages sent in response
+	rawHeader []byte
+}
+
+func newShellKernel(h *shellCallHandler, conn *kernelConnection) (_ *shellKernel, rerr error) {
+	k := &shellKernel{
+		h:       h,
+		session: uuid.NewString(),
+		key:     []byte(conn.Key),
+	}
+	defer func() {
+		if rerr != nil {
+			k.Close()
+		}
+	}()
+	for _, s := range []struct {
+		sock       **zmtpSocket
+		socketType string
+		port       int
+	}{
+		{&k.shell, zmtpRouter, conn.ShellPort},
+		{&k.control, zmtpRouter, conn.ControlPort},
+		{&k.stdin, zmtpRouter, conn.StdinPort},
+		{&k.iopub, zmtpPub, conn.IOPubPort},
+		{&k.hb, zmtpRep, conn.HBPort},
+	} {
+		sock, err := listenZMTP(s.socketType, net.JoinHostPort(conn.IP, strconv.Itoa(s.port)))
+		if err != nil {
+			return nil, err
+		}
+		*s.sock = sock
+	}
+	return k, nil
+}
+
+func (k *shellKernel) Close() error {
+	var errs error
+	for _, s := range []*zmtpSocket{k.shell, k.control, k.stdin, k.iopub, k.hb} {
+		if s != nil {
+			errs = errors.Join(errs, s.Close())
+		}
+	}
+	return errs
+}
+
+func (k *shellKernel) serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	k.stop = cancel
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return k.loop(ctx, k.shell)
+	})
+	eg.Go(func() error {
+		return k.loop(ctx, k.control)
+	})
+	eg.Go(func() error {
+		// the heartbeat echoes every message back
+		for {
+			frames, err := k.hb.Recv(ctx)
+			if err != nil {
+				return ignoreKernelStopped(err)
+			}
+			k.hb.Send(frames)
+		}
+	})
+	eg.Go(func() error {
+		// input requests aren't supported, so no replies are expected
+		for {
+			if _, err := k.stdin.Recv(ctx); err != nil {
+				return ignoreKernelStopped(err)
+			}
+		}
+	})
+
+	k.publish(nil, "status", map[string]any{"execution_state": "starting"})
+
+	return eg.Wait()
+}
+
+func (k *shellKernel) loop(ctx context.Context, sock *zmtpSocket) error {
+	slog := slog.SpanLogger(ctx, InstrumentationLibrary)
+	for {
+		frames, err := sock.Recv(ctx)
+		if err != nil {
+			return ignoreKernelStopped(err)
+		}
+		msg, err := k.decode(frames)
+		if err != nil {
+			slog.Warn("invalid kernel message", "error", err)
+			continue
+		}
+
+		k.publish(msg, "status", map[string]any{"execution_state": "busy"})
+
+		content, err := k.handle(ctx, msg)
+		if err != nil {
+			slog.Warn("kernel request failed", "type", msg.Header.MsgType, "error", err)
+			content = map[string]any{
+				"status": "error",
+				"ename":  "Error",
+				"evalue": err.Error(),
+			}
+		}
+		if content != nil {
+			replyType := strings.TrimSuffix(msg.Header.MsgType, "_request") + "_reply"
+			if err := k.send(sock, msg.Identities, replyType, msg, content); err != nil {
+				slog.Warn("failed to send kernel reply", "type", replyType, "error", err)
+			}
+		}
+
+		k.publish(msg, "status", map[string]any{"execution_state": "idle"})
+
+		if msg.Header.MsgType == "shutdown_request" {
+			k.stop()
+		}
+	}
+}
+
+// ignoreKernelStopped ignores the errors from receiving on a socket after
+// the kernel is stopped.
+func ignoreKernelStopped(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// handle returns the content of the reply to a request, or nil for
+// unsupported requests.
+func (k *shellKernel) handle(ctx context.Context, msg *kernelMessage) (any, error) {
+	switch msg.Header.MsgType {
+	case "kernel_info_request":
+		return map[string]any{
+			"status":                 "ok",
+			"protocol_version":       kernelProtocolVersion,
+			"implementation":         "dagger",
+			"implementation_version": engine.Version,
+			"banner":                 "Dagger Shell",
+			"language_info": map[string]any{
+				"name":            "dagger",
+				"version":         engine.Version,
+				"mimetype":        "text/x-sh",
+				"file_extension":  ".dsh",
+				"pygments_lexer":  "bash",
+				"codemirror_mode": "shell",
+			},
+			"help_links": []map[string]string{
+				{"text": "Dagger Shell", "url": "https://docs.dagger.io/features/shell"},
+			},
+		}, nil
+
+	case "execute_request":
+		var req struct {
+			Code   string `json:"code"`
+			Silent bool   `json:"silent"`
+		}
+		if err := json.Unmarshal(msg.Content, &req); err != nil {
+			return nil, err
+		}
+		return k.execute(ctx, msg, req.Code, req.Silent), nil
+
+	case "is_complete_request":
+		var req struct {
+			Code string `json:"code"`
+		}
+		if err := json.Unmarshal(msg.Content, &req); err != nil {
+			return nil, err
+		}
+		status := "complete"
+		if _, err := parseShell(strings.NewReader(req.Code), ""); err != nil {
+			status = "invalid"
+			if syntax.IsIncomplete(err) {
+				status = "incomplete"
+			}
+		}
+		return map[string]any{
+			"status": status,
+			"indent": "",
+		}, nil
+
+	case "complete_request":
+		var req struct {
+			Code      string `json:"code"`
+			CursorPos int    `json:"cursor_pos"`
+		}
+		if err := json.Unmarshal(msg.Content, &req); err != nil {
+			return nil, err
+		}
+		// cursor positions are in unicode code points
+		code := []rune(req.Code)
+		pos := min(max(req.CursorPos, 0), len(code))
+		prefix, matches, ok := (&shellAutoComplete{k.h}).complete(string(code[:pos]))
+		if !ok {
+			matches = []string{}
+		}
+		return map[string]any{
+			"status":       "ok",
+			"matches":      matches,
+			"cursor_start": pos - utf8.RuneCountInString(prefix),
+			"cursor_end":   pos,
+			"metadata":     map[string]any{},
+		}, nil
+
+	case "history_request":
+		return map[string]any{
+			"status":  "ok",
+			"history": []any{},
+		}, nil
+
+	case "comm_info_request":
+		return map[string]any{
+			"status": "ok",
+			"comms":  map[string]any{},
+		}, nil
+
+	case "interrupt_request":
+		k.mu.Lock()
+		if k.interrupt != nil {
+			k.interrupt()
+		}
+		k.mu.Unlock()
+		return map[string]any{"status": "ok"}, nil
+
+	case "shutdown_request":
+		var req struct {
+			Restart bool `json:"restart"`
+		}
+		if err := json.Unmarshal(msg.Content, &req); err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"status":  "ok",
+			"restart": req.Restart,
+		}, nil
+	}
+	return nil, nil
+}
+
+// execute runs a cell, publishing its outputs, and returns the content of
+// the reply.
+func (k *shellKernel) execute(ctx context.Context, msg *kernelMessage, code string, silent bool) map[string]any {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	k.mu.Lock()
+	if !silent {
+		k.executionCount++
+	}
+	count := k.executionCount
+	k.interrupt = cancel
+	k.mu.Unlock()
+
+	defer func() {
+		k.mu.Lock()
+		k.interrupt = nil
+		k.mu.Unlock()
+	}()
+
+	if !silent {
+		k.publish(msg, "execute_input", map[string]any{
+			"code":            code,
+			"execution_count": count,
+		})
+	}
+
+	err := k.run(ctx, msg, code)
+	if err == nil {
+		return map[string]any{
+			"status":           "ok",
+			"execution_count":  count,
+			"user_expressions": map[string]any{},
+			"payload":          []any{},
+		}
+	}
+
+	ename := "Error"
+	var es interp.ExitStatus
+	switch {
+	case errors.Is(err, context.Canceled):
+		ename = "Interrupted"
+	case errors.As(err, &es):
+		ename = fmt.Sprintf("ExitStatus(%d)", es)
+	}
+	content := map[string]any{
+		"ename":     ename,
+		"evalue":    err.Error(),
+		"traceback": []string{err.Error()},
+	}
+	k.publish(msg, "error", content)
+
+	content["status"] = "error"
+	content["execution_count"] = count
+	return content
+}
+
+// run executes code in the shell, publishing the output streams, and
+// objects that have a rich representation.
+func (k *shellKernel) run(ctx context.Context, msg *kernelMessage, code string) error {
+	file, err := parseShell(strings.NewReader(code), "")
+	if err != nil {
+		return err
+	}
+
+	stdoutW := newTerminalWriter(k.stream(msg, "stdout"))
+	stdoutW.SetProcessFunc(k.displayResolver(ctx, msg))
+	stderrW := newTerminalWriter(k.stream(msg, "stderr"))
+	interp.StdIO(nil, stdoutW, stderrW)(k.h.runner)
+
+	defer k.h.state.Prune(ctx)
+
+	return k.h.runner.Run(ctx, file)
+}
+
+func (k *shellKernel) stream(msg *kernelMessage, name string) func([]byte) (int, error) {
+	return func(p []byte) (int, error) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		err := k.publish(msg, "stream", map[string]any{
+			"name": name,
+			"text": string(p),
+		})
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+}
+
+// displayResolver resolves the state written to stdout, like
+// [shellCallHandler.stateResolver], but publishes objects that have a rich
+// representation as display data instead of printing their ID.
+func (k *shellKernel) displayResolver(ctx context.Context, msg *kernelMessage) func([]byte) ([]byte, error) {
+	resolve := k.h.stateResolver(ctx)
+	return func(b []byte) ([]byte, error) {
+		key := GetStateKey(string(b))
+		if key == "" {
+			return resolve(b)
+		}
+		st, err := k.h.state.Extract(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		r, err := k.h.StateResult(ctx, st)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := r.Value.(string); ok && r.IsObject() {
+			data, err := k.h.displayData(ctx, r.typeDef.Name(), id)
+			if err != nil {
+				return nil, err
+			}
+			if data != nil {
+				return nil, k.publish(msg, "display_data", map[string]any{
+					"data":     data,
+					"metadata": map[string]any{},
+				})
+			}
+		}
+		s, err := r.String()
+		return []byte(s), err
+	}
+}
+
+// publish broadcasts a message on the IOPub channel.
+func (k *shellKernel) publish(parent *kernelMessage, msgType string, content any) error {
+	topic := []byte("kernel." + k.session + "." + msgType)
+	return k.send(k.iopub, [][]byte{topic}, msgType, parent, content)
+}
+
+func (k *shellKernel) send(sock *zmtpSocket, identities [][]byte, msgType string, parent *kernelMessage, content any) error {
+	frames, err := k.encode(identities, msgType, parent, content)
+	if err != nil {
+		return err
+	}
+	return sock.Send(frames)
+}
+
+func (k *shellKernel) encode(identities [][]byte, msgType string, parent *kernelMessage, content any) ([][]byte, error) {
+	header, err := json.Marshal(kernelHeader{
+		MsgID:    uuid.NewString(),
+		Session:  k.session,
+		Username: "dagger",
+		Date:     time.Now().UTC().Format(time.RFC3339Nano),
+		MsgType:  msgType,
+		Version:  kernelProtocolVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	parentHeader := []byte("{}")
+	if parent != nil {
+		parentHeader = parent.rawHeader
+	}
+	metadata := []byte("{}")
+	body, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(identities, [][]byte{
+		[]byte(kernelDelimiter),
+		k.sign(header, parentHeader, metadata, body),
+		header,
+		parentHeader,
+		metadata,
+		body,
+	}), nil
+}
+
+func (k *shellKernel) decode(frames [][]byte) (*kernelMessage, error) {
+	i := slices.IndexFunc(frames, func(f []byte) bool {
+		return string(f) == kernelDelimiter
+	})
+	if i < 0 || len(frames) < i+6 {
+		return nil, errors.New("missing message frames")
+	}
+	signature, header, parentHeader, metadata, content := frames[i+1], frames[i+2], frames[i+3], frames[i+4], frames[i+5]
+	if !hmac.Equal(signature, k.sign(header, parentHeader, metadata, content)) {
+		return nil, errors.New("invalid signature")
+	}
+	msg := &kernelMessage{
+		Identities: frames[:i],
+		Content:    content,
+		rawHeader:  header,
+	}
+	if err := json.Unmarshal(header, &msg.Header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	return msg, nil
+}
+
+// sign returns the HMAC signature of a message, which is empty when the
+// connection has no key.
+func (k *shellKernel) sign(parts ...[]byte) []byte {
+	if len(k.key) == 0 {
+		return []byte{}
+	}
+	mac := hmac.New(sha256.New, k.key)
+	for _, p := range parts {
+		mac.Write(p)
+	}
+	return []byte(hex.EncodeToString(mac.Sum(nil)))
+}
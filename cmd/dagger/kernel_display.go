@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"dagger.io/dagger"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+const (
+	// kernelDisplayMaxEntries is the maximum number of paths shown in a
+	// directory tree
+	kernelDisplayMaxEntries = 1000
+
+	// kernelDisplayMaxFileSize is the maximum size of a file for showing its
+	// contents
+	kernelDisplayMaxFileSize = 1 << 20
+)
+
+// displayData returns the rich representations of an object for a notebook,
+// by MIME type, or nil if there's none for its type.
+func (h *shellCallHandler) displayData(ctx context.Context, typeName, id string) (map[string]any, error) {
+	switch typeName {
+	case "Directory":
+		return displayDirectory(ctx, h.dag.LoadDirectoryFromID(dagger.DirectoryID(id)))
+	case "File":
+		return displayFile(ctx, h.dag.LoadFileFromID(dagger.FileID(id)))
+	case "Container":
+		return h.displayContainer(ctx, id)
+	}
+	return nil, nil
+}
+
+func displayDirectory(ctx context.Context, dir *dagger.Directory) (map[string]any, error) {
+	paths, err := dir.Glob(ctx, "**")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"text/plain": renderTree(paths, kernelDisplayMaxEntries),
+	}, nil
+}
+
+// renderTree renders the paths in a directory like the `tree` command.
+//
+// Paths to directories have a trailing slash, as returned by
+// [dagger.Directory.Glob].
+func renderTree(paths []string, limit int) string {
+	type node map[string]node
+
+	var more int
+	if len(paths) > limit {
+		more = len(paths) - limit
+		paths = paths[:limit]
+	}
+
+	root := node{}
+	for _, p := range paths {
+		n := root
+		parts := strings.Split(strings.TrimSuffix(p, "/"), "/")
+		for i, part := range parts {
+			if i < len(parts)-1 || strings.HasSuffix(p, "/") {
+				part += "/"
+			}
+			if n[part] == nil {
+				n[part] = node{}
+			}
+			n = n[part]
+		}
+	}
+
+	sb := new(strings.Builder)
+	sb.WriteString(".\n")
+
+	var walk func(n node, indent string)
+	walk = func(n node, indent string) {
+		names := slices.Sorted(maps.Keys(n))
+		for i, name := range names {
+			branch, next := "├── ", "│   "
+			if i == len(names)-1 {
+				branch, next = "└── ", "    "
+			}
+			sb.WriteString(indent + branch + name + "\n")
+			walk(n[name], indent+next)
+		}
+	}
+	walk(root, "")
+
+	if more > 0 {
+		fmt.Fprintf(sb, "… %d more\n", more)
+	}
+	return sb.String()
+}
+
+func displayFile(ctx context.Context, f *dagger.File) (map[string]any, error) {
+	name, err := f.Name(ctx)
+	if err != nil {
+		return nil, err
+	}
+	size, err := f.Size(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary := map[string]any{
+		"text/plain": fmt.Sprintf("%s (%d bytes)", name, size),
+	}
+	if size > kernelDisplayMaxFileSize {
+		return summary, nil
+	}
+	contents, err := f.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.ValidString(contents) {
+		return summary, nil
+	}
+	highlighted, err := highlightHTML(name, contents)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"text/plain": contents,
+		"text/html":  highlighted,
+	}, nil
+}
+
+// highlightHTML returns the contents of a file with syntax highlighting,
+// with the language detected from the file name or its contents.
+func highlightHTML(name, contents string) (string, error) {
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Analyse(contents)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, contents)
+	if err != nil {
+		return "", err
+	}
+	sb := new(strings.Builder)
+	err = html.New(html.WithClasses(false)).Format(sb, styles.Get("github"), it)
+	return sb.String(), err
+}
+
+const containerConfigQuery = `
+query ContainerConfig($id: ContainerID!) {
+	loadContainerFromID(id: $id) {
+		platform
+		entrypoint
+		defaultArgs
+		workdir
+		user
+		envVariables {
+			name
+			value
+		}
+		exposedPorts {
+			port
+			protocol
+			description
+		}
+		labels {
+			name
+			value
+		}
+	}
+}
+`
+
+type containerConfig struct {
+	Platform     string   `json:"platform"`
+	Entrypoint   []string `json:"entrypoint"`
+	DefaultArgs  []string `json:"defaultArgs"`
+	Workdir      string   `json:"workdir"`
+	User         string   `json:"user"`
+	EnvVariables []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"envVariables"`
+	ExposedPorts []struct {
+		Port        int    `json:"port"`
+		Protocol    string `json:"protocol"`
+		Description string `json:"description"`
+	} `json:"exposedPorts"`
+	Labels []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"labels"`
+}
+
+func (h *shellCallHandler) displayContainer(ctx context.Context, id string) (map[string]any, error) {
+	var res struct {
+		Container containerConfig `json:"loadContainerFromID"`
+	}
+	err := h.dag.Do(ctx, &dagger.Request{
+		Query: containerConfigQuery,
+		Variables: map[string]any{
+			"id": id,
+		},
+	}, &dagger.Response{
+		Data: &res,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query container config: %w", err)
+	}
+	return map[string]any{
+		"text/plain":       res.Container.String(),
+		"application/json": res.Container,
+	}, nil
+}
+
+func (c containerConfig) String() string {
+	sb := new(strings.Builder)
+	tw := tabwriter.NewWriter(sb, 0, 4, 2, ' ', 0)
+
+	list := func(args []string) string {
+		b, _ := json.Marshal(args)
+		return string(b)
+	}
+
+	fmt.Fprintf(tw, "Platform:\t%s\n", c.Platform)
+	fmt.Fprintf(tw, "Entrypoint:\t%s\n", list(c.Entrypoint))
+	fmt.Fprintf(tw, "Default args:\t%s\n", list(c.DefaultArgs))
+	fmt.Fprintf(tw, "Workdir:\t%s\n", c.Workdir)
+	fmt.Fprintf(tw, "User:\t%s\n", c.User)
+	tw.Flush()
+
+	if len(c.EnvVariables) > 0 {
+		sb.WriteString("\nEnvironment:\n")
+		for _, env := range c.EnvVariables {
+			fmt.Fprintf(sb, "  %s=%s\n", env.Name, env.Value)
+		}
+	}
+	if len(c.ExposedPorts) > 0 {
+		sb.WriteString("\nExposed ports:\n")
+		for _, port := range c.ExposedPorts {
+			fmt.Fprintf(tw, "  %d/%s\t%s\n", port.Port, port.Protocol, port.Description)
+		}
+		tw.Flush()
+	}
+	if len(c.Labels) > 0 {
+		sb.WriteString("\nLabels:\n")
+		for _, label := range c.Labels {
+			fmt.Fprintf(sb, "  %s=%s\n", label.Name, label.Value)
+		}
+	}
+	return sb.String()
+}
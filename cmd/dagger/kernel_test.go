@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"dagger.io/dagger"
+	"github.com/dagger/dagger/dagql/idtui"
+	"github.com/dagger/testctx"
+	"github.com/stretchr/testify/require"
+)
+
+// zmtpTestPeer is the client end of a connection to a zmtpSocket.
+type zmtpTestPeer struct {
+	t testing.TB
+	c net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func dialZMTP(t testing.TB, port int, socketType string, props map[string]string) *zmtpTestPeer {
+	t.Helper()
+	c, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	p := &zmtpTestPeer{t: t, c: c, r: bufio.NewReader(c), w: bufio.NewWriter(c)}
+	peerProps, err := zmtpHandshake(p.r, p.w, socketType, props)
+	require.NoError(t, err)
+	require.NotEmpty(t, peerProps["Socket-Type"])
+	return p
+}
+
+func (p *zmtpTestPeer) send(frames ...[]byte) {
+	p.t.Helper()
+	require.NoError(p.t, writeZMTPMessage(p.w, frames))
+	require.NoError(p.t, p.w.Flush())
+}
+
+func (p *zmtpTestPeer) recv() [][]byte {
+	p.t.Helper()
+	p.c.SetReadDeadline(time.Now().Add(time.Minute))
+	frames, err := readZMTPMessage(p.r)
+	require.NoError(p.t, err)
+	return frames
+}
+
+func TestZMTPRouter(t *testing.T) {
+	ctx := context.Background()
+
+	sock, err := listenZMTP(zmtpRouter, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer sock.Close()
+
+	peer := dialZMTP(t, sock.Port(), "DEALER", map[string]string{"Identity": "peer"})
+	anon := dialZMTP(t, sock.Port(), "DEALER", nil)
+
+	// frames over 255 bytes use a long size
+	long := make([]byte, 1000)
+	peer.send([]byte("hello"), long)
+	frames, err := sock.Recv(ctx)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("peer"), []byte("hello"), long}, frames)
+
+	anon.send([]byte("hi"))
+	frames, err = sock.Recv(ctx)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	require.Equal(t, byte(0), frames[0][0])
+
+	// replies are routed by identity
+	require.NoError(t, sock.Send([][]byte{frames[0], []byte("to anon")}))
+	require.NoError(t, sock.Send([][]byte{[]byte("peer"), []byte("to peer")}))
+	require.Equal(t, [][]byte{[]byte("to anon")}, anon.recv())
+	require.Equal(t, [][]byte{[]byte("to peer")}, peer.recv())
+
+	// messages to unknown peers are dropped
+	require.NoError(t, sock.Send([][]byte{[]byte("gone"), []byte("lost")}))
+}
+
+func TestKernelMessageSignature(t *testing.T) {
+	k := &shellKernel{session: "kernel", key: []byte("secret")}
+
+	frames, err := k.encode([][]byte{[]byte("peer")}, "kernel_info_request", nil, map[string]any{})
+	require.NoError(t, err)
+
+	msg, err := k.decode(frames)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("peer")}, msg.Identities)
+	require.Equal(t, "kernel_info_request", msg.Header.MsgType)
+	require.Equal(t, "kernel", msg.Header.Session)
+	require.JSONEq(t, "{}", string(msg.Content))
+
+	// content signed with a different key
+	other := &shellKernel{session: "kernel", key: []byte("other")}
+	frames, err = other.encode(nil, "kernel_info_request", nil, map[string]any{})
+	require.NoError(t, err)
+	_, err = k.decode(frames)
+	require.ErrorContains(t, err, "invalid signature")
+
+	_, err = k.decode([][]byte{[]byte(kernelDelimiter)})
+	require.ErrorContains(t, err, "missing message frames")
+}
+
+func TestRenderTree(t *testing.T) {
+	paths := []string{
+		"cmd/",
+		"cmd/app/",
+		"cmd/app/main.go",
+		"go.mod",
+		"internal/util.go",
+		"README.md",
+	}
+	require.Equal(t, `.
+├── README.md
+├── cmd/
+│   └── app/
+│       └── main.go
+├── go.mod
+└── internal/
+    └── util.go
+`, renderTree(paths, 10))
+
+	require.Equal(t, `.
+└── cmd/
+    └── app/
+… 4 more
+`, renderTree(paths, 2))
+}
+
+// kernelTestClient talks to a kernel over its shell and IOPub channels.
+type kernelTestClient struct {
+	t     testing.TB
+	k     *shellKernel
+	shell *zmtpTestPeer
+	iopub *zmtpTestPeer
+}
+
+func startKernel(ctx context.Context, t testing.TB, h *shellCallHandler) *kernelTestClient {
+	t.Helper()
+	k, err := newShellKernel(h, &kernelConnection{
+		Transport: "tcp",
+		IP:        "127.0.0.1",
+		Key:       "secret",
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- k.serve(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+		k.Close()
+	})
+
+	c := &kernelTestClient{
+		t:     t,
+		k:     k,
+		shell: dialZMTP(t, k.shell.Port(), "DEALER", nil),
+		iopub: dialZMTP(t, k.iopub.Port(), "SUB", nil),
+	}
+	c.iopub.send([]byte{1})
+
+	// wait for the subscriber so no messages are missed
+	require.Eventually(t, func() bool {
+		k.iopub.mu.Lock()
+		defer k.iopub.mu.Unlock()
+		return len(k.iopub.peers) > 0
+	}, time.Minute, 10*time.Millisecond)
+
+	return c
+}
+
+// request sends a request on the shell channel, and returns the reply along
+// with the messages published while handling it.
+func (c *kernelTestClient) request(msgType string, content any) (*kernelMessage, []*kernelMessage) {
+	c.t.Helper()
+	frames, err := c.k.encode(nil, msgType, nil, content)
+	require.NoError(c.t, err)
+	c.shell.send(frames...)
+
+	reply, err := c.k.decode(c.shell.recv())
+	require.NoError(c.t, err)
+
+	var published []*kernelMessage
+	for {
+		msg, err := c.k.decode(c.iopub.recv())
+		require.NoError(c.t, err)
+		if msg.Header.MsgType == "status" && string(msg.Content) == `{"execution_state":"starting"}` {
+			continue
+		}
+		published = append(published, msg)
+		if msg.Header.MsgType == "status" && string(msg.Content) == `{"execution_state":"idle"}` {
+			return reply, published
+		}
+	}
+}
+
+func TestKernelInfo(t *testing.T) {
+	ctx := context.Background()
+	c := startKernel(ctx, t, &shellCallHandler{})
+
+	reply, published := c.request("kernel_info_request", map[string]any{})
+	require.Equal(t, "kernel_info_reply", reply.Header.MsgType)
+	var info struct {
+		Status       string `json:"status"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	}
+	require.NoError(t, json.Unmarshal(reply.Content, &info))
+	require.Equal(t, "ok", info.Status)
+	require.Equal(t, "dagger", info.LanguageInfo.Name)
+
+	require.Len(t, published, 2)
+	require.JSONEq(t, `{"execution_state":"busy"}`, string(published[0].Content))
+	require.JSONEq(t, `{"execution_state":"idle"}`, string(published[1].Content))
+
+	for code, status := range map[string]string{
+		"container | from alpine": "complete",
+		"container |":             "incomplete",
+		"container | )":           "invalid",
+	} {
+		reply, _ := c.request("is_complete_request", map[string]any{"code": code})
+		var res struct {
+			Status string `json:"status"`
+		}
+		require.NoError(t, json.Unmarshal(reply.Content, &res))
+		require.Equal(t, status, res.Status, code)
+	}
+}
+
+func (DaggerCMDSuite) TestKernel(ctx context.Context, t *testctx.T) {
+	client, err := dagger.Connect(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	handler := newShellCallHandler(client, &idtui.FrontendMock{})
+	handler.noModule = true
+	require.NoError(t, handler.Initialize(ctx))
+
+	c := startKernel(ctx, t, handler)
+
+	execute := func(code string) (map[string]any, []*kernelMessage) {
+		reply, published := c.request("execute_request", map[string]any{"code": code})
+		var res map[string]any
+		require.NoError(t, json.Unmarshal(reply.Content, &res))
+		return res, published
+	}
+	displayed := func(published []*kernelMessage) map[string]string {
+		for _, msg := range published {
+			if msg.Header.MsgType == "display_data" {
+				var res struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(msg.Content, &res))
+				data := map[string]string{}
+				for k, v := range res.Data {
+					if s, ok := v.(string); ok {
+						data[k] = s
+					}
+				}
+				return data
+			}
+		}
+		t.Fatal("no display data")
+		return nil
+	}
+
+	t.Run("directory", func(ctx context.Context, t *testctx.T) {
+		res, published := execute(`dir=$(directory | with-new-file src/main.go "package main")
+$dir`)
+		require.Equal(t, "ok", res["status"])
+		require.Equal(t, ".\n└── src/\n    └── main.go\n", displayed(published)["text/plain"])
+	})
+
+	t.Run("file", func(ctx context.Context, t *testctx.T) {
+		// variables are kept between cells
+		res, published := execute(`$dir | file src/main.go`)
+		require.Equal(t, "ok", res["status"])
+		data := displayed(published)
+		require.Equal(t, "package main", data["text/plain"])
+		require.Contains(t, data["text/html"], "<span")
+	})
+
+	t.Run("container", func(ctx context.Context, t *testctx.T) {
+		res, published := execute(`container | with-workdir /src | with-env-variable FOO bar`)
+		require.Equal(t, "ok", res["status"])
+		text := displayed(published)["text/plain"]
+		require.Regexp(t, `Workdir:\s+/src`, text)
+		require.Contains(t, text, "FOO=bar")
+	})
+
+	t.Run("stream", func(ctx context.Context, t *testctx.T) {
+		res, published := execute(`.echo hello`)
+		require.Equal(t, "ok", res["status"])
+		var stream []string
+		for _, msg := range published {
+			if msg.Header.MsgType == "stream" {
+				var s struct {
+					Name string `json:"name"`
+					Text string `json:"text"`
+				}
+				require.NoError(t, json.Unmarshal(msg.Content, &s))
+				stream = append(stream, s.Name+": "+s.Text)
+			}
+		}
+		require.Equal(t, []string{"stdout: hello\n"}, stream)
+	})
+
+	t.Run("error", func(ctx context.Context, t *testctx.T) {
+		res, published := execute(`container | bogus`)
+		require.Equal(t, "error", res["status"])
+		require.Contains(t, res["evalue"], `"bogus"`)
+		require.True(t, slices.ContainsFunc(published, func(msg *kernelMessage) bool {
+			return msg.Header.MsgType == "error"
+		}))
+	})
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// This is a minimal implementation of the ZeroMQ Message Transport Protocol
+// (ZMTP 3.0, https://rfc.zeromq.org/spec/23/), with the NULL security
+// mechanism, which is what Jupyter clients use to talk to kernels over TCP.
+//
+// Only the server side of the socket types a kernel needs is supported:
+// ROUTER and REP for requests, and PUB for broadcasting.
+//
+// It's implemented here rather than using a ZeroMQ library, since the libzmq
+// bindings need cgo, which the CLI is built without, and the pure-Go
+// implementations bring along reconnection, queueing and socket patterns that
+// a kernel accepting local connections doesn't need. kernel_zmtp_test.go
+// checks it against the bytes libzmq peers send and expect.
+
+const (
+	zmtpRouter = "ROUTER"
+	zmtpRep    = "REP"
+	zmtpPub    = "PUB"
+)
+
+const (
+	zmtpFlagMore    = 0x01
+	zmtpFlagLong    = 0x02
+	zmtpFlagCommand = 0x04
+
+	zmtpGreetingSize = 64
+
+	// zmtpMaxFrameSize protects against allocating huge frames from a
+	// corrupt stream
+	zmtpMaxFrameSize = 256 << 20
+)
+
+// zmtpSocket accepts connections from ZeroMQ peers on a TCP address.
+//
+// Messages received on a ROUTER or REP socket are prefixed with a frame
+// with the identity of the peer that sent it. To reply, send a message
+// prefixed with the same identity. Messages sent on a PUB socket are
+// broadcast to all peers, regardless of their subscriptions.
+type zmtpSocket struct {
+	socketType string
+	l          net.Listener
+
+	mu     sync.Mutex
+	peers  map[string]*zmtpConn
+	nextID uint32
+
+	in   chan [][]byte
+	done chan struct{}
+}
+
+type zmtpConn struct {
+	net.Conn
+	identity string
+
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func listenZMTP(socketType, addr string) (*zmtpSocket, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &zmtpSocket{
+		socketType: socketType,
+		l:          l,
+		peers:      map[string]*zmtpConn{},
+		in:         make(chan [][]byte, 64),
+		done:       make(chan struct{}),
+	}
+	go s.accept()
+	return s, nil
+}
+
+// Port is the TCP port the socket is bound to.
+func (s *zmtpSocket) Port() int {
+	return s.l.Addr().(*net.TCPAddr).Port
+}
+
+func (s *zmtpSocket) accept() {
+	for {
+		c, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(c)
+	}
+}
+
+func (s *zmtpSocket) serve(c net.Conn) {
+	defer c.Close()
+
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+
+	props, err := zmtpHandshake(r, w, s.socketType, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	identity := props["Identity"]
+	if identity == "" || identity[0] == 0 || s.peers[identity] != nil {
+		// generated identities start with a zero byte, so they don't
+		// collide with the ones chosen by peers
+		s.nextID++
+		identity = string(binary.BigEndian.AppendUint32([]byte{0}, s.nextID))
+	}
+	conn := &zmtpConn{Conn: c, identity: identity, w: w}
+	s.peers[identity] = conn
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.peers, identity)
+		s.mu.Unlock()
+	}()
+
+	for {
+		frames, err := readZMTPMessage(r)
+		if err != nil {
+			return
+		}
+		if s.socketType == zmtpPub {
+			// subscriptions are ignored, since everything is broadcast
+			continue
+		}
+		select {
+		case s.in <- append([][]byte{[]byte(identity)}, frames...):
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Recv returns the next message received on the socket, prefixed with the
+// identity of the peer that sent it.
+func (s *zmtpSocket) Recv(ctx context.Context) ([][]byte, error) {
+	select {
+	case frames := <-s.in:
+		return frames, nil
+	case <-s.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Send sends a message, to the peer identified by the first frame, or to
+// every peer on a PUB socket.
+//
+// Messages to peers that are no longer connected are dropped.
+func (s *zmtpSocket) Send(frames [][]byte) error {
+	if s.socketType != zmtpPub && len(frames) < 2 {
+		return errors.New("missing peer identity")
+	}
+
+	s.mu.Lock()
+	var conns []*zmtpConn
+	if s.socketType == zmtpPub {
+		for _, c := range s.peers {
+			conns = append(conns, c)
+		}
+	} else {
+		if c := s.peers[string(frames[0])]; c != nil {
+			conns = append(conns, c)
+		}
+		frames = frames[1:]
+	}
+	s.mu.Unlock()
+
+	var errs error
+	for _, c := range conns {
+		errs = errors.Join(errs, c.send(frames))
+	}
+	return errs
+}
+
+func (s *zmtpSocket) Close() error {
+	err := s.l.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	for _, c := range s.peers {
+		c.Close()
+	}
+	return err
+}
+
+func (c *zmtpConn) send(frames [][]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := writeZMTPMessage(c.w, frames); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+// zmtpHandshake exchanges greetings and READY commands with a peer,
+// returning the peer's properties.
+//
+// With the NULL mechanism, the handshake is the same on both sides.
+func zmtpHandshake(r *bufio.Reader, w *bufio.Writer, socketType string, props map[string]string) (map[string]string, error) {
+	greeting := make([]byte, zmtpGreetingSize)
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3 // major version
+	greeting[11] = 0 // minor version
+	copy(greeting[12:32], "NULL")
+	if _, err := w.Write(greeting); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	peer := make([]byte, zmtpGreetingSize)
+	if _, err := io.ReadFull(r, peer); err != nil {
+		return nil, err
+	}
+	if peer[0] != 0xff || peer[9]&0x01 == 0 {
+		return nil, errors.New("invalid ZMTP greeting")
+	}
+	if peer[10] < 3 {
+		return nil, fmt.Errorf("unsupported ZMTP version %d.%d", peer[10], peer[11])
+	}
+	if mechanism := string(bytes.TrimRight(peer[12:32], "\x00")); mechanism != "NULL" {
+		return nil, fmt.Errorf("unsupported ZMTP security mechanism %q", mechanism)
+	}
+
+	ready := []byte{5}
+	ready = append(ready, "READY"...)
+	ready = appendZMTPProperty(ready, "Socket-Type", socketType)
+	for name, value := range props {
+		ready = appendZMTPProperty(ready, name, value)
+	}
+	if err := writeZMTPFrame(w, zmtpFlagCommand, ready); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	flags, body, err := readZMTPFrame(r)
+	if err != nil {
+		return nil, err
+	}
+	if flags&zmtpFlagCommand == 0 {
+		return nil, errors.New("expected ZMTP READY command")
+	}
+	name, body, err := readZMTPShortString(body)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "READY":
+	case "ERROR":
+		reason, _, _ := readZMTPShortString(body)
+		return nil, fmt.Errorf("ZMTP handshake error: %s", reason)
+	default:
+		return nil, fmt.Errorf("expected ZMTP READY command, got %q", name)
+	}
+	return parseZMTPProperties(body)
+}
+
+func appendZMTPProperty(b []byte, name, value string) []byte {
+	b = append(b, byte(len(name)))
+	b = append(b, name...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+func parseZMTPProperties(b []byte) (map[string]string, error) {
+	props := map[string]string{}
+	for len(b) > 0 {
+		name, rest, err := readZMTPShortString(b)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) < 4 {
+			return nil, errors.New("invalid ZMTP property")
+		}
+		size := binary.BigEndian.Uint32(rest)
+		rest = rest[4:]
+		if uint32(len(rest)) < size {
+			return nil, errors.New("invalid ZMTP property")
+		}
+		props[name] = string(rest[:size])
+		b = rest[size:]
+	}
+	return props, nil
+}
+
+func readZMTPShortString(b []byte) (string, []byte, error) {
+	if len(b) == 0 || len(b) < int(b[0])+1 {
+		return "", nil, errors.New("invalid ZMTP string")
+	}
+	size := int(b[0])
+	return string(b[1 : size+1]), b[size+1:], nil
+}
+
+// readZMTPMessage reads the frames of the next message, skipping commands.
+func readZMTPMessage(r *bufio.Reader) ([][]byte, error) {
+	var frames [][]byte
+	for {
+		flags, body, err := readZMTPFrame(r)
+		if err != nil {
+			return nil, err
+		}
+		if flags&zmtpFlagCommand != 0 {
+			continue
+		}
+		frames = append(frames, body)
+		if flags&zmtpFlagMore == 0 {
+			return frames, nil
+		}
+	}
+}
+
+func writeZMTPMessage(w *bufio.Writer, frames [][]byte) error {
+	for i, frame := range frames {
+		var flags byte
+		if i < len(frames)-1 {
+			flags |= zmtpFlagMore
+		}
+		if err := writeZMTPFrame(w, flags, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readZMTPFrame(r *bufio.Reader) (byte, []byte, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var size uint64
+	if flags&zmtpFlagLong != 0 {
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(b[:])
+	} else {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(b)
+	}
+	if size > zmtpMaxFrameSize {
+		return 0, nil, fmt.Errorf("ZMTP frame too large: %d bytes", size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return flags, body, nil
+}
+
+func writeZMTPFrame(w *bufio.Writer, flags byte, body []byte) error {
+	if len(body) > 255 {
+		w.WriteByte(flags | zmtpFlagLong)
+		w.Write(binary.BigEndian.AppendUint64(nil, uint64(len(body))))
+	} else {
+		w.WriteByte(flags)
+		w.WriteByte(byte(len(body)))
+	}
+	_, err := w.Write(body)
+	return err
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// These tests check the socket against the bytes a libzmq 4.3 peer sends and
+// expects, as described by ZMTP 3.1 (https://rfc.zeromq.org/spec/37/), rather
+// than against our own handshake.
+
+var (
+	// The greeting of a libzmq 4.3 peer using the NULL mechanism: the
+	// signature, version 3.1, the mechanism, as-server, and filler.
+	libzmqGreeting = "\xff\x00\x00\x00\x00\x00\x00\x00\x01\x7f" +
+		"\x03\x01" +
+		"NULL" + strings.Repeat("\x00", 16) +
+		"\x00" +
+		strings.Repeat("\x00", 31)
+
+	// The greeting a zmtpSocket sends: version 3.0, which libzmq peers
+	// downgrade to.
+	zmtpSocketGreeting = "\xff\x00\x00\x00\x00\x00\x00\x00\x00\x7f" +
+		"\x03\x00" +
+		"NULL" + strings.Repeat("\x00", 16) +
+		"\x00" +
+		strings.Repeat("\x00", 31)
+)
+
+// dialRawZMTP connects to the socket, exchanging the greeting and READY
+// commands byte for byte.
+func dialRawZMTP(t *testing.T, port int, ready, expectedReady string) net.Conn {
+	t.Helper()
+	c, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	c.SetDeadline(time.Now().Add(time.Minute))
+
+	// libzmq sends its signature first, and the rest of its greeting once it
+	// has seen the peer's, but it's fine to send it all at once
+	_, err = io.WriteString(c, libzmqGreeting)
+	require.NoError(t, err)
+	requireRead(t, c, zmtpSocketGreeting)
+
+	_, err = io.WriteString(c, ready)
+	require.NoError(t, err)
+	requireRead(t, c, expectedReady)
+	return c
+}
+
+func requireRead(t *testing.T, r io.Reader, expected string) {
+	t.Helper()
+	actual := make([]byte, len(expected))
+	_, err := io.ReadFull(r, actual)
+	require.NoError(t, err)
+	require.Equal(t, []byte(expected), actual)
+}
+
+func TestZMTPLibzmqDealer(t *testing.T) {
+	ctx := context.Background()
+
+	sock, err := listenZMTP(zmtpRouter, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer sock.Close()
+
+	c := dialRawZMTP(t, sock.Port(),
+		// READY, with an empty identity
+		"\x04\x29\x05READY"+
+			"\x0bSocket-Type\x00\x00\x00\x06DEALER"+
+			"\x08Identity\x00\x00\x00\x00",
+		"\x04\x1c\x05READY"+
+			"\x0bSocket-Type\x00\x00\x00\x06ROUTER",
+	)
+
+	// an empty delimiter frame, followed by a long frame
+	body := bytes.Repeat([]byte("x"), 300)
+	_, err = io.WriteString(c, "\x01\x00"+
+		"\x02\x00\x00\x00\x00\x00\x00\x01\x2c"+string(body))
+	require.NoError(t, err)
+
+	frames, err := sock.Recv(ctx)
+	require.NoError(t, err)
+	require.Len(t, frames, 3)
+	// the peer didn't choose an identity, so one was generated
+	identity := frames[0]
+	require.Equal(t, byte(0), identity[0])
+	require.Empty(t, frames[1])
+	require.Equal(t, body, frames[2])
+
+	require.NoError(t, sock.Send([][]byte{identity, {}, []byte("world")}))
+	requireRead(t, c, "\x01\x00"+
+		"\x00\x05world")
+
+	require.NoError(t, sock.Send([][]byte{identity, body}))
+	requireRead(t, c, "\x02\x00\x00\x00\x00\x00\x00\x01\x2c"+string(body))
+}
+
+func TestZMTPLibzmqSub(t *testing.T) {
+	sock, err := listenZMTP(zmtpPub, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer sock.Close()
+
+	c := dialRawZMTP(t, sock.Port(),
+		"\x04\x19\x05READY"+
+			"\x0bSocket-Type\x00\x00\x00\x03SUB",
+		"\x04\x19\x05READY"+
+			"\x0bSocket-Type\x00\x00\x00\x03PUB",
+	)
+
+	// subscribe to everything, both as a ZMTP 3.1 SUBSCRIBE command and as a
+	// ZMTP 3.0 subscription message, which libzmq sends depending on the
+	// version of the peer; both are ignored
+	_, err = io.WriteString(c, "\x04\x0a\x09SUBSCRIBE"+
+		"\x00\x01\x01")
+	require.NoError(t, err)
+
+	// the peer is registered once the server has read its READY command
+	require.Eventually(t, func() bool {
+		sock.mu.Lock()
+		defer sock.mu.Unlock()
+		return len(sock.peers) == 1
+	}, time.Minute, 10*time.Millisecond)
+
+	require.NoError(t, sock.Send([][]byte{[]byte("topic"), []byte("data")}))
+	requireRead(t, c, "\x01\x05topic"+
+		"\x00\x04data")
+}
@@ -74,6 +74,8 @@ var (
 	dotFocusField     string
 	dotShowInternal   bool
 
+	profileOutputFilePath string
+
 	stdoutIsTTY = isatty.IsTerminal(os.Stdout.Fd())
 	stderrIsTTY = isatty.IsTerminal(os.Stderr.Fd())
 
@@ -138,6 +140,7 @@ func init() {
 		queryCmd,
 		runCmd,
 		watchCmd,
+		traceCmd,
 		configCmd,
 		checksCmd,
 		moduleInitCmd,
@@ -155,6 +158,7 @@ func init() {
 		shellCmd,
 		clientCmd,
 		mcpCmd,
+		kernelCmd,
 	)
 
 	rootCmd.AddGroup(moduleGroup)
@@ -330,7 +334,7 @@ func installGlobalFlags(flags *pflag.FlagSet) {
 	flags.CountVarP(&quiet, "quiet", "q", "Reduce verbosity (show progress, but clean up at the end)")
 	flags.BoolVarP(&silent, "silent", "s", silent, "Do not show progress at all")
 	flags.BoolVarP(&debugFlag, "debug", "d", debugFlag, "Show debug logs and full verbosity")
-	flags.StringVar(&progress, "progress", "auto", "Progress output format (auto, plain, tty, dots)")
+	flags.StringVar(&progress, "progress", "auto", "Progress output format (auto, plain, tty, dots, html=<path>)")
 	flags.BoolVarP(&interactive, "interactive", "i", false, "Spawn a terminal on container exec failure")
 	flags.StringVar(&interactiveCommand, "interactive-command", "/bin/sh", "Change the default command for interactive mode")
 	flags.BoolVarP(&web, "web", "w", false, "Open trace URL in a web browser")
@@ -341,6 +345,8 @@ func installGlobalFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&dotFocusField, "dot-focus-field", "", "In dot output, filter out vertices that aren't this field or descendents of this field")
 	flags.BoolVar(&dotShowInternal, "dot-show-internal", false, "In dot output, if true then include calls and spans marked as internal")
 
+	flags.StringVar(&profileOutputFilePath, "profile", "", "If set, write a profile of the run to the given path in Chrome trace event format, and print a summary of its critical path and cache usage")
+
 	// this flag changes the behaviour of a few commands, e.g. call, functions, core, shell, etc.
 	// all those functions will run in a remote cloud engine which gets created at execution time
 	flags.BoolVar(&useCloudEngine, "cloud", useCloudEngine, "Run in a Dagger Cloud Engine")
@@ -423,6 +429,7 @@ func main() {
 	opts.DotOutputFilePath = dotOutputFilePath
 	opts.DotFocusField = dotFocusField
 	opts.DotShowInternal = dotShowInternal
+	opts.ProfileOutputFilePath = profileOutputFilePath
 	opts.UsingCloudEngine = useCloudEngine || strings.HasPrefix(RunnerHost, engine.CloudRunnerHostPrefix)
 	if progress == "auto" {
 		if env := os.Getenv("DAGGER_PROGRESS"); env != "" {
@@ -439,7 +446,8 @@ func main() {
 		// if silent, don't even bother with the pretty frontend
 		progress = "plain"
 	}
-	switch progress {
+	progressType, progressArg, _ := strings.Cut(progress, "=")
+	switch progressType {
 	case "plain":
 		Frontend = idtui.NewPlain(stderr)
 	case "tty":
@@ -452,6 +460,12 @@ func main() {
 		Frontend = idtui.NewDots(stderr)
 	case "report":
 		Frontend = idtui.NewReporter(stderr)
+	case "html":
+		if progressArg == "" {
+			fmt.Fprintf(stderr, "progress %q requires a path, e.g. html=report.html\n", progress)
+			os.Exit(1)
+		}
+		Frontend = idtui.NewHTML(stderr, progressArg)
 	default:
 		fmt.Fprintf(stderr, "unknown progress type %q\n", progress)
 		os.Exit(1)
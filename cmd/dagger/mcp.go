@@ -4,29 +4,45 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"time"
 
 	"dagger.io/dagger/querybuilder"
 	"github.com/dagger/dagger/dagql/idtui"
 	"github.com/dagger/dagger/engine/client"
+	"github.com/dagger/dagger/engine/session/pipe"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/http2"
 )
 
 var (
-	mcpStdio      bool
-	mcpSseAddr    string
-	envPrivileged bool
+	mcpStdio         bool
+	mcpSseAddr       string
+	mcpHTTPAddr      string
+	mcpHTTPAuthToken string
+	envPrivileged    bool
 )
 
 func init() {
 	mcpCmd.PersistentFlags().BoolVar(&mcpStdio, "stdio", true, "Use standard input/output for communicating with the MCP server")
 	mcpCmd.PersistentFlags().BoolVar(&envPrivileged, "env-privileged", false, "Expose the core API as tools")
 	mcpCmd.PersistentFlags().StringVar(&mcpSseAddr, "sse-addr", "", "Address of the MCP SSE server (no SSE server if empty)")
+	mcpCmd.PersistentFlags().StringVar(&mcpHTTPAddr, "http-addr", "", "Serve the MCP streamable HTTP transport on this address instead of standard input/output (non-loopback addresses require --http-auth-token)")
+	mcpCmd.PersistentFlags().StringVar(&mcpHTTPAuthToken, "http-auth-token", "", "Secret bearer token that HTTP clients must provide (e.g. env://MCP_TOKEN, file://./token)")
 }
 
 var mcpCmd = &cobra.Command{
 	Use:   "mcp [options]",
 	Short: "Expose a dagger module as an MCP server",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if mcpHTTPAddr != "" {
+			// stdio is not used for MCP, so any progress output is fine
+			return nil
+		}
+
 		if progress == "tty" {
 			return fmt.Errorf("cannot use tty progress output: it interferes with mcp stdio")
 		}
@@ -42,10 +58,27 @@ var mcpCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		cmd.SetContext(idtui.WithPrintTraceLink(ctx, true))
-		return withEngine(ctx, client.Params{
+		params := client.Params{
 			Stdin:  stdin,
 			Stdout: stdout,
-		}, mcpStart)
+		}
+		// the client end of the pipe that the MCP server speaks HTTP/2 over,
+		// when serving the streamable HTTP transport
+		var conn net.Conn
+		if mcpHTTPAddr != "" {
+			if err := checkMCPHTTPAddr(mcpHTTPAddr, mcpHTTPAuthToken); err != nil {
+				return err
+			}
+			// connect the pipe to an in-memory conn instead of stdio
+			toEngineR, toEngineW := io.Pipe()
+			fromEngineR, fromEngineW := io.Pipe()
+			conn = pipe.NewConn(fromEngineR, toEngineW, closers{fromEngineR, toEngineW})
+			params.Stdin = toEngineR
+			params.Stdout = fromEngineW
+		}
+		return withEngine(ctx, params, func(ctx context.Context, engineClient *client.Client) error {
+			return mcpStart(ctx, engineClient, conn)
+		})
 	},
 	Hidden: true,
 	Annotations: map[string]string{
@@ -53,10 +86,35 @@ var mcpCmd = &cobra.Command{
 	},
 }
 
+// checkMCPHTTPAddr refuses to expose the MCP server beyond the local machine
+// without authentication, since it grants access to the engine.
+func checkMCPHTTPAddr(addr, authToken string) error {
+	if authToken != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid --http-addr %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("refusing to serve MCP on non-loopback address %q without --http-auth-token", addr)
+}
+
 // dagger -m github.com/org/repo mcp
-func mcpStart(ctx context.Context, engineClient *client.Client) error {
-	if mcpSseAddr != "" || !mcpStdio {
-		return errors.New("currently MCP only works with stdio")
+func mcpStart(ctx context.Context, engineClient *client.Client, conn net.Conn) error {
+	if mcpSseAddr != "" {
+		return errors.New("the legacy SSE transport is not supported; use --http-addr for the streamable HTTP transport")
+	}
+	if !mcpStdio && mcpHTTPAddr == "" {
+		return errors.New("no MCP transport specified; use --stdio or --http-addr")
+	}
+	if mcpHTTPAuthToken != "" && mcpHTTPAddr == "" {
+		return errors.New("--http-auth-token requires --http-addr")
 	}
 	modDef, err := initializeDefaultModule(ctx, engineClient.Dagger())
 	if err != nil && err != errModuleNotFound {
@@ -93,17 +151,28 @@ func mcpStart(ctx context.Context, engineClient *client.Client) error {
 			Arg("description", modDef.MainObject.Description()).
 			Select("id")
 
-		logMsg = fmt.Sprintf("Exposing module %q%s as an MCP server on standard input/output", modName, extraCore)
+		logMsg = fmt.Sprintf("Exposing module %q%s as an MCP server", modName, extraCore)
 	} else {
 		q = q.Root().Select("env").Arg("privileged", envPrivileged).Select("id")
 		logMsg = "Exposing Dagger core as an MCP server"
 	}
+	if mcpHTTPAddr == "" {
+		logMsg += " on standard input/output"
+	}
 
 	var envID string
 	if err := makeRequest(ctx, q, &envID); err != nil {
 		return fmt.Errorf("error making environment: %w", err)
 	}
 
+	var authTokenID string
+	if mcpHTTPAuthToken != "" {
+		q = q.Root().Select("secret").Arg("uri", mcpHTTPAuthToken).Select("id")
+		if err := makeRequest(ctx, q, &authTokenID); err != nil {
+			return fmt.Errorf("error loading auth token: %w", err)
+		}
+	}
+
 	fmt.Fprintln(stderr, logMsg)
 	q = q.Root().
 		Select("llm").
@@ -111,6 +180,14 @@ func mcpStart(ctx context.Context, engineClient *client.Client) error {
 		Select("withEnv").Arg("env", envID).
 		Select("__mcp")
 
+	if mcpHTTPAddr != "" {
+		q = q.Arg("transport", "http")
+		if authTokenID != "" {
+			q = q.Arg("authToken", authTokenID)
+		}
+		return mcpServeHTTP(ctx, q, conn)
+	}
+
 	var response any
 	if err := makeRequest(ctx, q, &response); err != nil {
 		return fmt.Errorf("error starting MCP server: %w", err)
@@ -118,3 +195,105 @@ func mcpStart(ctx context.Context, engineClient *client.Client) error {
 
 	return nil
 }
+
+// mcpServeHTTP starts the MCP server and proxies HTTP requests from any number
+// of MCP clients to it over a single multiplexed HTTP/2 connection to conn.
+func mcpServeHTTP(ctx context.Context, q *querybuilder.Selection, conn net.Conn) (rerr error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	l, err := net.Listen("tcp", mcpHTTPAddr)
+	if err != nil {
+		return fmt.Errorf("mcp listen: %w", err)
+	}
+	defer l.Close()
+
+	// the server runs until the query returns
+	srvErr := make(chan error, 1)
+	go func() {
+		var response any
+		err := makeRequest(ctx, q, &response)
+		if err == nil {
+			err = errors.New("MCP server stopped")
+		}
+		srvErr <- err
+		cancel(err)
+	}()
+
+	// unblock the connection handshake if the server fails to start
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	cc, err := (&http2.Transport{AllowHTTP: true}).NewClientConn(conn)
+	if err != nil {
+		if cause := context.Cause(ctx); cause != nil {
+			return fmt.Errorf("error starting MCP server: %w", cause)
+		}
+		return fmt.Errorf("mcp connect: %w", err)
+	}
+	defer cc.Close()
+
+	srv := &http.Server{
+		Handler: &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.Out.URL.Scheme = "http"
+				r.Out.URL.Host = "dagger-mcp"
+			},
+			Transport: cc,
+			// stream SSE responses as they come
+			FlushInterval: -1,
+		},
+		// Gosec G112: prevent slowloris attacks
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(_ net.Listener) context.Context {
+			return ctx
+		},
+	}
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		// give in-flight requests a chance to finish, but don't wait forever
+		// on long-lived event streams
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.WithoutCancel(ctx), mcpShutdownTimeout)
+		defer cancelShutdown()
+		err := srv.Shutdown(shutdownCtx)
+		if err != nil {
+			err = errors.Join(err, srv.Close())
+		}
+		shutdownErr <- err
+	}()
+	defer func() {
+		cancel(rerr)
+		if err := <-shutdownErr; err != nil {
+			rerr = errors.Join(rerr, fmt.Errorf("mcp shutdown: %w", err))
+		}
+	}()
+
+	fmt.Fprintf(stderr, "Serving MCP streamable HTTP transport on http://%s/mcp\n", l.Addr())
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	select {
+	case err := <-srvErr:
+		return fmt.Errorf("error serving MCP: %w", err)
+	default:
+		return context.Cause(ctx)
+	}
+}
+
+// mcpShutdownTimeout bounds how long to wait for MCP clients to finish their
+// requests when the server stops.
+const mcpShutdownTimeout = 5 * time.Second
+
+// closers closes all of its members.
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var errs []error
+	for _, c := range cs {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
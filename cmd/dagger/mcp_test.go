@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMCPHTTPAddr(t *testing.T) {
+	for _, tc := range []struct {
+		addr      string
+		authToken string
+		allowed   bool
+	}{
+		{"127.0.0.1:8080", "", true},
+		{"[::1]:8080", "", true},
+		{"localhost:8080", "", true},
+		{":8080", "", false},
+		{"0.0.0.0:8080", "", false},
+		{"192.168.1.2:8080", "", false},
+		{"example.com:8080", "", false},
+		{":8080", "env://MCP_TOKEN", true},
+		{"0.0.0.0:8080", "env://MCP_TOKEN", true},
+	} {
+		t.Run(tc.addr+"/"+tc.authToken, func(t *testing.T) {
+			err := checkMCPHTTPAddr(tc.addr, tc.authToken)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+	require.Error(t, checkMCPHTTPAddr("8080", ""))
+}
@@ -143,6 +143,7 @@ type moduleDef struct {
 	Interfaces  []*modTypeDef
 	Enums       []*modTypeDef
 	Inputs      []*modTypeDef
+	Unions      []*modTypeDef
 
 	// the ModuleSource definition for the module, needed by some arg types
 	// applying module-specific configs to the arg value.
@@ -305,6 +306,8 @@ func (m *moduleDef) loadTypeDefs(ctx context.Context, dag *dagger.Client) (rerr
 			m.Enums = append(m.Enums, typeDef)
 		case dagger.TypeDefKindInputKind:
 			m.Inputs = append(m.Inputs, typeDef)
+		case dagger.TypeDefKindUnionKind:
+			m.Unions = append(m.Unions, typeDef)
 		}
 	}
 
@@ -352,13 +355,16 @@ func (m *moduleDef) Long() string {
 }
 
 func (m *moduleDef) AsFunctionProviders() []functionProvider {
-	providers := make([]functionProvider, 0, len(m.Objects)+len(m.Interfaces))
+	providers := make([]functionProvider, 0, len(m.Objects)+len(m.Interfaces)+len(m.Unions))
 	for _, obj := range m.AsObjects() {
 		providers = append(providers, obj)
 	}
 	for _, iface := range m.AsInterfaces() {
 		providers = append(providers, iface)
 	}
+	for _, union := range m.AsUnions() {
+		providers = append(providers, union)
+	}
 	return providers
 }
 
@@ -393,6 +399,16 @@ func (m *moduleDef) AsEnums() []*modEnum {
 	return defs
 }
 
+func (m *moduleDef) AsUnions() []*modUnion {
+	var defs []*modUnion
+	for _, typeDef := range m.Unions {
+		if typeDef.AsUnion != nil {
+			defs = append(defs, typeDef.AsUnion)
+		}
+	}
+	return defs
+}
+
 func (m *moduleDef) AsInputs() []*modInput {
 	var defs []*modInput
 	for _, typeDef := range m.Inputs {
@@ -454,7 +470,18 @@ func (m *moduleDef) GetEnum(name string) *modEnum {
 	return nil
 }
 
-// GetFunctionProvider retrieves a saved object or interface type definition from the module as a functionProvider.
+// GetUnion retrieves a saved union type definition from the module.
+func (m *moduleDef) GetUnion(name string) *modUnion {
+	for _, union := range m.AsUnions() {
+		// Normalize name in case an SDK uses a different convention for union names.
+		if gqlObjectName(union.Name) == gqlObjectName(name) {
+			return union
+		}
+	}
+	return nil
+}
+
+// GetFunctionProvider retrieves a saved object, interface or union type definition from the module as a functionProvider.
 func (m *moduleDef) GetFunctionProvider(name string) functionProvider {
 	if obj := m.GetObject(name); obj != nil {
 		return obj
@@ -462,6 +489,9 @@ func (m *moduleDef) GetFunctionProvider(name string) functionProvider {
 	if iface := m.GetInterface(name); iface != nil {
 		return iface
 	}
+	if union := m.GetUnion(name); union != nil {
+		return union
+	}
 	return nil
 }
 
@@ -571,9 +601,18 @@ func (m *moduleDef) LoadTypeDef(typeDef *modTypeDef) {
 				typeDef.AsInput = input
 			}
 		}
+		if typeDef.AsUnion != nil && typeDef.AsUnion.Members == nil {
+			union := m.GetUnion(typeDef.AsUnion.Name)
+			if union != nil {
+				typeDef.AsUnion = union
+			}
+		}
 		if typeDef.AsList != nil {
 			m.LoadTypeDef(typeDef.AsList.ElementTypeDef)
 		}
+		if typeDef.AsMap != nil {
+			m.LoadTypeDef(typeDef.AsMap.ValueTypeDef)
+		}
 	})
 }
 
@@ -596,6 +635,8 @@ type modTypeDef struct {
 	AsList      *modList
 	AsScalar    *modScalar
 	AsEnum      *modEnum
+	AsMap       *modMap
+	AsUnion     *modUnion
 
 	// once protects concurrent update from LoadTypeDef
 	once sync.Once
@@ -625,6 +666,10 @@ func (t *modTypeDef) String() string {
 		return t.AsInterface.Name
 	case dagger.TypeDefKindListKind:
 		return "[]" + t.AsList.ElementTypeDef.String()
+	case dagger.TypeDefKindMapKind:
+		return "map[string]" + t.AsMap.ValueTypeDef.String()
+	case dagger.TypeDefKindUnionKind:
+		return t.AsUnion.Name
 	default:
 		// this should never happen because all values for kind are covered,
 		// unless a new one is added and this code isn't updated
@@ -652,6 +697,10 @@ func (t *modTypeDef) KindDisplay() string {
 		return "Interface"
 	case dagger.TypeDefKindListKind:
 		return "List of " + strings.ToLower(t.AsList.ElementTypeDef.KindDisplay()) + "s"
+	case dagger.TypeDefKindMapKind:
+		return "Map of " + strings.ToLower(t.AsMap.ValueTypeDef.KindDisplay()) + "s"
+	case dagger.TypeDefKindUnionKind:
+		return "Union"
 	default:
 		return ""
 	}
@@ -678,6 +727,10 @@ func (t *modTypeDef) Description() string {
 		return t.AsInterface.Description
 	case dagger.TypeDefKindListKind:
 		return t.AsList.ElementTypeDef.Description()
+	case dagger.TypeDefKindMapKind:
+		return t.AsMap.ValueTypeDef.Description()
+	case dagger.TypeDefKindUnionKind:
+		return t.AsUnion.Description
 	default:
 		// this should never happen because all values for kind are covered,
 		// unless a new one is added and this code isn't updated
@@ -751,6 +804,9 @@ func (t *modTypeDef) AsFunctionProvider() functionProvider {
 	if t.AsInterface != nil {
 		return t.AsInterface
 	}
+	if t.AsUnion != nil {
+		return t.AsUnion
+	}
 	return nil
 }
 
@@ -762,6 +818,7 @@ type modObject struct {
 	Fields           []*modField
 	Constructor      *modFunction
 	SourceModuleName string
+	SourceMap        *modSourceMap
 }
 
 var _ functionProvider = (*modObject)(nil)
@@ -834,6 +891,54 @@ func (o *modInterface) GetFunctions() []*modFunction {
 	return o.Functions
 }
 
+// modUnion is a representation of dagger.UnionTypeDef.
+type modUnion struct {
+	Name             string
+	Description      string
+	Members          []*modTypeDef
+	SourceModuleName string
+
+	functions []*modFunction
+	once      sync.Once
+}
+
+var _ functionProvider = (*modUnion)(nil)
+
+func (u *modUnion) ProviderName() string {
+	return u.Name
+}
+
+func (u *modUnion) Short() string {
+	return shortDescription(u.Description)
+}
+
+func (u *modUnion) IsCore() bool {
+	return u.SourceModuleName == ""
+}
+
+// GetFunctions returns the fields the API has on every union: typeName, and
+// an asFoo function to narrow the value to each of its members.
+func (u *modUnion) GetFunctions() []*modFunction {
+	u.once.Do(func() {
+		u.functions = append(u.functions, &modFunction{
+			Name:        "typeName",
+			Description: "The name of the object type of this value.",
+			ReturnType:  &modTypeDef{Kind: dagger.TypeDefKindStringKind},
+		})
+		for _, member := range u.Members {
+			if member.AsObject == nil {
+				continue
+			}
+			u.functions = append(u.functions, &modFunction{
+				Name:        "as" + member.AsObject.Name,
+				Description: fmt.Sprintf("Retrieve the value as a %s, failing if it is another type.", member.AsObject.Name),
+				ReturnType:  member,
+			})
+		}
+	})
+	return u.functions
+}
+
 type modScalar struct {
 	Name        string
 	Description string
@@ -877,6 +982,11 @@ type modList struct {
 	ElementTypeDef *modTypeDef
 }
 
+// modMap is a representation of dagger.MapTypeDef.
+type modMap struct {
+	ValueTypeDef *modTypeDef
+}
+
 // modField is a representation of dagger.FieldTypeDef.
 type modField struct {
 	Name        string
@@ -900,12 +1010,22 @@ func shortDescription(desc string) string {
 	return s
 }
 
+// modSourceMap is a representation of dagger.SourceMap.
+type modSourceMap struct {
+	Module   string
+	Filename string
+	Line     int
+	Column   int
+	URL      string
+}
+
 // modFunction is a representation of dagger.Function.
 type modFunction struct {
 	Name        string
 	Description string
 	ReturnType  *modTypeDef
 	Args        []*modFunctionArg
+	SourceMap   *modSourceMap
 	cmdName     string
 	once        sync.Once
 }
@@ -994,6 +1114,7 @@ type modFunctionArg struct {
 	DefaultValue dagger.JSON
 	DefaultPath  string
 	Ignore       []string
+	SourceMap    *modSourceMap
 	flagName     string
 	once         sync.Once
 }
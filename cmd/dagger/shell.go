@@ -33,23 +33,48 @@ import (
 var (
 	shellCode string
 
+	// shellCheck checks scripts without executing them
+	shellCheck bool
+
+	// shellLSP serves the Language Server Protocol on stdio
+	shellLSP bool
+
 	llmModel string
 )
 
 func shellAddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&shellCode, "command", "c", "", "Execute a dagger shell command")
+	cmd.Flags().BoolVar(&shellCheck, "check", false, "Check scripts for errors without executing them")
+	cmd.Flags().BoolVar(&shellLSP, "lsp", false, "Serve the Language Server Protocol for shell scripts on standard input/output")
 	cmd.Flags().StringVar(&llmModel, "model", "", "LLM model to use (e.g., 'claude-sonnet-4-5', 'gpt-4.1')")
 }
 
 var shellCmd = &cobra.Command{
 	Use:   "shell [options] [file...]",
 	Short: "Run an interactive dagger shell",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if shellLSP && progress == "tty" {
+			// stdio is used by the language client
+			Frontend = idtui.NewPlain(stderr)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SetContext(idtui.WithPrintTraceLink(cmd.Context(), true))
 		return withEngine(cmd.Context(), initModuleParams(args), func(ctx context.Context, engineClient *client.Client) error {
 			dag := engineClient.Dagger()
 			handler := newShellCallHandler(dag, Frontend)
 
+			// Example: `dagger shell --check job.dsh`
+			if shellCheck {
+				return handler.CheckAll(ctx, args, cmd.ErrOrStderr())
+			}
+
+			// Example: `dagger shell --lsp`
+			if shellLSP {
+				return handler.ServeLSP(ctx, stdin, stdout)
+			}
+
 			err := handler.RunAll(ctx, args)
 
 			// Don't bother printing the error message if the TUI is enabled.
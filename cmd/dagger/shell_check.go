@@ -0,0 +1,738 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"dagger.io/dagger"
+	"github.com/spf13/pflag"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// shellDiagnostic is an error found while checking a script, without
+// executing it.
+type shellDiagnostic struct {
+	Pos     syntax.Pos
+	End     syntax.Pos
+	Message string
+}
+
+func (d shellDiagnostic) String(name string) string {
+	if name == "" {
+		return fmt.Sprintf("%d:%d: %s", d.Pos.Line(), d.Pos.Col(), d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", name, d.Pos.Line(), d.Pos.Col(), d.Message)
+}
+
+// CheckAll is the entry point for `dagger shell --check`
+//
+// It statically checks the scripts from the same sources as RunAll, writing
+// the errors found to w, one per line.
+func (h *shellCallHandler) CheckAll(ctx context.Context, args []string, w io.Writer) error {
+	if err := h.Initialize(ctx); err != nil {
+		return err
+	}
+
+	type script struct {
+		name   string
+		reader func() (io.ReadCloser, error)
+	}
+	var scripts []script
+	switch {
+	case shellCode != "":
+		scripts = append(scripts, script{"", func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(shellCode)), nil
+		}})
+	case len(args) == 0:
+		scripts = append(scripts, script{"-", func() (io.ReadCloser, error) {
+			return io.NopCloser(os.Stdin), nil
+		}})
+	default:
+		for _, path := range args {
+			scripts = append(scripts, script{path, func() (io.ReadCloser, error) {
+				return os.Open(path)
+			}})
+		}
+	}
+
+	var failed int
+	for _, s := range scripts {
+		r, err := s.reader()
+		if err != nil {
+			return err
+		}
+		diags, err := h.Check(ctx, r, s.name)
+		r.Close()
+		if err != nil {
+			return err
+		}
+		for _, d := range diags {
+			fmt.Fprintln(w, d.String(s.name))
+		}
+		failed += len(diags)
+	}
+	if failed > 0 {
+		return fmt.Errorf("found %d error(s)", failed)
+	}
+	return nil
+}
+
+// Check resolves every pipeline in a script against the loaded modules,
+// without executing it, and returns the errors found.
+//
+// Parsing errors are returned as a single diagnostic, since nothing can be
+// checked after them.
+func (h *shellCallHandler) Check(ctx context.Context, reader io.Reader, name string) ([]shellDiagnostic, error) {
+	c, err := h.analyze(ctx, reader, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.diags, nil
+}
+
+// analyze resolves every pipeline in a script, like Check, also keeping
+// track of what each resolved word refers to.
+func (h *shellCallHandler) analyze(ctx context.Context, reader io.Reader, name string) (*shellChecker, error) {
+	c := &shellChecker{
+		ctx:   ctx,
+		h:     h,
+		funcs: map[string]struct{}{},
+		vars:  map[string]*shellCheckResult{},
+	}
+
+	file, err := parseShell(reader, name)
+	if err != nil {
+		var perr syntax.ParseError
+		if errors.As(err, &perr) {
+			c.diags = append(c.diags, shellDiagnostic{
+				Pos:     perr.Pos,
+				End:     perr.Pos,
+				Message: perr.Text,
+			})
+			return c, nil
+		}
+		return nil, err
+	}
+
+	syntax.Walk(file, func(node syntax.Node) bool {
+		if fn, ok := node.(*syntax.FuncDecl); ok {
+			c.funcs[fn.Name.Value] = struct{}{}
+		}
+		return true
+	})
+	for _, stmt := range file.Stmts {
+		c.pipe(nil, false, stmt)
+	}
+	return c, nil
+}
+
+// shellChecker statically checks a script, following the same lookups as
+// the exec handler.
+type shellChecker struct {
+	ctx context.Context
+	h   *shellCallHandler
+
+	// funcs are the names of the functions declared in the script
+	funcs map[string]struct{}
+
+	// vars are the results assigned to variables, by name, if known
+	vars map[string]*shellCheckResult
+
+	diags []shellDiagnostic
+
+	// symbols are the words that were resolved, in order
+	symbols []shellSymbol
+}
+
+// shellSymbol is a word in a script, along with what it was resolved to.
+type shellSymbol struct {
+	node syntax.Node
+	def  *moduleDef
+
+	// only one of these is set
+	fn      *modFunction
+	arg     *modFunctionArg
+	cmd     *ShellCommand
+	typeDef *modTypeDef
+}
+
+// shellCheckResult is the statically known result of a command, which
+// determines what can be piped after it.
+type shellCheckResult struct {
+	def *moduleDef
+
+	// namespace is set by the namespace-setting builtins (.deps, .stdlib
+	// and .core)
+	namespace string
+
+	// fn is the last function called
+	fn *modFunction
+}
+
+// provider returns the object or interface the result can be chained with,
+// if any.
+func (r *shellCheckResult) provider() functionProvider {
+	if r.fn == nil {
+		return nil
+	}
+	return r.def.GetFunctionProvider(r.fn.ReturnType.Name())
+}
+
+// shellCheckArg is an argument of a command.
+type shellCheckArg struct {
+	word *syntax.Word
+
+	// value is the argument with each expansion replaced by a placeholder
+	value string
+
+	// literal is true if the argument has no expansions
+	literal bool
+
+	// result is the result of the command substitution or variable the
+	// argument entirely consists of, if known
+	result *shellCheckResult
+}
+
+// shellCheckPlaceholder replaces expansions in argument values, which
+// can't be known until the script is executed.
+const shellCheckPlaceholder = "\x00"
+
+func (c *shellChecker) report(node syntax.Node, format string, args ...any) {
+	c.diags = append(c.diags, shellDiagnostic{
+		Pos:     node.Pos(),
+		End:     node.End(),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// pipe checks a statement, receiving the result of the previous command if
+// piped, and returns its result if known.
+func (c *shellChecker) pipe(prev *shellCheckResult, piped bool, stmt *syntax.Stmt) *shellCheckResult {
+	for _, redir := range stmt.Redirs {
+		c.walk(redir)
+	}
+	switch cmd := stmt.Cmd.(type) {
+	case nil:
+		return prev
+	case *syntax.CallExpr:
+		return c.call(prev, piped, cmd)
+	case *syntax.BinaryCmd:
+		if cmd.Op == syntax.Pipe {
+			prev = c.pipe(prev, piped, cmd.X)
+			return c.pipe(prev, true, cmd.Y)
+		}
+		c.pipe(nil, false, cmd.X)
+		c.pipe(nil, false, cmd.Y)
+		return nil
+	default:
+		c.walk(cmd)
+		return nil
+	}
+}
+
+// walk checks the statements and command substitutions nested in a node.
+func (c *shellChecker) walk(node syntax.Node) {
+	syntax.Walk(node, func(node syntax.Node) bool {
+		switch node := node.(type) {
+		case *syntax.Stmt:
+			c.pipe(nil, false, node)
+			return false
+		case *syntax.CmdSubst:
+			c.subst(node)
+			return false
+		}
+		return true
+	})
+}
+
+// subst checks a command substitution and returns the result of its last
+// statement.
+func (c *shellChecker) subst(subst *syntax.CmdSubst) *shellCheckResult {
+	var res *shellCheckResult
+	for _, stmt := range subst.Stmts {
+		res = c.pipe(nil, false, stmt)
+	}
+	return res
+}
+
+// arg checks the expansions in a word and returns it as an argument.
+func (c *shellChecker) arg(word *syntax.Word) shellCheckArg {
+	arg := shellCheckArg{
+		word:    word,
+		literal: true,
+	}
+	var value strings.Builder
+	var expansions int
+	for _, part := range word.Parts {
+		lit, ok := literalPart(part)
+		if ok {
+			value.WriteString(lit)
+			continue
+		}
+		arg.literal = false
+		expansions++
+		value.WriteString(shellCheckPlaceholder)
+
+		if dq, ok := part.(*syntax.DblQuoted); ok && len(dq.Parts) == 1 {
+			part = dq.Parts[0]
+		}
+		switch part := part.(type) {
+		case *syntax.CmdSubst:
+			arg.result = c.subst(part)
+		case *syntax.ParamExp:
+			if isPlainParamExp(part) {
+				arg.result = c.vars[part.Param.Value]
+				if res := arg.result; res != nil && res.fn != nil {
+					if t := res.def.GetTypeDef(res.fn.ReturnType.Name()); t != nil {
+						c.symbols = append(c.symbols, shellSymbol{node: part, def: res.def, typeDef: t})
+					}
+				}
+			}
+			c.walk(part)
+		default:
+			c.walk(part)
+		}
+	}
+	if expansions > 1 {
+		// the result of concatenated expansions isn't known
+		arg.result = nil
+	}
+	arg.value = value.String()
+	return arg
+}
+
+// literalPart returns the value of a word part without expansions.
+func literalPart(part syntax.WordPart) (string, bool) {
+	switch part := part.(type) {
+	case *syntax.Lit:
+		return part.Value, true
+	case *syntax.SglQuoted:
+		return part.Value, true
+	case *syntax.DblQuoted:
+		var sb strings.Builder
+		for _, p := range part.Parts {
+			lit, ok := p.(*syntax.Lit)
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(lit.Value)
+		}
+		return sb.String(), true
+	}
+	return "", false
+}
+
+// isPlainParamExp returns true for $FOO and ${FOO}, which expand to the
+// variable's value as-is.
+func isPlainParamExp(pe *syntax.ParamExp) bool {
+	return pe.Param != nil && !pe.Excl && !pe.Length && !pe.Width &&
+		pe.Index == nil && pe.Slice == nil && pe.Repl == nil &&
+		pe.Names == 0 && pe.Exp == nil
+}
+
+// call checks a simple command and returns its result, if known.
+func (c *shellChecker) call(prev *shellCheckResult, piped bool, call *syntax.CallExpr) *shellCheckResult {
+	for _, as := range call.Assigns {
+		var res *shellCheckResult
+		if as.Value != nil {
+			res = c.arg(as.Value).result
+		}
+		if as.Array != nil {
+			c.walk(as.Array)
+		}
+		if as.Index != nil {
+			c.walk(as.Index)
+		}
+		if len(call.Args) == 0 {
+			c.vars[as.Name.Value] = res
+		}
+	}
+	if len(call.Args) == 0 {
+		return nil
+	}
+
+	args := make([]shellCheckArg, 0, len(call.Args))
+	for _, word := range call.Args {
+		args = append(args, c.arg(word))
+	}
+
+	if !args[0].literal {
+		// A state value at the start of a pipeline.
+		// Example: `$FOO | bar`
+		if !piped && len(args) == 1 {
+			return args[0].result
+		}
+		return nil
+	}
+
+	name := args[0].value
+	if strings.HasPrefix(name, ".") {
+		return c.builtin(prev, piped, args)
+	}
+	if _, ok := c.funcs[name]; ok {
+		return nil
+	}
+	if after, ok := strings.CutPrefix(name, shellInterpBuiltinPrefix); ok && interp.IsBuiltin(after) {
+		return nil
+	}
+	if name == shellInternalCmd {
+		c.report(args[0].word, "command %q is reserved for internal use", shellInternalCmd)
+		return nil
+	}
+	if piped {
+		return c.chained(prev, args)
+	}
+	return c.entrypoint(args)
+}
+
+// builtin checks a builtin command's usage and returns the namespace it sets,
+// if any.
+func (c *shellChecker) builtin(prev *shellCheckResult, piped bool, args []shellCheckArg) *shellCheckResult {
+	name := args[0].value
+	cmd, err := c.h.BuiltinCommand(name)
+	if err != nil {
+		c.report(args[0].word, "%s", err)
+		return nil
+	}
+	if cmd == nil {
+		return nil
+	}
+	c.symbols = append(c.symbols, shellSymbol{node: args[0].word, cmd: cmd})
+
+	switch {
+	case cmd.State == RequiredState && !piped:
+		c.report(args[0].word, "command %q must be piped", name)
+	case cmd.State == NoState && piped:
+		c.report(args[0].word, "command %q cannot be piped", name)
+	}
+
+	if cmd.Args != nil {
+		values := make([]string, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			if !arg.literal {
+				// the number of arguments depends on the expansions
+				values = nil
+				break
+			}
+			values = append(values, arg.value)
+		}
+		if values != nil {
+			if err := cmd.Args(values); err != nil {
+				c.report(args[0].word, "command %q %s", name, err)
+			}
+		}
+	}
+
+	switch name {
+	case shellDepsCmdName:
+		md, err := c.h.GetModuleDef(nil)
+		if err != nil {
+			c.report(args[0].word, "%s", err)
+			return nil
+		}
+		return &shellCheckResult{def: md, namespace: name}
+	case shellStdlibCmdName, shellCoreCmdName:
+		return &shellCheckResult{def: c.h.GetDef(nil), namespace: name}
+	}
+	return nil
+}
+
+// entrypoint checks the first command in a pipeline, following the same
+// lookups as StateLookup.
+func (c *shellChecker) entrypoint(args []shellCheckArg) *shellCheckResult {
+	name := args[0].value
+
+	if md, _ := c.h.GetModuleDef(nil); md != nil {
+		// 1. Function in current context
+		if md.HasMainFunction(name) {
+			fn, err := md.GetFunction(md.MainObject.AsFunctionProvider(), name)
+			if err != nil {
+				c.report(args[0].word, "%s", err)
+				return nil
+			}
+			return c.function(md, fn, args)
+		}
+
+		// 2. Is it the current module's name?
+		if md.Name == name {
+			return c.function(md, md.MainObject.AsObject.Constructor, args)
+		}
+
+		// 3. Dependency short name
+		if dep := md.GetDependency(name); dep != nil {
+			return c.dependency(args)
+		}
+	}
+
+	// 4. Standard library command
+	if res := c.stdlib(args); res != nil {
+		return res
+	}
+
+	// 5. Path to local or remote module source
+	def, _, err := c.h.maybeLoadModule(c.ctx, name)
+	if err != nil {
+		c.report(args[0].word, "%s", err)
+		return nil
+	}
+	if def != nil {
+		return c.function(def, def.MainObject.AsObject.Constructor, args)
+	}
+
+	c.report(args[0].word, "function or module %q not found", name)
+	return nil
+}
+
+// chained checks a command piped after another one.
+func (c *shellChecker) chained(prev *shellCheckResult, args []shellCheckArg) *shellCheckResult {
+	if prev == nil {
+		// unknown input, e.g. from a function declared in the script
+		return nil
+	}
+	name := args[0].value
+
+	switch prev.namespace {
+	case shellStdlibCmdName:
+		// Example: .stdlib | <command>`
+		if res := c.stdlib(args); res != nil {
+			return res
+		}
+		c.report(args[0].word, "command not found: %q", name)
+		return nil
+
+	case shellDepsCmdName:
+		// Example: `.deps | <dependency>`
+		return c.dependency(args)
+
+	case shellCoreCmdName:
+		// Example: `.core | <function>`
+		fn := prev.def.GetCoreFunction(name)
+		if fn == nil {
+			c.report(args[0].word, "core function %q not found", name)
+			return nil
+		}
+		return c.function(prev.def, fn, args)
+	}
+
+	if prev.fn == nil {
+		return nil
+	}
+	fp := prev.provider()
+	if fp == nil {
+		c.report(args[0].word, "cannot pipe %q after %q returning a non-object type", name, prev.fn.CmdName())
+		return nil
+	}
+	fn, err := prev.def.GetFunction(fp, name)
+	if err != nil {
+		c.report(args[0].word, "%s", err)
+		return nil
+	}
+	return c.function(prev.def, fn, args)
+}
+
+// stdlib checks a call to a standard library command, returning nil if
+// there's no such command.
+func (c *shellChecker) stdlib(args []shellCheckArg) *shellCheckResult {
+	if cmd, _ := c.h.StdlibCommand(args[0].value); cmd == nil {
+		return nil
+	}
+	def := c.h.GetDef(nil)
+	fn := def.GetCoreFunction(args[0].value)
+	if fn == nil {
+		return nil
+	}
+	return c.function(def, fn, args)
+}
+
+// dependency checks a call to the constructor of a dependency, loading its
+// type definitions.
+func (c *shellChecker) dependency(args []shellCheckArg) *shellCheckResult {
+	_, def, err := c.h.GetDependency(c.ctx, args[0].value)
+	if err != nil {
+		c.report(args[0].word, "%s", err)
+		return nil
+	}
+	return c.function(def, def.MainObject.AsObject.Constructor, args)
+}
+
+// function checks the arguments of a function call and returns its result.
+//
+// Argument errors don't prevent checking the rest of the pipeline since the
+// function's return type is still known.
+func (c *shellChecker) function(def *moduleDef, fn *modFunction, args []shellCheckArg) *shellCheckResult {
+	if fn == nil {
+		return nil
+	}
+	c.symbols = append(c.symbols, shellSymbol{node: args[0].word, def: def, fn: fn})
+	for _, arg := range args[1:] {
+		name, ok := strings.CutPrefix(arg.value, "--")
+		if !ok {
+			continue
+		}
+		if name == "" {
+			// the rest are positional arguments
+			break
+		}
+		name, _, _ = strings.Cut(name, "=")
+		if a, err := fn.GetArg(name); err == nil {
+			c.symbols = append(c.symbols, shellSymbol{node: arg.word, def: def, arg: a})
+		}
+	}
+
+	if err := c.checkArgs(fn, args[1:]); err != nil {
+		c.report(args[0].word, "function %q: %s", fn.CmdName(), err)
+	}
+	return &shellCheckResult{def: def, fn: fn}
+}
+
+// checkArgs validates the arguments of a function call, like
+// parseArgumentValues, but without resolving their values.
+//
+// Arguments with expansions are assumed to expand to a single argument, and
+// only the type of the objects they resolve to is checked.
+func (c *shellChecker) checkArgs(fn *modFunction, args []shellCheckArg) error {
+	// First consume the flags to get the positional arguments, like
+	// shellPreprocessArgs.
+	flags := pflag.NewFlagSet(fn.CmdName(), pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	for _, arg := range fn.Args {
+		name := arg.FlagName()
+		switch arg.TypeDef.Kind {
+		case dagger.TypeDefKindListKind:
+			flags.StringSlice(name, nil, "")
+		case dagger.TypeDefKindBooleanKind:
+			flags.Var(&shellCheckBool{}, name, "")
+			flags.Lookup(name).NoOptDefVal = "true"
+		default:
+			flags.String(name, "", "")
+		}
+	}
+	values := make([]string, 0, len(args))
+	placeholders := map[string]*shellCheckResult{}
+	for i, arg := range args {
+		value := arg.value
+		if !arg.literal {
+			// tag the placeholder to find the argument's result, when it's
+			// the whole value of a flag or positional argument
+			tag := shellCheckPlaceholder + strconv.Itoa(i) + shellCheckPlaceholder
+			value = strings.ReplaceAll(value, shellCheckPlaceholder, tag)
+			placeholders[tag] = arg.result
+		}
+		values = append(values, value)
+	}
+	if err := flags.Parse(values); err != nil {
+		return checkErrHelp(err, values)
+	}
+
+	pos := flags.Args()
+	if flags.ArgsLenAtDash() == 1 {
+		pos = pos[1:]
+	}
+
+	// Then validate each value with the argument's flag, unless it has
+	// expansions.
+	typed := pflag.NewFlagSet(fn.CmdName(), pflag.ContinueOnError)
+	typed.SetOutput(io.Discard)
+	for _, arg := range fn.Args {
+		if err := arg.AddFlag(typed); err != nil {
+			typed.String(arg.FlagName(), "", "")
+		}
+	}
+	set := func(arg *modFunctionArg, value string) error {
+		if strings.Contains(value, shellCheckPlaceholder) {
+			return checkArgResult(arg, placeholders[value])
+		}
+		if err := typed.Set(arg.FlagName(), value); err != nil {
+			return fmt.Errorf("invalid argument %q: %w", arg.FlagName(), err)
+		}
+		return nil
+	}
+
+	reqs := fn.RequiredArgs()
+	if len(reqs) == 1 && len(pos) > 1 && reqs[0].TypeDef.String() == "[]string" {
+		// all positional arguments are elements of the list
+		pos = nil
+	}
+	var remaining []*modFunctionArg
+	for _, arg := range reqs {
+		if flag := flags.Lookup(arg.FlagName()); flag == nil || !flag.Changed {
+			remaining = append(remaining, arg)
+		}
+	}
+	if pos != nil && len(pos) != len(remaining) {
+		return fmt.Errorf("requires %d positional argument(s), received %d", len(remaining), len(pos))
+	}
+
+	var errs []error
+	for i, value := range pos {
+		errs = append(errs, set(remaining[i], value))
+	}
+	flags.Visit(func(f *pflag.Flag) {
+		arg, err := fn.GetArg(f.Name)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range slice.GetSlice() {
+				errs = append(errs, set(arg, v))
+			}
+			return
+		}
+		errs = append(errs, set(arg, f.Value.String()))
+	})
+	return errors.Join(errs...)
+}
+
+// checkArgResult checks that a command substitution or variable resolves to
+// the object an argument expects.
+func checkArgResult(arg *modFunctionArg, res *shellCheckResult) error {
+	if res == nil || res.fn == nil {
+		return nil
+	}
+	want := arg.TypeDef
+	if want.AsList != nil {
+		want = want.AsList.ElementTypeDef
+	}
+	if want.AsObject == nil {
+		return nil
+	}
+	got := res.fn.ReturnType
+	if got.AsFunctionProvider() == nil || got.Name() != want.AsObject.Name {
+		return fmt.Errorf("argument %q expects %s, got %s", arg.FlagName(), want.String(), got.String())
+	}
+	return nil
+}
+
+// shellCheckBool is a boolean flag value which also accepts placeholders.
+type shellCheckBool struct {
+	value string
+}
+
+func (v *shellCheckBool) Type() string {
+	return "bool"
+}
+
+func (v *shellCheckBool) IsBoolFlag() bool {
+	return true
+}
+
+func (v *shellCheckBool) Set(s string) error {
+	if !strings.Contains(s, shellCheckPlaceholder) {
+		if _, err := strconv.ParseBool(s); err != nil {
+			return err
+		}
+	}
+	v.value = s
+	return nil
+}
+
+func (v *shellCheckBool) String() string {
+	return v.value
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/dagger/dagger/dagql/idtui"
+	"github.com/dagger/testctx"
+	"github.com/stretchr/testify/require"
+)
+
+func (DaggerCMDSuite) TestShellCheck(ctx context.Context, t *testctx.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, os.DirFS(filepath.Join(wd, "../../modules"))))
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	require.NoError(t, cmd.Run())
+
+	os.Chdir(dir)
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+	t.Setenv("DAGGER_MODULE", "./wolfi")
+
+	client, err := dagger.Connect(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	handler := newShellCallHandler(client, &idtui.FrontendMock{})
+	require.NoError(t, handler.Initialize(ctx))
+
+	for _, tc := range []struct {
+		script string
+		errors []string
+	}{
+		{
+			script: `container | from alpine | with-exec echo hello | stdout`,
+		},
+		{
+			// module function, dependency and variables
+			script: `ctr=$(container --packages=git)
+alpine | container | with-directory /src $(directory) | stdout
+$ctr | with-mounted-directory /src $(directory) | stdout`,
+		},
+		{
+			script: `container | from alpine | with-exek echo hello | stdout`,
+			errors: []string{`1:27: no function "with-exek" in type "Container"`},
+		},
+		{
+			script: "container\ncontainer | from alpine | with-exec --bogus echo | stdout",
+			errors: []string{`2:27: function "with-exec": unknown flag: --bogus`},
+		},
+		{
+			script: `container | from | stdout`,
+			errors: []string{`1:13: function "from": requires 1 positional argument(s), received 0`},
+		},
+		{
+			script: `container | from alpine | stdout | file /foo`,
+			errors: []string{`1:36: cannot pipe "file" after "stdout" returning a non-object type`},
+		},
+		{
+			script: `container | with-directory /src $(container) | stdout`,
+			errors: []string{`1:13: function "with-directory": argument "source" expects Directory, got Container`},
+		},
+		{
+			script: `container | with-exec --expect=MAYBE echo | stdout`,
+			errors: []string{`1:13: function "with-exec": invalid argument "expect": value should be one of SUCCESS,FAILURE,ANY`},
+		},
+		{
+			script: `foo() { container | bogus; }; foo | with-exec echo`,
+			errors: []string{`1:21: no function "bogus" in type "Container"`},
+		},
+		{
+			script: `dir=$(directory | with-new-fil foo bar)`,
+			errors: []string{`1:19: no function "with-new-fil" in type "Directory"`},
+		},
+	} {
+		t.Run(tc.script, func(ctx context.Context, t *testctx.T) {
+			diags, err := handler.Check(ctx, strings.NewReader(tc.script), "")
+			require.NoError(t, err)
+			errs := make([]string, 0, len(diags))
+			for _, d := range diags {
+				errs = append(errs, d.String(""))
+			}
+			if len(tc.errors) == 0 {
+				require.Empty(t, errs)
+			} else {
+				require.Equal(t, tc.errors, errs)
+			}
+		})
+	}
+}
+
+func TestShellCheckParseError(t *testing.T) {
+	// parsing errors are reported before anything is loaded
+	h := &shellCallHandler{}
+	diags, err := h.Check(context.Background(), strings.NewReader("container\ncontainer | from alpine |"), "job.dsh")
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	require.Equal(t, "job.dsh:2:25: | must be followed by a statement", diags[0].String("job.dsh"))
+}
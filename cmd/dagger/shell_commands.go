@@ -583,6 +583,46 @@ Without arguments, the current working directory is replaced by the initial cont
 				return h.Print(ctx, strings.Join(contents, "\n"))
 			},
 		},
+		&ShellCommand{
+			Use: ".save <name>",
+			Description: `Save the shell variables and working directory under a name
+
+Objects are saved by ID, so the result of a pipeline isn't evaluated when saving it.
+Exported variables aren't saved. Sessions are stored in the user's state directory.
+
+Example:
+
+  ctr=$(container | from alpine | with-exec apk add git)
+  .save work
+`,
+			Args:  ExactArgs(1),
+			State: NoState,
+			Run: func(ctx context.Context, cmd *ShellCommand, args []string, _ *ShellState) error {
+				return h.SaveSession(ctx, args[0])
+			},
+		},
+		&ShellCommand{
+			Use: ".restore <name>",
+			Description: `Restore the shell variables and working directory saved with .save
+
+Objects are loaded from their ID when used, hitting the cache from the session
+that saved them.
+
+Example:
+
+  .restore work
+  $ctr | with-exec git --version | stdout
+`,
+			Args:  ExactArgs(1),
+			State: NoState,
+			Run: func(ctx context.Context, cmd *ShellCommand, args []string, _ *ShellState) error {
+				code, err := h.RestoreSession(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return interp.HandlerCtx(ctx).Builtin(ctx, []string{"eval", code})
+			},
+		},
 		&ShellCommand{
 			Use:         ".types",
 			Description: "List all types available in the current context",
@@ -19,11 +19,30 @@ var _ editline.AutoCompleteFn = (*shellAutoComplete)(nil).Do
 
 func (h *shellAutoComplete) Do(entireInput [][]rune, row, col int) (msg string, comp editline.Completions) {
 	line, pos := computil.Flatten(entireInput, row, col)
-	line = line[:pos]
+
+	prefix, matches, ok := h.complete(line[:pos])
+	if !ok {
+		return "", nil
+	}
+	return "", editline.SimpleWordsCompletion(
+		matches,
+		"completion",
+		col,
+		pos-len(prefix),
+		pos,
+	)
+}
+
+// complete returns the completions at the end of the given input, along with
+// the prefix of the in-progress word they replace.
+//
+// It returns false if there's no completion context at that point.
+func (h *shellAutoComplete) complete(line string) (prefix string, matches []string, ok bool) {
+	pos := len(line)
 
 	file, err := parseShell(strings.NewReader(line), "", syntax.RecoverErrors(5))
 	if err != nil {
-		return "", nil
+		return "", nil, false
 	}
 
 	// find the smallest stmt next to the cursor - this allows accurate
@@ -107,11 +126,10 @@ func (h *shellAutoComplete) Do(entireInput [][]rune, row, col int) (msg string,
 		shctx = h.dispatch(shctx, stmt, cursor)
 	}
 	if shctx == nil {
-		return "", nil
+		return "", nil, false
 	}
 
 	completions := shctx.completions(inprogressPrefix)
-	var matches []string
 	suggested := map[string]struct{}{}
 	for _, c := range completions {
 		if strings.HasPrefix(c, inprogressPrefix) {
@@ -122,13 +140,7 @@ func (h *shellAutoComplete) Do(entireInput [][]rune, row, col int) (msg string,
 			suggested[c] = struct{}{}
 		}
 	}
-	return "", editline.SimpleWordsCompletion(
-		matches,
-		"completion",
-		col,
-		pos-len(inprogressPrefix),
-		pos,
-	)
+	return inprogressPrefix, matches, true
 }
 
 func (h *shellAutoComplete) dispatch(previous *CompletionContext, stmt *syntax.Stmt, cursor uint) *CompletionContext {
@@ -0,0 +1,528 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ServeLSP is the entry point for `dagger shell --lsp`
+//
+// It serves the Language Server Protocol over r and w, so that editors can
+// provide completion, documentation on hover, go-to-definition and
+// diagnostics for shell scripts, resolved against the loaded modules.
+func (h *shellCallHandler) ServeLSP(ctx context.Context, r io.Reader, w io.Writer) error {
+	if err := h.Initialize(ctx); err != nil {
+		return err
+	}
+	s := &shellLanguageServer{
+		h:    h,
+		w:    w,
+		docs: map[string]*shellLSPDocument{},
+	}
+	return s.serve(ctx, bufio.NewReader(r))
+}
+
+// shellLanguageServer handles the messages from a language client, one at a
+// time.
+type shellLanguageServer struct {
+	h *shellCallHandler
+	w io.Writer
+
+	// docs are the documents opened in the client, by URI
+	docs map[string]*shellLSPDocument
+
+	shutdown bool
+}
+
+// shellLSPDocument is the content of a document opened in the client, and
+// the result of analyzing it.
+type shellLSPDocument struct {
+	text     string
+	analysis *shellChecker
+}
+
+type lspMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *lspError) Error() string {
+	return e.Message
+}
+
+const (
+	lspMethodNotFound  = -32601
+	lspInvalidParams   = -32602
+	lspInternalError   = -32603
+	lspSeverityError   = 1
+	lspTextSyncFull    = 1
+	lspKindFunction    = 3
+	lspMessageTypeWarn = 2
+)
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspTextDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+func (s *shellLanguageServer) serve(ctx context.Context, r *bufio.Reader) error {
+	for {
+		msg, err := readLSPMessage(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+
+		result, err := s.handle(ctx, msg)
+
+		if msg.ID == nil {
+			// notifications don't have a response
+			if err != nil {
+				s.notify("window/logMessage", map[string]any{
+					"type":    lspMessageTypeWarn,
+					"message": fmt.Sprintf("%s: %s", msg.Method, err),
+				})
+			}
+			continue
+		}
+		if err := s.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *shellLanguageServer) handle(ctx context.Context, msg *lspMessage) (any, error) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": lspTextSyncFull,
+				"completionProvider": map[string]any{
+					"triggerCharacters": []string{" ", "|", "-"},
+				},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]any{
+				"name": "dagger shell",
+			},
+		}, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.update(ctx, params.TextDocument.URI, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// only full syncs are supported, so the last change has the
+		// entire content
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.update(ctx, params.TextDocument.URI, text)
+
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.publishDiagnostics(params.TextDocument.URI, nil)
+		return nil, nil
+
+	case "textDocument/completion":
+		doc, pos, err := s.position(msg.Params)
+		if err != nil {
+			return nil, err
+		}
+		return s.completion(doc, pos), nil
+
+	case "textDocument/hover":
+		doc, pos, err := s.position(msg.Params)
+		if err != nil {
+			return nil, err
+		}
+		return s.hover(doc, pos), nil
+
+	case "textDocument/definition":
+		doc, pos, err := s.position(msg.Params)
+		if err != nil {
+			return nil, err
+		}
+		return s.definition(ctx, doc, pos)
+	}
+
+	if msg.ID == nil {
+		// ignore notifications that aren't supported, e.g., `initialized`
+		return nil, nil
+	}
+	return nil, &lspError{
+		Code:    lspMethodNotFound,
+		Message: fmt.Sprintf("method not supported: %s", msg.Method),
+	}
+}
+
+// update analyzes the new content of a document and sends the errors found
+// to the client.
+func (s *shellLanguageServer) update(ctx context.Context, uri, text string) error {
+	analysis, err := s.h.analyze(ctx, strings.NewReader(text), lspFilename(uri))
+	if err != nil {
+		return err
+	}
+	doc := &shellLSPDocument{
+		text:     text,
+		analysis: analysis,
+	}
+	s.docs[uri] = doc
+
+	diags := make([]map[string]any, 0, len(analysis.diags))
+	for _, d := range analysis.diags {
+		diags = append(diags, map[string]any{
+			"range": lspRange{
+				Start: doc.positionAt(int(d.Pos.Offset())),
+				End:   doc.positionAt(int(d.End.Offset())),
+			},
+			"severity": lspSeverityError,
+			"source":   "dagger",
+			"message":  d.Message,
+		})
+	}
+	s.publishDiagnostics(uri, diags)
+	return nil
+}
+
+func (s *shellLanguageServer) publishDiagnostics(uri string, diags []map[string]any) {
+	if diags == nil {
+		diags = []map[string]any{}
+	}
+	s.notify("textDocument/publishDiagnostics", map[string]any{
+		"uri":         uri,
+		"diagnostics": diags,
+	})
+}
+
+// position returns the document and byte offset requested by a client.
+func (s *shellLanguageServer) position(params json.RawMessage) (*shellLSPDocument, int, error) {
+	var p lspTextDocumentPosition
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, 0, err
+	}
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return nil, 0, &lspError{
+			Code:    lspInvalidParams,
+			Message: fmt.Sprintf("document not opened: %s", p.TextDocument.URI),
+		}
+	}
+	return doc, doc.offset(p.Position), nil
+}
+
+// completion suggests the same words as the interactive shell, for the text
+// up to the cursor.
+func (s *shellLanguageServer) completion(doc *shellLSPDocument, pos int) []map[string]any {
+	ac := &shellAutoComplete{s.h}
+	prefix, matches, ok := ac.complete(doc.text[:pos])
+	if !ok {
+		return []map[string]any{}
+	}
+	replace := lspRange{
+		Start: doc.positionAt(pos - len(prefix)),
+		End:   doc.positionAt(pos),
+	}
+	items := make([]map[string]any, 0, len(matches))
+	for _, m := range matches {
+		items = append(items, map[string]any{
+			"label": m,
+			"kind":  lspKindFunction,
+			"textEdit": map[string]any{
+				"range":   replace,
+				"newText": m,
+			},
+		})
+	}
+	return items
+}
+
+// hover shows the same documentation as `.help` for the word under the
+// cursor.
+func (s *shellLanguageServer) hover(doc *shellLSPDocument, pos int) any {
+	sym := doc.symbolAt(pos)
+	if sym == nil {
+		return nil
+	}
+	var help string
+	switch {
+	case sym.cmd != nil:
+		help = sym.cmd.Help()
+	case sym.arg != nil:
+		help = sym.arg.Usage()
+		if long := sym.arg.Long(); long != "" {
+			help += "\n\n" + long
+		}
+	case sym.typeDef != nil:
+		help = shellTypeDoc(sym.typeDef)
+	case sym.def.HasModule() && sym.fn == sym.def.MainObject.AsObject.Constructor:
+		help = s.h.ModuleDoc(sym.def)
+	default:
+		help = s.h.FunctionDoc(sym.def, sym.fn)
+	}
+	return map[string]any{
+		"contents": map[string]any{
+			"kind":  "markdown",
+			"value": "```text\n" + strings.TrimSpace(ansi.Strip(help)) + "\n```",
+		},
+		"range": lspRange{
+			Start: doc.positionAt(int(sym.node.Pos().Offset())),
+			End:   doc.positionAt(int(sym.node.End().Offset())),
+		},
+	}
+}
+
+// definition returns the location in the module source where the function,
+// argument or object under the cursor is declared, if known.
+func (s *shellLanguageServer) definition(ctx context.Context, doc *shellLSPDocument, pos int) (any, error) {
+	sym := doc.symbolAt(pos)
+	if sym == nil || sym.def == nil {
+		return nil, nil
+	}
+	var sm *modSourceMap
+	switch {
+	case sym.arg != nil:
+		sm = sym.arg.SourceMap
+	case sym.typeDef != nil && sym.typeDef.AsObject != nil:
+		sm = sym.typeDef.AsObject.SourceMap
+	case sym.fn != nil:
+		sm = sym.fn.SourceMap
+		if sm == nil && sym.def.HasModule() && sym.fn == sym.def.MainObject.AsObject.Constructor {
+			// modules without a constructor are defined by their main object
+			sm = sym.def.MainObject.AsObject.SourceMap
+		}
+	}
+	if sm == nil || sm.Filename == "" {
+		return nil, nil
+	}
+
+	start := lspPosition{
+		Line:      max(sm.Line-1, 0),
+		Character: max(sm.Column-1, 0),
+	}
+	loc := lspLocation{
+		URI:   sm.URL,
+		Range: lspRange{Start: start, End: start},
+	}
+	if loc.URI == "" {
+		// Source maps are relative to the context directory of the module
+		// that declared them, which can be a dependency when it's an object
+		// from a dependency.
+		def := sym.def
+		if sm.Module != "" && sm.Module != def.Name {
+			if dep := def.GetDependency(sm.Module); dep != nil {
+				def = dep
+			}
+		}
+		if def.Source == nil {
+			return nil, nil
+		}
+		contextDir, err := def.Source.LocalContextDirectoryPath(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get context directory of module %q: %w", def.Name, err)
+		}
+		loc.URI = (&url.URL{
+			Scheme: "file",
+			Path:   filepath.ToSlash(filepath.Join(contextDir, sm.Filename)),
+		}).String()
+	}
+	return loc, nil
+}
+
+// symbolAt returns the innermost resolved word at the given byte offset.
+func (d *shellLSPDocument) symbolAt(pos int) *shellSymbol {
+	var found *shellSymbol
+	for i, sym := range d.analysis.symbols {
+		start, end := int(sym.node.Pos().Offset()), int(sym.node.End().Offset())
+		if pos < start || pos > end {
+			continue
+		}
+		if found == nil || start >= int(found.node.Pos().Offset()) && end <= int(found.node.End().Offset()) {
+			found = &d.analysis.symbols[i]
+		}
+	}
+	return found
+}
+
+// positionAt converts a byte offset in the document to a position, which
+// counts characters in UTF-16 code units.
+func (d *shellLSPDocument) positionAt(offset int) lspPosition {
+	offset = min(max(offset, 0), len(d.text))
+	before := d.text[:offset]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	var units int
+	for _, r := range before[lineStart:] {
+		units += utf16.RuneLen(r)
+	}
+	return lspPosition{
+		Line:      strings.Count(before, "\n"),
+		Character: units,
+	}
+}
+
+// offset converts a position to a byte offset in the document.
+func (d *shellLSPDocument) offset(pos lspPosition) int {
+	var offset int
+	for range pos.Line {
+		i := strings.IndexByte(d.text[offset:], '\n')
+		if i < 0 {
+			return len(d.text)
+		}
+		offset += i + 1
+	}
+	line := d.text[offset:]
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	var units int
+	for i, r := range line {
+		if units >= pos.Character {
+			return offset + i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return offset + len(line)
+}
+
+// lspFilename returns the file path of a document URI, for error messages.
+func lspFilename(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// readLSPMessage reads a message framed by a Content-Length header.
+func readLSPMessage(r *bufio.Reader) (*lspMessage, error) {
+	body, err := readLSPBody(r)
+	if err != nil {
+		return nil, err
+	}
+	var msg lspMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("decode message: %w", err)
+	}
+	return &msg, nil
+}
+
+func readLSPBody(r *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %w", err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+func (s *shellLanguageServer) write(msg map[string]any) error {
+	msg["jsonrpc"] = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func (s *shellLanguageServer) reply(id json.RawMessage, result any, err error) error {
+	if err != nil {
+		var lerr *lspError
+		if !errors.As(err, &lerr) {
+			lerr = &lspError{Code: lspInternalError, Message: err.Error()}
+		}
+		return s.write(map[string]any{"id": id, "error": lerr})
+	}
+	return s.write(map[string]any{"id": id, "result": result})
+}
+
+func (s *shellLanguageServer) notify(method string, params any) {
+	// errors writing are caught when replying to the next request
+	s.write(map[string]any{"method": method, "params": params})
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/dagger/dagger/dagql/idtui"
+	"github.com/dagger/testctx"
+	"github.com/stretchr/testify/require"
+)
+
+// lspSession encodes requests and notifications from a language client.
+type lspSession struct {
+	buf bytes.Buffer
+	id  int
+}
+
+func (c *lspSession) request(method string, params any) int {
+	c.id++
+	c.send(map[string]any{"jsonrpc": "2.0", "id": c.id, "method": method, "params": params})
+	return c.id
+}
+
+func (c *lspSession) notify(method string, params any) {
+	c.send(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *lspSession) send(msg map[string]any) {
+	body, _ := json.Marshal(msg)
+	fmt.Fprintf(&c.buf, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+type lspTestMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *lspError       `json:"error"`
+}
+
+// serveLSP runs the language server until the session's messages are
+// consumed, and returns the messages it sent.
+func serveLSP(ctx context.Context, t testing.TB, h *shellCallHandler, c *lspSession) []lspTestMessage {
+	t.Helper()
+	var out bytes.Buffer
+	s := &shellLanguageServer{
+		h:    h,
+		w:    &out,
+		docs: map[string]*shellLSPDocument{},
+	}
+	require.NoError(t, s.serve(ctx, bufio.NewReader(&c.buf)))
+
+	var msgs []lspTestMessage
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readLSPBody(r)
+		if errors.Is(err, io.EOF) {
+			return msgs
+		}
+		require.NoError(t, err)
+		var msg lspTestMessage
+		require.NoError(t, json.Unmarshal(body, &msg))
+		msgs = append(msgs, msg)
+	}
+}
+
+func lspResult(t testing.TB, msgs []lspTestMessage, id int, v any) {
+	t.Helper()
+	for _, msg := range msgs {
+		if msg.ID != nil && *msg.ID == id && msg.Method == "" {
+			require.Nil(t, msg.Error)
+			require.NoError(t, json.Unmarshal(msg.Result, v))
+			return
+		}
+	}
+	t.Fatalf("no response to request %d", id)
+}
+
+func TestShellLSPDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	uri := "file:///src/job.dsh"
+
+	c := &lspSession{}
+	initID := c.request("initialize", map[string]any{})
+	c.notify("initialized", map[string]any{})
+	// parsing errors are reported before anything is loaded
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{
+			"uri":  uri,
+			"text": "# ✓ done\ncontainer | from alpine |",
+		},
+	})
+	hoverID := c.request("textDocument/hover", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": 1, "character": 2},
+	})
+	unknownID := c.request("textDocument/formatting", map[string]any{})
+	c.request("shutdown", nil)
+	c.notify("exit", nil)
+
+	msgs := serveLSP(ctx, t, &shellCallHandler{}, c)
+
+	var init struct {
+		Capabilities struct {
+			HoverProvider      bool `json:"hoverProvider"`
+			DefinitionProvider bool `json:"definitionProvider"`
+		} `json:"capabilities"`
+	}
+	lspResult(t, msgs, initID, &init)
+	require.True(t, init.Capabilities.HoverProvider)
+	require.True(t, init.Capabilities.DefinitionProvider)
+
+	type publishDiagnostics struct {
+		URI         string `json:"uri"`
+		Diagnostics []struct {
+			Range   lspRange `json:"range"`
+			Message string   `json:"message"`
+		} `json:"diagnostics"`
+	}
+	var diags []publishDiagnostics
+	for _, msg := range msgs {
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var p publishDiagnostics
+			require.NoError(t, json.Unmarshal(msg.Params, &p))
+			diags = append(diags, p)
+		}
+	}
+	require.Len(t, diags, 1)
+	require.Equal(t, uri, diags[0].URI)
+	require.Len(t, diags[0].Diagnostics, 1)
+	require.Equal(t, "| must be followed by a statement", diags[0].Diagnostics[0].Message)
+	require.Equal(t, lspPosition{Line: 1, Character: 24}, diags[0].Diagnostics[0].Range.Start)
+
+	var hover any
+	lspResult(t, msgs, hoverID, &hover)
+	require.Nil(t, hover)
+
+	for _, msg := range msgs {
+		if msg.ID != nil && *msg.ID == unknownID {
+			require.NotNil(t, msg.Error)
+			require.Equal(t, lspMethodNotFound, msg.Error.Code)
+		}
+	}
+}
+
+func TestShellLSPPositions(t *testing.T) {
+	doc := &shellLSPDocument{text: "# 🐳 whale\ncontainer | from alpine"}
+
+	// the emoji is a single rune, but two UTF-16 code units
+	for _, tc := range []struct {
+		offset int
+		pos    lspPosition
+	}{
+		{0, lspPosition{Line: 0, Character: 0}},
+		{strings.Index(doc.text, "whale"), lspPosition{Line: 0, Character: 5}},
+		{strings.Index(doc.text, "container"), lspPosition{Line: 1, Character: 0}},
+		{strings.Index(doc.text, "from"), lspPosition{Line: 1, Character: 12}},
+		{len(doc.text), lspPosition{Line: 1, Character: 23}},
+	} {
+		require.Equal(t, tc.pos, doc.positionAt(tc.offset))
+		require.Equal(t, tc.offset, doc.offset(tc.pos))
+	}
+
+	// positions past the end of a line are clamped to it
+	require.Equal(t, strings.Index(doc.text, "\n"), doc.offset(lspPosition{Line: 0, Character: 100}))
+	require.Equal(t, len(doc.text), doc.offset(lspPosition{Line: 5, Character: 0}))
+}
+
+func (DaggerCMDSuite) TestShellLSP(ctx context.Context, t *testctx.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, os.DirFS(filepath.Join(wd, "../../modules"))))
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	require.NoError(t, cmd.Run())
+
+	os.Chdir(dir)
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+	t.Setenv("DAGGER_MODULE", "./wolfi")
+
+	client, err := dagger.Connect(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	handler := newShellCallHandler(client, &idtui.FrontendMock{})
+	require.NoError(t, handler.Initialize(ctx))
+
+	uri := "file://" + filepath.Join(dir, "job.dsh")
+	text := "ctr=$(container --packages=git)\n$ctr | with-exec --expect=ANY git | stdo"
+
+	c := &lspSession{}
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "text": text},
+	})
+	at := func(line, character int) map[string]any {
+		return map[string]any{
+			"textDocument": map[string]any{"uri": uri},
+			"position":     map[string]any{"line": line, "character": character},
+		}
+	}
+	fnHoverID := c.request("textDocument/hover", at(1, 9))
+	argHoverID := c.request("textDocument/hover", at(1, 20))
+	typeHoverID := c.request("textDocument/hover", at(1, 1))
+	modHoverID := c.request("textDocument/hover", at(0, 8))
+	completionID := c.request("textDocument/completion", at(1, len("$ctr | with-exec --expect=ANY git | stdo")))
+	definitionID := c.request("textDocument/definition", at(0, 8))
+	c.request("shutdown", nil)
+	c.notify("exit", nil)
+
+	msgs := serveLSP(ctx, t, handler, c)
+
+	type hover struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+	}
+
+	var fnHover hover
+	lspResult(t, msgs, fnHoverID, &fnHover)
+	require.Contains(t, fnHover.Contents.Value, "with-exec <args> [options]")
+
+	var argHover hover
+	lspResult(t, msgs, argHoverID, &argHover)
+	require.Contains(t, argHover.Contents.Value, "--expect ReturnType")
+
+	var typeHover hover
+	lspResult(t, msgs, typeHoverID, &typeHover)
+	require.Contains(t, typeHover.Contents.Value, "OBJECT")
+	require.Contains(t, typeHover.Contents.Value, "with-exec")
+
+	var modHover hover
+	lspResult(t, msgs, modHoverID, &modHover)
+	require.Contains(t, modHover.Contents.Value, "container [options]")
+
+	var completions []struct {
+		Label string `json:"label"`
+	}
+	lspResult(t, msgs, completionID, &completions)
+	require.Equal(t, "stdout", completions[0].Label)
+
+	var definition lspLocation
+	lspResult(t, msgs, definitionID, &definition)
+	require.Equal(t, "file://"+filepath.Join(dir, "wolfi", "main.go"), definition.URI)
+}
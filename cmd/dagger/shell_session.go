@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// shellSessionsDir is where sessions saved with `.save` are stored.
+var shellSessionsDir = filepath.Join(xdg.StateHome, "dagger", "shell-sessions")
+
+// shellSessionSkippedVars are variables managed by the interpreter, which
+// shouldn't be restored.
+var shellSessionSkippedVars = []string{"HOME", "IFS", "OLDPWD", "OPTIND", "PPID", "PWD"}
+
+// shellSession is the state of a shell session that persists across
+// processes.
+type shellSession struct {
+	// Workdir is the reference to the working directory, as printed by `.pwd`
+	Workdir string `json:"workdir"`
+
+	// Vars are the shell variables, by name
+	Vars map[string]shellSessionVar `json:"vars"`
+}
+
+// shellSessionVar is the value of a shell variable.
+//
+// Objects are saved by ID instead of the chain of calls that produced them.
+// IDs are content-addressed, so a restored object is only resolved when
+// used, and can hit the cache from the previous session.
+type shellSessionVar struct {
+	// Value is the value of a string variable
+	Value string `json:"value,omitempty"`
+
+	// ID is the encoded ID of an object
+	ID string `json:"id,omitempty"`
+
+	// Type is the name of the object's type
+	Type string `json:"type,omitempty"`
+
+	// Module is the reference to the module the object was loaded from,
+	// which has the definitions for its type
+	Module string `json:"module,omitempty"`
+}
+
+func shellSessionPath(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid session name %q", name)
+	}
+	return filepath.Join(shellSessionsDir, name+".json"), nil
+}
+
+// SaveSession saves the shell variables and working directory under the
+// given name.
+//
+// Exported variables are part of the environment the shell was started
+// with, so they're not saved.
+func (h *shellCallHandler) SaveSession(ctx context.Context, name string) error {
+	path, err := shellSessionPath(name)
+	if err != nil {
+		return err
+	}
+
+	hctx := interp.HandlerCtx(ctx)
+
+	sess := shellSession{
+		Workdir: h.Pwd(),
+		Vars:    make(map[string]shellSessionVar),
+	}
+
+	for name, v := range hctx.Env.Each {
+		if !v.IsSet() || v.Exported || v.ReadOnly || v.Kind != expand.String {
+			continue
+		}
+		if slices.Contains(shellSessionSkippedVars, name) {
+			continue
+		}
+		sv, err := h.sessionVar(ctx, v.Str)
+		if err != nil {
+			fmt.Fprintf(hctx.Stderr, "skipping variable %q: %s\n", name, err)
+			continue
+		}
+		sess.Vars[name] = sv
+	}
+
+	b, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
+
+// sessionVar returns the value of a variable to save, replacing a state
+// with the ID of the object it resolves to.
+func (h *shellCallHandler) sessionVar(ctx context.Context, value string) (shellSessionVar, error) {
+	if !HasState(value) {
+		return shellSessionVar{Value: value}, nil
+	}
+	key := GetStateKey(value)
+	if key == "" {
+		return shellSessionVar{}, errors.New("only values with a single object can be saved")
+	}
+	st, err := h.state.Load(key)
+	if err != nil {
+		return shellSessionVar{}, err
+	}
+	if st.Cmd != "" || st.IsEmpty() {
+		return shellSessionVar{}, errors.New("not an object")
+	}
+
+	def := h.GetDef(st)
+	t, err := st.GetTypeDef(def)
+	if err != nil {
+		return shellSessionVar{}, err
+	}
+	if t.AsObject == nil && t.AsInterface == nil {
+		return shellSessionVar{}, fmt.Errorf("type %q is not an object", t.String())
+	}
+
+	// Selecting the ID doesn't evaluate the object.
+	var id string
+	if err := st.QueryBuilder(h.dag).Select("id").Bind(&id).Execute(ctx); err != nil {
+		return shellSessionVar{}, err
+	}
+
+	v := shellSessionVar{
+		ID:   id,
+		Type: t.Name(),
+	}
+	if def.HasModule() {
+		v.Module = def.SourceRoot
+	}
+	return v, nil
+}
+
+// RestoreSession restores a session saved under the given name, changing
+// the working directory and returning the assignments for the variables.
+//
+// Objects are loaded from their ID lazily, as any other state.
+func (h *shellCallHandler) RestoreSession(ctx context.Context, name string) (string, error) {
+	path, err := shellSessionPath(name)
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("session %q not found", name)
+		}
+		return "", err
+	}
+	var sess shellSession
+	if err := json.Unmarshal(b, &sess); err != nil {
+		return "", fmt.Errorf("invalid session %q: %w", name, err)
+	}
+
+	if sess.Workdir != "" {
+		if err := h.ChangeDir(ctx, sess.Workdir); err != nil {
+			return "", fmt.Errorf("restore working directory: %w", err)
+		}
+	}
+
+	var sb strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(sess.Vars)) {
+		if !syntax.ValidName(name) {
+			return "", fmt.Errorf("invalid variable name %q", name)
+		}
+		v := sess.Vars[name]
+		value := v.Value
+		if v.ID != "" {
+			st := ShellState{
+				Calls: []FunctionCall{
+					{
+						Object: "Query",
+						Name:   "load" + v.Type + "FromID",
+						Arguments: map[string]any{
+							"id": v.ID,
+						},
+						ReturnObject: v.Type,
+					},
+				},
+			}
+			if v.Module != "" {
+				_, cfg, err := h.maybeLoadModule(ctx, v.Module)
+				if err != nil {
+					return "", fmt.Errorf("restore variable %q: %w", name, err)
+				}
+				if cfg == nil {
+					return "", fmt.Errorf("restore variable %q: module %q not found", name, v.Module)
+				}
+				st.ModDigest = cfg.Digest
+			}
+			value = newStateToken(h.state.Store(st))
+		}
+		quoted, err := syntax.Quote(value, syntax.LangBash)
+		if err != nil {
+			return "", fmt.Errorf("restore variable %q: %w", name, err)
+		}
+		fmt.Fprintf(&sb, "%s=%s\n", name, quoted)
+	}
+	return sb.String(), nil
+}
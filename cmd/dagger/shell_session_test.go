@@ -0,0 +1,19 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellSessionPath(t *testing.T) {
+	path, err := shellSessionPath("work")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(shellSessionsDir, "work.json"), path)
+
+	for _, name := range []string{"", ".", "..", ".hidden", "../work", "a/b"} {
+		_, err := shellSessionPath(name)
+		require.ErrorContains(t, err, "invalid session name", name)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dagger/dagger/dagql/dagui"
+	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/client"
+	"github.com/dagger/dagger/engine/clientdb"
+	"github.com/dagger/dagger/util/cleanups"
+)
+
+// traceFile, if set, records all telemetry emitted by the CLI.
+var traceFile *clientdb.File
+
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Save and view traces offline",
+	Annotations: map[string]string{
+		"experimental": "true",
+	},
+}
+
+// traceExportClient is the ID of a client whose stored trace to export.
+var traceExportClient string
+
+var traceExportCmd = &cobra.Command{
+	Use:   "export [options] <file> [<command>...]",
+	Short: "Save the trace of a past or new run to a file",
+	Long: `Saves the spans, logs and metrics of a run to a file.
+
+With --client, exports the trace the engine stored for a client, whether it's
+still running or has already finished. The engine keeps the traces of finished
+clients for an hour. The ID of the client is logged when it connects, and
+shown by "dagger watch". Exporting a client from another session requires the
+engine's operator token, passed in DAGGER_OPERATOR_TOKEN.
+
+Otherwise, executes the specified command in a Dagger session, like "dagger
+run", and saves its trace.
+
+The file can be viewed later with "dagger trace view", without access to the
+engine that ran it.`,
+	Example: `dagger trace export --client mcbzxj5ltaybb2y2stcftzepj build.db
+dagger trace export build.db -- dagger call build
+dagger trace view build.db`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if traceExportClient != "" {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(2)(cmd, args)
+	},
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		path := args[0]
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+		f, err := clientdb.OpenFile(ctx, path)
+		if err != nil {
+			return fmt.Errorf("open trace file: %w", err)
+		}
+		defer f.Close()
+
+		if traceExportClient != "" {
+			return exportClientTrace(ctx, f, traceExportClient)
+		}
+
+		traceFile = f
+		return Run(cmd, args[1:])
+	},
+}
+
+var traceViewCmd = &cobra.Command{
+	Use:          "view [options] <file>",
+	Short:        "View a trace saved with \"dagger trace export\"",
+	Example:      `dagger trace view build.db`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		path := args[0]
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+		f, err := clientdb.OpenFile(ctx, path)
+		if err != nil {
+			return fmt.Errorf("open trace file: %w", err)
+		}
+		defer f.Close()
+
+		primary, err := traceFileRoot(ctx, f)
+		if err != nil {
+			return err
+		}
+
+		viewOpts := opts
+		// keep the TUI open for navigation once everything is replayed
+		viewOpts.NoExit = true
+		return Frontend.Run(ctx, viewOpts, func(ctx context.Context) (cleanups.CleanupF, error) {
+			Frontend.SetPrimary(primary)
+			return nil, f.Replay(ctx,
+				Frontend.SpanExporter(),
+				Frontend.LogExporter(),
+				Frontend.MetricExporter())
+		})
+	},
+}
+
+var traceDiffCmd = &cobra.Command{
+	Use:   "diff [options] <before> <after>",
+	Short: "Compare the calls made in two traces saved with \"dagger trace export\"",
+	Long: `Compares two traces saved with "dagger trace export", matching calls by their
+digest, and reports calls that changed, lost cache, or took significantly
+longer or shorter.
+
+For calls whose digest changed, the arguments that changed it are shown, to
+help track down what's busting the cache.`,
+	Example:      `dagger trace diff yesterday.db today.db`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		before, err := loadTraceFile(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		after, err := loadTraceFile(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		return dagui.DiffRuns(before, after).WriteSummary(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	// don't require -- to disambiguate subcommand flags
+	traceExportCmd.Flags().SetInterspersed(false)
+	traceExportCmd.Flags().StringVar(&traceExportClient, "client", "", "Export the trace the engine stored for the client with this ID, instead of running a command")
+
+	traceCmd.AddCommand(traceExportCmd, traceViewCmd, traceDiffCmd)
+}
+
+// exportClientTrace saves the trace the engine stored for a client to the
+// file, waiting for the client to finish if it's still running.
+func exportClientTrace(ctx context.Context, f *clientdb.File, clientID string) error {
+	params := client.Params{
+		RunnerHost:    RunnerHost,
+		Command:       spanName(os.Args),
+		OperatorToken: os.Getenv("DAGGER_OPERATOR_TOKEN"),
+	}
+	if useCloudEngine {
+		params.RunnerHost = engine.DefaultCloudRunnerHost
+	}
+	sess, err := client.Connect(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	if err := sess.WatchClient(ctx, clientID,
+		f.SpanExporter(),
+		f.LogExporter(),
+		[]sdkmetric.Exporter{f.MetricExporter()},
+	); err != nil {
+		return fmt.Errorf("export client %s: %w", clientID, err)
+	}
+	return nil
+}
+
+// loadTraceFile replays the spans and logs in a trace file into a new DB.
+func loadTraceFile(ctx context.Context, path string) (*dagui.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	f, err := clientdb.OpenFile(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file: %w", err)
+	}
+	defer f.Close()
+
+	db := dagui.NewDB()
+	if err := f.Replay(ctx, db, db.LogExporter(), nil); err != nil {
+		return nil, fmt.Errorf("replay %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// traceFileRoot finds the span to treat as the primary span: the first one
+// recorded without a parent, or else the first one recorded.
+func traceFileRoot(ctx context.Context, f *clientdb.File) (dagui.SpanID, error) {
+	var root *clientdb.Span
+	for lastID := int64(0); root == nil || root.ParentSpanID.Valid; {
+		spans, err := f.SelectSpansSince(ctx, clientdb.SelectSpansSinceParams{
+			ID:    lastID,
+			Limit: 1000,
+		})
+		if err != nil {
+			return dagui.SpanID{}, fmt.Errorf("select spans: %w", err)
+		}
+		if len(spans) == 0 {
+			break
+		}
+		for _, span := range spans {
+			if root == nil || !span.ParentSpanID.Valid {
+				root = &span
+			}
+			if !span.ParentSpanID.Valid {
+				break
+			}
+		}
+		lastID = spans[len(spans)-1].ID
+	}
+	if root == nil {
+		return dagui.SpanID{}, fmt.Errorf("no spans found in trace file")
+	}
+	spanID, err := trace.SpanIDFromHex(root.SpanID)
+	if err != nil {
+		return dagui.SpanID{}, fmt.Errorf("parse span ID: %w", err)
+	}
+	return dagui.SpanID{SpanID: spanID}, nil
+}
@@ -1,35 +1,327 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"dagger.io/dagger"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
-	"go.opentelemetry.io/otel/trace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 
+	"github.com/dagger/dagger/dagql/dagui"
+	"github.com/dagger/dagger/dagql/idtui"
+	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/client"
+	"github.com/dagger/dagger/util/cleanups"
 )
 
 var watchCmd = &cobra.Command{
-	Use:    "watch",
+	Use:    "watch [options] [client-id]",
 	Hidden: true,
-	Args:   cobra.NoArgs,
+	Args:   cobra.MaximumNArgs(1),
 	Annotations: map[string]string{
 		"experimental": "true",
 	},
 	Aliases: []string{"w"},
 	Short:   "Watch activity across all Dagger sessions.",
-	Example: `dagger watch`,
-	RunE:    Watch,
+	Long: `Lists the clients connected to the engine across all sessions, and
+attaches to the selected client to show its progress.
+
+Watching other sessions requires the operator token configured in the
+engine's security.operatorToken setting, passed in DAGGER_OPERATOR_TOKEN.
+
+If a client ID is given, attaches to it directly. Without a terminal, prints
+the list of clients and exits.`,
+	Example: `dagger watch
+dagger watch mcbzxj5ltaybb2y2stcftzepj`,
+	RunE: Watch,
+}
+
+//go:embed watch.graphql
+var loadActiveClientsQuery string
+
+func Watch(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	params := client.Params{
+		RunnerHost:    RunnerHost,
+		Command:       spanName(os.Args),
+		OperatorToken: os.Getenv("DAGGER_OPERATOR_TOKEN"),
+	}
+	if useCloudEngine {
+		params.RunnerHost = engine.DefaultCloudRunnerHost
+	}
+	sess, err := client.Connect(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	var target string
+	if len(args) > 0 {
+		target = args[0]
+	} else if !hasTTY {
+		clients, err := loadActiveClients(ctx, sess)
+		if err != nil {
+			return err
+		}
+		return printActiveClients(cmd, clients)
+	} else {
+		target, err = pickActiveClient(ctx, sess)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			// quit without picking a client
+			return nil
+		}
+	}
+
+	watchOpts := opts
+	// keep showing the client's progress after it goes away
+	watchOpts.NoExit = true
+	return Frontend.Run(ctx, watchOpts, func(ctx context.Context) (cleanups.CleanupF, error) {
+		return nil, sess.WatchClient(ctx, target,
+			Frontend.SpanExporter(),
+			Frontend.LogExporter(),
+			[]sdkmetric.Exporter{Frontend.MetricExporter()})
+	})
+}
+
+type activeClient struct {
+	ClientID            string
+	SessionID           string
+	Main                bool
+	Hostname            string
+	Module              string
+	Command             string
+	StartedTimeUnixNano int64
+	Status              string
 }
 
-func Watch(cmd *cobra.Command, _ []string) error {
-	// HACK: the PubSub service treats the 000000000 trace ID as "subscribe to
-	// everything", and the client subscribes to its current trace ID, so let's
-	// just zero it out.
-	ctx := trace.ContextWithSpanContext(cmd.Context(), trace.SpanContext{})
+func (c activeClient) Started() time.Time {
+	return time.Unix(0, c.StartedTimeUnixNano)
+}
 
-	return withEngine(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
-		<-ctx.Done()
-		return ctx.Err()
+// loadActiveClients lists the clients connected to the engine, other than
+// ourselves, grouping nested clients beneath the main client of their session.
+func loadActiveClients(ctx context.Context, sess *client.Client) ([]activeClient, error) {
+	var res struct {
+		Engine struct {
+			ActiveClients []activeClient
+		}
+	}
+	err := sess.Dagger().Do(ctx, &dagger.Request{
+		Query: loadActiveClientsQuery,
+	}, &dagger.Response{
+		Data: &res,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	sessionStarts := map[string]int64{}
+	var clients []activeClient
+	for _, c := range res.Engine.ActiveClients {
+		if c.SessionID == sess.SessionID {
+			continue
+		}
+		if start, ok := sessionStarts[c.SessionID]; !ok || c.StartedTimeUnixNano < start {
+			sessionStarts[c.SessionID] = c.StartedTimeUnixNano
+		}
+		clients = append(clients, c)
+	}
+	slices.SortStableFunc(clients, func(a, b activeClient) int {
+		if a.SessionID != b.SessionID {
+			return cmp.Or(
+				cmp.Compare(sessionStarts[a.SessionID], sessionStarts[b.SessionID]),
+				cmp.Compare(a.SessionID, b.SessionID),
+			)
+		}
+		if a.Main != b.Main {
+			if a.Main {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.StartedTimeUnixNano, b.StartedTimeUnixNano)
+	})
+	return clients, nil
+}
+
+func printActiveClients(cmd *cobra.Command, clients []activeClient) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', tabwriter.DiscardEmptyColumns)
+	fmt.Fprintf(tw, "CLIENT\tSESSION\tHOST\tMODULE\tCOMMAND\tDURATION\tSTATUS\n")
+	for _, c := range clients {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.ClientID,
+			c.SessionID,
+			c.Hostname,
+			c.Module,
+			c.Command,
+			dagui.FormatDuration(time.Since(c.Started())),
+			c.Status)
+	}
+	return tw.Flush()
+}
+
+// pickActiveClient shows a live-updating list of clients, and returns the one
+// the user selects, or "" if they quit.
+func pickActiveClient(ctx context.Context, sess *client.Client) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	model := &watchModel{
+		ctx:  ctx,
+		sess: sess,
+		table: table.New(
+			table.WithColumns(watchColumns(80)),
+			table.WithFocused(true),
+		),
+	}
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.
+		Bold(true).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true)
+	styles.Selected = styles.Selected.
+		Foreground(lipgloss.ANSIColor(termenv.ANSIBrightWhite)).
+		Background(lipgloss.ANSIColor(termenv.ANSIBlue))
+	model.table.SetStyles(styles)
+
+	final, err := tea.NewProgram(model,
+		tea.WithContext(ctx),
+		tea.WithAltScreen(),
+		tea.WithInput(stdin),
+		tea.WithOutput(idtui.NewOutput(stderr).Writer()),
+	).Run()
+	if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return "", err
+	}
+	m := final.(*watchModel)
+	return m.selected, m.err
+}
+
+func watchColumns(width int) []table.Column {
+	// fixed-width columns, with the command taking up the rest
+	cols := []table.Column{
+		{Title: "CLIENT", Width: 25},
+		{Title: "HOST", Width: 16},
+		{Title: "MODULE", Width: 16},
+		{Title: "COMMAND"},
+		{Title: "DURATION", Width: 10},
+		{Title: "STATUS", Width: 10},
+	}
+	rest := width
+	for _, col := range cols {
+		// each column is padded by one space on either side
+		rest -= col.Width + 2
+	}
+	cols[3].Width = max(rest-2, 10)
+	return cols
+}
+
+const watchRefreshInterval = time.Second
+
+type watchModel struct {
+	ctx  context.Context
+	sess *client.Client
+
+	table   table.Model
+	clients []activeClient
+
+	selected string
+	err      error
+}
+
+type watchClientsMsg struct {
+	clients []activeClient
+	err     error
+}
+
+func (m *watchModel) refresh() tea.Cmd {
+	return func() tea.Msg {
+		clients, err := loadActiveClients(m.ctx, m.sess)
+		return watchClientsMsg{clients, err}
+	}
+}
+
+func (m *watchModel) Init() tea.Cmd {
+	return m.refresh()
+}
+
+func (m *watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "enter":
+			if i := m.table.Cursor(); i >= 0 && i < len(m.clients) {
+				m.selected = m.clients[i].ClientID
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+	case tea.WindowSizeMsg:
+		m.table.SetColumns(watchColumns(msg.Width))
+		m.table.SetWidth(msg.Width)
+		// leave room for the title and help
+		m.table.SetHeight(max(msg.Height-4, 1))
+		return m, nil
+	case watchClientsMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		m.clients = msg.clients
+		m.table.SetRows(m.rows())
+		return m, tea.Tick(watchRefreshInterval, func(time.Time) tea.Msg {
+			return m.refresh()()
+		})
+	}
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m *watchModel) rows() []table.Row {
+	rows := make([]table.Row, len(m.clients))
+	for i, c := range m.clients {
+		id := c.ClientID
+		if !c.Main {
+			// nested beneath the main client of its session
+			id = "↳ " + id
+		}
+		rows[i] = table.Row{
+			id,
+			c.Hostname,
+			c.Module,
+			c.Command,
+			dagui.FormatDuration(time.Since(c.Started())),
+			c.Status,
+		}
+	}
+	return rows
+}
+
+func (m *watchModel) View() string {
+	var view strings.Builder
+	fmt.Fprintf(&view, "%d clients connected to the engine\n\n", len(m.clients))
+	view.WriteString(m.table.View())
+	view.WriteString("\n")
+	view.WriteString(idtui.KeymapStyle.Render("↑/↓ select • enter attach • q quit"))
+	return view.String()
 }
@@ -278,16 +278,16 @@ func main() { //nolint:gocyclo
 			}
 		}
 
-		ctx = InitTelemetry(ctx)
-
-		bklog.G(ctx).Debug("loading buildkit config file")
-		bkcfg, err := bkconfig.LoadFile(c.GlobalString("config"))
+		// load the engine config first, since it configures telemetry
+		cfg, err := config.LoadDefault()
 		if err != nil {
 			return err
 		}
 
-		bklog.G(ctx).Debug("loading engine config file")
-		cfg, err := config.LoadDefault()
+		ctx = InitTelemetry(ctx, cfg.Telemetry)
+
+		bklog.G(ctx).Debug("loading buildkit config file")
+		bkcfg, err := bkconfig.LoadFile(c.GlobalString("config"))
 		if err != nil {
 			return err
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/dagger/dagger/engine/metrics"
 	"github.com/dagger/dagger/engine/server"
 	"github.com/dagger/dagger/engine/slog"
 )
@@ -33,6 +34,11 @@ var (
 		Name: "dagger_local_cache_corrupt_db_reset",
 		Help: "If set, the local cache database was found to be corrupt and reset",
 	})
+
+	runningServicesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "dagger_running_services",
+		Help: "Number of services currently running across all sessions",
+	})
 )
 
 // setupMetricsServer starts an HTTP server to expose Prometheus metrics
@@ -49,6 +55,13 @@ func setupMetricsServer(ctx context.Context, srv *server.Server, addr string) er
 	if err := prometheus.Register(localCacheCorruptDBResetGauge); err != nil {
 		return err
 	}
+	if err := prometheus.Register(runningServicesGauge); err != nil {
+		return err
+	}
+	// metrics updated by the engine as things happen
+	if err := metrics.Register(prometheus.DefaultRegisterer); err != nil {
+		return err
+	}
 
 	// Only update local cache metrics at most every 5 minutes to avoid excessive holding
 	// of buildkit's DiskUsage lock.
@@ -91,6 +104,7 @@ func setupMetricsServer(ctx context.Context, srv *server.Server, addr string) er
 	// Set up HTTP server
 	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
 		connectedClientsGauge.Set(float64(srv.ConnectedClients()))
+		runningServicesGauge.Set(float64(srv.RunningServices()))
 
 		var dbReset float64
 		if srv.CorruptDBReset() {
@@ -13,6 +13,8 @@ import (
 
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/config"
+	enginetel "github.com/dagger/dagger/engine/telemetry"
 )
 
 const (
@@ -37,7 +39,7 @@ func init() {
 	}
 }
 
-func InitTelemetry(ctx context.Context) context.Context {
+func InitTelemetry(ctx context.Context, cfg *config.TelemetryConfig) context.Context {
 	otelResource, err := resource.New(ctx,
 		resource.WithHost(),
 		resource.WithAttributes(
@@ -51,8 +53,14 @@ func InitTelemetry(ctx context.Context) context.Context {
 		return ctx
 	}
 
+	exporters, err := enginetel.ConfiguredExporters(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to configure telemetry exporters", "error", err)
+	}
+
 	ctx = telemetry.Init(ctx, telemetry.Config{
-		Resource: otelResource,
+		Resource:              otelResource,
+		BatchedTraceExporters: exporters,
 	})
 
 	// send engine logs to OTel. logrus is the globally used logger; bklog
@@ -198,7 +198,7 @@ func markdownTable(headers []string, rows ...[]string) string {
 	}
 	sb.WriteString("|\n")
 	for _, row := range rows {
-		sb.WriteString("|" + strings.Join(row, " | ") + " |\n")
+		sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
 	}
 	return sb.String()
 }
@@ -1793,6 +1793,28 @@ func (container *Container) Evaluate(ctx context.Context) (*buildkit.Result, err
 	})
 }
 
+// ExecLogs evaluates the container, calling emit with the output of its execs
+// as they run. Execs that have already run aren't run again, so their output
+// isn't emitted.
+func (container *Container) ExecLogs(ctx context.Context, emit func(*LogEntry) error) (rerr error) {
+	ctx, span := Tracer(ctx).Start(ctx, "stream exec logs", telemetry.Passthrough())
+	defer telemetry.EndWithCause(span, &rerr)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	var evalErr error
+	go func() {
+		defer close(done)
+		_, evalErr = container.Evaluate(ctx)
+	}()
+	if err := streamLogs(ctx, span.SpanContext().SpanID(), trace.SpanID{}, done, emit); err != nil {
+		return err
+	}
+	return evalErr
+}
+
 func (container *Container) Exists(ctx context.Context, srv *dagql.Server, targetPath string, targetType ExistsType, doNotFollowSymlinks bool) (bool, error) {
 	mnt, mntSubpath, err := locatePath(container, targetPath)
 	if err != nil {
@@ -80,3 +80,26 @@ func (*EngineCacheEntry) Type() *ast.Type {
 func (*EngineCacheEntry) TypeDescription() string {
 	return "An individual cache entry in a cache entry set"
 }
+
+type EngineClient struct {
+	ClientID            string `field:"true" name:"clientID" doc:"The ID of the client."`
+	SessionID           string `field:"true" name:"sessionID" doc:"The ID of the session the client belongs to."`
+	Main                bool   `field:"true" doc:"Whether the client started its session, as opposed to being nested within it."`
+	Hostname            string `field:"true" doc:"The hostname of the machine the client is running on."`
+	Version             string `field:"true" doc:"The version of the client."`
+	Module              string `field:"true" doc:"The name of the module the client is running, if any."`
+	Command             string `field:"true" doc:"The command the client is running, if known."`
+	StartedTimeUnixNano int    `field:"true" doc:"The time the client connected, in Unix nanoseconds."`
+	Status              string `field:"true" doc:"The status of the client: starting, running or stopping."`
+}
+
+func (*EngineClient) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EngineClient",
+		NonNull:   true,
+	}
+}
+
+func (*EngineClient) TypeDescription() string {
+	return "A client connected to the Dagger engine"
+}
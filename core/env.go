@@ -298,12 +298,14 @@ var TypesHiddenFromEnvExtensions = []dagql.Typed{
 	&InterfaceTypeDef{},
 	&ListTypeDef{},
 	&LLMTokenUsage{},
+	&MapTypeDef{},
 	&ObjectTypeDef{},
 	&ScalarTypeDef{},
 	&SDKConfig{},
 	&SourceMap{},
 	&TerminalLegacy{},
 	&TypeDef{},
+	&UnionTypeDef{},
 }
 
 func (s EnvHook) ExtendEnvType(targetType dagql.ObjectType) error {
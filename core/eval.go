@@ -0,0 +1,439 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dagger.io/dagger/telemetry"
+	doublestar "github.com/bmatcuk/doublestar/v4"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dagger/dagger/dagql"
+)
+
+// An evaluation of an agent: a prompt run against an environment, a number
+// of times for each model, with assertions on the outcome.
+type Eval struct {
+	Prompt      string `field:"true" doc:"The prompt given to the agent."`
+	Attempts    int    `field:"true" doc:"The number of attempts to run for each model."`
+	MaxAPICalls int    `field:"true" name:"maxAPICalls" doc:"The maximum number of API calls per attempt, or 0 for no limit."`
+
+	// The environment to run the agent in, if any
+	Env dagql.ObjectResult[*Env]
+	// The models to evaluate; empty for the default model
+	Models []string
+	// The checks to run on the outcome of each attempt
+	Assertions []*EvalAssertion
+}
+
+func (*Eval) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Eval",
+		NonNull:   true,
+	}
+}
+
+func (*Eval) TypeDescription() string {
+	return dagql.FormatDescription(
+		`An evaluation of an agent against a prompt and environment.`,
+		`Each model is given the prompt a number of times, and each attempt is checked against a set of assertions.`,
+	)
+}
+
+func NewEval(prompt string, attempts int) (*Eval, error) {
+	if attempts < 1 {
+		return nil, fmt.Errorf("attempts must be at least 1, got %d", attempts)
+	}
+	return &Eval{
+		Prompt:   prompt,
+		Attempts: attempts,
+	}, nil
+}
+
+func (eval *Eval) Clone() *Eval {
+	cp := *eval
+	cp.Models = slices.Clone(cp.Models)
+	cp.Assertions = slices.Clone(cp.Assertions)
+	return &cp
+}
+
+func (eval *Eval) WithEnv(env dagql.ObjectResult[*Env]) *Eval {
+	eval = eval.Clone()
+	eval.Env = env
+	return eval
+}
+
+func (eval *Eval) WithModel(model string) *Eval {
+	eval = eval.Clone()
+	if !slices.Contains(eval.Models, model) {
+		eval.Models = append(eval.Models, model)
+	}
+	return eval
+}
+
+func (eval *Eval) WithAttempts(attempts int) (*Eval, error) {
+	if attempts < 1 {
+		return nil, fmt.Errorf("attempts must be at least 1, got %d", attempts)
+	}
+	eval = eval.Clone()
+	eval.Attempts = attempts
+	return eval, nil
+}
+
+func (eval *Eval) WithMaxAPICalls(maxAPICalls int) *Eval {
+	eval = eval.Clone()
+	eval.MaxAPICalls = maxAPICalls
+	return eval
+}
+
+func (eval *Eval) WithAssertion(assertion *EvalAssertion) (*Eval, error) {
+	if assertion.Matches != "" {
+		if _, err := regexp.Compile(assertion.Matches); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	eval = eval.Clone()
+	eval.Assertions = append(eval.Assertions, assertion)
+	return eval, nil
+}
+
+type evalAssertionKind string
+
+const (
+	evalAssertReply     evalAssertionKind = "reply"
+	evalAssertOutput    evalAssertionKind = "output"
+	evalAssertChangeset evalAssertionKind = "changeset"
+)
+
+// A check on the outcome of an attempt.
+type EvalAssertion struct {
+	Kind evalAssertionKind
+
+	// For reply and output assertions: the text must contain a substring
+	// and/or match a regular expression
+	Contains string
+	Matches  string
+
+	// For output assertions: the name of the output, which must be set
+	Output string
+
+	// For changeset assertions: paths (or globs) that must have been added,
+	// modified or removed from the workspace
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+func NewEvalReplyAssertion(contains, matches string) *EvalAssertion {
+	return &EvalAssertion{
+		Kind:     evalAssertReply,
+		Contains: contains,
+		Matches:  matches,
+	}
+}
+
+func NewEvalOutputAssertion(output, contains, matches string) *EvalAssertion {
+	return &EvalAssertion{
+		Kind:     evalAssertOutput,
+		Output:   output,
+		Contains: contains,
+		Matches:  matches,
+	}
+}
+
+func NewEvalChangesetAssertion(added, modified, removed []string) *EvalAssertion {
+	return &EvalAssertion{
+		Kind:     evalAssertChangeset,
+		Added:    added,
+		Modified: modified,
+		Removed:  removed,
+	}
+}
+
+// check returns an error describing why the outcome of an attempt does not
+// satisfy the assertion.
+func (a *EvalAssertion) check(ctx context.Context, eval *Eval, llm *LLM) error {
+	switch a.Kind {
+	case evalAssertReply:
+		reply, err := llm.LastReply(ctx)
+		if err != nil {
+			return err
+		}
+		return checkEvalText("reply", reply, a.Contains, a.Matches)
+	case evalAssertOutput:
+		output, ok := llm.Env().Self().Output(a.Output)
+		if !ok || output.Value == nil {
+			return fmt.Errorf("output %q was not set", a.Output)
+		}
+		if a.Contains == "" && a.Matches == "" {
+			return nil
+		}
+		text := output.String()
+		if obj, ok := output.AsObject(); ok {
+			if file, ok := dagql.UnwrapAs[*File](obj); ok {
+				contents, err := file.Contents(ctx, nil, nil)
+				if err != nil {
+					return fmt.Errorf("read output %q: %w", a.Output, err)
+				}
+				text = string(contents)
+			}
+		}
+		return checkEvalText(fmt.Sprintf("output %q", a.Output), text, a.Contains, a.Matches)
+	case evalAssertChangeset:
+		if eval.Env.Self() == nil {
+			return errors.New("changeset assertions require an environment")
+		}
+		changes, err := NewChangeset(ctx, eval.Env.Self().Workspace, llm.Env().Self().Workspace)
+		if err != nil {
+			return err
+		}
+		return errors.Join(
+			checkEvalPaths("added", a.Added, changes.AddedPaths),
+			checkEvalPaths("modified", a.Modified, changes.ModifiedPaths),
+			checkEvalPaths("removed", a.Removed, changes.RemovedPaths),
+		)
+	default:
+		return fmt.Errorf("unknown assertion kind %q", a.Kind)
+	}
+}
+
+func checkEvalText(what, text, contains, matches string) error {
+	if contains != "" && !strings.Contains(text, contains) {
+		return fmt.Errorf("%s does not contain %q", what, contains)
+	}
+	if matches != "" {
+		re, err := regexp.Compile(matches)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(text) {
+			return fmt.Errorf("%s does not match %q", what, matches)
+		}
+	}
+	return nil
+}
+
+func checkEvalPaths(change string, patterns, paths []string) error {
+	var errs []error
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(pattern, "/")
+		if !slices.ContainsFunc(paths, func(p string) bool {
+			p = strings.TrimPrefix(p, "/")
+			if match, _ := doublestar.Match(pattern, p); match {
+				return true
+			}
+			// directories are listed with a trailing slash
+			return strings.TrimSuffix(p, "/") == strings.TrimSuffix(pattern, "/")
+		}) {
+			errs = append(errs, fmt.Errorf("%s was not %s", pattern, change))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// The outcome of an evaluation, for each model.
+type EvalReport struct {
+	Models []*EvalModelReport `field:"true" doc:"The results for each model."`
+}
+
+func (*EvalReport) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EvalReport",
+		NonNull:   true,
+	}
+}
+
+func (*EvalReport) TypeDescription() string {
+	return "The outcome of an evaluation, for each model."
+}
+
+// The outcome of an evaluation for a single model.
+type EvalModelReport struct {
+	Model       string         `field:"true" doc:"The model that was evaluated." json:"model"`
+	Attempts    int            `field:"true" doc:"The number of attempts." json:"attempts"`
+	Successes   int            `field:"true" doc:"The number of attempts that satisfied every assertion." json:"successes"`
+	SuccessRate float64        `field:"true" doc:"The fraction of attempts that succeeded, between 0 and 1." json:"success_rate"`
+	TokenUsage  *LLMTokenUsage `field:"true" doc:"The tokens used across all attempts." json:"token_usage"`
+	MeanLatency float64        `field:"true" doc:"The mean duration of an attempt, in seconds." json:"mean_latency"`
+	Failures    []string       `field:"true" doc:"Why each failed attempt failed." json:"failures"`
+
+	totalLatency time.Duration
+}
+
+func (*EvalModelReport) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EvalModelReport",
+		NonNull:   true,
+	}
+}
+
+func (*EvalModelReport) TypeDescription() string {
+	return "The outcome of an evaluation for a single model."
+}
+
+// Table renders the report as a markdown table.
+func (report *EvalReport) Table() string {
+	headers := []string{"model", "success rate", "input tokens", "output tokens", "mean latency"}
+	rows := [][]string{}
+	for _, m := range report.Models {
+		rows = append(rows, []string{
+			m.Model,
+			fmt.Sprintf("%d/%d (%.0f%%)", m.Successes, m.Attempts, m.SuccessRate*100),
+			strconv.FormatInt(m.TokenUsage.InputTokens, 10),
+			strconv.FormatInt(m.TokenUsage.OutputTokens, 10),
+			(time.Duration(m.MeanLatency * float64(time.Second))).Round(time.Millisecond).String(),
+		})
+	}
+	return markdownTable(headers, rows...)
+}
+
+func (report *EvalReport) JSON() (JSON, error) {
+	return json.Marshal(report.Models)
+}
+
+// evalMaxConcurrency is how many attempts an evaluation runs at once, to
+// avoid flooding the model providers with requests.
+const evalMaxConcurrency = 4
+
+// Run every attempt for every model, and report the outcomes.
+func (eval *Eval) Run(ctx context.Context, srv *dagql.Server) (*EvalReport, error) {
+	models := eval.Models
+	if len(models) == 0 {
+		// the default model
+		models = []string{""}
+	}
+
+	report := &EvalReport{}
+	var mu sync.Mutex
+	eg := new(errgroup.Group)
+	eg.SetLimit(evalMaxConcurrency)
+	// the failure of each attempt of each model, indexed by attempt, so the
+	// report lists them in order no matter when they finished
+	failures := make([][]string, len(models))
+	for i, model := range models {
+		modelReport := &EvalModelReport{
+			Model:      model,
+			Attempts:   eval.Attempts,
+			TokenUsage: &LLMTokenUsage{},
+			Failures:   []string{},
+		}
+		report.Models = append(report.Models, modelReport)
+		failures[i] = make([]string, eval.Attempts)
+		for attempt := 1; attempt <= eval.Attempts; attempt++ {
+			eg.Go(func() error {
+				resolved, usage, latency, err := eval.runAttempt(ctx, srv, model, attempt)
+				mu.Lock()
+				defer mu.Unlock()
+				if resolved != "" {
+					modelReport.Model = resolved
+				}
+				modelReport.TokenUsage.add(usage)
+				modelReport.totalLatency += latency
+				if err != nil {
+					failures[i][attempt-1] = fmt.Sprintf("attempt %d: %s", attempt, strings.ReplaceAll(err.Error(), "\n", "; "))
+				} else {
+					modelReport.Successes++
+				}
+				return nil
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	for i, m := range report.Models {
+		for _, failure := range failures[i] {
+			if failure != "" {
+				m.Failures = append(m.Failures, failure)
+			}
+		}
+		m.SuccessRate = float64(m.Successes) / float64(m.Attempts)
+		m.MeanLatency = (m.totalLatency / time.Duration(m.Attempts)).Seconds()
+	}
+	return report, nil
+}
+
+// runAttempt runs a single attempt, returning the resolved model name, the
+// tokens used, and how long it took. The error describes why the attempt
+// failed, if it did.
+func (eval *Eval) runAttempt(ctx context.Context, srv *dagql.Server, model string, attempt int) (resolved string, usage LLMTokenUsage, latency time.Duration, rerr error) {
+	name := model
+	if name == "" {
+		name = "default model"
+	}
+	ctx, span := Tracer(ctx).Start(ctx, fmt.Sprintf("eval %s: attempt %d", name, attempt),
+		telemetry.Reveal(),
+		trace.WithAttributes(
+			attribute.Bool(telemetry.UIRollUpSpansAttr, true),
+		))
+	defer telemetry.EndWithCause(span, &rerr)
+
+	llmArgs := []dagql.NamedInput{}
+	if model != "" {
+		llmArgs = append(llmArgs, dagql.NamedInput{Name: "model", Value: dagql.String(model)})
+	}
+	if eval.MaxAPICalls > 0 {
+		llmArgs = append(llmArgs, dagql.NamedInput{Name: "maxAPICalls", Value: dagql.Int(eval.MaxAPICalls)})
+	}
+	sels := []dagql.Selector{{
+		View:  srv.View,
+		Field: "llm",
+		Args:  llmArgs,
+	}}
+	if eval.Env.Self() != nil {
+		sels = append(sels, dagql.Selector{
+			View:  srv.View,
+			Field: "withEnv",
+			Args: []dagql.NamedInput{
+				{Name: "env", Value: dagql.NewID[*Env](eval.Env.ID())},
+			},
+		})
+	}
+	sels = append(sels, dagql.Selector{
+		View:  srv.View,
+		Field: "withPrompt",
+		Args: []dagql.NamedInput{
+			{Name: "prompt", Value: dagql.String(eval.Prompt)},
+		},
+	}, dagql.Selector{
+		// each attempt is a separate branch of the conversation
+		View:  srv.View,
+		Field: "attempt",
+		Args: []dagql.NamedInput{
+			{Name: "number", Value: dagql.Int(attempt)},
+		},
+	})
+	var inst dagql.ObjectResult[*LLM]
+	if err := srv.Select(ctx, srv.Root(), &inst, sels...); err != nil {
+		return "", usage, 0, err
+	}
+	llm := inst.Self()
+
+	start := time.Now()
+	syncErr := llm.Sync(ctx)
+	latency = time.Since(start)
+	usage = llm.tokenUsage()
+	if ep, err := llm.Endpoint(ctx); err == nil {
+		resolved = ep.Model
+	}
+	if syncErr != nil {
+		return resolved, usage, latency, syncErr
+	}
+
+	var errs []error
+	for _, assertion := range eval.Assertions {
+		errs = append(errs, assertion.check(ctx, eval, llm))
+	}
+	return resolved, usage, latency, errors.Join(errs...)
+}
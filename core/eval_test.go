@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEvalText(t *testing.T) {
+	require.NoError(t, checkEvalText("reply", "the answer is 42", "", ""))
+	require.NoError(t, checkEvalText("reply", "the answer is 42", "answer", `\d+$`))
+	require.EqualError(t, checkEvalText("reply", "the answer is 42", "question", ""),
+		`reply does not contain "question"`)
+	require.EqualError(t, checkEvalText("reply", "the answer is 42", "", `^\d+$`),
+		`reply does not match "^\\d+$"`)
+}
+
+func TestCheckEvalPaths(t *testing.T) {
+	paths := []string{"main.go", "pkg/", "pkg/util.go"}
+	require.NoError(t, checkEvalPaths("added", []string{"main.go", "/pkg", "pkg/*.go"}, paths))
+	require.EqualError(t, checkEvalPaths("added", []string{"main.go", "README.md", "cmd/**"}, paths),
+		"README.md was not added\ncmd/** was not added")
+}
+
+func TestEvalReportTable(t *testing.T) {
+	report := &EvalReport{
+		Models: []*EvalModelReport{{
+			Model:       "gpt-4.1",
+			Attempts:    4,
+			Successes:   3,
+			SuccessRate: 0.75,
+			TokenUsage:  &LLMTokenUsage{InputTokens: 1200, OutputTokens: 300},
+			MeanLatency: 1.5,
+		}},
+	}
+	require.Equal(t, "| model | success rate | input tokens | output tokens | mean latency |\n"+
+		"| -- | -- | -- | -- | -- |\n"+
+		"| gpt-4.1 | 3/4 (75%) | 1200 | 300 | 1.5s |\n", report.Table())
+}
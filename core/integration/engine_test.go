@@ -712,6 +712,37 @@ func (EngineSuite) TestPrometheusMetrics(ctx context.Context, t *testctx.T) {
 		return err
 	})
 
+	// run a known workload against the engine: prune the cache, then pull an
+	// image and run an exec in it, twice so the second run hits the cache
+	workload := []string{"core", "container",
+		"from", "--address", alpineImage,
+		"with-exec", "--args", "true",
+		"sync",
+	}
+	_, err := clientCtr.
+		WithEnvVariable("CACHEBUST", rand.Text()).
+		With(daggerNonNestedExec("core", "engine", "local-cache", "prune")).
+		With(daggerNonNestedExec(workload...)).
+		With(daggerNonNestedExec(workload...)).
+		Sync(ctx)
+	require.NoError(t, err)
+
+	// each check is given the sum of all series of a metric whose name and
+	// labels start with the given prefix
+	checks := map[string]func(float64) bool{
+		"dagger_connected_clients":                 isEqual(1),
+		"dagger_local_cache_total_disk_size_bytes": isPositive,
+		"dagger_local_cache_entries":               isPositive,
+		`dagger_operations_total{cached="false"`:   isPositive,
+		`dagger_operations_total{cached="true"`:    isPositive,
+		"dagger_operation_duration_seconds_count":  isPositive,
+		"dagger_registry_pull_bytes_total":         isPositive,
+		`dagger_gc_runs_total{trigger="manual"}`:   isPositive,
+		"dagger_session_duration_seconds_count":    isPositive,
+		"dagger_running_execs":                     isEqual(0),
+		"dagger_running_services":                  isEqual(0),
+	}
+
 	var foundAll bool
 	for range 30 {
 		out, err := clientCtr.
@@ -725,60 +756,37 @@ func (EngineSuite) TestPrometheusMetrics(ctx context.Context, t *testctx.T) {
 			continue
 		}
 
-		// find the lines with metrics we care about testing
-		soughtMetrics := map[string]struct{}{
-			"dagger_connected_clients":                 {},
-			"dagger_local_cache_total_disk_size_bytes": {},
-			"dagger_local_cache_entries":               {},
-		}
-		foundMetrics := map[string]int{}
+		// sum the values of the series we care about testing
+		values := map[string]float64{}
 		for _, line := range strings.Split(out, "\n") {
 			line = strings.TrimSpace(line)
-
-			for metricName := range soughtMetrics {
-				numStr, found := strings.CutPrefix(line, metricName+" ")
-				if !found {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			series, numStr, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			for prefix := range checks {
+				if !matchesSeries(series, prefix) {
 					continue
 				}
-				num, err := strconv.Atoi(numStr)
+				num, err := strconv.ParseFloat(numStr, 64)
 				require.NoError(t, err)
-
-				delete(soughtMetrics, metricName)
-				foundMetrics[metricName] = num
+				values[prefix] += num
 			}
-
-			if len(soughtMetrics) == 0 {
-				break
-			}
-		}
-
-		if len(soughtMetrics) != 0 {
-			t.Logf("did not find all sought metrics in output: %v", soughtMetrics)
-			time.Sleep(1 * time.Second)
-			continue
 		}
 
-		// found everything, but validate values
 		validatedAll := true
-		for metricName, num := range foundMetrics {
-			switch metricName {
-			case "dagger_connected_clients":
-				if num != 1 {
-					t.Logf("expected dagger_connected_clients = 1, got %d", num)
-					validatedAll = false
-				}
-			case "dagger_local_cache_total_disk_size_bytes":
-				if num <= 0 {
-					t.Logf("expected dagger_local_cache_total_disk_size_bytes > 0, got %d", num)
-					validatedAll = false
-				}
-			case "dagger_local_cache_entries":
-				if num <= 0 {
-					t.Logf("expected dagger_local_cache_entries >= 0, got %d", num)
-					validatedAll = false
-				}
-			default:
-				t.Fatalf("unexpected metric %q found in output", metricName)
+		for prefix, check := range checks {
+			num, found := values[prefix]
+			switch {
+			case !found:
+				t.Logf("did not find %s in output", prefix)
+				validatedAll = false
+			case !check(num):
+				t.Logf("unexpected value for %s: %v", prefix, num)
+				validatedAll = false
 			}
 		}
 
@@ -796,6 +804,25 @@ func (EngineSuite) TestPrometheusMetrics(ctx context.Context, t *testctx.T) {
 	require.NoError(t, eg.Wait(), "error from client exec")
 }
 
+// matchesSeries reports whether a Prometheus series, e.g. foo{a="b",c="d"},
+// is of the metric named by prefix, and has the labels it starts with, if any.
+func matchesSeries(series, prefix string) bool {
+	if !strings.Contains(prefix, "{") {
+		return series == prefix || strings.HasPrefix(series, prefix+"{")
+	}
+	return strings.HasPrefix(series, strings.TrimSuffix(prefix, "}"))
+}
+
+func isPositive(num float64) bool {
+	return num > 0
+}
+
+func isEqual(expected float64) func(float64) bool {
+	return func(num float64) bool {
+		return num == expected
+	}
+}
+
 func (EngineSuite) TestClientMetadataReuse(ctx context.Context, t *testctx.T) {
 	c1 := connect(ctx, t)
 	c2 := connect(ctx, t)
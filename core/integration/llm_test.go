@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -361,6 +362,112 @@ func (LLMSuite) TestAllowLLM(ctx context.Context, t *testctx.T) {
 	})
 }
 
+func (LLMSuite) TestSubagents(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// the parent's recording ends with its call to Delegate; stepping once runs
+	// the sub-agent and records the tool result in the parent's history
+	delegate := func(t *testctx.T, subagentModel string, outputType any) llmHistoryMessage {
+		parentModel := replayModel(t,
+			map[string]any{"role": "user", "content": "what is the answer?"},
+			map[string]any{"role": "assistant", "content": "", "tool_calls": []any{
+				replayToolCall("Delegate", map[string]any{
+					"task":         "find the answer",
+					"systemPrompt": nil,
+					"inputs":       []string{},
+					"outputType":   outputType,
+				}),
+			}},
+		)
+		js, err := c.LLM(dagger.LLMOpts{Model: parentModel}).
+			WithSubagents(dagger.LLMWithSubagentsOpts{Model: subagentModel}).
+			WithPrompt("what is the answer?").
+			Step(ctx)
+		require.NoError(t, err)
+		history, err := js.HistoryJSON(ctx)
+		require.NoError(t, err)
+		var messages []llmHistoryMessage
+		require.NoError(t, json.Unmarshal([]byte(history), &messages))
+		require.Len(t, messages, 3)
+		return messages[2]
+	}
+
+	t.Run("final reply", func(ctx context.Context, t *testctx.T) {
+		result := delegate(t, replayModel(t,
+			map[string]any{"role": "user", "content": "find the answer"},
+			map[string]any{"role": "assistant", "content": "the answer is 42"},
+		), nil)
+		require.False(t, result.ToolErrored)
+		require.Equal(t, "the answer is 42", result.Content)
+	})
+
+	t.Run("typed output", func(ctx context.Context, t *testctx.T) {
+		result := delegate(t, replayModel(t,
+			map[string]any{"role": "user", "content": "find the answer"},
+			map[string]any{"role": "assistant", "content": "", "tool_calls": []any{
+				replayToolCall("Save", map[string]any{
+					"name":  "result",
+					"value": "42",
+				}),
+			}},
+		), "String")
+		require.False(t, result.ToolErrored)
+		require.Equal(t, "42", result.Content)
+	})
+
+	t.Run("sub-agents cannot delegate", func(ctx context.Context, t *testctx.T) {
+		result := delegate(t, replayModel(t,
+			map[string]any{"role": "user", "content": "find the answer"},
+			map[string]any{"role": "assistant", "content": "", "tool_calls": []any{
+				replayToolCall("Delegate", map[string]any{
+					"task":         "find the answer for me",
+					"systemPrompt": nil,
+					"inputs":       []string{},
+					"outputType":   nil,
+				}),
+			}},
+			map[string]any{"role": "user", "content": `tool "Delegate" is not available`},
+			map[string]any{"role": "assistant", "content": "I could not delegate"},
+		), nil)
+		require.False(t, result.ToolErrored)
+		require.Equal(t, "I could not delegate", result.Content)
+	})
+
+	t.Run("sub-agent error", func(ctx context.Context, t *testctx.T) {
+		result := delegate(t, replayModel(t,
+			map[string]any{"role": "user", "content": "a different task"},
+			map[string]any{"role": "assistant", "content": "the answer is 42"},
+		), nil)
+		require.True(t, result.ToolErrored)
+		require.Contains(t, result.Content, "sub-agent failed")
+		require.Contains(t, result.Content, "message history diverges")
+	})
+}
+
+type llmHistoryMessage struct {
+	Role        string `json:"role"`
+	Content     string `json:"content"`
+	ToolErrored bool   `json:"tool_errored"`
+}
+
+// replayModel returns a model that replays the given message history.
+func replayModel(t *testctx.T, messages ...map[string]any) string {
+	replayData, err := json.Marshal(messages)
+	require.NoError(t, err)
+	return "replay/" + base64.StdEncoding.EncodeToString(replayData)
+}
+
+func replayToolCall(name string, args map[string]any) map[string]any {
+	return map[string]any{
+		"id":   "call_" + strings.ToLower(name),
+		"type": "function",
+		"function": map[string]any{
+			"name":      name,
+			"arguments": args,
+		},
+	}
+}
+
 func testGoProgram(ctx context.Context, t *testctx.T, c *dagger.Client, program *dagger.File, re any) {
 	name, err := program.Name(ctx)
 	require.NoError(t, err)
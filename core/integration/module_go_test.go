@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -1886,3 +1887,135 @@ func (p *Playground) SayHello() string {
 	require.NoError(t, err)
 	require.JSONEq(t, `{"playground":{"sayHello":"hello!", "directory":{"entries": []}}}`, out)
 }
+
+func (GoSuite) TestPaginated(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	modGen := c.Container().From(golangImage).
+		WithMountedFile(testCLIBinPath, daggerCliFile(t, c)).
+		WithWorkdir("/work").
+		With(daggerExec("init", "--source=.", "--name=test", "--sdk=go")).
+		WithNewFile("main.go", `package main
+
+type Test struct{}
+
+// +paginated
+func (m *Test) Items() []string {
+	return []string{"a", "b", "c"}
+}
+
+func (m *Test) Plain() []string {
+	return []string{"a", "b", "c"}
+}
+`,
+		)
+
+	out, err := modGen.With(daggerQuery(`{test{itemsConnection(first: 2){nodes hasNextPage totalCount}}}`)).Stdout(ctx)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"test":{"itemsConnection":{"nodes":["a","b"],"hasNextPage":true,"totalCount":3}}}`, out)
+
+	// functions are only paginated when they opt in
+	_, err = modGen.With(daggerQuery(`{test{plainConnection{nodes}}}`)).Stdout(ctx)
+	requireErrOut(t, err, "plainConnection")
+
+	// only lists can be paginated
+	_, err = modGen.
+		WithNewFile("main.go", `package main
+
+type Test struct{}
+
+// +paginated
+func (m *Test) Item() string {
+	return "a"
+}
+`,
+		).
+		With(daggerQuery(`{test{item}}`)).
+		Sync(ctx)
+	requireErrOut(t, err, "must return a list of non-list elements to be paginated")
+}
+
+func (GoSuite) TestMock(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	modGen := c.Container().From(golangImage).
+		WithMountedFile(testCLIBinPath, daggerCliFile(t, c)).
+		WithWorkdir("/work").
+		With(daggerExec("init", "--source=.", "--name=test", "--sdk=go"))
+
+	daggerJSON, err := modGen.File("dagger.json").Contents(ctx)
+	require.NoError(t, err)
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal([]byte(daggerJSON), &cfg))
+	cfg["sdk"] = map[string]any{
+		"source": "go",
+		"config": map[string]any{"mock": true},
+	}
+	mockJSON, err := json.Marshal(cfg)
+	require.NoError(t, err)
+
+	modGen = modGen.
+		WithNewFile("dagger.json", string(mockJSON)).
+		WithNewFile("main.go", `package main
+
+import "context"
+
+type Test struct{}
+
+func (m *Test) Hello(ctx context.Context) (string, error) {
+	return dag.Container().From("alpine").WithExec([]string{"echo", "hello"}).Stdout(ctx)
+}
+`).
+		WithNewFile("main_test.go", `package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"dagger/test/internal/dagger"
+)
+
+const helloChain = `+"`"+`container.from(address: "alpine").withExec(args: ["echo","hello"]).stdout`+"`"+`
+
+func TestHello(t *testing.T) {
+	mock := dagger.NewMock().Stub(helloChain, "hello\n")
+
+	out, err := (&Test{}).Hello(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "hello\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if calls := mock.Calls(); !slices.Equal(calls, []string{helloChain}) {
+		t.Fatalf("unexpected calls %q", calls)
+	}
+}
+
+func TestHelloError(t *testing.T) {
+	boom := errors.New("no alpine today")
+	dagger.NewMock().StubError(helloChain, boom)
+
+	_, err := (&Test{}).Hello(context.Background())
+	if !errors.Is(err, boom) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+`).
+		With(daggerExec("develop"))
+
+	// the module still works against the engine
+	out, err := modGen.With(daggerQuery(`{test{hello}}`)).Stdout(ctx)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"test":{"hello":"hello\n"}}`, out)
+
+	// and its unit tests run against the mock, without an engine
+	out, err = modGen.
+		WithExec([]string{"go", "test", "-v", "./..."}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Contains(t, out, "--- PASS: TestHello ")
+	require.Contains(t, out, "--- PASS: TestHelloError ")
+}
@@ -1261,6 +1261,69 @@ ctr=$(container)
 	})
 }
 
+func (ShellSuite) TestSaveRestore(ctx context.Context, t *testctx.T) {
+	src := `package main
+
+type Test struct{}
+
+func (m *Test) Foo(bar string) *Foo {
+	return &Foo{Bar: bar}
+}
+
+type Foo struct{
+	Bar string
+}
+`
+
+	t.Run("restore in new session", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		out, err := modInit(t, c, "go", src).
+			With(daggerShell(`
+msg=hello
+dir=$(directory | with-new-file msg "$msg")
+foo=$(foo world)
+.save work
+`)).
+			With(daggerShell(`
+.restore work
+.echo $msg
+$dir | file msg | contents
+$foo | bar
+`)).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello\nhelloworld", out)
+	})
+
+	t.Run("restore working directory", func(ctx context.Context, t *testctx.T) {
+		foo := `package main
+
+type Foo struct{}
+
+func (m *Foo) Bar() string {
+	return "foobar"
+}
+`
+		c := connect(ctx, t)
+		out, err := modInit(t, c, "go", src).
+			With(daggerExec("init", "--sdk=go", "--source=foo", "foo")).
+			With(sdkSourceAt("foo", "go", foo)).
+			With(daggerShell(".cd foo; .save work")).
+			With(daggerShell(".restore work; bar")).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "foobar", out)
+	})
+
+	t.Run("not found", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		_, err := daggerCliBase(t, c).
+			With(daggerShell(".restore nope")).
+			Sync(ctx)
+		requireErrOut(t, err, `session "nope" not found`)
+	})
+}
+
 func (ShellSuite) TestNamedArguments(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
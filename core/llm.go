@@ -81,6 +81,16 @@ type LLM struct {
 
 	// Whether to disable the default system prompt
 	disableDefaultSystemPrompt bool
+
+	// Automatic compaction of the message history, if any
+	contextPolicy *LLMContextPolicy
+	// Messages before this index report usage from before the last compaction
+	staleUsage int
+	// Usage of messages that were compacted away
+	compactedUsage LLMTokenUsage
+
+	// Messages added to the history while looping, for streaming
+	loopLog *llmLoopLog
 }
 
 type LLMEndpoint struct {
@@ -120,6 +130,14 @@ func (*LLMTokenUsage) Type() *ast.Type {
 	}
 }
 
+func (usage *LLMTokenUsage) add(other LLMTokenUsage) {
+	usage.InputTokens += other.InputTokens
+	usage.OutputTokens += other.OutputTokens
+	usage.CachedTokenReads += other.CachedTokenReads
+	usage.CachedTokenWrites += other.CachedTokenWrites
+	usage.TotalTokens += other.TotalTokens
+}
+
 // ModelMessage represents a generic message in the LLM conversation
 type ModelMessage struct {
 	Role        string        `json:"role"`
@@ -141,6 +159,43 @@ type FuncCall struct {
 	Arguments map[string]any `json:"arguments"`
 }
 
+// LLMEvent is a message added to an LLM's history while it runs.
+type LLMEvent struct {
+	Role        string `field:"true" doc:"The role of the message's author: user, assistant, or system."`
+	Content     string `field:"true" doc:"The text content of the message."`
+	ToolCalls   JSON   `field:"true" doc:"The tools called by the assistant, as a JSON array."`
+	ToolCallID  string `field:"true" doc:"The ID of the tool call that this message is the result of, if any."`
+	ToolErrored bool   `field:"true" doc:"Whether the tool call that this message is the result of failed."`
+}
+
+func (*LLMEvent) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "LLMEvent",
+		NonNull:   true,
+	}
+}
+
+func (*LLMEvent) TypeDescription() string {
+	return "A message added to an LLM's history while it runs."
+}
+
+func newLLMEvent(msg *ModelMessage) (*LLMEvent, error) {
+	toolCalls, err := json.Marshal(msg.ToolCalls)
+	if err != nil {
+		return nil, err
+	}
+	if msg.ToolCalls == nil {
+		toolCalls = []byte("[]")
+	}
+	return &LLMEvent{
+		Role:        msg.Role,
+		Content:     msg.Content,
+		ToolCalls:   JSON(toolCalls),
+		ToolCallID:  msg.ToolCallID,
+		ToolErrored: msg.ToolErrored,
+	}, nil
+}
+
 const (
 	OpenAI    LLMProvider = "openai"
 	Anthropic LLMProvider = "anthropic"
@@ -456,13 +511,19 @@ func (q *Query) NewLLM(ctx context.Context, model string, maxAPICalls int) (*LLM
 	}); err != nil {
 		return nil, err
 	}
+	return newLLM(model, maxAPICalls, newMCP(env)), nil
+}
+
+func newLLM(model string, maxAPICalls int, mcp *MCP) *LLM {
+	mcp.model = model
 	return &LLM{
 		model:       model,
 		maxAPICalls: maxAPICalls,
-		mcp:         newMCP(env),
+		mcp:         mcp,
 		once:        &sync.Once{},
 		endpointMtx: &sync.Mutex{},
-	}, nil
+		loopLog:     newLLMLoopLog(),
+	}
 }
 
 func (llm *LLM) WithStaticTools() *LLM {
@@ -500,6 +561,7 @@ func (llm *LLM) Clone() *LLM {
 	cp.endpointMtx = &sync.Mutex{}
 	cp.once = &sync.Once{}
 	cp.err = nil
+	cp.loopLog = newLLMLoopLog()
 	return &cp
 }
 
@@ -552,6 +614,7 @@ func (llm *LLM) ToolsDoc(ctx context.Context) (string, error) {
 func (llm *LLM) WithModel(model string) *LLM {
 	llm = llm.Clone()
 	llm.model = model
+	llm.mcp.model = model
 
 	llm.endpointMtx.Lock()
 	defer llm.endpointMtx.Unlock()
@@ -595,6 +658,7 @@ func (llm *LLM) WithoutMessageHistory() *LLM {
 	llm.messages = slices.DeleteFunc(llm.messages, func(msg *ModelMessage) bool {
 		return msg.Role != "system"
 	})
+	llm.staleUsage = 0
 	return llm
 }
 
@@ -750,13 +814,113 @@ func (llm *LLM) Interject(ctx context.Context) error {
 		return errors.New("no interjection provided; giving up")
 	}
 	fmt.Fprint(stdio.Stdout, msg)
-	llm.messages = append(llm.messages, &ModelMessage{
+	llm.appendMessages(&ModelMessage{
 		Role:    "user",
 		Content: msg,
 	})
 	return nil
 }
 
+// appendMessages adds messages to the history while looping.
+func (llm *LLM) appendMessages(msgs ...*ModelMessage) {
+	llm.messages = append(llm.messages, msgs...)
+	llm.loopLog.append(msgs...)
+}
+
+// Events calls loop to evaluate the LLM, calling emit with each message added
+// to the history along the way.
+//
+// The loop is expected to return the LLM that was evaluated, which may be a
+// different (but equivalent) instance if its evaluation was cached, in which
+// case its messages are emitted once it returns.
+func (llm *LLM) Events(ctx context.Context, loop func(context.Context) (*LLM, error), emit func(*LLMEvent) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	type result struct {
+		llm *LLM
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := loop(ctx)
+		done <- result{res, err}
+	}()
+
+	var emitted int
+	emitFrom := func(log *llmLoopLog) (<-chan struct{}, error) {
+		msgs, changed := log.since(emitted)
+		for _, msg := range msgs {
+			event, err := newLLMEvent(msg)
+			if err == nil {
+				err = emit(event)
+			}
+			if err != nil {
+				// stop looping; nobody is listening anymore
+				cancel(err)
+				return nil, err
+			}
+			emitted++
+		}
+		return changed, nil
+	}
+	for {
+		changed, err := emitFrom(llm.loopLog)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case res := <-done:
+			if res.err != nil {
+				return res.err
+			}
+			// emit anything we haven't seen yet, either because it was added
+			// since we last looked, or because the loop ran on another instance
+			if res.llm != nil && res.llm.loopLog != llm.loopLog {
+				emitted = 0
+				llm = res.llm
+			}
+			if _, err := emitFrom(llm.loopLog); err != nil {
+				return err
+			}
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// llmLoopLog records the messages added to an LLM's history while it loops,
+// so they can be streamed to any number of subscribers as they arrive.
+type llmLoopLog struct {
+	mu       sync.Mutex
+	messages []*ModelMessage
+	// closed and replaced whenever messages are added
+	changed chan struct{}
+}
+
+func newLLMLoopLog() *llmLoopLog {
+	return &llmLoopLog{changed: make(chan struct{})}
+}
+
+func (log *llmLoopLog) append(msgs ...*ModelMessage) {
+	if log == nil {
+		return
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.messages = append(log.messages, msgs...)
+	close(log.changed)
+	log.changed = make(chan struct{})
+}
+
+// since returns the messages added after the first n, along with a channel
+// that is closed when more are added.
+func (log *llmLoopLog) since(n int) ([]*ModelMessage, <-chan struct{}) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return slices.Clone(log.messages[min(n, len(log.messages)):]), log.changed
+}
+
 func mdQuote(msg string) string {
 	lines := strings.Split(msg, "\n")
 	for i, line := range lines {
@@ -820,6 +984,16 @@ func (llm *LLM) loop(ctx context.Context) error {
 			return err
 		}
 
+		ep, err := llm.Endpoint(ctx)
+		if err != nil {
+			return err
+		}
+		client := ep.Client
+
+		if err := llm.compactContext(ctx, client, tools); err != nil {
+			return fmt.Errorf("compact context: %w", err)
+		}
+
 		messagesToSend := llm.messagesWithSystemPrompt()
 
 		var newMessages []*ModelMessage
@@ -859,11 +1033,6 @@ func (llm *LLM) loop(ctx context.Context) error {
 		var res *LLMResponse
 
 		// Retry operation
-		ep, err := llm.Endpoint(ctx)
-		if err != nil {
-			return err
-		}
-		client := ep.Client
 		err = backoff.Retry(func() error {
 			var sendErr error
 			ctx, span := Tracer(ctx).Start(ctx, "LLM query", telemetry.Reveal(), trace.WithAttributes(
@@ -911,7 +1080,7 @@ func (llm *LLM) loop(ctx context.Context) error {
 		}
 
 		// Add the model reply to the history
-		llm.messages = append(llm.messages, &ModelMessage{
+		llm.appendMessages(&ModelMessage{
 			Role:       "assistant",
 			Content:    res.Content,
 			ToolCalls:  res.ToolCalls,
@@ -932,7 +1101,7 @@ func (llm *LLM) loop(ctx context.Context) error {
 		}
 
 		// Run tool calls in batch with efficient MCP syncing
-		llm.messages = append(llm.messages, llm.mcp.CallBatch(ctx, tools, res.ToolCalls)...)
+		llm.appendMessages(llm.mcp.CallBatch(ctx, tools, res.ToolCalls)...)
 
 		if llm.mcp.Returned() {
 			// we returned; exit the loop, since some models just keep going
@@ -1103,13 +1272,19 @@ func (llm *LLM) TokenUsage(ctx context.Context, dag *dagql.Server) (*LLMTokenUsa
 	if err := llm.Sync(ctx); err != nil {
 		return nil, err
 	}
-	var res LLMTokenUsage
+	res := llm.tokenUsage()
+	return &res, nil
+}
+
+// tokenUsage sums the usage of each message, along with any sub-agents and
+// compacted messages.
+func (llm *LLM) tokenUsage() LLMTokenUsage {
+	res := llm.compactedUsage
 	for _, msg := range llm.messages {
-		res.InputTokens += msg.TokenUsage.InputTokens
-		res.OutputTokens += msg.TokenUsage.OutputTokens
-		res.CachedTokenReads += msg.TokenUsage.CachedTokenReads
-		res.CachedTokenWrites += msg.TokenUsage.CachedTokenWrites
-		res.TotalTokens += msg.TokenUsage.TotalTokens
+		res.add(msg.TokenUsage)
 	}
-	return &res, nil
+	llm.mcp.mu.Lock()
+	res.add(llm.mcp.subagentUsage)
+	llm.mcp.mu.Unlock()
+	return res
 }
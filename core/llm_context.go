@@ -0,0 +1,252 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/core/prompts"
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type LLMContextStrategy string
+
+var LLMContextStrategies = dagql.NewEnum[LLMContextStrategy]()
+
+var (
+	LLMContextStrategySummarize = LLMContextStrategies.Register("SUMMARIZE",
+		"Replace older messages with a summary written by the model")
+	LLMContextStrategyTruncateToolOutputs = LLMContextStrategies.Register("TRUNCATE_TOOL_OUTPUTS",
+		"Truncate the outputs of older tool calls")
+)
+
+func (LLMContextStrategy) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "LLMContextStrategy",
+		NonNull:   true,
+	}
+}
+
+func (LLMContextStrategy) TypeDescription() string {
+	return "Strategy for compacting an LLM's context once it exceeds its token budget."
+}
+
+func (LLMContextStrategy) Decoder() dagql.InputDecoder {
+	return LLMContextStrategies
+}
+
+func (strategy LLMContextStrategy) ToLiteral() call.Literal {
+	return LLMContextStrategies.Literal(strategy)
+}
+
+func (strategy LLMContextStrategy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(strategy))
+}
+
+func (strategy *LLMContextStrategy) UnmarshalJSON(payload []byte) error {
+	var str string
+	if err := json.Unmarshal(payload, &str); err != nil {
+		return err
+	}
+	*strategy = LLMContextStrategy(str)
+	return nil
+}
+
+// LLMContextPolicy limits the size of the context sent to the model.
+type LLMContextPolicy struct {
+	MaxTokens int
+	Strategy  LLMContextStrategy
+}
+
+const (
+	// A rough estimate, used for messages the model has not yet seen
+	llmCharsPerToken = 4
+
+	// How much of an older tool output to keep when truncating it
+	llmTruncatedToolOutputChars = 500
+)
+
+// Compact the message history automatically whenever it grows beyond
+// maxTokens.
+func (llm *LLM) WithContextPolicy(maxTokens int, strategy LLMContextStrategy) (*LLM, error) {
+	if maxTokens <= 0 {
+		return nil, fmt.Errorf("maxTokens must be positive, got %d", maxTokens)
+	}
+	llm = llm.Clone()
+	llm.contextPolicy = &LLMContextPolicy{
+		MaxTokens: maxTokens,
+		Strategy:  strategy,
+	}
+	return llm, nil
+}
+
+// estimateContextTokens estimates the size of the given messages. The usage
+// reported with the most recent reply is exact, so only messages after it are
+// estimated, unless the history was rewritten since (at or after stale).
+func estimateContextTokens(messages []*ModelMessage, stale int) int64 {
+	var tokens int64
+	start := 0
+	for i := len(messages) - 1; i >= stale && i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role == "assistant" && msg.TokenUsage.InputTokens > 0 {
+			usage := msg.TokenUsage
+			tokens = usage.InputTokens + usage.CachedTokenReads + usage.CachedTokenWrites + usage.OutputTokens
+			start = i + 1
+			break
+		}
+	}
+	for _, msg := range messages[start:] {
+		tokens += estimateMessageTokens(msg)
+	}
+	return tokens
+}
+
+func estimateMessageTokens(msg *ModelMessage) int64 {
+	size := len(msg.Content)
+	for _, call := range msg.ToolCalls {
+		size += len(call.Function.Name)
+		if args, err := json.Marshal(call.Function.Arguments); err == nil {
+			size += len(args)
+		}
+	}
+	return int64(size/llmCharsPerToken + 1)
+}
+
+// compactContext applies the context policy, if the history has outgrown it.
+func (llm *LLM) compactContext(ctx context.Context, client LLMClient, tools []LLMTool) error {
+	policy := llm.contextPolicy
+	if policy == nil {
+		return nil
+	}
+	estimate := estimateContextTokens(llm.messages, llm.staleUsage)
+	if estimate <= int64(policy.MaxTokens) {
+		return nil
+	}
+
+	ctx, span := Tracer(ctx).Start(ctx, "compact LLM context",
+		telemetry.Reveal(),
+		trace.WithAttributes(
+			attribute.String(telemetry.UIActorEmojiAttr, "🗜️"),
+		))
+	var err error
+	defer telemetry.EndWithCause(span, &err)
+
+	switch policy.Strategy {
+	case LLMContextStrategyTruncateToolOutputs:
+		llm.messages = truncateToolOutputs(llm.messages, int64(policy.MaxTokens))
+	case LLMContextStrategySummarize, "":
+		err = llm.summarizeContext(ctx, client, tools, int64(policy.MaxTokens))
+	default:
+		err = fmt.Errorf("unknown context strategy %q", policy.Strategy)
+	}
+	if err != nil {
+		return err
+	}
+	// the usage reported so far no longer reflects the size of the context
+	llm.staleUsage = len(llm.messages)
+	return nil
+}
+
+// truncateToolOutputs truncates tool outputs, oldest first, until the
+// messages fit within maxTokens. Results of the most recent tool calls are
+// kept intact, since the model has not seen them yet.
+func truncateToolOutputs(messages []*ModelMessage, maxTokens int64) []*ModelMessage {
+	messages = slices.Clone(messages)
+	latest := len(messages)
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "assistant" {
+			latest = i
+			break
+		}
+	}
+	// the usage of earlier replies is invalidated as soon as we change anything
+	stale := len(messages)
+	for i, msg := range messages[:latest] {
+		if estimateContextTokens(messages, stale) <= maxTokens {
+			break
+		}
+		if msg.ToolCallID == "" || len(msg.Content) <= llmTruncatedToolOutputChars {
+			continue
+		}
+		cp := *msg
+		cp.Content = fmt.Sprintf("%s\n\n[truncated %d characters to save space]",
+			msg.Content[:llmTruncatedToolOutputChars],
+			len(msg.Content)-llmTruncatedToolOutputChars)
+		messages[i] = &cp
+	}
+	return messages
+}
+
+// summaryCutoff returns the index of the first message to keep verbatim
+// when summarizing, such that the kept messages take up at most budget
+// tokens. Tool results always stay with the call that requested them, so
+// the cutoff never lands on one.
+func summaryCutoff(messages []*ModelMessage, budget int64) int {
+	cutoff := len(messages)
+	var kept int64
+	for i := len(messages) - 1; i >= 0; i-- {
+		kept += estimateMessageTokens(messages[i])
+		if kept > budget {
+			break
+		}
+		if messages[i].ToolCallID == "" && messages[i].Role != "system" {
+			cutoff = i
+		}
+	}
+	return cutoff
+}
+
+// summarizeContext replaces older messages with a summary written by the
+// model, keeping recent messages and explicit system prompts verbatim.
+func (llm *LLM) summarizeContext(ctx context.Context, client LLMClient, tools []LLMTool, maxTokens int64) error {
+	// leave half of the budget for the conversation to continue
+	cutoff := summaryCutoff(llm.messages, maxTokens/2)
+
+	var systemPrompts, older []*ModelMessage
+	for _, msg := range llm.messages[:cutoff] {
+		if msg.Role == "system" {
+			systemPrompts = append(systemPrompts, msg)
+		} else {
+			older = append(older, msg)
+		}
+	}
+	if len(older) == 0 {
+		return fmt.Errorf("cannot fit the most recent messages within %d tokens", maxTokens)
+	}
+
+	instructions, err := prompts.FS.ReadFile("compact.md")
+	if err != nil {
+		// this should be caught at dev time
+		panic(err)
+	}
+	// tools are sent along so that providers accept the tool calls in the
+	// history, but the prompt asks the model not to call them
+	res, err := client.SendQuery(ctx, append(older, &ModelMessage{
+		Role:    "user",
+		Content: string(instructions),
+	}), tools)
+	if err != nil {
+		return fmt.Errorf("summarize context: %w", err)
+	}
+
+	// keep track of the tokens spent on the summarized messages
+	for _, msg := range older {
+		llm.compactedUsage.add(msg.TokenUsage)
+	}
+	llm.compactedUsage.add(res.TokenUsage)
+
+	messages := slices.Clone(systemPrompts)
+	messages = append(messages, &ModelMessage{
+		Role:    "user",
+		Content: "This session is being continued from an earlier conversation, which is summarized below.\n\n" + res.Content,
+	})
+	messages = append(messages, llm.messages[cutoff:]...)
+	llm.messages = messages
+	return nil
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"dagger.io/dagger/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dagger/dagger/dagql"
+)
+
+// The name of the output that a sub-agent saves its result to
+const subagentResultOutput = "result"
+
+// Allow the LLM to delegate tasks to sub-agents, each running with its own
+// environment and system prompt. If model is empty, sub-agents use the LLM's
+// model at the time of delegation.
+func (llm *LLM) WithSubagents(model string) *LLM {
+	llm = llm.Clone()
+	llm.mcp.subagents = true
+	llm.mcp.subagentModel = model
+	return llm
+}
+
+func (m *MCP) delegateTool(srv *dagql.Server) LLMTool {
+	return LLMTool{
+		Name: "Delegate",
+		Description: `Delegate a focused task to a sub-agent, and return its result.
+
+The sub-agent does not see this conversation, and can only access the objects passed to it as inputs, so describe the task completely.`,
+		// Sub-agents run in their own environment, so they may run in parallel.
+		ReadOnly: true,
+		Schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"task": map[string]any{
+					"type":        "string",
+					"description": "The task for the sub-agent to perform.",
+				},
+				"systemPrompt": map[string]any{
+					"type":        []string{"string", "null"},
+					"description": "An optional system prompt focusing the sub-agent on its role.",
+				},
+				"inputs": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "IDs of the objects to give the sub-agent, e.g. Directory#1.",
+				},
+				"outputType": map[string]any{
+					"type":        []string{"string", "null"},
+					"description": "The type of result the sub-agent must return, e.g. Directory or String. If null, its final reply is returned.",
+				},
+			},
+			"required":             []string{"task", "systemPrompt", "inputs", "outputType"},
+			"additionalProperties": false,
+		},
+		Strict: true,
+		Call: ToolFunc(srv, func(ctx context.Context, args struct {
+			Task         string
+			SystemPrompt string   `default:""`
+			Inputs       []string `default:"[]"`
+			OutputType   string   `default:""`
+		}) (any, error) {
+			sub, err := m.newSubagent(ctx, srv, args.Inputs, args.OutputType)
+			if err != nil {
+				return nil, err
+			}
+			if args.SystemPrompt != "" {
+				sub = sub.WithSystemPrompt(args.SystemPrompt)
+			}
+			sub = sub.WithPrompt(args.Task)
+
+			ctx, span := Tracer(ctx).Start(ctx, "delegate to sub-agent",
+				telemetry.Reveal(),
+				trace.WithAttributes(
+					attribute.String(telemetry.UIActorEmojiAttr, "🤖"),
+				))
+			defer span.End()
+
+			syncErr := sub.Sync(ctx)
+			// roll up usage regardless of the outcome; the tokens were spent
+			usage := sub.tokenUsage()
+			m.mu.Lock()
+			m.subagentUsage.add(usage)
+			m.mu.Unlock()
+			if syncErr != nil {
+				return nil, fmt.Errorf("sub-agent failed: %w", syncErr)
+			}
+
+			if args.OutputType == "" {
+				return sub.LastReply(ctx)
+			}
+			output, ok := sub.mcp.env.Self().Output(subagentResultOutput)
+			if !ok || output.Value == nil {
+				return nil, fmt.Errorf("sub-agent did not save a %s result", args.OutputType)
+			}
+			return m.outputToLLM(ctx, srv, output.Value)
+		}),
+	}
+}
+
+// newSubagent creates an LLM with a fresh, unprivileged environment sharing
+// the workspace, containing only the given inputs and an optional output.
+func (m *MCP) newSubagent(ctx context.Context, srv *dagql.Server, inputs []string, outputType string) (*LLM, error) {
+	var env dagql.ObjectResult[*Env]
+	if err := srv.Select(ctx, srv.Root(), &env, dagql.Selector{
+		View:  srv.View,
+		Field: "env",
+	}, dagql.Selector{
+		View:  srv.View,
+		Field: "withWorkspace",
+		Args: []dagql.NamedInput{
+			{
+				Name:  "workspace",
+				Value: dagql.NewID[*Directory](m.env.Self().Workspace.ID()),
+			},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create sub-agent environment: %w", err)
+	}
+
+	for _, id := range inputs {
+		bnd, found, err := m.Input(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown object %q", id)
+		}
+		obj, ok := bnd.AsObject()
+		if !ok {
+			return nil, fmt.Errorf("input %q is not an object", id)
+		}
+		desc := bnd.Description
+		if desc == "" {
+			desc = id
+		}
+		if err := srv.Select(ctx, env, &env, dagql.Selector{
+			View:  srv.View,
+			Field: "with" + obj.Type().Name() + "Input",
+			Args: []dagql.NamedInput{
+				{
+					Name:  "name",
+					Value: dagql.String(subagentInputName(id)),
+				},
+				{
+					Name:  "value",
+					Value: dagql.NewDynamicID[dagql.Typed](obj.ID(), obj),
+				},
+				{
+					Name:  "description",
+					Value: dagql.String(desc),
+				},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to pass %q to sub-agent: %w", id, err)
+		}
+	}
+
+	if outputType != "" {
+		if err := srv.Select(ctx, env, &env, dagql.Selector{
+			View:  srv.View,
+			Field: "with" + outputType + "Output",
+			Args: []dagql.NamedInput{
+				{
+					Name:  "name",
+					Value: dagql.String(subagentResultOutput),
+				},
+				{
+					Name:  "description",
+					Value: dagql.String("The result of the task."),
+				},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("unsupported output type %q: %w", outputType, err)
+		}
+	}
+
+	return newLLM(m.subagentLLMModel(), 0, m.subagentMCP(env)), nil
+}
+
+// subagentLLMModel returns the model sub-agents use: the configured one, or
+// else the LLM's current model.
+func (m *MCP) subagentLLMModel() string {
+	return cmp.Or(m.subagentModel, m.model)
+}
+
+// subagentMCP returns the tools of a sub-agent running in the given
+// environment: the same API, except that it may not delegate further.
+func (m *MCP) subagentMCP(env dagql.ObjectResult[*Env]) *MCP {
+	mcp := newMCP(env)
+	mcp.staticTools = m.staticTools
+	mcp.blockedMethods = maps.Clone(m.blockedMethods)
+	for typeName, methods := range mcp.blockedMethods {
+		mcp.blockedMethods[typeName] = slices.Clone(methods)
+	}
+	return mcp
+}
+
+// subagentInputName converts an object ID like Directory#1 into a binding
+// name like directory_1.
+func subagentInputName(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "#", "_"))
+}
@@ -2,6 +2,9 @@ package core
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -203,3 +206,153 @@ GEMINI_MODEL=gemini-model`, nil
 	assert.Equal(t, "gemini-base-url", r.GeminiBaseURL)
 	assert.Equal(t, "gemini-model", r.GeminiModel)
 }
+
+func TestLlmTokenUsageIncludesSubagents(t *testing.T) {
+	llm := &LLM{
+		mcp: &MCP{
+			mu: &sync.Mutex{},
+			subagentUsage: LLMTokenUsage{
+				InputTokens:  100,
+				OutputTokens: 10,
+				TotalTokens:  110,
+			},
+		},
+		messages: []*ModelMessage{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", Content: "hello", TokenUsage: LLMTokenUsage{
+				InputTokens:      5,
+				OutputTokens:     2,
+				CachedTokenReads: 3,
+				TotalTokens:      7,
+			}},
+		},
+	}
+	assert.Equal(t, LLMTokenUsage{
+		InputTokens:      105,
+		OutputTokens:     12,
+		CachedTokenReads: 3,
+		TotalTokens:      117,
+	}, llm.tokenUsage())
+}
+
+func TestLlmSubagentModel(t *testing.T) {
+	llm := newLLM("parent-model", 0, newMCP(dagql.ObjectResult[*Env]{}))
+
+	t.Run("default", func(t *testing.T) {
+		withSubagents := llm.WithSubagents("")
+		assert.Equal(t, "parent-model", withSubagents.mcp.subagentLLMModel())
+		// follows the parent's model when it changes later
+		assert.Equal(t, "newer-model", withSubagents.WithModel("newer-model").mcp.subagentLLMModel())
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		withSubagents := llm.WithSubagents("subagent-model")
+		assert.Equal(t, "subagent-model", withSubagents.mcp.subagentLLMModel())
+		assert.Equal(t, "subagent-model", withSubagents.WithModel("newer-model").mcp.subagentLLMModel())
+	})
+}
+
+func TestLlmSubagentMCP(t *testing.T) {
+	parent := newMCP(dagql.ObjectResult[*Env]{})
+	parent.staticTools = true
+	parent.subagents = true
+	parent.subagentModel = "subagent-model"
+	parent.blockedMethods["Container"] = append(parent.blockedMethods["Container"], "terminal")
+	parent.objsByID["Container#1"] = nil
+
+	sub := parent.subagentMCP(dagql.ObjectResult[*Env]{})
+	assert.True(t, sub.staticTools)
+	// sub-agents may not delegate further
+	assert.False(t, sub.subagents)
+	// nor see the parent's objects unless they're passed as inputs
+	assert.Empty(t, sub.objsByID)
+	assert.Equal(t, parent.blockedMethods, sub.blockedMethods)
+
+	// blocking methods in the sub-agent doesn't affect the parent
+	sub.blockedMethods["Container"] = append(sub.blockedMethods["Container"], "withExec")
+	assert.NotContains(t, parent.blockedMethods["Container"], "withExec")
+}
+
+func TestLlmContextCompaction(t *testing.T) {
+	bigOutput := strings.Repeat("x", 4000)
+	messages := []*ModelMessage{
+		{Role: "system", Content: "be helpful"},
+		{Role: "user", Content: "list the files"},
+		{Role: "assistant", ToolCalls: []LLMToolCall{{ID: "call1"}}},
+		{Role: "user", ToolCallID: "call1", Content: bigOutput},
+		{Role: "assistant", ToolCalls: []LLMToolCall{{ID: "call2"}}},
+		{Role: "user", ToolCallID: "call2", Content: bigOutput},
+	}
+
+	t.Run("estimate", func(t *testing.T) {
+		withUsage := slices.Clone(messages)
+		withUsage[4] = &ModelMessage{
+			Role:       "assistant",
+			ToolCalls:  []LLMToolCall{{ID: "call2"}},
+			TokenUsage: LLMTokenUsage{InputTokens: 2000, OutputTokens: 10},
+		}
+		// reported usage, plus an estimate for the latest tool output
+		assert.Equal(t, int64(2000+10+1001), estimateContextTokens(withUsage, 0))
+		// once stale, everything is estimated
+		assert.Equal(t, int64(3+4+2+1001+2+1001), estimateContextTokens(withUsage, len(withUsage)))
+	})
+
+	t.Run("truncate tool outputs", func(t *testing.T) {
+		truncated := truncateToolOutputs(messages, 1500)
+		assert.Len(t, truncated, len(messages))
+		assert.Contains(t, truncated[3].Content, "[truncated 3500 characters")
+		// the latest output has not been seen by the model yet
+		assert.Equal(t, bigOutput, truncated[5].Content)
+		// the original history is left alone
+		assert.Equal(t, bigOutput, messages[3].Content)
+	})
+
+	t.Run("summary cutoff", func(t *testing.T) {
+		// never separate a tool result from its call
+		assert.Equal(t, 4, summaryCutoff(messages, 1500))
+		// nothing fits; summarize everything
+		assert.Equal(t, len(messages), summaryCutoff(messages, 500))
+		// everything fits; only the system prompt is skipped
+		assert.Equal(t, 1, summaryCutoff(messages, 5000))
+	})
+}
+
+func TestLlmEvents(t *testing.T) {
+	contents := func(events []*LLMEvent) []string {
+		var out []string
+		for _, event := range events {
+			out = append(out, event.Content)
+		}
+		return out
+	}
+
+	t.Run("streams the loop as it runs", func(t *testing.T) {
+		llm := &LLM{loopLog: newLLMLoopLog()}
+		var events []*LLMEvent
+		err := llm.Events(t.Context(), func(ctx context.Context) (*LLM, error) {
+			llm.appendMessages(&ModelMessage{Role: "assistant", Content: "one"})
+			llm.appendMessages(&ModelMessage{Role: "assistant", Content: "two"})
+			return llm, nil
+		}, func(event *LLMEvent) error {
+			events = append(events, event)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, contents(events))
+	})
+
+	t.Run("replays a cached loop", func(t *testing.T) {
+		llm := &LLM{loopLog: newLLMLoopLog()}
+		cached := &LLM{loopLog: newLLMLoopLog()}
+		cached.appendMessages(&ModelMessage{Role: "assistant", Content: "cached"})
+		var events []*LLMEvent
+		err := llm.Events(t.Context(), func(ctx context.Context) (*LLM, error) {
+			return cached, nil
+		}, func(event *LLMEvent) error {
+			events = append(events, event)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cached"}, contents(events))
+	})
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/trace"
+	otlpcommonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/clientdb"
+	"github.com/dagger/dagger/engine/slog"
+)
+
+// LogEntry is a chunk of output written by a process.
+type LogEntry struct {
+	Stream LogStream `field:"true" doc:"The stream that the output was written to."`
+	Data   string    `field:"true" doc:"The output, which may include partial lines."`
+}
+
+func (*LogEntry) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "LogEntry",
+		NonNull:   true,
+	}
+}
+
+func (*LogEntry) TypeDescription() string {
+	return "A chunk of output written by a process."
+}
+
+// LogStream is a GraphQL enum type.
+type LogStream string
+
+var LogStreams = dagql.NewEnum[LogStream]()
+
+var (
+	LogStreamStdout = LogStreams.Register("STDOUT")
+	LogStreamStderr = LogStreams.Register("STDERR")
+)
+
+func (stream LogStream) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "LogStream",
+		NonNull:   true,
+	}
+}
+
+func (stream LogStream) TypeDescription() string {
+	return "The standard stream that a process wrote output to."
+}
+
+func (stream LogStream) Decoder() dagql.InputDecoder {
+	return LogStreams
+}
+
+func (stream LogStream) ToLiteral() call.Literal {
+	return LogStreams.Literal(stream)
+}
+
+const (
+	// How often to check for new logs while streaming them.
+	logsPollInterval = 100 * time.Millisecond
+
+	// How long to keep waiting for streams to end once the work that wrote to
+	// them is done, since logs are recorded asynchronously.
+	logsSettleTimeout = 5 * time.Second
+
+	logsBatchSize = 1000
+)
+
+// streamLogs calls emit with each chunk of output logged by processes beneath
+// the parent span, until done is closed and every stream that was written to
+// has ended. If only is valid, output logged to any other span is skipped.
+func streamLogs(ctx context.Context, parent, only trace.SpanID, done <-chan struct{}, emit func(*LogEntry) error) error {
+	root, err := CurrentQuery(ctx)
+	if err != nil {
+		return err
+	}
+	mainMeta, err := root.MainClientCallerMetadata(ctx)
+	if err != nil {
+		return fmt.Errorf("get main client caller metadata: %w", err)
+	}
+	q, err := root.ClientTelemetry(ctx, mainMeta.SessionID, mainMeta.ClientID)
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+
+	type streamKey struct {
+		spanID string
+		stream LogStream
+	}
+	// streams that have been written to but haven't ended yet
+	open := map[streamKey]bool{}
+
+	var lastLogID int64
+	var settled <-chan time.Time
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			logs, err := q.SelectLogsBeneathSpan(ctx, clientdb.SelectLogsBeneathSpanParams{
+				ID:     lastLogID,
+				SpanID: sql.NullString{Valid: true, String: parent.String()},
+				Limit:  logsBatchSize,
+			})
+			if err != nil {
+				return err
+			}
+			for _, log := range logs {
+				lastLogID = log.ID
+				if only.IsValid() && log.SpanID.String != only.String() {
+					continue
+				}
+				entry, eof, ok := parseLogEntry(log)
+				if !ok {
+					continue
+				}
+				key := streamKey{log.SpanID.String, entry.Stream}
+				if eof {
+					delete(open, key)
+					continue
+				}
+				open[key] = true
+				if err := emit(entry); err != nil {
+					return err
+				}
+			}
+			if len(logs) < logsBatchSize {
+				break
+			}
+		}
+		if settled != nil && len(open) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-done:
+			done = nil
+			settled = time.After(logsSettleTimeout)
+		case <-settled:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseLogEntry converts a log written to a process's stdout or stderr into a
+// LogEntry, reporting whether it marks the end of the stream. Any other logs
+// are skipped.
+func parseLogEntry(log clientdb.Log) (_ *LogEntry, eof bool, ok bool) {
+	var attrs []*otlpcommonv1.KeyValue
+	if err := clientdb.UnmarshalProtoJSONs(log.Attributes, &otlpcommonv1.KeyValue{}, &attrs); err != nil {
+		slog.Warn("failed to unmarshal log attributes", "error", err)
+		return nil, false, false
+	}
+	entry := &LogEntry{}
+	for _, attr := range attrs {
+		switch attr.Key {
+		case telemetry.StdioStreamAttr:
+			switch attr.Value.GetIntValue() {
+			case 1:
+				entry.Stream = LogStreamStdout
+			case 2:
+				entry.Stream = LogStreamStderr
+			}
+		case telemetry.StdioEOFAttr:
+			eof = attr.Value.GetBoolValue()
+		case telemetry.LogsVerboseAttr, telemetry.LogsGlobalAttr:
+			if attr.Value.GetBoolValue() {
+				return nil, false, false
+			}
+		}
+	}
+	if entry.Stream == "" {
+		return nil, false, false
+	}
+	if eof {
+		return entry, true, true
+	}
+
+	var body otlpcommonv1.AnyValue
+	if err := proto.Unmarshal(log.Body, &body); err != nil {
+		slog.Warn("failed to unmarshal log body", "error", err, "log", log.ID)
+		return nil, false, false
+	}
+	switch x := body.GetValue().(type) {
+	case *otlpcommonv1.AnyValue_StringValue:
+		entry.Data = x.StringValue
+	case *otlpcommonv1.AnyValue_BytesValue:
+		entry.Data = string(x.BytesValue)
+	default:
+		return nil, false, false
+	}
+	return entry, false, true
+}
@@ -99,6 +99,14 @@ type MCP struct {
 	mcpServers map[string]*MCPServerConfig
 	// Persistent MCP sessions.
 	mcpSessions map[string]*mcp.ClientSession
+	// Whether the model may delegate tasks to sub-agents
+	subagents bool
+	// The model of the LLM using these tools
+	model string
+	// The model used by sub-agents; defaults to the LLM's model
+	subagentModel string
+	// Token usage of sub-agents, rolled up into the LLM's token usage
+	subagentUsage LLMTokenUsage
 	// Synchronize any concurrent tool call results.
 	mu *sync.Mutex
 }
@@ -572,6 +580,10 @@ func (m *MCP) typeTools(allTools *LLMToolSet, srv *dagql.Server, schema *ast.Sch
 			// don't expose deprecated APIs
 			continue
 		}
+		if field.Directives.ForName(streamingDirectiveName) != nil {
+			// can only be selected in a subscription
+			continue
+		}
 		if references(field, TypesHiddenFromEnvExtensions...) {
 			// references a banned type
 			continue
@@ -1642,6 +1654,10 @@ func (m *MCP) loadBuiltins(srv *dagql.Server, allTools, objectMethods *LLMToolSe
 		allTools.Add(m.saveTool(srv))
 	}
 
+	if m.subagents {
+		allTools.Add(m.delegateTool(srv))
+	}
+
 	if len(m.env.Self().inputsByName) > 0 {
 		allTools.Add(LLMTool{
 			Name:        "UserProvidedValues",
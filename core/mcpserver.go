@@ -2,18 +2,30 @@ package core
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	stdlog "log"
+	"mime"
+	"net/http"
+	"path"
 	"slices"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/engine/session/pipe"
 	"github.com/dagger/dagger/internal/buildkit/util/bklog"
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/vektah/gqlparser/v2/ast"
+	"golang.org/x/net/http2"
 )
 
 // mcpDefaultAny lets us skip the typed defaults
@@ -107,11 +119,26 @@ func genMcpToolOpts(tool LLMTool) ([]mcp.ToolOption, error) {
 	return toolOpts, nil
 }
 
+// MCPTransport is the transport used to serve an MCP server to the client.
+type MCPTransport string
+
+const (
+	// Serve a single MCP client over the client's standard input/output.
+	MCPTransportStdio MCPTransport = "stdio"
+	// Serve any number of MCP clients using the streamable HTTP transport,
+	// multiplexed over HTTP/2 on a pipe to the client.
+	MCPTransportHTTP MCPTransport = "http"
+)
+
 type mcpServer struct {
 	*mcpserver.MCPServer
 	dag  *dagql.Server
 	env  *MCP
 	pipe io.ReadWriteCloser
+
+	transport MCPTransport
+	// Bearer token required by HTTP clients, if any
+	authToken string
 }
 
 func (s mcpServer) genMcpToolHandler(tool LLMTool) mcpserver.ToolHandlerFunc {
@@ -140,6 +167,13 @@ func (s mcpServer) genMcpToolHandler(tool LLMTool) mcpserver.ToolHandlerFunc {
 		if err := s.setTools(ctx); err != nil {
 			return nil, err
 		}
+		// tool calls may have rebound inputs, so refresh resources and prompts too
+		if err := s.setResources(ctx); err != nil {
+			return nil, err
+		}
+		if err := s.setPrompts(ctx); err != nil {
+			return nil, err
+		}
 
 		return mcp.NewToolResultText(text), nil
 	}
@@ -175,6 +209,232 @@ func (s mcpServer) setTools(ctx context.Context) error {
 	return nil
 }
 
+// mcpInputResourcePrefix is the URI prefix under which Env inputs are
+// published as MCP resources, e.g. dagger://inputs/source/README.md
+const mcpInputResourcePrefix = "dagger://inputs/"
+
+func (s mcpServer) sortedInputs() []*Binding {
+	inputs := s.env.env.Self().Inputs()
+	slices.SortFunc(inputs, func(a, b *Binding) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return inputs
+}
+
+func (s mcpServer) setResources(ctx context.Context) error {
+	var resources []mcpserver.ServerResource
+	var templates []mcpserver.ServerResourceTemplate
+	for _, bnd := range s.sortedInputs() {
+		obj, ok := bnd.AsObject()
+		if !ok {
+			continue
+		}
+		switch obj.Type().Name() {
+		case "File":
+		case "Directory", "Container":
+			// allow browsing into the filesystem
+			templates = append(templates, mcpserver.ServerResourceTemplate{
+				Template: mcp.NewResourceTemplate(
+					mcpInputResourcePrefix+bnd.Key+"/{+path}",
+					bnd.Key+" files",
+					mcp.WithTemplateDescription(fmt.Sprintf("Files and directories within the %s input %q.", obj.Type().Name(), bnd.Key)),
+				),
+				Handler: mcpserver.ResourceTemplateHandlerFunc(s.genMcpResourceHandler(bnd, obj)),
+			})
+		default:
+			continue
+		}
+		resources = append(resources, mcpserver.ServerResource{
+			Resource: mcp.NewResource(
+				mcpInputResourcePrefix+bnd.Key,
+				bnd.Key,
+				mcp.WithResourceDescription(bnd.Description),
+			),
+			Handler: s.genMcpResourceHandler(bnd, obj),
+		})
+	}
+	s.SetResources(resources...)
+	s.SetResourceTemplates(templates...)
+	return nil
+}
+
+func (s mcpServer) genMcpResourceHandler(bnd *Binding, obj dagql.AnyObjectResult) mcpserver.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		uri := request.Params.URI
+		subpath, ok := strings.CutPrefix(uri, mcpInputResourcePrefix+bnd.Key)
+		if !ok {
+			return nil, fmt.Errorf("[dagger] resource %q does not belong to input %q", uri, bnd.Key)
+		}
+		return s.readResource(ctx, uri, obj, strings.TrimPrefix(subpath, "/"))
+	}
+}
+
+// readResource reads a File, or lists or reads a path within a Directory or
+// Container's root filesystem.
+func (s mcpServer) readResource(ctx context.Context, uri string, obj dagql.AnyObjectResult, subpath string) ([]mcp.ResourceContents, error) {
+	var dir *Directory
+	switch x := obj.Unwrap().(type) {
+	case *File:
+		if subpath != "" {
+			return nil, fmt.Errorf("%s: not a directory", uri)
+		}
+		return fileResourceContents(ctx, uri, x)
+	case *Directory:
+		dir = x
+	case *Container:
+		rootfs, err := x.RootFS(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get container rootfs: %w", err)
+		}
+		dir = rootfs
+	default:
+		return nil, fmt.Errorf("%s: unsupported resource type %s", uri, obj.Type().Name())
+	}
+
+	isDir := subpath == ""
+	if !isDir {
+		var err error
+		isDir, err = dir.Exists(ctx, s.dag, subpath, ExistsTypeDirectory, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !isDir {
+		file, err := dir.File(ctx, subpath)
+		if err != nil {
+			return nil, err
+		}
+		return fileResourceContents(ctx, uri, file)
+	}
+	entries, err := dir.Entries(ctx, subpath)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "text/plain",
+			Text:     strings.Join(entries, "\n"),
+		},
+	}, nil
+}
+
+func fileResourceContents(ctx context.Context, uri string, file *File) ([]mcp.ResourceContents, error) {
+	contents, err := file.Contents(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := mime.TypeByExtension(path.Ext(file.File))
+	if !utf8.Valid(contents) {
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		return []mcp.ResourceContents{
+			mcp.BlobResourceContents{
+				URI:      uri,
+				MIMEType: mimeType,
+				Blob:     base64.StdEncoding.EncodeToString(contents),
+			},
+		}, nil
+	}
+	if mimeType == "" {
+		mimeType = "text/plain"
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: mimeType,
+			Text:     string(contents),
+		},
+	}, nil
+}
+
+func (s mcpServer) setPrompts(ctx context.Context) error {
+	srv, err := s.env.Server(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get server: %w", err)
+	}
+	schema := srv.Schema()
+	var prompts []mcpserver.ServerPrompt
+	for _, bnd := range s.sortedInputs() {
+		obj, ok := bnd.AsObject()
+		if !ok {
+			continue
+		}
+		typeDef, ok := schema.Types[obj.Type().Name()]
+		if !ok {
+			continue
+		}
+		for _, field := range typeDef.Fields {
+			if field.Directives.ForName(promptDirectiveName) == nil {
+				continue
+			}
+			prompt, ok := genMcpPrompt(bnd.Key+"_"+field.Name, field)
+			if !ok {
+				continue
+			}
+			prompts = append(prompts, mcpserver.ServerPrompt{
+				Prompt:  prompt,
+				Handler: s.genMcpPromptHandler(srv, schema, obj, field),
+			})
+		}
+	}
+	s.SetPrompts(prompts...)
+	return nil
+}
+
+// genMcpPrompt converts a prompt function into an MCP prompt. MCP prompt
+// arguments are always strings, so functions that take or return anything else
+// are skipped.
+func genMcpPrompt(name string, field *ast.FieldDefinition) (mcp.Prompt, bool) {
+	if field.Type.Elem != nil || field.Type.NamedType != "String" {
+		return mcp.Prompt{}, false
+	}
+	opts := []mcp.PromptOption{
+		mcp.WithPromptDescription(strings.TrimSpace(field.Description)),
+	}
+	for _, arg := range field.Arguments {
+		if arg.Type.Elem != nil || arg.Type.NamedType != "String" {
+			return mcp.Prompt{}, false
+		}
+		argOpts := []mcp.ArgumentOption{
+			mcp.ArgumentDescription(strings.TrimSpace(arg.Description)),
+		}
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+	return mcp.NewPrompt(name, opts...), true
+}
+
+func (s mcpServer) genMcpPromptHandler(srv *dagql.Server, schema *ast.Schema, obj dagql.AnyObjectResult, field *ast.FieldDefinition) mcpserver.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := make(map[string]any, len(request.Params.Arguments))
+		for k, v := range request.Params.Arguments {
+			args[k] = v
+		}
+		sels, err := s.env.toolCallToSelections(ctx, srv, schema, obj.ObjectType(), field, args, nil)
+		if err != nil {
+			return nil, fmt.Errorf("[dagger] failed to convert prompt arguments: %w", err)
+		}
+		var val dagql.AnyResult
+		if err := srv.Select(ctx, obj, &val, sels...); err != nil {
+			return nil, err
+		}
+		text, ok := dagql.UnwrapAs[dagql.String](val)
+		if !ok {
+			return nil, fmt.Errorf("[dagger] expected prompt %q to return a string, got %T", request.Params.Name, val)
+		}
+		return mcp.NewGetPromptResult(
+			strings.TrimSpace(field.Description),
+			[]mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String())),
+			},
+		), nil
+	}
+}
+
 func (s mcpServer) run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -182,19 +442,25 @@ func (s mcpServer) run(ctx context.Context) error {
 	if err := s.setTools(ctx); err != nil {
 		return err
 	}
+	if err := s.setResources(ctx); err != nil {
+		return err
+	}
+	if err := s.setPrompts(ctx); err != nil {
+		return err
+	}
 
 	errCh := make(chan error)
 
-	stdioSrv := mcpserver.NewStdioServer(s.MCPServer)
-
-	// MCP library requires standard log package
-	logger := stdlog.New(bklog.G(ctx).Writer(), "", 0)
-	stdioSrv.SetErrorLogger(logger)
-
 	// Start MCP server in a goroutine
 	go func() {
 		defer close(errCh)
-		err := stdioSrv.Listen(ctx, s.pipe, s.pipe)
+		var err error
+		switch s.transport {
+		case MCPTransportHTTP:
+			err = s.serveHTTP(ctx)
+		default:
+			err = s.serveStdio(ctx)
+		}
 		if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, io.EOF) {
 			select {
 			case <-ctx.Done():
@@ -211,7 +477,129 @@ func (s mcpServer) run(ctx context.Context) error {
 	}
 }
 
-func (llm *LLM) MCP(ctx context.Context, dag *dagql.Server) error {
+func (s mcpServer) serveStdio(ctx context.Context) error {
+	stdioSrv := mcpserver.NewStdioServer(s.MCPServer)
+
+	// MCP library requires standard log package
+	logger := stdlog.New(bklog.G(ctx).Writer(), "", 0)
+	stdioSrv.SetErrorLogger(logger)
+
+	return stdioSrv.Listen(ctx, s.pipe, s.pipe)
+}
+
+// serveHTTP serves the streamable HTTP transport over HTTP/2 on the client
+// pipe. The client multiplexes any number of MCP clients' requests over it,
+// all sharing this server and its environment.
+func (s mcpServer) serveHTTP(ctx context.Context) error {
+	var handler http.Handler = mcpserver.NewStreamableHTTPServer(s.MCPServer,
+		mcpserver.WithSessionIdManager(newMCPSessionIDManager()),
+	)
+	if s.authToken != "" {
+		handler = mcpBearerAuth(s.authToken, handler)
+	}
+
+	conn := pipe.NewConn(s.pipe, s.pipe, s.pipe)
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	(&http2.Server{}).ServeConn(conn, &http2.ServeConnOpts{
+		// requests need the dagql server and client metadata from the context
+		Context: ctx,
+		Handler: handler,
+	})
+	return ctx.Err()
+}
+
+// mcpBearerAuth rejects any request that does not carry the given bearer token.
+func mcpBearerAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dagger"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mcpSessionIDManager keeps track of the MCP sessions it has issued, so that
+// HTTP clients can resume a session after reconnecting by sending its ID.
+// Sessions that were never issued, have been terminated, or have been idle
+// for longer than mcpSessionIdleTimeout are reported as terminated, prompting
+// clients to initialize a new one.
+type mcpSessionIDManager struct {
+	mu sync.Mutex
+	// session ID -> when the session was last used
+	sessions map[string]time.Time
+}
+
+var _ mcpserver.SessionIdManager = (*mcpSessionIDManager)(nil)
+
+// mcpSessionIdleTimeout is how long a session may go unused before it's
+// forgotten. Clients that don't terminate their sessions would otherwise leak
+// them for the lifetime of the server.
+const mcpSessionIdleTimeout = time.Hour
+
+func newMCPSessionIDManager() *mcpSessionIDManager {
+	return &mcpSessionIDManager{
+		sessions: map[string]time.Time{},
+	}
+}
+
+func (m *mcpSessionIDManager) Generate() string {
+	id := mcpSessionIDPrefix + uuid.NewString()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.expire(now)
+	m.sessions[id] = now
+	return id
+}
+
+func (m *mcpSessionIDManager) Validate(sessionID string) (isTerminated bool, err error) {
+	if !strings.HasPrefix(sessionID, mcpSessionIDPrefix) {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.expire(now)
+	if _, known := m.sessions[sessionID]; !known {
+		return true, nil
+	}
+	m.sessions[sessionID] = now
+	return false, nil
+}
+
+func (m *mcpSessionIDManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sessionID)
+	return false, nil
+}
+
+// expire forgets sessions that have been idle for too long. The caller must
+// hold m.mu.
+func (m *mcpSessionIDManager) expire(now time.Time) {
+	for id, lastUsed := range m.sessions {
+		if now.Sub(lastUsed) > mcpSessionIdleTimeout {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+const mcpSessionIDPrefix = "dagger-mcp-"
+
+func (llm *LLM) MCP(ctx context.Context, dag *dagql.Server, transport MCPTransport, authToken dagql.ObjectResult[*Secret]) error {
+	switch transport {
+	case MCPTransportStdio, MCPTransportHTTP:
+	default:
+		return fmt.Errorf("unsupported MCP transport %q", transport)
+	}
+
 	// Get buildkit client
 	query, err := CurrentQuery(ctx)
 	if err != nil {
@@ -222,17 +610,35 @@ func (llm *LLM) MCP(ctx context.Context, dag *dagql.Server) error {
 		return fmt.Errorf("buildkit client error: %w", err)
 	}
 
+	var token string
+	if authToken.Self() != nil {
+		secretStore, err := query.Secrets(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get secret store: %w", err)
+		}
+		plaintext, err := secretStore.GetSecretPlaintext(ctx, authToken.ID().Digest())
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(plaintext))
+	}
+
 	rwc, err := bk.OpenPipe(ctx)
 	if err != nil {
 		return fmt.Errorf("open pipe error: %w", err)
 	}
 
 	s := mcpServer{
-		mcpserver.NewMCPServer("Dagger", "0.0.1",
-			mcpserver.WithInstructions(llm.mcp.DefaultSystemPrompt())),
-		dag,
-		llm.mcp,
-		rwc,
+		MCPServer: mcpserver.NewMCPServer("Dagger", "0.0.1",
+			mcpserver.WithInstructions(llm.mcp.DefaultSystemPrompt()),
+			// inputs and prompts change as tools rebind the environment
+			mcpserver.WithResourceCapabilities(false, true),
+			mcpserver.WithPromptCapabilities(true)),
+		dag:       dag,
+		env:       llm.mcp,
+		pipe:      rwc,
+		transport: transport,
+		authToken: token,
 	}
 
 	return s.run(ctx)
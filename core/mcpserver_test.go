@@ -0,0 +1,114 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestGenMcpPrompt(t *testing.T) {
+	t.Run("string args", func(t *testing.T) {
+		prompt, ok := genMcpPrompt("repo_review", &ast.FieldDefinition{
+			Name:        "review",
+			Description: "Review a change.\n",
+			Type:        ast.NonNullNamedType("String", nil),
+			Arguments: ast.ArgumentDefinitionList{
+				{Name: "focus", Description: "What to focus on.", Type: ast.NonNullNamedType("String", nil)},
+				{Name: "tone", Type: ast.NamedType("String", nil)},
+				{Name: "style", Type: ast.NonNullNamedType("String", nil), DefaultValue: &ast.Value{Raw: "terse", Kind: ast.StringValue}},
+			},
+		})
+		require.True(t, ok)
+		require.Equal(t, "repo_review", prompt.Name)
+		require.Equal(t, "Review a change.", prompt.Description)
+		require.Len(t, prompt.Arguments, 3)
+		require.Equal(t, "focus", prompt.Arguments[0].Name)
+		require.Equal(t, "What to focus on.", prompt.Arguments[0].Description)
+		require.True(t, prompt.Arguments[0].Required)
+		require.False(t, prompt.Arguments[1].Required)
+		require.False(t, prompt.Arguments[2].Required)
+	})
+
+	t.Run("non-string return", func(t *testing.T) {
+		_, ok := genMcpPrompt("repo_count", &ast.FieldDefinition{
+			Name: "count",
+			Type: ast.NonNullNamedType("Int", nil),
+		})
+		require.False(t, ok)
+	})
+
+	t.Run("non-string arg", func(t *testing.T) {
+		_, ok := genMcpPrompt("repo_review", &ast.FieldDefinition{
+			Name: "review",
+			Type: ast.NonNullNamedType("String", nil),
+			Arguments: ast.ArgumentDefinitionList{
+				{Name: "source", Type: ast.NonNullNamedType("DirectoryID", nil)},
+			},
+		})
+		require.False(t, ok)
+	})
+}
+
+func TestMCPSessionIDManager(t *testing.T) {
+	m := newMCPSessionIDManager()
+
+	id := m.Generate()
+	terminated, err := m.Validate(id)
+	require.NoError(t, err)
+	require.False(t, terminated)
+
+	// sessions the server never issued must be re-initialized
+	terminated, err = m.Validate(mcpSessionIDPrefix + "unknown")
+	require.NoError(t, err)
+	require.True(t, terminated)
+
+	_, err = m.Validate("bogus")
+	require.Error(t, err)
+
+	notAllowed, err := m.Terminate(id)
+	require.NoError(t, err)
+	require.False(t, notAllowed)
+	terminated, err = m.Validate(id)
+	require.NoError(t, err)
+	require.True(t, terminated)
+	require.NotContains(t, m.sessions, id)
+
+	// idle sessions are forgotten
+	idle := m.Generate()
+	m.sessions[idle] = time.Now().Add(-2 * mcpSessionIdleTimeout)
+	active := m.Generate()
+	require.NotContains(t, m.sessions, idle)
+	terminated, err = m.Validate(idle)
+	require.NoError(t, err)
+	require.True(t, terminated)
+	terminated, err = m.Validate(active)
+	require.NoError(t, err)
+	require.False(t, terminated)
+}
+
+func TestMCPBearerAuth(t *testing.T) {
+	h := mcpBearerAuth("s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for _, tc := range []struct {
+		header string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Basic s3cret", http.StatusUnauthorized},
+		{"Bearer s3cret", http.StatusNoContent},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, tc.status, rec.Code, "Authorization: %q", tc.header)
+	}
+}
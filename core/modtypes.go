@@ -1,7 +1,9 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/dagger/dagger/dagql"
@@ -169,6 +171,90 @@ func (t *ListType) TypeDef() *TypeDef {
 	}
 }
 
+// MapType is a map of string keys to values of a single type, represented as
+// JSON in the schema.
+type MapType struct {
+	Value      *TypeDef
+	Underlying ModType
+}
+
+var _ ModType = &MapType{}
+
+func (t *MapType) ConvertFromSDKResult(ctx context.Context, value any) (dagql.AnyResult, error) {
+	if value == nil {
+		slog.Debug("MapType.ConvertFromSDKResult: got nil value")
+		// return an empty map, _not_ nil
+		return dagql.NewResultForCurrentID(ctx, JSON("{}"))
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("MapType.ConvertFromSDKResult: expected map[string]any, got %T", value)
+	}
+	values := make(map[string]any, len(m))
+	for k, v := range m {
+		res, err := t.Underlying.ConvertFromSDKResult(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("map key %q: %w", k, err)
+		}
+		if res != nil {
+			values[k] = res.Unwrap()
+		} else {
+			values[k] = nil
+		}
+	}
+	bs, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("MapType.ConvertFromSDKResult: %w", err)
+	}
+	return dagql.NewResultForCurrentID(ctx, JSON(bs))
+}
+
+func (t *MapType) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	bs, ok := value.(JSON)
+	if !ok {
+		return nil, fmt.Errorf("%T.ConvertToSDKInput: expected JSON, got %T: %#v", t, value, value)
+	}
+	var m map[string]any
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%T.ConvertToSDKInput: expected a JSON object: %w", t, err)
+	}
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		input, err := t.Value.ToInput().Decoder().DecodeInput(v)
+		if err != nil {
+			return nil, fmt.Errorf("map key %q: %w", k, err)
+		}
+		result[k], err = t.Underlying.ConvertToSDKInput(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("map key %q: %w", k, err)
+		}
+	}
+	return result, nil
+}
+
+func (t *MapType) CollectCoreIDs(context.Context, dagql.AnyResult, map[digest.Digest]*resource.ID) error {
+	// maps can't hold objects
+	return nil
+}
+
+func (t *MapType) SourceMod() Mod {
+	return t.Underlying.SourceMod()
+}
+
+func (t *MapType) TypeDef() *TypeDef {
+	return &TypeDef{
+		Kind: TypeDefKindMap,
+		AsMap: dagql.NonNull(&MapTypeDef{
+			ValueTypeDef: t.Value.Clone(),
+		}),
+	}
+}
+
 type NullableType struct {
 	InnerDef *TypeDef
 	Inner    ModType
@@ -59,6 +59,9 @@ type Module struct {
 	// The module's enumerations
 	EnumDefs []*TypeDef `field:"true" name:"enums" doc:"Enumerations served by this module."`
 
+	// The module's unions
+	UnionDefs []*TypeDef `field:"true" name:"unions" doc:"Unions served by this module."`
+
 	// IsToolchain indicates this module was loaded as a toolchain dependency.
 	// Toolchain modules are allowed to share types with the modules that depend on them.
 	IsToolchain bool
@@ -454,13 +457,27 @@ func (mod *Module) Install(ctx context.Context, dag *dagql.Server) error {
 		enum.Install(dag)
 	}
 
+	for _, def := range mod.UnionDefs {
+		unionDef := def.AsUnion.Value
+
+		slog.ExtraDebug("installing union", "name", mod.Name(), "union", unionDef.Name, "members", len(unionDef.Members))
+
+		union := &UnionType{
+			typeDef: unionDef,
+			mod:     mod,
+		}
+		if err := union.Install(ctx, dag); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (mod *Module) TypeDefs(ctx context.Context, dag *dagql.Server) ([]*TypeDef, error) {
 	// TODO: use dag arg to reflect dynamic updates (if/when we support that)
 
-	typeDefs := make([]*TypeDef, 0, len(mod.ObjectDefs)+len(mod.InterfaceDefs)+len(mod.EnumDefs))
+	typeDefs := make([]*TypeDef, 0, len(mod.ObjectDefs)+len(mod.InterfaceDefs)+len(mod.EnumDefs)+len(mod.UnionDefs))
 
 	for _, def := range mod.ObjectDefs {
 		typeDef := def.Clone()
@@ -486,6 +503,14 @@ func (mod *Module) TypeDefs(ctx context.Context, dag *dagql.Server) ([]*TypeDef,
 		typeDefs = append(typeDefs, typeDef)
 	}
 
+	for _, def := range mod.UnionDefs {
+		typeDef := def.Clone()
+		if typeDef.AsUnion.Valid {
+			typeDef.AsUnion.Value.SourceModuleName = mod.Name()
+		}
+		typeDefs = append(typeDefs, typeDef)
+	}
+
 	return typeDefs, nil
 }
 
@@ -526,6 +551,8 @@ func (mod *Module) ModTypeFor(ctx context.Context, typeDef *TypeDef, checkDirect
 		modType, ok = mod.modTypeForPrimitive(typeDef)
 	case TypeDefKindList:
 		modType, ok, err = mod.modTypeForList(ctx, typeDef, checkDirectDeps)
+	case TypeDefKindMap:
+		modType, ok, err = mod.modTypeForMap(ctx, typeDef, checkDirectDeps)
 	case TypeDefKindObject:
 		modType, ok, err = mod.modTypeFromDeps(ctx, typeDef, checkDirectDeps)
 		if ok || err != nil {
@@ -551,6 +578,12 @@ func (mod *Module) ModTypeFor(ctx context.Context, typeDef *TypeDef, checkDirect
 			return modType, ok, err
 		}
 		modType, ok = mod.modTypeForEnum(typeDef)
+	case TypeDefKindUnion:
+		modType, ok, err = mod.modTypeFromDeps(ctx, typeDef, checkDirectDeps)
+		if ok || err != nil {
+			return modType, ok, err
+		}
+		modType, ok = mod.modTypeForUnion(typeDef)
 	default:
 		return nil, false, fmt.Errorf("unexpected type def kind %s", typeDef.Kind)
 	}
@@ -603,6 +636,21 @@ func (mod *Module) modTypeForList(ctx context.Context, typedef *TypeDef, checkDi
 	}, true, nil
 }
 
+func (mod *Module) modTypeForMap(ctx context.Context, typedef *TypeDef, checkDirectDeps bool) (ModType, bool, error) {
+	underlyingType, ok, err := mod.ModTypeFor(ctx, typedef.AsMap.Value.ValueTypeDef, checkDirectDeps)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get underlying type: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	return &MapType{
+		Value:      typedef.AsMap.Value.ValueTypeDef,
+		Underlying: underlyingType,
+	}, true, nil
+}
+
 func (mod *Module) modTypeForObject(typeDef *TypeDef) (ModType, bool) {
 	for _, obj := range mod.ObjectDefs {
 		if obj.AsObject.Value.Name == typeDef.AsObject.Value.Name {
@@ -645,11 +693,27 @@ func (mod *Module) modTypeForEnum(typeDef *TypeDef) (ModType, bool) {
 	return nil, false
 }
 
+func (mod *Module) modTypeForUnion(typeDef *TypeDef) (ModType, bool) {
+	for _, union := range mod.UnionDefs {
+		if union.AsUnion.Value.Name == typeDef.AsUnion.Value.Name {
+			return &UnionType{
+				typeDef: union.AsUnion.Value,
+				mod:     mod,
+			}, true
+		}
+	}
+
+	slog.ExtraDebug("module did not find union", "mod", mod.Name(), "union", typeDef.AsUnion.Value.Name)
+	return nil, false
+}
+
 // verify the typedef is has no reserved names
 func (mod *Module) validateTypeDef(ctx context.Context, typeDef *TypeDef) error {
 	switch typeDef.Kind {
 	case TypeDefKindList:
 		return mod.validateTypeDef(ctx, typeDef.AsList.Value.ElementTypeDef)
+	case TypeDefKindMap:
+		return checkMapValueType(typeDef.AsMap.Value.ValueTypeDef)
 	case TypeDefKindObject:
 		return mod.validateObjectTypeDef(ctx, typeDef)
 	case TypeDefKindInterface:
@@ -792,6 +856,28 @@ func (mod *Module) namespaceTypeDef(ctx context.Context, modPath string, typeDef
 		if err := mod.namespaceTypeDef(ctx, modPath, typeDef.AsList.Value.ElementTypeDef); err != nil {
 			return err
 		}
+	case TypeDefKindMap:
+		if err := mod.namespaceTypeDef(ctx, modPath, typeDef.AsMap.Value.ValueTypeDef); err != nil {
+			return err
+		}
+	case TypeDefKindUnion:
+		union := typeDef.AsUnion.Value
+
+		// only namespace unions defined in this module
+		_, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+		if err != nil {
+			return fmt.Errorf("failed to get mod type for type def: %w", err)
+		}
+		if !ok {
+			union.Name = namespaceObject(union.OriginalName, mod.Name(), mod.OriginalName)
+			union.SourceMap = mod.namespaceSourceMap(modPath, union.SourceMap)
+		}
+
+		for _, member := range union.Members {
+			if err := mod.namespaceTypeDef(ctx, modPath, member); err != nil {
+				return err
+			}
+		}
 	case TypeDefKindObject:
 		obj := typeDef.AsObject.Value
 
@@ -1065,6 +1151,11 @@ func (mod Module) Clone() *Module {
 		cp.EnumDefs[i] = def.Clone()
 	}
 
+	cp.UnionDefs = make([]*TypeDef, len(mod.UnionDefs))
+	for i, def := range mod.UnionDefs {
+		cp.UnionDefs[i] = def.Clone()
+	}
+
 	if cp.SDKConfig != nil {
 		cp.SDKConfig = cp.SDKConfig.Clone()
 	}
@@ -1078,6 +1169,7 @@ func (mod Module) CloneWithoutDefs() *Module {
 	cp.EnumDefs = []*TypeDef{}
 	cp.ObjectDefs = []*TypeDef{}
 	cp.InterfaceDefs = []*TypeDef{}
+	cp.UnionDefs = []*TypeDef{}
 
 	return cp
 }
@@ -1168,6 +1260,35 @@ func (mod *Module) WithEnum(ctx context.Context, def *TypeDef) (*Module, error)
 	return mod, nil
 }
 
+func (mod *Module) WithUnion(ctx context.Context, def *TypeDef) (*Module, error) {
+	mod = mod.Clone()
+	if !def.AsUnion.Valid {
+		return nil, fmt.Errorf("expected union type def, got %s: %+v", def.Kind, def)
+	}
+	if len(def.AsUnion.Value.Members) == 0 {
+		return nil, fmt.Errorf("union %q has no members", def.AsUnion.Value.OriginalName)
+	}
+
+	// skip validation+namespacing for module objects being constructed by SDK with* calls
+	// they will be validated when merged into the real final module
+
+	if mod.Deps != nil {
+		if err := mod.validateTypeDef(ctx, def); err != nil {
+			return nil, fmt.Errorf("failed to validate type def: %w", err)
+		}
+	}
+	if mod.NameField != "" {
+		def = def.Clone()
+		modPath := mod.modulePath()
+		if err := mod.namespaceTypeDef(ctx, modPath, def); err != nil {
+			return nil, fmt.Errorf("failed to namespace type def: %w", err)
+		}
+	}
+
+	mod.UnionDefs = append(mod.UnionDefs, def)
+	return mod, nil
+}
+
 type CurrentModule struct {
 	Module *Module
 }
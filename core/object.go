@@ -22,6 +22,12 @@ const trivialFieldDirectiveName = "trivialResolveField"
 // indicates an ast field is deprecated
 const deprecatedDirectiveName = "deprecated"
 
+// indicates an ast field streams its values in a subscription
+const streamingDirectiveName = "streaming"
+
+// indicates an ast field renders a prompt template
+const promptDirectiveName = "prompt"
+
 type ModuleObjectType struct {
 	typeDef *ObjectTypeDef
 	mod     *Module
@@ -601,6 +607,7 @@ func objFun(ctx context.Context, mod *Module, objDef *ObjectTypeDef, fun *Functi
 	}
 	spec.Module = mod.IDModule()
 	spec.GetCacheConfig = modFun.CacheConfigForCall
+	spec.Paginated = fun.IsPaginated
 
 	return dagql.Field[*ModuleObject]{
 		Spec: &spec,
@@ -145,6 +145,10 @@ type Server interface {
 	// The list of connected client IDs
 	Clients() []string
 
+	// The details of every client connected to the engine that the current
+	// client may watch, across all sessions
+	EngineClients(ctx context.Context) ([]*EngineClient, error)
+
 	// Return a client connected to a cloud engine. If bool return is false, the local engine should be used. Session attachables for the returned client will be proxied back to the calling client.
 	CloudEngineClient(
 		ctx context.Context,
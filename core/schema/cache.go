@@ -54,6 +54,7 @@ func (s *cacheSchema) cacheVolumeCacheKey(
 		return nil, err
 	}
 	namespaceKey := namespaceFromModule(m)
+	dagql.RecordCacheKeyInput(ctx, "cache volume namespace", namespaceKey)
 	resp.CacheKey.CallKey = hashutil.HashStrings(resp.CacheKey.CallKey, namespaceKey).String()
 	return resp, nil
 }
@@ -75,6 +76,7 @@ func (s *cacheSchema) cacheVolume(ctx context.Context, parent dagql.ObjectResult
 		return inst, err
 	}
 	namespaceKey := namespaceFromModule(m)
+	dagql.RecordCacheKeyInput(ctx, "cache volume namespace", namespaceKey)
 	err = srv.Select(ctx, srv.Root(), &inst, dagql.Selector{
 		Field: "cacheVolume",
 		Args: []dagql.NamedInput{
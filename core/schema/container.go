@@ -528,6 +528,12 @@ func (s *containerSchema) Install(srv *dagql.Server) {
 			Doc(`The exit code of the last executed command`,
 				`Returns an error if no command was executed`),
 
+		dagql.Func("execLogs", s.execLogs).
+			DoNotCache("Streams output as the container is evaluated.").
+			Doc(`Evaluate the container, streaming the output of its commands as they run.`,
+				`Commands that have already run aren't run again, so their output isn't streamed.`,
+				`Can only be selected in a subscription.`),
+
 		dagql.NodeFunc("withSymlink", s.withSymlink).
 			Doc(`Return a snapshot with a symlink`).
 			Args(
@@ -1055,6 +1061,9 @@ func (s *containerSchema) withExecCacheKey(
 	if err != nil {
 		return nil, err
 	}
+	if args.ExecMD.Self != nil {
+		dagql.RecordCacheKeyInput(ctx, "function call cache mixin", string(args.ExecMD.Self.CacheMixin))
+	}
 
 	resp := &dagql.GetCacheConfigResponse{CacheKey: req.CacheKey}
 	resp.CacheKey.CallKey = hashutil.HashStrings(
@@ -1068,6 +1077,10 @@ func (s *containerSchema) stdout(ctx context.Context, parent *core.Container, _
 	return parent.Stdout(ctx)
 }
 
+func (s *containerSchema) execLogs(ctx context.Context, parent *core.Container, _ struct{}) (dagql.Stream[*core.LogEntry], error) {
+	return dagql.NewStream(parent.ExecLogs), nil
+}
+
 //nolint:dupl
 func (s *containerSchema) stdoutLegacy(ctx context.Context, parent dagql.ObjectResult[*core.Container], _ struct{}) (string, error) {
 	srv, err := core.CurrentDagqlServer(ctx)
@@ -62,6 +62,7 @@ func (m *CoreMod) Install(ctx context.Context, dag *dagql.Server) error {
 		&engineSchema{},
 		&cloudSchema{},
 		&llmSchema{dag},
+		&evalSchema{dag},
 		&jsonvalueSchema{},
 		&envfileSchema{},
 		&addressSchema{},
@@ -92,6 +93,19 @@ func (m *CoreMod) ModTypeFor(ctx context.Context, typeDef *core.TypeDef, checkDi
 			Underlying: underlyingType,
 		}
 
+	case core.TypeDefKindMap:
+		underlyingType, ok, err := m.ModTypeFor(ctx, typeDef.AsMap.Value.ValueTypeDef, checkDirectDeps)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get underlying type: %w", err)
+		}
+		if !ok {
+			return nil, false, nil
+		}
+		modType = &core.MapType{
+			Value:      typeDef.AsMap.Value.ValueTypeDef,
+			Underlying: underlyingType,
+		}
+
 	case core.TypeDefKindScalar:
 		_, ok := m.Dag.ScalarType(typeDef.AsScalar.Value.Name)
 		if !ok {
@@ -165,6 +179,10 @@ func (m *CoreMod) ModTypeFor(ctx context.Context, typeDef *core.TypeDef, checkDi
 		// core does not yet define any interfaces
 		return nil, false, nil
 
+	case core.TypeDefKindUnion:
+		// core does not yet define any unions
+		return nil, false, nil
+
 	default:
 		return nil, false, fmt.Errorf("unexpected type def kind %s", typeDef.Kind)
 	}
@@ -207,6 +225,7 @@ func (m *CoreMod) TypeDefs(ctx context.Context, dag *dagql.Server) ([]*core.Type
 	for _, dagqlDirective := range dagqlSchema.Directives() {
 		schema.Directives = append(schema.Directives, dagqlToCodegenDirectiveDef(dagqlDirective))
 	}
+	schema.ScrubStreamingFields()
 
 	typeDefs := make([]*core.TypeDef, 0, len(schema.Types))
 	for _, introspectionType := range schema.Types {
@@ -54,12 +54,14 @@ func (s *directorySchema) Install(srv *dagql.Server) {
 		dagql.NodeFunc("entries", DagOpWrapper(srv, s.entries)).
 			View(AllVersion). // entries returns different results in different versions
 			Doc(`Returns a list of files and directories at the given path.`).
+			Paginated().
 			Args(
 				dagql.Arg("path").Doc(`Location of the directory to look at (e.g., "/src").`),
 			),
 		dagql.NodeFunc("glob", DagOpWrapper(srv, s.glob)).
 			View(AllVersion). // glob returns different results in different versions
 			Doc(`Returns a list of files and directories that matche the given pattern.`).
+			Paginated().
 			Args(
 				dagql.Arg("pattern").Doc(`Pattern to match (e.g., "*.md").`),
 			),
@@ -23,8 +23,14 @@ func (s *engineSchema) Install(srv *dagql.Server) {
 		dagql.Func("clients", s.clients).
 			DoNotCache("Clients can connect and disconnect at any time").
 			Doc("The list of connected client IDs"),
+		dagql.Func("activeClients", s.activeClients).
+			Experimental("Subject to change while dagger watch is developed").
+			DoNotCache("Clients can connect and disconnect at any time").
+			Doc("The clients connected to the engine that the current client may watch, across all sessions"),
 	}.Install(srv)
 
+	dagql.Fields[*core.EngineClient]{}.Install(srv)
+
 	dagql.Fields[*core.Engine]{
 		dagql.Func("localCache", s.localCache).
 			Doc("The local (on-disk) cache for the Dagger engine"),
@@ -43,7 +49,8 @@ func (s *engineSchema) Install(srv *dagql.Server) {
 
 	dagql.Fields[*core.EngineCacheEntrySet]{
 		dagql.Func("entries", s.cacheEntrySetEntries).
-			Doc("The list of individual cache entries in the set"),
+			Doc("The list of individual cache entries in the set").
+			Paginated(),
 	}.Install(srv)
 
 	dagql.Fields[*core.EngineCacheEntry]{}.Install(srv)
@@ -87,6 +94,17 @@ func (s *engineSchema) clients(ctx context.Context, parent *core.Engine, args st
 	return query.Clients(), nil
 }
 
+func (s *engineSchema) activeClients(ctx context.Context, parent *core.Engine, args struct{}) (dagql.Array[*core.EngineClient], error) {
+	query, err := core.CurrentQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.RequireMainClient(ctx); err != nil {
+		return nil, err
+	}
+	return query.EngineClients(ctx)
+}
+
 func (s *engineSchema) cacheEntrySet(ctx context.Context, parent dagql.ObjectResult[*core.EngineCache], args struct {
 	Key string `default:""`
 }) (inst dagql.Result[*core.EngineCacheEntrySet], _ error) {
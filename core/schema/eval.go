@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"context"
+
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
+)
+
+type evalSchema struct {
+	srv *dagql.Server
+}
+
+var _ SchemaResolvers = &evalSchema{}
+
+func (s evalSchema) Install(srv *dagql.Server) {
+	dagql.Fields[*core.Query]{
+		dagql.Func("eval", s.eval).
+			Experimental("Evals are not yet stabilized").
+			Doc(`Initialize an evaluation of an agent`).
+			Args(
+				dagql.Arg("prompt").Doc("The prompt to give the agent"),
+				dagql.Arg("attempts").Doc("The number of attempts to run for each model"),
+			),
+	}.Install(srv)
+	dagql.Fields[*core.Eval]{
+		dagql.Func("withEnv", s.withEnv).
+			Doc("Run the agent in an environment").
+			Args(
+				dagql.Arg("env").Doc("The environment to give the agent"),
+			),
+		dagql.Func("withModel", s.withModel).
+			Doc("Add a model to evaluate. If no model is added, the default model is evaluated.").
+			Args(
+				dagql.Arg("model").Doc("The model to evaluate"),
+			),
+		dagql.Func("withAttempts", s.withAttempts).
+			Doc("Set the number of attempts to run for each model").
+			Args(
+				dagql.Arg("attempts").Doc("The number of attempts"),
+			),
+		dagql.Func("withMaxAPICalls", s.withMaxAPICalls).
+			Doc("Cap the number of API calls for each attempt").
+			Args(
+				dagql.Arg("maxAPICalls").Doc("The maximum number of API calls, or 0 for no limit"),
+			),
+		dagql.Func("withReplyAssertion", s.withReplyAssertion).
+			Doc("Assert that the agent's last reply contains a string and/or matches a pattern").
+			Args(
+				dagql.Arg("contains").Doc("A string that the reply must contain"),
+				dagql.Arg("matches").Doc("A regular expression that the reply must match"),
+			),
+		dagql.Func("withOutputAssertion", s.withOutputAssertion).
+			Doc(
+				"Assert that the agent saved an output in its environment.",
+				"String outputs and the contents of File outputs may also be checked against a string and/or a pattern.",
+			).
+			Args(
+				dagql.Arg("name").Doc("The name of the output"),
+				dagql.Arg("contains").Doc("A string that the output must contain"),
+				dagql.Arg("matches").Doc("A regular expression that the output must match"),
+			),
+		dagql.Func("withChangesetAssertion", s.withChangesetAssertion).
+			Doc("Assert that the agent changed its environment's workspace").
+			Args(
+				dagql.Arg("added").Doc("Paths or glob patterns that must have been added"),
+				dagql.Arg("modified").Doc("Paths or glob patterns that must have been modified"),
+				dagql.Arg("removed").Doc("Paths or glob patterns that must have been removed"),
+			),
+		dagql.FuncWithCacheKey("run", s.run, dagql.CachePerSession).
+			Doc("Run every attempt for every model, and report the outcomes"),
+	}.Install(srv)
+	dagql.Fields[*core.EvalReport]{
+		dagql.Func("table", s.table).
+			Doc("Render the report as a markdown table"),
+		dagql.Func("json", s.json).
+			Doc("Render the report as JSON"),
+	}.Install(srv)
+	dagql.Fields[*core.EvalModelReport]{}.Install(srv)
+}
+
+func (s *evalSchema) eval(ctx context.Context, parent *core.Query, args struct {
+	Prompt   string
+	Attempts int `default:"1"`
+}) (*core.Eval, error) {
+	return core.NewEval(args.Prompt, args.Attempts)
+}
+
+func (s *evalSchema) withEnv(ctx context.Context, eval *core.Eval, args struct {
+	Env core.EnvID
+}) (*core.Eval, error) {
+	env, err := args.Env.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return eval.WithEnv(env), nil
+}
+
+func (s *evalSchema) withModel(ctx context.Context, eval *core.Eval, args struct {
+	Model string
+}) (*core.Eval, error) {
+	return eval.WithModel(args.Model), nil
+}
+
+func (s *evalSchema) withAttempts(ctx context.Context, eval *core.Eval, args struct {
+	Attempts int
+}) (*core.Eval, error) {
+	return eval.WithAttempts(args.Attempts)
+}
+
+func (s *evalSchema) withMaxAPICalls(ctx context.Context, eval *core.Eval, args struct {
+	MaxAPICalls int `name:"maxAPICalls"`
+}) (*core.Eval, error) {
+	return eval.WithMaxAPICalls(args.MaxAPICalls), nil
+}
+
+func (s *evalSchema) withReplyAssertion(ctx context.Context, eval *core.Eval, args struct {
+	Contains string `default:""`
+	Matches  string `default:""`
+}) (*core.Eval, error) {
+	return eval.WithAssertion(core.NewEvalReplyAssertion(args.Contains, args.Matches))
+}
+
+func (s *evalSchema) withOutputAssertion(ctx context.Context, eval *core.Eval, args struct {
+	Name     string
+	Contains string `default:""`
+	Matches  string `default:""`
+}) (*core.Eval, error) {
+	return eval.WithAssertion(core.NewEvalOutputAssertion(args.Name, args.Contains, args.Matches))
+}
+
+func (s *evalSchema) withChangesetAssertion(ctx context.Context, eval *core.Eval, args struct {
+	Added    []string `default:"[]"`
+	Modified []string `default:"[]"`
+	Removed  []string `default:"[]"`
+}) (*core.Eval, error) {
+	return eval.WithAssertion(core.NewEvalChangesetAssertion(args.Added, args.Modified, args.Removed))
+}
+
+func (s *evalSchema) run(ctx context.Context, eval *core.Eval, _ struct{}) (*core.EvalReport, error) {
+	return eval.Run(ctx, s.srv)
+}
+
+func (s *evalSchema) table(ctx context.Context, report *core.EvalReport, _ struct{}) (string, error) {
+	return report.Table(), nil
+}
+
+func (s *evalSchema) json(ctx context.Context, report *core.EvalReport, _ struct{}) (core.JSON, error) {
+	return report.JSON()
+}
@@ -105,6 +105,7 @@ func (s *gitSchema) Install(srv *dagql.Server) {
 
 		dagql.Func("tags", s.tags).
 			Doc(`tags that match any of the given glob patterns.`).
+			Paginated().
 			Args(
 				dagql.Arg("patterns").Doc(`Glob patterns (e.g., "refs/tags/v*").`),
 			),
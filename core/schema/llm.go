@@ -49,6 +49,17 @@ func (s llmSchema) Install(srv *dagql.Server) {
 			Doc("return the LLM's current environment"),
 		dagql.Func("withStaticTools", s.withStaticTools).
 			Doc("Use a static set of tools for method calls, e.g. for MCP clients that do not support dynamic tool registration"),
+		dagql.Func("withSubagents", s.withSubagents).
+			Doc("Allow the LLM to delegate tasks to sub-agents, each with its own environment and system prompt").
+			Args(
+				dagql.Arg("model").Doc("The model for sub-agents to use. Defaults to the LLM's current model."),
+			),
+		dagql.Func("withContextPolicy", s.withContextPolicy).
+			Doc("Compact the message history automatically whenever it grows beyond a token budget").
+			Args(
+				dagql.Arg("maxTokens").Doc("The maximum number of tokens to send to the model"),
+				dagql.Arg("strategy").Doc("How to compact the message history"),
+			),
 		dagql.Func("withModel", s.withModel).
 			Doc("swap out the llm model").
 			Args(
@@ -59,10 +70,12 @@ func (s llmSchema) Install(srv *dagql.Server) {
 			Args(
 				dagql.Arg("prompt").Doc("The prompt to send"),
 			),
-		dagql.Func("__mcp", func(ctx context.Context, self *core.LLM, _ struct{}) (dagql.Nullable[core.Void], error) {
-			return dagql.Null[core.Void](), self.MCP(ctx, srv)
-		}).
-			Doc("instantiates an mcp server"),
+		dagql.Func("__mcp", s.mcp).
+			Doc("instantiates an mcp server").
+			Args(
+				dagql.Arg("transport").Doc(`The transport to serve: "stdio" or "http"`),
+				dagql.Arg("authToken").Doc("A bearer token that HTTP clients must provide"),
+			),
 		dagql.Func("withPromptFile", s.withPromptFile).
 			Doc("append the contents of a file to the llm context").
 			Args(
@@ -114,8 +127,28 @@ func (s llmSchema) Install(srv *dagql.Server) {
 			Doc("returns the type of the current state"),
 		dagql.Func("tokenUsage", s.tokenUsage).
 			Doc("returns the token usage of the current state"),
+		dagql.NodeFunc("events", func(ctx context.Context, self dagql.ObjectResult[*core.LLM], _ struct{}) (dagql.Stream[*core.LLMEvent], error) {
+			return dagql.NewStream(func(ctx context.Context, emit func(*core.LLMEvent) error) error {
+				// evaluate the same (cached) loop as everyone else
+				loop := func(ctx context.Context) (*core.LLM, error) {
+					var inst dagql.Result[*core.LLM]
+					if err := srv.Select(ctx, self, &inst, dagql.Selector{
+						Field: "loop",
+					}); err != nil {
+						return nil, err
+					}
+					return inst.Self(), nil
+				}
+				return self.Self().Events(ctx, loop, emit)
+			}), nil
+		}).
+			DoNotCache("Streams messages as the model replies").
+			Doc("Submit the queued prompt and keep going until the model ends its turn, streaming each message added to the history",
+				"Can only be selected in a subscription."),
 	}.Install(srv)
 	dagql.Fields[*core.LLMTokenUsage]{}.Install(srv)
+	dagql.Fields[*core.LLMEvent]{}.Install(srv)
+	core.LLMContextStrategies.Install(srv)
 }
 
 func (s *llmSchema) withEnv(ctx context.Context, llm *core.LLM, args struct {
@@ -132,6 +165,19 @@ func (s *llmSchema) withStaticTools(ctx context.Context, llm *core.LLM, args str
 	return llm.WithStaticTools(), nil
 }
 
+func (s *llmSchema) withSubagents(ctx context.Context, llm *core.LLM, args struct {
+	Model string `default:""`
+}) (*core.LLM, error) {
+	return llm.WithSubagents(args.Model), nil
+}
+
+func (s *llmSchema) withContextPolicy(ctx context.Context, llm *core.LLM, args struct {
+	MaxTokens int
+	Strategy  core.LLMContextStrategy `default:"SUMMARIZE"`
+}) (*core.LLM, error) {
+	return llm.WithContextPolicy(args.MaxTokens, args.Strategy)
+}
+
 func (s *llmSchema) env(ctx context.Context, llm *core.LLM, args struct{}) (res dagql.ObjectResult[*core.Env], _ error) {
 	if err := llm.Sync(ctx); err != nil {
 		return res, err
@@ -208,6 +254,21 @@ func (s *llmSchema) withMCPServer(ctx context.Context, llm *core.LLM, args struc
 	return llm.WithMCPServer(args.Name, svc), nil
 }
 
+func (s *llmSchema) mcp(ctx context.Context, llm *core.LLM, args struct {
+	Transport string `default:"stdio"`
+	AuthToken dagql.Optional[core.SecretID]
+}) (dagql.Nullable[core.Void], error) {
+	var authToken dagql.ObjectResult[*core.Secret]
+	if args.AuthToken.Valid {
+		var err error
+		authToken, err = args.AuthToken.Value.Load(ctx, s.srv)
+		if err != nil {
+			return dagql.Null[core.Void](), err
+		}
+	}
+	return dagql.Null[core.Void](), llm.MCP(ctx, s.srv, core.MCPTransport(args.Transport), authToken)
+}
+
 func (s *llmSchema) withPromptFile(ctx context.Context, llm *core.LLM, args struct {
 	File core.FileID
 }) (*core.LLM, error) {
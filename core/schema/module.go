@@ -116,6 +116,9 @@ func (s *moduleSchema) Install(dag *dagql.Server) {
 		dagql.Func("withEnum", s.moduleWithEnum).
 			Doc(`This module plus the given Enum type and associated values`),
 
+		dagql.Func("withUnion", s.moduleWithUnion).
+			Doc(`This module plus the given Union type`),
+
 		dagql.Func("runtime", s.moduleRuntime).
 			Doc(`The container that runs the module's entrypoint. It will fail to execute if the module doesn't compile.`),
 
@@ -173,6 +176,14 @@ func (s *moduleSchema) Install(dag *dagql.Server) {
 		dagql.Func("withCheck", s.functionWithCheck).
 			Doc(`Returns the function with a flag indicating it's a check.`),
 
+		dagql.Func("withPrompt", s.functionWithPrompt).
+			Doc(`Returns the function with a flag indicating it renders a prompt template.`,
+				`Prompt functions are exposed as prompts by MCP servers.`),
+
+		dagql.Func("withPaginated", s.functionWithPaginated).
+			Doc(`Returns the function with a flag indicating the list it returns can be selected in pages.`,
+				`A "<name>Connection" field is installed alongside the function, with first and after arguments selecting a page of the list.`),
+
 		dagql.Func("withSourceMap", s.functionWithSourceMap).
 			Doc(`Returns the function with the given source map.`).
 			Args(
@@ -219,6 +230,10 @@ func (s *moduleSchema) Install(dag *dagql.Server) {
 		dagql.Func("withListOf", s.typeDefWithListOf).
 			Doc(`Returns a TypeDef of kind List with the provided type for its elements.`),
 
+		dagql.Func("withMapOf", s.typeDefWithMapOf).
+			Doc(`Returns a TypeDef of kind Map with string keys and the provided type for its values.`,
+				`Maps are represented as JSON objects, so their values can't be objects, interfaces or unions.`),
+
 		dagql.Func("withObject", s.typeDefWithObject).
 			Doc(`Returns a TypeDef of kind Object with the provided name.`,
 				`Note that an object's fields and functions may be omitted if the
@@ -274,6 +289,22 @@ func (s *moduleSchema) Install(dag *dagql.Server) {
 				dagql.Arg("sourceMap").Doc(`The source map for the enum member definition.`),
 				dagql.Arg("deprecated").Doc(`If deprecated, the reason or migration path.`),
 			),
+
+		dagql.Func("withUnion", s.typeDefWithUnion).
+			Doc(`Returns a TypeDef of kind Union with the provided name.`,
+				`Note that a union's members may be omitted if the intent is only to refer to a union.
+				This is how functions are able to return their own, or any other circular reference.`).
+			Args(
+				dagql.Arg("name").Doc(`The name of the union`),
+				dagql.Arg("description").Doc(`A doc string for the union, if any`),
+				dagql.Arg("sourceMap").Doc(`The source map for the union definition.`),
+			),
+
+		dagql.Func("withUnionMember", s.typeDefWithUnionMember).
+			Doc(`Adds a member type to a Union TypeDef, failing if the type is not a union or the member is not an object.`).
+			Args(
+				dagql.Arg("member").Doc(`The object type to add to the union`),
+			),
 	}.Install(dag)
 
 	dagql.Fields[*core.ObjectTypeDef]{}.Install(dag)
@@ -281,6 +312,8 @@ func (s *moduleSchema) Install(dag *dagql.Server) {
 	dagql.Fields[*core.InputTypeDef]{}.Install(dag)
 	dagql.Fields[*core.FieldTypeDef]{}.Install(dag)
 	dagql.Fields[*core.ListTypeDef]{}.Install(dag)
+	dagql.Fields[*core.MapTypeDef]{}.Install(dag)
+	dagql.Fields[*core.UnionTypeDef]{}.Install(dag)
 	dagql.Fields[*core.ScalarTypeDef]{}.Install(dag)
 	dagql.Fields[*core.EnumTypeDef]{
 		dagql.Func("values", func(ctx context.Context, self *core.EnumTypeDef, _ struct{}) (dagql.Array[*core.EnumMemberTypeDef], error) {
@@ -331,6 +364,21 @@ func (s *moduleSchema) typeDefWithListOf(ctx context.Context, def *core.TypeDef,
 	return def.WithListOf(elemType.Self()), nil
 }
 
+func (s *moduleSchema) typeDefWithMapOf(ctx context.Context, def *core.TypeDef, args struct {
+	ValueType core.TypeDefID
+}) (*core.TypeDef, error) {
+	dag, err := core.CurrentDagqlServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dag server: %w", err)
+	}
+
+	valueType, err := args.ValueType.Load(ctx, dag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode value type: %w", err)
+	}
+	return def.WithMapOf(valueType.Self())
+}
+
 func (s *moduleSchema) typeDefWithObject(ctx context.Context, def *core.TypeDef, args struct {
 	Name        string
 	Description string `default:""`
@@ -435,6 +483,36 @@ func (s *moduleSchema) typeDefWithEnum(ctx context.Context, def *core.TypeDef, a
 	return def.WithEnum(args.Name, args.Description, sourceMap), nil
 }
 
+func (s *moduleSchema) typeDefWithUnion(ctx context.Context, def *core.TypeDef, args struct {
+	Name        string
+	Description string `default:""`
+	SourceMap   dagql.Optional[core.SourceMapID]
+}) (*core.TypeDef, error) {
+	if args.Name == "" {
+		return nil, fmt.Errorf("union type def must have a name")
+	}
+	sourceMap, err := s.loadSourceMap(ctx, args.SourceMap)
+	if err != nil {
+		return nil, err
+	}
+	return def.WithUnion(args.Name, args.Description, sourceMap), nil
+}
+
+func (s *moduleSchema) typeDefWithUnionMember(ctx context.Context, def *core.TypeDef, args struct {
+	Member core.TypeDefID
+}) (*core.TypeDef, error) {
+	dag, err := core.CurrentDagqlServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dag server: %w", err)
+	}
+
+	member, err := args.Member.Load(ctx, dag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode member type: %w", err)
+	}
+	return def.WithUnionMember(member.Self())
+}
+
 func (s *moduleSchema) typeDefWithEnumValue(ctx context.Context, def *core.TypeDef, args struct {
 	Value       string
 	Description string `default:""`
@@ -533,6 +611,18 @@ func (s *moduleSchema) functionWithCheck(ctx context.Context, fn *core.Function,
 	return fn.WithCheck(), nil
 }
 
+func (s *moduleSchema) functionWithPrompt(ctx context.Context, fn *core.Function, args struct{}) (*core.Function, error) {
+	return fn.WithPrompt(), nil
+}
+
+func (s *moduleSchema) functionWithPaginated(ctx context.Context, fn *core.Function, args struct{}) (*core.Function, error) {
+	if fn.ReturnType.Kind != core.TypeDefKindList ||
+		fn.ReturnType.AsList.Value.ElementTypeDef.Kind == core.TypeDefKindList {
+		return nil, fmt.Errorf("function %q must return a list of non-list elements to be paginated", fn.Name)
+	}
+	return fn.WithPaginated(), nil
+}
+
 func (s *moduleSchema) functionWithArg(ctx context.Context, fn *core.Function, args struct {
 	Name         string
 	TypeDef      core.TypeDefID
@@ -874,6 +964,22 @@ func (s *moduleSchema) moduleWithEnum(ctx context.Context, mod *core.Module, arg
 	return mod.WithEnum(ctx, def.Self())
 }
 
+func (s *moduleSchema) moduleWithUnion(ctx context.Context, mod *core.Module, args struct {
+	Union core.TypeDefID
+}) (_ *core.Module, rerr error) {
+	dag, err := core.CurrentDagqlServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dag server: %w", err)
+	}
+
+	def, err := args.Union.Load(ctx, dag)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.WithUnion(ctx, def.Self())
+}
+
 func (s *moduleSchema) currentModuleName(
 	ctx context.Context,
 	curMod *core.CurrentModule,
@@ -2814,6 +2814,12 @@ func (s *moduleSourceSchema) runModuleDefInSDK(ctx context.Context, src, srcInst
 			return nil, fmt.Errorf("failed to add enum to module %q: %w", modName, err)
 		}
 	}
+	for _, union := range initialized.UnionDefs {
+		mod, err = mod.WithUnion(ctx, union)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add union to module %q: %w", modName, err)
+		}
+	}
 	err = mod.Patch()
 	if err != nil {
 		return nil, fmt.Errorf("failed to patch module %q: %w", modName, err)
@@ -47,6 +47,7 @@ func (s *querySchema) Install(srv *dagql.Server) {
 	core.ReturnTypesEnum.Install(srv)
 	core.ModuleSourceExperimentalFeatures.Install(srv)
 	core.FunctionCachePolicyEnum.Install(srv)
+	core.LogStreams.Install(srv)
 
 	dagql.MustInputSpec(PipelineLabel{}).Install(srv)
 	dagql.MustInputSpec(core.PortForward{}).Install(srv)
@@ -56,6 +57,8 @@ func (s *querySchema) Install(srv *dagql.Server) {
 
 	dagql.Fields[core.Port]{}.Install(srv)
 
+	dagql.Fields[*core.LogEntry]{}.Install(srv)
+
 	dagql.Fields[Label]{}.Install(srv)
 
 	dagql.Fields[*core.Query]{
@@ -113,6 +116,7 @@ func (s *querySchema) schemaJSONFile(
 		introspectionResponse.Schema.Directives = append(introspectionResponse.Schema.Directives, dagqlToCodegenDirectiveDef(dagqlDirective))
 	}
 
+	introspectionResponse.Schema.ScrubStreamingFields()
 	for _, typed := range core.TypesHiddenFromModuleSDKs {
 		introspectionResponse.Schema.ScrubType(typed.Type().Name())
 		introspectionResponse.Schema.ScrubType(dagql.IDTypeNameFor(typed))
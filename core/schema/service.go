@@ -143,6 +143,11 @@ func (s *serviceSchema) Install(srv *dagql.Server) {
 
 		dagql.NodeFunc("terminal", s.terminal).
 			DoNotCache("Imperatively mutates runtime state."),
+
+		dagql.NodeFunc("logs", s.logs).
+			DoNotCache("Streams output while the service runs.").
+			Doc(`Start the service if it isn't running already, and stream its output until it exits.`,
+				`Can only be selected in a subscription.`),
 	}.Install(srv)
 }
 
@@ -366,6 +371,12 @@ func (s *serviceSchema) stop(ctx context.Context, parent dagql.ObjectResult[*cor
 	return dagql.NewResultForCurrentID(ctx, id)
 }
 
+func (s *serviceSchema) logs(ctx context.Context, parent dagql.ObjectResult[*core.Service], _ struct{}) (dagql.Stream[*core.LogEntry], error) {
+	return dagql.NewStream(func(ctx context.Context, emit func(*core.LogEntry) error) error {
+		return parent.Self().Logs(ctx, parent.ID(), emit)
+	}), nil
+}
+
 type serviceTerminalArgs struct {
 	core.ExecTerminalArgs
 }
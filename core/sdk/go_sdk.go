@@ -42,6 +42,8 @@ type goSDK struct {
 
 type goSDKConfig struct {
 	GoPrivate string `json:"goprivate,omitempty"`
+	// Mock generates a mock client, to unit test the module without an engine.
+	Mock bool `json:"mock,omitempty"`
 }
 
 func (sdk *goSDK) AsRuntime() (core.Runtime, bool) {
@@ -577,6 +579,10 @@ func (sdk *goSDK) baseWithCodegen(
 		return ctr, fmt.Errorf("unknown sdk config keys found %v", mapstructureMetadata.Unused)
 	}
 
+	if config.Mock {
+		codegenArgs = append(codegenArgs, "--mock")
+	}
+
 	configSelectors := getSDKConfigSelectors(ctx, config)
 	selectors = append(selectors, configSelectors...)
 
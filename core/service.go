@@ -261,6 +261,39 @@ func (svc *Service) Stop(ctx context.Context, id *call.ID, kill bool) error {
 	return svcs.Stop(ctx, id, kill, svc.TunnelUpstream.Self() != nil)
 }
 
+// Logs starts the service if it isn't running already, calling emit with its
+// output until it exits.
+func (svc *Service) Logs(ctx context.Context, id *call.ID, emit func(*LogEntry) error) error {
+	if svc.Container == nil {
+		return fmt.Errorf("logs are only available for container services")
+	}
+	query, err := CurrentQuery(ctx)
+	if err != nil {
+		return err
+	}
+	svcs, err := query.Services(ctx)
+	if err != nil {
+		return err
+	}
+	running, err := svcs.Start(ctx, id, svc, false)
+	if err != nil {
+		return err
+	}
+	// keep the service running while we're streaming its logs
+	defer svcs.Detach(context.WithoutCancel(ctx), running)
+
+	exited := make(chan struct{})
+	var exitErr error
+	go func() {
+		defer close(exited)
+		exitErr = running.Wait(ctx)
+	}()
+	if err := streamLogs(ctx, running.ParentSpan.SpanID(), running.Span.SpanID(), exited, emit); err != nil {
+		return err
+	}
+	return exitErr
+}
+
 type ServiceIO struct {
 	Stdin       io.ReadCloser
 	Stdout      io.WriteCloser
@@ -492,6 +525,7 @@ func (svc *Service) startContainer(
 		meta.Env = addDefaultEnvvar(meta.Env, "TERM", "xterm")
 	}
 
+	parentSpan := trace.SpanContextFromContext(ctx)
 	ctx, span := Tracer(ctx).Start(
 		// The parent is the call site that triggered it to start.
 		ctx,
@@ -684,6 +718,8 @@ func (svc *Service) startContainer(
 			Wait:        waitSvc,
 			Exec:        execSvc,
 			ContainerID: svcID,
+			Span:        span.SpanContext(),
+			ParentSpan:  parentSpan,
 		}, nil
 	case <-exited:
 		if exitErr != nil {
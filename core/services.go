@@ -9,6 +9,7 @@ import (
 	"github.com/dagger/dagger/internal/buildkit/util/bklog"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/dagger/dagger/dagql/call"
@@ -69,6 +70,11 @@ type RunningService struct {
 
 	// The runc container ID, if any
 	ContainerID string
+
+	// The span that the service's output is logged to, and its parent, if it
+	// has a backing container.
+	Span       trace.SpanContext
+	ParentSpan trace.SpanContext
 }
 
 // ServiceKey is a unique identifier for a service.
@@ -87,6 +93,13 @@ func NewServices() *Services {
 	}
 }
 
+// Running returns the number of services that are currently running.
+func (ss *Services) Running() int {
+	ss.l.Lock()
+	defer ss.l.Unlock()
+	return len(ss.running)
+}
+
 // Get returns the running service for the given service. If the service is
 // starting, it waits for it and either returns the running service or an error
 // if it failed to start. If the service is not running or starting, an error
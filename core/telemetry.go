@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"path"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
@@ -14,6 +16,7 @@ import (
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/metrics"
 	"github.com/dagger/dagger/engine/slog"
 	"github.com/dagger/dagger/internal/buildkit/solver/pb"
 	"github.com/opencontainers/go-digest"
@@ -53,6 +56,8 @@ func AroundFunc(
 		return ctx, dagql.NoopDone
 	}
 
+	start := time.Now()
+
 	var base string
 	if id.Receiver() == nil {
 		base = "Query"
@@ -120,12 +125,31 @@ func AroundFunc(
 
 	return ctx, func(res dagql.AnyResult, cached bool, err *error) {
 		defer telemetry.EndWithCause(span, err)
+		recordMetrics(ctx, id, spanName, start, cached)
 		recordStatus(ctx, res, span, cached, err, id)
 		logResult(ctx, res, self, id)
 		collectEffects(ctx, res, span, self)
 	}
 }
 
+// recordMetrics records the call in the engine's Prometheus metrics, labeled
+// by the module it's attributed to: the module defining the field if any,
+// otherwise the module whose code made the call.
+func recordMetrics(ctx context.Context, id *call.ID, field string, start time.Time, cached bool) {
+	var module string
+	if mod := id.Module(); mod != nil {
+		module = mod.Name()
+	} else if q, err := CurrentQuery(ctx); err == nil {
+		if mod, err := q.CurrentModule(ctx); err == nil {
+			module = mod.Name()
+		}
+	}
+	metrics.Operations.WithLabelValues(module, strconv.FormatBool(cached)).Inc()
+	if !cached {
+		metrics.OperationDuration.WithLabelValues(field, module).Observe(time.Since(start).Seconds())
+	}
+}
+
 type moduleCallRef struct {
 	ref          string
 	version      string
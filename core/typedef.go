@@ -34,6 +34,13 @@ type Function struct {
 	// IsCheck indicates whether this function is a check
 	IsCheck bool
 
+	// IsPrompt indicates whether this function renders a prompt template
+	IsPrompt bool
+
+	// IsPaginated indicates whether the list this function returns can also be
+	// selected in pages, through a "<name>Connection" field
+	IsPaginated bool
+
 	// OriginalName of the parent object
 	ParentOriginalName string
 
@@ -87,6 +94,11 @@ func (fn *Function) Directives() []*ast.Directive {
 			Name: "check",
 		})
 	}
+	if fn.IsPrompt {
+		directives = append(directives, &ast.Directive{
+			Name: promptDirectiveName,
+		})
+	}
 	return directives
 }
 
@@ -127,10 +139,15 @@ func (fn *Function) FieldSpec(ctx context.Context, mod *Module) (dagql.FieldSpec
 		var defaultVal dagql.Input
 		if arg.DefaultValue != nil {
 			var val any
-			dec := json.NewDecoder(bytes.NewReader(arg.DefaultValue.Bytes()))
-			dec.UseNumber()
-			if err := dec.Decode(&val); err != nil {
-				return spec, fmt.Errorf("failed to decode default value for arg %q: %w", arg.Name, err)
+			if arg.TypeDef.Kind == TypeDefKindMap {
+				// maps are represented as JSON, so the default is used as-is
+				val = json.RawMessage(arg.DefaultValue)
+			} else {
+				dec := json.NewDecoder(bytes.NewReader(arg.DefaultValue.Bytes()))
+				dec.UseNumber()
+				if err := dec.Decode(&val); err != nil {
+					return spec, fmt.Errorf("failed to decode default value for arg %q: %w", arg.Name, err)
+				}
 			}
 
 			var err error
@@ -204,6 +221,18 @@ func (fn *Function) WithCheck() *Function {
 	return fn
 }
 
+func (fn *Function) WithPrompt() *Function {
+	fn = fn.Clone()
+	fn.IsPrompt = true
+	return fn
+}
+
+func (fn *Function) WithPaginated() *Function {
+	fn = fn.Clone()
+	fn.IsPaginated = true
+	return fn
+}
+
 func (fn *Function) WithArg(name string, typeDef *TypeDef, desc string, defaultValue JSON, defaultPath string, ignore []string, sourceMap *SourceMap, deprecated *string) *Function {
 	fn = fn.Clone()
 	arg := &FunctionArg{
@@ -479,6 +508,8 @@ type TypeDef struct {
 	AsInput     dagql.Nullable[*InputTypeDef]     `field:"true" doc:"If kind is INPUT, the input-specific type definition. If kind is not INPUT, this will be null."`
 	AsScalar    dagql.Nullable[*ScalarTypeDef]    `field:"true" doc:"If kind is SCALAR, the scalar-specific type definition. If kind is not SCALAR, this will be null."`
 	AsEnum      dagql.Nullable[*EnumTypeDef]      `field:"true" doc:"If kind is ENUM, the enum-specific type definition. If kind is not ENUM, this will be null."`
+	AsMap       dagql.Nullable[*MapTypeDef]       `field:"true" doc:"If kind is MAP, the map-specific type definition. If kind is not MAP, this will be null."`
+	AsUnion     dagql.Nullable[*UnionTypeDef]     `field:"true" doc:"If kind is UNION, the union-specific type definition. If kind is not UNION, this will be null."`
 }
 
 func (typeDef TypeDef) Clone() *TypeDef {
@@ -501,6 +532,12 @@ func (typeDef TypeDef) Clone() *TypeDef {
 	if typeDef.AsEnum.Valid {
 		cp.AsEnum.Value = typeDef.AsEnum.Value.Clone()
 	}
+	if typeDef.AsMap.Valid {
+		cp.AsMap.Value = typeDef.AsMap.Value.Clone()
+	}
+	if typeDef.AsUnion.Valid {
+		cp.AsUnion.Value = typeDef.AsUnion.Value.Clone()
+	}
 	return &cp
 }
 
@@ -536,6 +573,10 @@ func (typeDef *TypeDef) ToTyped() dagql.Typed {
 		typed = &ModuleObject{TypeDef: typeDef.AsObject.Value}
 	case TypeDefKindInterface:
 		typed = &InterfaceAnnotatedValue{TypeDef: typeDef.AsInterface.Value}
+	case TypeDefKindMap:
+		typed = JSON{}
+	case TypeDefKindUnion:
+		typed = &UnionValue{TypeDef: typeDef.AsUnion.Value}
 	case TypeDefKindVoid:
 		typed = Void{}
 	case TypeDefKindInput:
@@ -572,6 +613,10 @@ func (typeDef *TypeDef) ToInput() dagql.Input {
 		typed = DynamicID{typeName: typeDef.AsObject.Value.Name}
 	case TypeDefKindInterface:
 		typed = DynamicID{typeName: typeDef.AsInterface.Value.Name}
+	case TypeDefKindMap:
+		typed = JSON{}
+	case TypeDefKindUnion:
+		typed = DynamicID{typeName: typeDef.AsUnion.Value.Name}
 	case TypeDefKindVoid:
 		typed = Void{}
 	default:
@@ -616,6 +661,56 @@ func (typeDef *TypeDef) WithListOf(elem *TypeDef) *TypeDef {
 	return typeDef
 }
 
+func (typeDef *TypeDef) WithMapOf(value *TypeDef) (*TypeDef, error) {
+	if err := checkMapValueType(value); err != nil {
+		return nil, err
+	}
+	typeDef = typeDef.WithKind(TypeDefKindMap)
+	typeDef.AsMap = dagql.NonNull(&MapTypeDef{
+		ValueTypeDef: value,
+	})
+	return typeDef, nil
+}
+
+// checkMapValueType returns an error if maps can't hold values of the given
+// type. Maps are represented as JSON, so they can only hold values that are
+// represented the same way by every SDK.
+func checkMapValueType(typeDef *TypeDef) error {
+	switch typeDef.Kind {
+	case TypeDefKindString, TypeDefKindInteger, TypeDefKindFloat, TypeDefKindBoolean, TypeDefKindScalar, TypeDefKindEnum:
+		return nil
+	case TypeDefKindList:
+		return checkMapValueType(typeDef.AsList.Value.ElementTypeDef)
+	default:
+		return fmt.Errorf("map values cannot be of kind %s", typeDef.Kind)
+	}
+}
+
+func (typeDef *TypeDef) WithUnion(name, desc string, sourceMap *SourceMap) *TypeDef {
+	typeDef = typeDef.WithKind(TypeDefKindUnion)
+	typeDef.AsUnion = dagql.NonNull(NewUnionTypeDef(name, desc).WithSourceMap(sourceMap))
+	return typeDef
+}
+
+func (typeDef *TypeDef) WithUnionMember(member *TypeDef) (*TypeDef, error) {
+	if !typeDef.AsUnion.Valid {
+		return nil, fmt.Errorf("cannot add member to non-union type: %s", typeDef.Kind)
+	}
+	if member.Kind != TypeDefKindObject {
+		return nil, fmt.Errorf("union members must be objects, got %s", member.Kind)
+	}
+	if member.Optional {
+		return nil, fmt.Errorf("union members cannot be optional")
+	}
+	if _, ok := typeDef.AsUnion.Value.MemberByName(member.AsObject.Value.Name); ok {
+		return nil, fmt.Errorf("union %q already has member %q", typeDef.AsUnion.Value.OriginalName, member.AsObject.Value.OriginalName)
+	}
+
+	typeDef = typeDef.Clone()
+	typeDef.AsUnion.Value.Members = append(typeDef.AsUnion.Value.Members, member)
+	return typeDef, nil
+}
+
 func (typeDef *TypeDef) WithObject(name, desc string, deprecated *string, sourceMap *SourceMap) *TypeDef {
 	typeDef = typeDef.WithKind(TypeDefKindObject)
 	typeDef.AsObject = dagql.NonNull(NewObjectTypeDef(name, desc, deprecated).WithSourceMap(sourceMap))
@@ -785,6 +880,16 @@ func (typeDef *TypeDef) IsSubtypeOf(otherDef *TypeDef) bool {
 			return false
 		}
 		return typeDef.AsInterface.Value.IsSubtypeOf(otherDef.AsInterface.Value)
+	case TypeDefKindMap:
+		if otherDef.Kind != TypeDefKindMap {
+			return false
+		}
+		return typeDef.AsMap.Value.ValueTypeDef.IsSubtypeOf(otherDef.AsMap.Value.ValueTypeDef)
+	case TypeDefKindUnion:
+		if otherDef.Kind != TypeDefKindUnion {
+			return false
+		}
+		return typeDef.AsUnion.Value.Name == otherDef.AsUnion.Value.Name
 	default:
 		return false
 	}
@@ -1121,6 +1226,111 @@ func (typeDef ListTypeDef) Clone() *ListTypeDef {
 	return &cp
 }
 
+type MapTypeDef struct {
+	ValueTypeDef *TypeDef `field:"true" doc:"The type of the values in the map. Keys are always strings."`
+}
+
+func (*MapTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "MapTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*MapTypeDef) TypeDescription() string {
+	return dagql.FormatDescription(
+		`A definition of a map type in a Module.`,
+		`Maps have string keys, and are represented as JSON objects in the GraphQL schema.`)
+}
+
+func (typeDef MapTypeDef) Clone() *MapTypeDef {
+	cp := typeDef
+	if typeDef.ValueTypeDef != nil {
+		cp.ValueTypeDef = typeDef.ValueTypeDef.Clone()
+	}
+	return &cp
+}
+
+type UnionTypeDef struct {
+	// Name is the standardized name of the union (CamelCase), as used for the union in the graphql schema
+	Name        string                     `field:"true" doc:"The name of the union."`
+	Description string                     `field:"true" doc:"A doc string for the union, if any."`
+	Members     []*TypeDef                 `field:"true" doc:"The object types that a value of the union can be."`
+	SourceMap   dagql.Nullable[*SourceMap] `field:"true" doc:"The location of this union declaration."`
+
+	// SourceModuleName is currently only set when returning the TypeDef from the Unions field on Module
+	SourceModuleName string `field:"true" doc:"If this UnionTypeDef is associated with a Module, the name of the module. Unset otherwise."`
+
+	// Below are not in public API
+
+	// The original name of the union as provided by the SDK that defined it, used
+	// when invoking the SDK so it doesn't need to think as hard about case conversions
+	OriginalName string
+}
+
+func NewUnionTypeDef(name, description string) *UnionTypeDef {
+	return &UnionTypeDef{
+		Name:         strcase.ToCamel(name),
+		OriginalName: name,
+		Description:  description,
+	}
+}
+
+func (*UnionTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "UnionTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*UnionTypeDef) TypeDescription() string {
+	return "A definition of a custom union of object types defined in a Module."
+}
+
+func (union UnionTypeDef) Clone() *UnionTypeDef {
+	cp := union
+
+	cp.Members = make([]*TypeDef, len(union.Members))
+	for i, member := range union.Members {
+		cp.Members[i] = member.Clone()
+	}
+	if cp.SourceMap.Valid {
+		cp.SourceMap.Value = cp.SourceMap.Value.Clone()
+	}
+
+	return &cp
+}
+
+func (union *UnionTypeDef) WithSourceMap(sourceMap *SourceMap) *UnionTypeDef {
+	if sourceMap == nil {
+		return union
+	}
+	union = union.Clone()
+	union.SourceMap = dagql.NonNull(sourceMap)
+	return union
+}
+
+// MemberByName returns the member object type with the given GraphQL name.
+func (union *UnionTypeDef) MemberByName(name string) (*ObjectTypeDef, bool) {
+	for _, member := range union.Members {
+		if member.AsObject.Value.Name == name {
+			return member.AsObject.Value, true
+		}
+	}
+	return nil, false
+}
+
+// MemberByOriginalName returns the member object type with the given name, as
+// provided by the SDK that defined it.
+func (union *UnionTypeDef) MemberByOriginalName(name string) (*ObjectTypeDef, bool) {
+	for _, member := range union.Members {
+		if member.AsObject.Value.OriginalName == name {
+			return member.AsObject.Value, true
+		}
+	}
+	return nil, false
+}
+
 type InputTypeDef struct {
 	Name   string          `field:"true" doc:"The name of the input object."`
 	Fields []*FieldTypeDef `field:"true" doc:"Static fields defined on this input object, if any."`
@@ -1373,6 +1583,16 @@ var (
 		"Always paired with an EnumTypeDef.",
 	)
 	_ = TypeDefKinds.AliasView("ENUM", "ENUM_KIND", enumView)
+
+	TypeDefKindMap = TypeDefKinds.Register("MAP_KIND",
+		"A map of string keys to values all having the same type.",
+		"Always paired with a MapTypeDef.",
+	)
+
+	TypeDefKindUnion = TypeDefKinds.Register("UNION_KIND",
+		"A named type whose values can be any one of a set of object types.",
+		"Always paired with a UnionTypeDef.",
+	)
 )
 
 func (k TypeDefKind) Type() *ast.Type {
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dagger/dagger/dagql"
 )
 
@@ -58,6 +60,20 @@ var Samples = map[TypeDefKind]*TypeDef{
 	TypeDefKindVoid: {
 		Kind: TypeDefKindVoid,
 	},
+	TypeDefKindMap: {
+		Kind: TypeDefKindMap,
+		AsMap: dagql.NonNull(&MapTypeDef{
+			ValueTypeDef: &TypeDef{
+				Kind: TypeDefKindString,
+			},
+		}),
+	},
+	TypeDefKindUnion: {
+		Kind: TypeDefKindUnion,
+		AsUnion: dagql.NonNull(&UnionTypeDef{
+			Name: "FooUnion",
+		}),
+	},
 }
 
 func TestTypeDefConversions(t *testing.T) {
@@ -86,3 +102,53 @@ func TestTypeDefConversions(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeDefWithMapOf(t *testing.T) {
+	for _, kind := range []TypeDefKind{
+		TypeDefKindString,
+		TypeDefKindInteger,
+		TypeDefKindFloat,
+		TypeDefKindBoolean,
+		TypeDefKindScalar,
+		TypeDefKindEnum,
+		TypeDefKindList,
+	} {
+		t.Run(kind.String(), func(t *testing.T) {
+			def, err := (&TypeDef{}).WithMapOf(Samples[kind])
+			require.NoError(t, err)
+			require.Equal(t, TypeDefKindMap, def.Kind)
+			require.Equal(t, "JSON!", def.ToType().String())
+		})
+	}
+	for _, kind := range []TypeDefKind{
+		TypeDefKindObject,
+		TypeDefKindInterface,
+		TypeDefKindUnion,
+		TypeDefKindMap,
+	} {
+		t.Run(kind.String(), func(t *testing.T) {
+			_, err := (&TypeDef{}).WithMapOf(Samples[kind])
+			require.ErrorContains(t, err, "map values cannot be of kind "+kind.String())
+		})
+	}
+}
+
+func TestTypeDefWithUnionMember(t *testing.T) {
+	def := (&TypeDef{}).WithUnion("fooOrBar", "", nil)
+	require.Equal(t, "FooOrBar", def.AsUnion.Value.Name)
+
+	def, err := def.WithUnionMember((&TypeDef{}).WithObject("Foo", "", nil, nil))
+	require.NoError(t, err)
+	def, err = def.WithUnionMember((&TypeDef{}).WithObject("Bar", "", nil, nil))
+	require.NoError(t, err)
+	require.Len(t, def.AsUnion.Value.Members, 2)
+	_, ok := def.AsUnion.Value.MemberByName("Bar")
+	require.True(t, ok)
+
+	_, err = def.WithUnionMember((&TypeDef{}).WithObject("Foo", "", nil, nil))
+	require.ErrorContains(t, err, `already has member "Foo"`)
+	_, err = def.WithUnionMember(Samples[TypeDefKindString])
+	require.ErrorContains(t, err, "union members must be objects")
+	_, err = Samples[TypeDefKindString].WithUnionMember(Samples[TypeDefKindObject])
+	require.ErrorContains(t, err, "cannot add member to non-union type")
+}
@@ -0,0 +1,334 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dagger/dagger/internal/buildkit/solver/pb"
+	"github.com/opencontainers/go-digest"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/server/resource"
+	"github.com/dagger/dagger/engine/slog"
+)
+
+type UnionType struct {
+	mod *Module
+
+	// the type def metadata, with namespacing already applied
+	typeDef *UnionTypeDef
+}
+
+var _ ModType = (*UnionType)(nil)
+
+// The keys of the JSON object that SDKs use to represent values of their own
+// unions, tagging the member's value with the name of its type.
+const (
+	unionTypeNameKey = "typeName"
+	unionValueKey    = "value"
+)
+
+// ConvertFromSDKResult converts a value returned by an SDK to a value of the
+// union, failing if it isn't one of the union's members.
+//
+// SDKs return values of their own unions as an object like
+// {"typeName": "Foo", "value": ...}, where the value is in the form expected
+// for the member type. Values obtained from the API are returned as their ID.
+func (union *UnionType) ConvertFromSDKResult(ctx context.Context, value any) (dagql.AnyResult, error) {
+	if value == nil {
+		slog.Warn("UnionType.ConvertFromSDKResult: got nil value")
+		return nil, nil
+	}
+
+	var member dagql.AnyResult
+	switch value := value.(type) {
+	case map[string]any:
+		typeName, ok := value[unionTypeNameKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("union %s value is missing its %q", union.typeDef.Name, unionTypeNameKey)
+		}
+		memberDef, ok := union.typeDef.MemberByOriginalName(typeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a member of union %s", typeName, union.typeDef.Name)
+		}
+		memberType, err := union.memberType(ctx, memberDef)
+		if err != nil {
+			return nil, err
+		}
+		// give the member the ID of selecting it from the union, so that it
+		// can be loaded on its own
+		curID := dagql.CurrentID(ctx)
+		memberID := curID.Append(
+			memberType.TypeDef().ToType(),
+			"as"+memberDef.Name,
+			call.WithView(curID.View()),
+			call.WithModule(curID.Module()),
+		)
+		member, err = memberType.ConvertFromSDKResult(dagql.ContextWithID(ctx, memberID), value[unionValueKey])
+		if err != nil {
+			return nil, fmt.Errorf("convert %s value: %w", typeName, err)
+		}
+		if member == nil {
+			return nil, fmt.Errorf("union %s value is missing its %q", union.typeDef.Name, unionValueKey)
+		}
+	case string:
+		id := new(call.ID)
+		if err := id.Decode(value); err != nil {
+			return nil, fmt.Errorf("decode ID: %w", err)
+		}
+		var err error
+		member, err = union.loadMember(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	case dagql.IDable:
+		var err error
+		member, err = union.loadMember(ctx, value.ID())
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unexpected union value type for conversion from sdk result %T: %+v", value, value)
+	}
+
+	return dagql.NewResultForCurrentID(ctx, &UnionValue{
+		TypeDef: union.typeDef,
+		Member:  member,
+	})
+}
+
+// loadMember loads the object with the given ID, which may either be one of
+// the union's members or a value of the union itself.
+func (union *UnionType) loadMember(ctx context.Context, id *call.ID) (dagql.AnyResult, error) {
+	query, err := CurrentQuery(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("current query: %w", err)
+	}
+	deps, err := query.IDDeps(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	dag, err := deps.Schema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	val, err := dag.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load union ID %s: %w", id.DisplaySelf(), err)
+	}
+
+	if unionVal, ok := val.Unwrap().(*UnionValue); ok {
+		if unionVal.TypeDef.Name != union.typeDef.Name {
+			return nil, fmt.Errorf("value of union %s is not a %s", unionVal.TypeDef.Name, union.typeDef.Name)
+		}
+		return unionVal.Member, nil
+	}
+
+	typeName := val.Type().Name()
+	if _, ok := union.typeDef.MemberByName(typeName); !ok {
+		return nil, fmt.Errorf("type %s is not a member of union %s", typeName, union.typeDef.Name)
+	}
+	return val, nil
+}
+
+// memberType returns the ModType of one of the union's members.
+func (union *UnionType) memberType(ctx context.Context, member *ObjectTypeDef) (ModType, error) {
+	memberType, ok, err := union.mod.ModTypeFor(ctx, &TypeDef{
+		Kind:     TypeDefKindObject,
+		AsObject: dagql.NonNull(member),
+	}, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mod type for union member %q: %w", member.Name, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("could not find mod type for union member %q", member.Name)
+	}
+	return memberType, nil
+}
+
+// ConvertToSDKInput converts the ID of a union value or any of its members to
+// the tagged form described in ConvertFromSDKResult.
+func (union *UnionType) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch value := value.(type) {
+	case DynamicID:
+		member, err := union.loadMember(ctx, value.ID())
+		if err != nil {
+			return nil, err
+		}
+		memberDef, ok := union.typeDef.MemberByName(member.Type().Name())
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a member of union %s", member.Type().Name(), union.typeDef.Name)
+		}
+		memberType, err := union.memberType(ctx, memberDef)
+		if err != nil {
+			return nil, err
+		}
+		memberVal, err := memberType.ConvertToSDKInput(ctx, DynamicID{
+			typeName: memberDef.Name,
+			id:       member.ID(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("convert %s value: %w", memberDef.Name, err)
+		}
+		return map[string]any{
+			unionTypeNameKey: memberDef.OriginalName,
+			unionValueKey:    memberVal,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unexpected union value type for conversion to sdk input %T", value)
+	}
+}
+
+func (union *UnionType) CollectCoreIDs(ctx context.Context, value dagql.AnyResult, ids map[digest.Digest]*resource.ID) error {
+	if value == nil {
+		return nil
+	}
+	unionVal, ok := value.Unwrap().(*UnionValue)
+	if !ok {
+		return fmt.Errorf("unexpected union value type for collecting IDs %T", value.Unwrap())
+	}
+	if unionVal.Member == nil {
+		return nil
+	}
+	member, ok := union.typeDef.MemberByName(unionVal.Member.Type().Name())
+	if !ok {
+		return fmt.Errorf("type %s is not a member of union %s", unionVal.Member.Type().Name(), union.typeDef.Name)
+	}
+	memberType, err := union.memberType(ctx, member)
+	if err != nil {
+		return err
+	}
+	ctx = dagql.ContextWithID(ctx, unionVal.Member.ID())
+	return memberType.CollectCoreIDs(ctx, unionVal.Member, ids)
+}
+
+func (union *UnionType) SourceMod() Mod {
+	return union.mod
+}
+
+func (union *UnionType) TypeDef() *TypeDef {
+	return &TypeDef{
+		Kind:    TypeDefKindUnion,
+		AsUnion: dagql.NonNull(union.typeDef.Clone()),
+	}
+}
+
+func (union *UnionType) Install(ctx context.Context, dag *dagql.Server) error {
+	slog.ExtraDebug("installing union", "union", union.typeDef.Name)
+
+	if union.mod.ResultID == nil {
+		return fmt.Errorf("installing union %q too early", union.typeDef.Name)
+	}
+	class := dagql.NewClass(dag, dagql.ClassOpts[*UnionValue]{
+		Typed: &UnionValue{
+			TypeDef: union.typeDef,
+		},
+	})
+
+	unionName := union.typeDef.Name
+	fields := []dagql.Field[*UnionValue]{
+		{
+			Spec: &dagql.FieldSpec{
+				Name:        "typeName",
+				Description: "The name of the object type of this value.",
+				Type:        dagql.String(""),
+				Module:      union.mod.IDModule(),
+			},
+			Func: func(ctx context.Context, self dagql.ObjectResult[*UnionValue], _ map[string]dagql.Input, _ call.View) (dagql.AnyResult, error) {
+				return dagql.NewResultForCurrentID(ctx, dagql.String(self.Self().Member.Type().Name()))
+			},
+		},
+	}
+	for _, member := range union.typeDef.Members {
+		memberName := member.AsObject.Value.Name
+		fields = append(fields, dagql.Field[*UnionValue]{
+			Spec: &dagql.FieldSpec{
+				Name:        "as" + memberName,
+				Description: fmt.Sprintf("Retrieve the value as a %s, failing if it is another type.", memberName),
+				Type:        member.ToTyped(),
+				Module:      union.mod.IDModule(),
+			},
+			Func: func(ctx context.Context, self dagql.ObjectResult[*UnionValue], _ map[string]dagql.Input, _ call.View) (dagql.AnyResult, error) {
+				val := self.Self().Member
+				if typeName := val.Type().Name(); typeName != memberName {
+					return nil, fmt.Errorf("%s value is a %s, not a %s", unionName, typeName, memberName)
+				}
+				return val, nil
+			},
+		})
+	}
+
+	class.Install(fields...)
+	dag.InstallObject(class)
+
+	idScalar := DynamicID{
+		typeName: unionName,
+	}
+
+	// override loadFooFromID to allow the ID of any member of this union
+	dag.Root().ObjectType().Extend(
+		dagql.FieldSpec{
+			Name:        fmt.Sprintf("load%sFromID", class.TypeName()),
+			Description: fmt.Sprintf("Load a %s from its ID, or the ID of any of its members.", class.TypeName()),
+			Type:        class.Typed(),
+			Args: dagql.NewInputSpecs(
+				dagql.InputSpec{
+					Name: "id",
+					Type: idScalar,
+				},
+			),
+			Module:     union.mod.IDModule(),
+			DoNotCache: "There's no point caching the loading call of an ID vs. letting the ID's calls cache on their own.",
+		},
+		func(ctx context.Context, self dagql.AnyResult, args map[string]dagql.Input) (dagql.AnyResult, error) {
+			return union.ConvertFromSDKResult(ctx, args["id"])
+		},
+	)
+
+	return nil
+}
+
+// UnionValue is a value of a union type, which is always one of the union's
+// member objects.
+type UnionValue struct {
+	TypeDef *UnionTypeDef
+	Member  dagql.AnyResult
+}
+
+var _ dagql.Typed = (*UnionValue)(nil)
+
+func (union *UnionValue) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: union.TypeDef.Name,
+		NonNull:   true,
+	}
+}
+
+func (union *UnionValue) TypeDescription() string {
+	return formatGqlDescription(union.TypeDef.Description)
+}
+
+func (union *UnionValue) TypeDefinition(view call.View) *ast.Definition {
+	def := &ast.Definition{
+		Kind: ast.Object,
+		Name: union.Type().Name(),
+	}
+	if union.TypeDef.SourceMap.Valid {
+		def.Directives = append(def.Directives, union.TypeDef.SourceMap.Value.TypeDirective())
+	}
+	return def
+}
+
+var _ HasPBDefinitions = (*UnionValue)(nil)
+
+func (union *UnionValue) PBDefinitions(ctx context.Context) ([]*pb.Definition, error) {
+	if union.Member == nil {
+		return nil, nil
+	}
+	return collectPBDefinitions(ctx, union.Member.Unwrap())
+}
@@ -0,0 +1,167 @@
+package dagql
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/sourcegraph/conc/pool"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// maxBatchSize is the maximum number of operations in a batched request.
+	maxBatchSize = 1000
+
+	// batchContentType is the content type of the response to a batched
+	// request: a line of JSON for each operation.
+	batchContentType = "application/x-ndjson"
+)
+
+// batchConcurrency is the maximum number of operations of a batched request
+// executed at once, so that a large batch can't flood the server. Operations
+// mostly wait on the engine, so a few run at once even with a single CPU.
+var batchConcurrency = max(runtime.GOMAXPROCS(0), 4)
+
+// BatchPOST is a transport for POST requests with a JSON array of operations
+// in the body, instead of a single one.
+//
+// The operations are executed concurrently against the same server, up to
+// batchConcurrency at once, so they share its cache, and the response to each
+// one is streamed back as soon as it's ready, in the order they finish. This
+// saves round trips for clients that resolve many independent operations at
+// once, which is especially noticeable when the engine is remote.
+//
+// Each line of the response is a BatchResponse, with the index of its
+// operation in the request.
+type BatchPOST struct{}
+
+var _ graphql.Transport = BatchPOST{}
+
+// BatchResponse is the response to an operation in a batched request.
+type BatchResponse struct {
+	// Index is the position of the operation in the request.
+	Index int `json:"index"`
+
+	*graphql.Response
+}
+
+// Supports returns true for POST requests with a JSON array in the body.
+//
+// The body is buffered, so it can still be read by other transports.
+func (BatchPOST) Supports(r *http.Request) bool {
+	if r.Method != http.MethodPost || r.Header.Get("Upgrade") != "" || r.Body == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return false
+	}
+
+	br := bufio.NewReader(r.Body)
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, r.Body}
+
+	for i := 1; ; i++ {
+		b, err := br.Peek(i)
+		if err != nil {
+			return false
+		}
+		switch c := b[i-1]; c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		default:
+			return c == '['
+		}
+	}
+}
+
+func (BatchPOST) Do(w http.ResponseWriter, r *http.Request, exec graphql.GraphExecutor) {
+	ctx := r.Context()
+	start := graphql.Now()
+
+	var batch []*graphql.RawParams
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&batch); err != nil {
+		writeBatchError(w, exec, r, "json request body could not be decoded: %s", err)
+		return
+	}
+	if len(batch) > maxBatchSize {
+		writeBatchError(w, exec, r, "too many operations in batch: %d > %d", len(batch), maxBatchSize)
+		return
+	}
+	readTime := graphql.TraceTiming{
+		Start: start,
+		End:   graphql.Now(),
+	}
+
+	w.Header().Set("Content-Type", batchContentType)
+	w.WriteHeader(http.StatusOK)
+
+	var mu sync.Mutex
+	flusher, _ := w.(http.Flusher)
+	write := func(i int, res *graphql.Response) {
+		b, err := json.Marshal(BatchResponse{Index: i, Response: res})
+		if err != nil {
+			b, _ = json.Marshal(BatchResponse{Index: i, Response: &graphql.Response{
+				Errors: gqlerror.List{gqlerror.Errorf("marshal: %s", err)},
+			}})
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(append(b, '\n'))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	p := pool.New().WithMaxGoroutines(batchConcurrency)
+	for i, params := range batch {
+		p.Go(func() {
+			if params == nil {
+				write(i, exec.DispatchError(ctx, gqlerror.List{gqlerror.Errorf("missing operation")}))
+				return
+			}
+			params.Headers = r.Header
+			params.ReadTime = readTime
+
+			rc, errs := exec.CreateOperationContext(ctx, params)
+			if errs != nil {
+				write(i, exec.DispatchError(graphql.WithOperationContext(ctx, rc), errs))
+				return
+			}
+			if rc.Operation != nil && rc.Operation.Operation == ast.Subscription {
+				write(i, exec.DispatchError(graphql.WithOperationContext(ctx, rc), gqlerror.List{
+					gqlerror.Errorf("subscriptions cannot be batched"),
+				}))
+				return
+			}
+			responses, ctx := exec.DispatchOperation(ctx, rc)
+			write(i, responses(ctx))
+		})
+	}
+	p.Wait()
+}
+
+// writeBatchError responds to a batched request that couldn't be decoded
+// with a single error, like other transports.
+func writeBatchError(w http.ResponseWriter, exec graphql.GraphExecutor, r *http.Request, format string, args ...any) {
+	res := exec.DispatchError(r.Context(), gqlerror.List{gqlerror.Errorf(format, args...)})
+	b, err := json.Marshal(res)
+	if err != nil {
+		panic(fmt.Errorf("marshal error response: %w", err))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(b)
+}
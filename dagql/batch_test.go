@@ -0,0 +1,68 @@
+package dagql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// concurrencyExecutor is a graphql.GraphExecutor recording how many operations
+// run at once.
+type concurrencyExecutor struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (e *concurrencyExecutor) CreateOperationContext(ctx context.Context, params *graphql.RawParams) (*graphql.OperationContext, gqlerror.List) {
+	return &graphql.OperationContext{RawQuery: params.Query}, nil
+}
+
+func (e *concurrencyExecutor) DispatchOperation(ctx context.Context, opCtx *graphql.OperationContext) (graphql.ResponseHandler, context.Context) {
+	return func(ctx context.Context) *graphql.Response {
+		e.mu.Lock()
+		e.running++
+		e.peak = max(e.peak, e.running)
+		e.mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		e.mu.Lock()
+		e.running--
+		e.mu.Unlock()
+		return &graphql.Response{Data: json.RawMessage(`{}`)}
+	}, ctx
+}
+
+func (e *concurrencyExecutor) DispatchError(ctx context.Context, list gqlerror.List) *graphql.Response {
+	return &graphql.Response{Errors: list}
+}
+
+func TestBatchConcurrency(t *testing.T) {
+	size := batchConcurrency * 10
+	ops := make([]string, size)
+	for i := range ops {
+		ops[i] = fmt.Sprintf(`{"query":"query { op%d }"}`, i)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader("["+strings.Join(ops, ",")+"]"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	exec := &concurrencyExecutor{}
+	BatchPOST{}.Do(w, req, exec)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, strings.Split(strings.TrimSpace(w.Body.String()), "\n"), size)
+	require.LessOrEqual(t, exec.peak, batchConcurrency)
+	require.Greater(t, exec.peak, 1, "operations should still run concurrently")
+}
@@ -0,0 +1,216 @@
+package dagql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/cache"
+	"github.com/dagger/dagger/engine/slog"
+	"github.com/dagger/dagger/util/hashutil"
+)
+
+// CacheKeyInput is something that went into a call's cache key, recorded so
+// that cache misses can be explained by comparing against a previous call.
+type CacheKeyInput struct {
+	// A description of the input, e.g. `argument "args"` or "client ID".
+	Name string `json:"name"`
+
+	// The digest of the input.
+	Digest string `json:"digest"`
+
+	// A human-readable form of the input, if it's safe to show.
+	Value string `json:"value,omitempty"`
+}
+
+type cacheKeyInputsKey struct{}
+
+type cacheKeyInputs struct {
+	mu     sync.Mutex
+	inputs []CacheKeyInput
+}
+
+func withCacheKeyInputs(ctx context.Context) (context.Context, *cacheKeyInputs) {
+	inputs := &cacheKeyInputs{}
+	return context.WithValue(ctx, cacheKeyInputsKey{}, inputs), inputs
+}
+
+// RecordCacheKeyInput records a value that a GetCacheConfig func mixed into a
+// call's cache key, so that a cache miss caused by it can be explained.
+//
+// It does nothing unless cache misses are being explained.
+func RecordCacheKeyInput(ctx context.Context, name, value string) {
+	inputs, ok := ctx.Value(cacheKeyInputsKey{}).(*cacheKeyInputs)
+	if !ok {
+		return
+	}
+	inputs.mu.Lock()
+	defer inputs.mu.Unlock()
+	inputs.inputs = append(inputs.inputs, CacheKeyInput{
+		Name:   name,
+		Digest: hashutil.HashStrings(value).String(),
+		Value:  value,
+	})
+}
+
+func (inputs *cacheKeyInputs) recorded() []CacheKeyInput {
+	if inputs == nil {
+		return nil
+	}
+	inputs.mu.Lock()
+	defer inputs.mu.Unlock()
+	return inputs.inputs
+}
+
+// callKeyInputs returns everything that went into the cache key of a call to
+// the given ID, in the order they should be blamed for a cache miss: the
+// receiver comes last, since if it changed, its own miss is more interesting.
+func callKeyInputs(id *call.ID, recorded []CacheKeyInput) []CacheKeyInput {
+	var inputs []CacheKeyInput
+	if mod := id.Call().Module; mod != nil {
+		val := mod.Ref
+		if mod.Pin != "" {
+			val += "@" + mod.Pin
+		}
+		inputs = append(inputs, CacheKeyInput{
+			Name:   "module",
+			Digest: mod.CallDigest,
+			Value:  val,
+		})
+	}
+	for _, arg := range id.Args() {
+		input := CacheKeyInput{
+			Name: fmt.Sprintf("argument %q", arg.Name()),
+		}
+		h, err := call.AppendArgumentBytes(arg.PB(), hashutil.NewHasher())
+		if err != nil {
+			slog.Warn("failed to digest argument", "arg", arg.Name(), "err", err)
+			continue
+		}
+		input.Digest = h.DigestAndClose()
+		if !arg.IsSensitive() {
+			input.Value = arg.Value().Display()
+		}
+		inputs = append(inputs, input)
+	}
+	inputs = append(inputs, recorded...)
+	if id.HasCustomDigest() {
+		// the key may have been overridden in a way that wasn't recorded above,
+		// e.g. with a content hash
+		inputs = append(inputs, CacheKeyInput{
+			Name:   "custom cache key",
+			Digest: string(id.Digest()),
+		})
+	}
+	if recv := id.Receiver(); recv != nil {
+		inputs = append(inputs, CacheKeyInput{
+			Name:   "receiver",
+			Digest: string(recv.Digest()),
+			Value:  recv.Path(),
+		})
+	}
+	return inputs
+}
+
+// callPosition identifies a call by the chain of fields leading up to it,
+// ignoring arguments, so that it can be matched up with the same call in a
+// previous run even if its arguments changed.
+func callPosition(id *call.ID) string {
+	h := hashutil.NewHasher()
+	for ; id != nil; id = id.Receiver() {
+		h = h.WithString(id.Field()).
+			WithInt64(id.Nth()).
+			WithString(string(id.View()))
+		if mod := id.Module(); mod != nil {
+			h = h.WithString(mod.Name())
+		}
+		h = h.WithDelim()
+	}
+	return h.DigestAndClose()
+}
+
+// explainCacheMiss explains why a call missed the cache by comparing its
+// inputs with the most similar of the previous calls at the same position.
+func explainCacheMiss(callKey string, inputs []CacheKeyInput, prevs []cache.CallInputs) string {
+	var closest []CacheKeyInput
+	closestScore := -1
+	for _, prev := range prevs {
+		if prev.CallKey == callKey {
+			return "no inputs changed since the last call; its result was pruned or expired"
+		}
+		var prevInputs []CacheKeyInput
+		if err := json.Unmarshal(prev.Inputs, &prevInputs); err != nil {
+			slog.Warn("failed to decode previous cache key inputs", "err", err)
+			continue
+		}
+		// prefer the call with the most inputs in common, so that sibling calls
+		// at the same position are compared against each other
+		score := 0
+		for _, input := range inputs {
+			for _, prevInput := range prevInputs {
+				if prevInput == input {
+					score++
+					break
+				}
+			}
+		}
+		if score > closestScore {
+			closest = prevInputs
+			closestScore = score
+		}
+	}
+	if closest == nil {
+		return "no previous call recorded"
+	}
+
+	prevByName := make(map[string]CacheKeyInput, len(closest))
+	for _, prev := range closest {
+		prevByName[prev.Name] = prev
+	}
+	for _, input := range inputs {
+		prev, found := prevByName[input.Name]
+		switch {
+		case !found:
+			return fmt.Sprintf("%s was added: %s", input.Name, describeCacheKeyInput(input))
+		case prev.Digest == input.Digest:
+			continue
+		case prev.Value != input.Value || input.Value == "":
+			return fmt.Sprintf("%s changed: %s → %s", input.Name,
+				describeCacheKeyInput(prev),
+				describeCacheKeyInput(input))
+		default:
+			// same value, but different digest, e.g. an object whose content
+			// changed
+			return fmt.Sprintf("%s changed: %s (digest %s → %s)", input.Name,
+				describeCacheKeyInput(input),
+				prev.Digest,
+				input.Digest)
+		}
+	}
+	names := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		names[input.Name] = true
+	}
+	for _, prev := range closest {
+		if !names[prev.Name] {
+			return fmt.Sprintf("%s was removed: %s", prev.Name, describeCacheKeyInput(prev))
+		}
+	}
+	return "cache key changed, but none of its recorded inputs did"
+}
+
+// The maximum length of an input's value to show before eliding the rest.
+const maxCacheKeyInputLen = 80
+
+func describeCacheKeyInput(input CacheKeyInput) string {
+	if input.Value == "" {
+		return input.Digest
+	}
+	val := []rune(input.Value)
+	if len(val) > maxCacheKeyInputLen {
+		return string(val[:maxCacheKeyInputLen]) + "…"
+	}
+	return string(val)
+}
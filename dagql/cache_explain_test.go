@@ -0,0 +1,151 @@
+package dagql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/engine/cache"
+)
+
+func TestExplainCacheMiss(t *testing.T) {
+	prev := []CacheKeyInput{
+		{Name: `argument "args"`, Digest: "a1", Value: `["go", "test"]`},
+		{Name: `argument "source"`, Digest: "s1", Value: "host.directory"},
+		{Name: "receiver", Digest: "r1", Value: "container.from"},
+	}
+	sibling := []CacheKeyInput{
+		{Name: `argument "args"`, Digest: "a2", Value: `["go", "vet"]`},
+		{Name: `argument "source"`, Digest: "s1", Value: "host.directory"},
+		{Name: "receiver", Digest: "r1", Value: "container.from"},
+	}
+	prevs := func(inputs ...[]CacheKeyInput) []cache.CallInputs {
+		var calls []cache.CallInputs
+		for i, in := range inputs {
+			payload, err := json.Marshal(in)
+			require.NoError(t, err)
+			calls = append(calls, cache.CallInputs{
+				CallKey: string(rune('a' + i)),
+				Inputs:  payload,
+			})
+		}
+		return calls
+	}
+
+	for _, tc := range []struct {
+		name   string
+		inputs []CacheKeyInput
+		prevs  []cache.CallInputs
+		expect string
+	}{
+		{
+			name:   "no previous call",
+			inputs: prev,
+			expect: "no previous call recorded",
+		},
+		{
+			name:   "same key",
+			inputs: prev,
+			prevs:  []cache.CallInputs{{CallKey: "key"}},
+			expect: "no inputs changed since the last call; its result was pruned or expired",
+		},
+		{
+			name: "argument changed",
+			inputs: []CacheKeyInput{
+				{Name: `argument "args"`, Digest: "a3", Value: `["go", "test", "-v"]`},
+				{Name: `argument "source"`, Digest: "s1", Value: "host.directory"},
+				{Name: "receiver", Digest: "r1", Value: "container.from"},
+			},
+			prevs:  prevs(prev),
+			expect: `argument "args" changed: ["go", "test"] → ["go", "test", "-v"]`,
+		},
+		{
+			// compared against prev rather than the more recent sibling, since
+			// it has more inputs in common
+			name: "content changed",
+			inputs: []CacheKeyInput{
+				{Name: `argument "args"`, Digest: "a1", Value: `["go", "test"]`},
+				{Name: `argument "source"`, Digest: "s2", Value: "host.directory"},
+				{Name: "receiver", Digest: "r1", Value: "container.from"},
+			},
+			prevs:  prevs(sibling, prev),
+			expect: `argument "source" changed: host.directory (digest s1 → s2)`,
+		},
+		{
+			name: "input added",
+			inputs: append([]CacheKeyInput{
+				{Name: "client ID", Digest: "c1", Value: "client"},
+			}, prev...),
+			prevs:  prevs(prev),
+			expect: "client ID was added: client",
+		},
+		{
+			name:   "input removed",
+			inputs: prev[1:],
+			prevs:  prevs(prev),
+			expect: `argument "args" was removed: ["go", "test"]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, explainCacheMiss("key", tc.inputs, tc.prevs))
+		})
+	}
+}
+
+func TestSessionCacheExplainMisses(t *testing.T) {
+	ctx := t.Context()
+
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+	withTelemetry := WithTelemetry(func(ctx context.Context) (context.Context, func(AnyResult, bool, *error)) {
+		ctx, span := tracer.Start(ctx, "call")
+		return ctx, func(AnyResult, bool, *error) {
+			span.End()
+		}
+	})
+
+	c, err := cache.NewCache[string, AnyResult](ctx, "")
+	require.NoError(t, err)
+
+	callWithArg := func(scope, callKey, val string) {
+		sc := NewSessionCache(c)
+		sc.ExplainMisses(scope)
+		_, err := sc.GetOrInitializeValue(ctx, CacheKey{CallKey: callKey}, nil,
+			withTelemetry,
+			WithCacheKeyInputs("position", []CacheKeyInput{
+				{Name: `argument "val"`, Digest: val, Value: val},
+			}))
+		require.NoError(t, err)
+	}
+	reasons := func() []string {
+		var reasons []string
+		for _, span := range spans.Ended() {
+			var reason string
+			for _, attr := range span.Attributes() {
+				if attr.Key == telemetry.DagCacheMissReasonAttr {
+					reason = attr.Value.AsString()
+				}
+			}
+			reasons = append(reasons, reason)
+		}
+		return reasons
+	}
+
+	callWithArg("me", "1", "foo")
+	callWithArg("me", "2", "bar")
+	// a cache hit doesn't need explaining
+	callWithArg("me", "2", "bar")
+	// calls recorded by someone else are never compared against
+	callWithArg("someone else", "3", "baz")
+	require.Equal(t, []string{
+		"no previous call recorded",
+		`argument "val" changed: foo → bar`,
+		"",
+		"no previous call recorded",
+	}, reasons())
+}
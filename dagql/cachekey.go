@@ -36,6 +36,7 @@ func CachePerClientObject[A any](
 	if clientMD.ClientID == "" {
 		return nil, fmt.Errorf("client ID not found in context")
 	}
+	RecordCacheKeyInput(ctx, "client ID", clientMD.ClientID)
 
 	resp := &GetCacheConfigResponse{
 		CacheKey: req.CacheKey,
@@ -69,6 +70,7 @@ func CachePerSessionObject[A any](
 	if clientMD.SessionID == "" {
 		return nil, fmt.Errorf("session ID not found in context")
 	}
+	RecordCacheKeyInput(ctx, "session ID", clientMD.SessionID)
 
 	resp := &GetCacheConfigResponse{
 		CacheKey: req.CacheKey,
@@ -107,12 +109,13 @@ func CacheAsRequested[T Typed, A CacheControllableArgs](ctx context.Context, i O
 // should always run but if the returned object is passed around it should continue to be that snapshot rather than the API
 // always re-running.
 func CachePerCall[P Typed, A any](
-	_ context.Context,
+	ctx context.Context,
 	_ ObjectResult[P],
 	_ A,
 	req GetCacheConfigRequest,
 ) (*GetCacheConfigResponse, error) {
 	randID := identity.NewID()
+	RecordCacheKeyInput(ctx, "per-call key", randID)
 	resp := &GetCacheConfigResponse{CacheKey: req.CacheKey}
 	resp.CacheKey.CallKey = randID
 	return resp, nil
@@ -130,6 +133,8 @@ func CachePerSchema[P Typed, A any](srv *Server) func(context.Context, ObjectRes
 		_ A,
 		req GetCacheConfigRequest,
 	) (*GetCacheConfigResponse, error) {
+		RecordCacheKeyInput(ctx, "schema", srv.SchemaDigest().String())
+
 		resp := &GetCacheConfigResponse{CacheKey: req.CacheKey}
 		resp.CacheKey.CallKey = hashutil.HashStrings(
 			resp.CacheKey.CallKey,
@@ -158,6 +163,8 @@ func CachePerClientSchema[P Typed, A any](srv *Server) func(context.Context, Obj
 		if clientMD.ClientID == "" {
 			return nil, fmt.Errorf("client ID not found in context")
 		}
+		RecordCacheKeyInput(ctx, "schema", srv.SchemaDigest().String())
+		RecordCacheKeyInput(ctx, "client ID", clientMD.ClientID)
 
 		resp := &GetCacheConfigResponse{CacheKey: req.CacheKey}
 		resp.CacheKey.CallKey = hashutil.HashStrings(
@@ -0,0 +1,297 @@
+package dagql
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql/call"
+)
+
+// Connection is a page of the elements of a list, following the GraphQL cursor
+// connections convention.
+//
+// Connections are selected through the "<field>Connection" field installed
+// alongside each Paginated field, which accepts the same arguments as the
+// field, along with first and after arguments selecting the page.
+//
+// The paginated field is resolved in full, and its result is cached like any
+// other call, so selecting the next page doesn't resolve it again. Only the
+// elements of the page are turned into results and sent back though, so
+// clients can iterate over huge lists without building giant responses.
+type Connection struct {
+	// elem is the type of the list's elements.
+	elem *ast.Type
+
+	// list is the result of the paginated field.
+	list AnyResult
+	// offset is the number of elements before the page.
+	offset int
+	// count is the number of elements in the page.
+	count int
+}
+
+var _ Typed = (*Connection)(nil)
+
+func (conn *Connection) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: conn.elem.Name() + "Connection",
+		NonNull:   true,
+	}
+}
+
+var _ Descriptive = (*Connection)(nil)
+
+func (conn *Connection) TypeDescription() string {
+	return fmt.Sprintf("A page of a list of %s, selected by a cursor.", conn.elem.Name())
+}
+
+// Total returns the number of elements in the whole list.
+func (conn *Connection) Total() int {
+	if conn.list == nil {
+		return 0
+	}
+	enum, ok := conn.list.Unwrap().(Enumerable)
+	if !ok {
+		return 0
+	}
+	return enum.Len()
+}
+
+// EndCursor returns the cursor of the last element of the page, if any.
+func (conn *Connection) EndCursor() (string, bool) {
+	if conn.count == 0 {
+		return "", false
+	}
+	return encodeCursor(conn.offset + conn.count), true
+}
+
+// HasNextPage returns true if there are elements after the page.
+func (conn *Connection) HasNextPage() bool {
+	return conn.offset+conn.count < conn.Total()
+}
+
+const cursorPrefix = "cursor:"
+
+// encodeCursor returns an opaque cursor pointing to the nth element of a
+// list, with 1 representing the first element.
+func encodeCursor(nth int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(nth)))
+}
+
+// decodeCursor returns the position of the element the cursor points to.
+func decodeCursor(cursor string) (int, error) {
+	payload, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+	nth, ok := strings.CutPrefix(string(payload), cursorPrefix)
+	if !ok {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	n, err := strconv.Atoi(nth)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return n, nil
+}
+
+// connectionNodes is the list of elements in a Connection's page.
+//
+// Elements are loaded lazily from the underlying list, and keep the IDs they
+// have in it, so they don't depend on the page they were selected from.
+type connectionNodes struct {
+	conn     *Connection
+	listType *ast.Type
+}
+
+var _ Typed = connectionNodes{}
+
+func (nodes connectionNodes) Type() *ast.Type {
+	return nodes.listType
+}
+
+var _ Enumerable = connectionNodes{}
+
+func (nodes connectionNodes) Element() Typed {
+	return nodes.enum().Element()
+}
+
+func (nodes connectionNodes) Len() int {
+	return nodes.conn.count
+}
+
+func (nodes connectionNodes) Nth(i int) (Typed, error) {
+	if i < 1 || i > nodes.conn.count {
+		return nil, fmt.Errorf("index %d out of bounds", i)
+	}
+	return nodes.enum().Nth(nodes.conn.offset + i)
+}
+
+func (nodes connectionNodes) NthValue(i int, _ *call.ID) (AnyResult, error) {
+	if i < 1 || i > nodes.conn.count {
+		return nil, fmt.Errorf("index %d out of bounds", i)
+	}
+	return nodes.conn.list.NthValue(nodes.conn.offset + i)
+}
+
+func (nodes connectionNodes) enum() Enumerable {
+	return nodes.conn.list.Unwrap().(Enumerable)
+}
+
+// connectionArgs are the arguments added to a connection field.
+var connectionArgs = []InputSpec{
+	{
+		Name:        "first",
+		Description: "The maximum number of elements to return. All remaining elements are returned if unset.",
+		Type:        Optional[Int]{},
+	},
+	{
+		Name:        "after",
+		Description: "Only return elements after the given cursor, as returned by endCursor.",
+		Type:        Optional[String]{},
+	},
+}
+
+// connectionField returns the "<field>Connection" field paginating the given
+// list field, installing its Connection type if needed.
+func (class Class[T]) connectionField(spec *FieldSpec) (Field[T], error) {
+	listType := spec.Type.Type()
+	if listType.Elem == nil || listType.Elem.Elem != nil {
+		return Field[T]{}, fmt.Errorf("field %q must return a list of non-list elements to be paginated", spec.Name)
+	}
+
+	conn := &Connection{elem: listType.Elem}
+	if class.installConnection != nil {
+		class.installConnection(conn, listType)
+	}
+
+	args := InputSpecs{raw: slices.Clone(spec.Args.raw)}
+	args.Add(connectionArgs...)
+
+	return Field[T]{
+		Spec: &FieldSpec{
+			Name:               spec.Name + "Connection",
+			Description:        fmt.Sprintf("A page of the elements returned by %s, selected by a cursor.", spec.Name),
+			Args:               args,
+			Type:               conn,
+			DeprecatedReason:   spec.DeprecatedReason,
+			ExperimentalReason: spec.ExperimentalReason,
+			Module:             spec.Module,
+			ViewFilter:         spec.ViewFilter,
+			DoNotCache:         spec.DoNotCache,
+			TTL:                spec.TTL,
+		},
+		Func: func(ctx context.Context, self ObjectResult[T], args map[string]Input, view call.View) (AnyResult, error) {
+			list, err := selectPaginated(ctx, self, spec, args, view)
+			if err != nil {
+				return nil, err
+			}
+			page := &Connection{
+				elem: conn.elem,
+				list: list,
+			}
+
+			total := page.Total()
+			if after, ok := args["after"].(Optional[String]); ok && after.Valid {
+				offset, err := decodeCursor(after.Value.String())
+				if err != nil {
+					return nil, err
+				}
+				page.offset = min(offset, total)
+			}
+			page.count = total - page.offset
+			if first, ok := args["first"].(Optional[Int]); ok && first.Valid {
+				if first.Value < 0 {
+					return nil, fmt.Errorf("first must not be negative, got %d", first.Value)
+				}
+				page.count = min(page.count, first.Value.Int())
+			}
+
+			return NewResultForCurrentID(ctx, page)
+		},
+	}, nil
+}
+
+// selectPaginated selects the list paginated by a connection, with the
+// arguments explicitly passed to the connection field.
+func selectPaginated(ctx context.Context, self AnyObjectResult, spec *FieldSpec, args map[string]Input, view call.View) (AnyResult, error) {
+	srv := CurrentDagqlServer(ctx)
+	if srv == nil {
+		return nil, fmt.Errorf("no server in context to select %q", spec.Name)
+	}
+
+	sel := Selector{Field: spec.Name}
+	if spec.ViewFilter != nil {
+		sel.View = view
+	}
+	if id := CurrentID(ctx); id != nil {
+		for _, arg := range id.Args() {
+			if _, ok := spec.Args.Input(arg.Name(), view); !ok {
+				continue
+			}
+			sel.Args = append(sel.Args, NamedInput{
+				Name:  arg.Name(),
+				Value: args[arg.Name()],
+			})
+		}
+	}
+
+	var list AnyResult
+	if err := srv.Select(ctx, self, &list, sel); err != nil {
+		return nil, err
+	}
+	if list != nil {
+		if _, ok := list.Unwrap().(Enumerable); !ok {
+			return nil, fmt.Errorf("cannot paginate non-Enumerable %T", list.Unwrap())
+		}
+	}
+	return list, nil
+}
+
+// installConnection installs the object type of the given Connection, unless
+// it's already installed.
+func (s *Server) installConnection(conn *Connection, listType *ast.Type) {
+	if _, ok := s.ObjectType(conn.Type().Name()); ok {
+		return
+	}
+
+	class := NewClass(s, ClassOpts[*Connection]{
+		Typed: conn,
+	})
+	class.Install(
+		Field[*Connection]{
+			Spec: &FieldSpec{
+				Name:        "nodes",
+				Description: "The elements of the page.",
+				Type:        connectionNodes{listType: listType},
+			},
+			Func: func(ctx context.Context, self ObjectResult[*Connection], _ map[string]Input, _ call.View) (AnyResult, error) {
+				return NewResultForCurrentID(ctx, connectionNodes{
+					conn:     self.Self(),
+					listType: listType,
+				})
+			},
+		},
+		Func("endCursor", func(ctx context.Context, self *Connection, _ struct{}) (Nullable[String], error) {
+			cursor, ok := self.EndCursor()
+			if !ok {
+				return Null[String](), nil
+			}
+			return NonNull(NewString(cursor)), nil
+		}).Doc(`The cursor of the last element of the page, to pass as "after" to select the next page.`,
+			`Null if the page is empty.`),
+		Func("hasNextPage", func(ctx context.Context, self *Connection, _ struct{}) (Boolean, error) {
+			return NewBoolean(self.HasNextPage()), nil
+		}).Doc(`Whether there are elements after this page.`),
+		Func("totalCount", func(ctx context.Context, self *Connection, _ struct{}) (Int, error) {
+			return NewInt(self.Total()), nil
+		}).Doc(`The number of elements in the whole list.`),
+	)
+	s.InstallObject(class)
+}
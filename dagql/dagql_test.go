@@ -4,14 +4,21 @@ import (
 	"bytes"
 	"context"
 	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -2905,3 +2912,476 @@ func TestInstallHooks(t *testing.T) {
 	require.Equal(t, 200, res.OtherPoint.Y)
 	require.Equal(t, "hello world!", res.OtherPoint.Hello)
 }
+
+func TestSubscriptions(t *testing.T) {
+	srv := dagql.NewServer(Query{}, newCache(t))
+	points.Install[Query](srv)
+
+	dagql.Fields[*points.Point]{
+		dagql.Func("walk", func(ctx context.Context, self *points.Point, args struct {
+			Steps int
+		}) (dagql.Stream[*points.Point], error) {
+			return dagql.NewStream(func(ctx context.Context, emit func(*points.Point) error) error {
+				for i := 1; i <= args.Steps; i++ {
+					if err := emit(&points.Point{X: self.X + i, Y: self.Y}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}), nil
+		}).DoNotCache("streams are consumed as they're subscribed to"),
+	}.Install(srv)
+
+	gql := client.New(dagql.NewDefaultHandler(srv))
+
+	t.Run("streams each element", func(t *testing.T) {
+		sub := gql.Websocket(`subscription {
+			point(x: 6, y: 7) {
+				walk(steps: 3) {
+					x
+					y
+				}
+			}
+		}`)
+		defer sub.Close()
+
+		for i := 1; i <= 3; i++ {
+			var res struct {
+				Point struct {
+					Walk struct {
+						X, Y int
+					}
+				}
+			}
+			require.NoError(t, sub.Next(&res))
+			require.Equal(t, 6+i, res.Point.Walk.X)
+			require.Equal(t, 7, res.Point.Walk.Y)
+		}
+		err := sub.Next(&struct{}{})
+		require.ErrorContains(t, err, "complete")
+	})
+
+	t.Run("queries still work over websockets", func(t *testing.T) {
+		var res struct {
+			Point struct {
+				X, Y int
+			}
+		}
+		require.NoError(t, gql.WebsocketOnce(`query {
+			point(x: 6, y: 7) {
+				x
+				y
+			}
+		}`, &res))
+		require.Equal(t, 6, res.Point.X)
+	})
+
+	t.Run("streams can only be subscribed to", func(t *testing.T) {
+		reqFail(t, gql, `query {
+			point(x: 6, y: 7) {
+				walk(steps: 3) {
+					x
+				}
+			}
+		}`, "walk can only be selected in a subscription")
+	})
+
+	t.Run("subscriptions select a single chain", func(t *testing.T) {
+		sub := gql.Websocket(`subscription {
+			point(x: 6, y: 7) {
+				x
+				walk(steps: 3) {
+					x
+				}
+			}
+		}`)
+		defer sub.Close()
+		err := sub.Next(&struct{}{})
+		require.ErrorContains(t, err, "subscriptions must select exactly one field of Point")
+	})
+}
+
+func TestConnections(t *testing.T) {
+	srv := dagql.NewServer(Query{}, newCache(t))
+	points.Install[Query](srv)
+
+	var calls int
+	dagql.Fields[Query]{
+		dagql.Func("ints", func(ctx context.Context, self Query, args struct {
+			Count int
+		}) ([]int, error) {
+			calls++
+			ints := make([]int, args.Count)
+			for i := range ints {
+				ints[i] = i + 1
+			}
+			return ints, nil
+		}).Paginated(),
+		dagql.Func("points", func(ctx context.Context, self Query, args struct{}) ([]*points.Point, error) {
+			return []*points.Point{{X: 1, Y: 2}, {X: 3, Y: 4}, {X: 5, Y: 6}}, nil
+		}).Paginated(),
+	}.Install(srv)
+
+	gql := client.New(dagql.NewDefaultHandler(srv))
+
+	type intsPage struct {
+		Nodes       []int
+		EndCursor   *string
+		HasNextPage bool
+		TotalCount  int
+	}
+
+	t.Run("iterates over pages", func(t *testing.T) {
+		var nodes []int
+		var after string
+		for {
+			var res struct {
+				IntsConnection intsPage
+			}
+			afterArg := ""
+			if after != "" {
+				afterArg = fmt.Sprintf(", after: %q", after)
+			}
+			req(t, gql, `query {
+				intsConnection(count: 10, first: 4`+afterArg+`) {
+					nodes
+					endCursor
+					hasNextPage
+					totalCount
+				}
+			}`, &res)
+			require.Equal(t, 10, res.IntsConnection.TotalCount)
+			require.LessOrEqual(t, len(res.IntsConnection.Nodes), 4)
+			nodes = append(nodes, res.IntsConnection.Nodes...)
+			if !res.IntsConnection.HasNextPage {
+				break
+			}
+			require.NotNil(t, res.IntsConnection.EndCursor)
+			after = *res.IntsConnection.EndCursor
+		}
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, nodes)
+
+		// the list is computed once and shared by all pages
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("without first returns the rest", func(t *testing.T) {
+		var res struct {
+			IntsConnection intsPage
+		}
+		req(t, gql, `query {
+			intsConnection(count: 3) {
+				nodes
+				endCursor
+				hasNextPage
+			}
+		}`, &res)
+		require.Equal(t, []int{1, 2, 3}, res.IntsConnection.Nodes)
+		require.False(t, res.IntsConnection.HasNextPage)
+		require.NotNil(t, res.IntsConnection.EndCursor)
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		var res struct {
+			IntsConnection intsPage
+		}
+		req(t, gql, `query {
+			intsConnection(count: 0, first: 2) {
+				nodes
+				endCursor
+				hasNextPage
+			}
+		}`, &res)
+		require.Empty(t, res.IntsConnection.Nodes)
+		require.Nil(t, res.IntsConnection.EndCursor)
+		require.False(t, res.IntsConnection.HasNextPage)
+	})
+
+	t.Run("objects keep their IDs", func(t *testing.T) {
+		var res struct {
+			PointsConnection struct {
+				Nodes []struct {
+					ID string
+					X  int
+				}
+			}
+		}
+		req(t, gql, `query {
+			pointsConnection(first: 1, after: "`+base64Cursor(1)+`") {
+				nodes {
+					id
+					x
+				}
+			}
+		}`, &res)
+		require.Len(t, res.PointsConnection.Nodes, 1)
+		require.Equal(t, 3, res.PointsConnection.Nodes[0].X)
+
+		var id call.ID
+		require.NoError(t, id.Decode(res.PointsConnection.Nodes[0].ID))
+		require.Equal(t, "points", id.Receiver().Field())
+		require.EqualValues(t, 2, id.Nth())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		reqFail(t, gql, `query {
+			intsConnection(count: 3, after: "nope") {
+				nodes
+			}
+		}`, "invalid cursor")
+		reqFail(t, gql, `query {
+			intsConnection(count: 3, first: -1) {
+				nodes
+			}
+		}`, "first must not be negative")
+	})
+}
+
+func base64Cursor(nth int) string {
+	return base64.StdEncoding.EncodeToString([]byte("cursor:" + strconv.Itoa(nth)))
+}
+
+func TestPersistedQueries(t *testing.T) {
+	srv := dagql.NewServer(Query{}, newCache(t))
+	points.Install[Query](srv)
+
+	query := `query { point(x: 6, y: 7) { x y } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+	persisted := func(hash string) client.Option {
+		return client.Extensions(map[string]any{
+			"persistedQuery": map[string]any{
+				"version":    1,
+				"sha256Hash": hash,
+			},
+		})
+	}
+
+	type pointRes struct {
+		Point struct {
+			X, Y int
+		}
+	}
+
+	// each request gets a new handler, as in the engine
+	newClient := func() *client.Client {
+		return client.New(dagql.NewDefaultHandler(srv))
+	}
+
+	t.Run("unknown hash", func(t *testing.T) {
+		var res pointRes
+		err := newClient().Post(``, &res, persisted(strings.Repeat("0", 64)))
+		require.ErrorContains(t, err, "PersistedQueryNotFound")
+	})
+
+	t.Run("persist and reuse", func(t *testing.T) {
+		var res pointRes
+		require.NoError(t, newClient().Post(query, &res, persisted(hash)))
+		require.Equal(t, 6, res.Point.X)
+
+		for range 3 {
+			var res pointRes
+			require.NoError(t, newClient().Post(``, &res, persisted(hash)))
+			require.Equal(t, 6, res.Point.X)
+			require.Equal(t, 7, res.Point.Y)
+		}
+	})
+
+	t.Run("mismatched hash", func(t *testing.T) {
+		var res pointRes
+		err := newClient().Post(`query { point(x: 1, y: 2) { x } }`, &res, persisted(hash))
+		require.ErrorContains(t, err, "hash does not match")
+	})
+
+	t.Run("schema changes", func(t *testing.T) {
+		dagql.Fields[*points.Point]{
+			dagql.Func("sum", func(ctx context.Context, self *points.Point, args struct{}) (int, error) {
+				return self.X + self.Y, nil
+			}),
+		}.Install(srv)
+
+		var res pointRes
+		require.NoError(t, newClient().Post(``, &res, persisted(hash)))
+		require.Equal(t, 6, res.Point.X)
+
+		var sumRes struct {
+			Point struct {
+				Sum int
+			}
+		}
+		require.NoError(t, newClient().Post(`query { point(x: 6, y: 7) { sum } }`, &sumRes))
+		require.Equal(t, 13, sumRes.Point.Sum)
+	})
+}
+
+func TestQuerySelectionsCache(t *testing.T) {
+	srv := dagql.NewServer(Query{}, newCache(t))
+	gql := client.New(dagql.NewDefaultHandler(srv))
+
+	t.Run("views", func(t *testing.T) {
+		dagql.Fields[Query]{
+			dagql.Func("which", func(ctx context.Context, self Query, args struct{}) (string, error) {
+				return "first", nil
+			}).View(dagql.ExactView("firstView")),
+			dagql.Func("which", func(ctx context.Context, self Query, args struct{}) (string, error) {
+				return "second", nil
+			}).View(dagql.ExactView("secondView")),
+		}.Install(srv)
+		t.Cleanup(func() { srv.View = "" })
+
+		var res struct {
+			Which string
+		}
+		srv.View = "firstView"
+		req(t, gql, `query { which }`, &res)
+		require.Equal(t, "first", res.Which)
+
+		// the same query must not reuse the selections parsed for another view
+		srv.View = "secondView"
+		req(t, gql, `query { which }`, &res)
+		require.Equal(t, "second", res.Which)
+
+		srv.View = "firstView"
+		req(t, gql, `query { which }`, &res)
+		require.Equal(t, "first", res.Which)
+	})
+
+	t.Run("schema changes", func(t *testing.T) {
+		dagql.Fields[Query]{
+			dagql.Func("echo", func(ctx context.Context, self Query, args struct {
+				Value int
+			}) (string, error) {
+				return fmt.Sprintf("int %d", args.Value), nil
+			}).DoNotCache("the result depends on the schema"),
+		}.Install(srv)
+
+		var res struct {
+			Echo string
+		}
+		req(t, gql, `query { echo(value: 1) }`, &res)
+		require.Equal(t, "int 1", res.Echo)
+
+		// redefining the field invalidates the selections parsed for the
+		// previous schema, whose arguments were decoded as the previous types
+		dagql.Fields[Query]{
+			dagql.Func("echo", func(ctx context.Context, self Query, args struct {
+				Value dagql.Optional[dagql.Int]
+			}) (string, error) {
+				return fmt.Sprintf("optional %d", args.Value.Value), nil
+			}).DoNotCache("the result depends on the schema"),
+		}.Install(srv)
+		req(t, gql, `query { echo(value: 1) }`, &res)
+		require.Equal(t, "optional 1", res.Echo)
+	})
+}
+
+// writeNotifier is a ResponseWriter calling a func after each write.
+type writeNotifier struct {
+	*httptest.ResponseRecorder
+	written func()
+}
+
+func (w writeNotifier) Write(b []byte) (int, error) {
+	defer w.written()
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestBatchedQueries(t *testing.T) {
+	srv := dagql.NewServer(Query{}, newCache(t))
+	points.Install[Query](srv)
+
+	// first only returns once a response has been streamed, so second must
+	// have run concurrently and been written first
+	streamed := make(chan struct{})
+	dagql.Fields[Query]{
+		dagql.Func("first", func(ctx context.Context, self Query, args struct{}) (int, error) {
+			select {
+			case <-streamed:
+				return 1, nil
+			case <-time.After(time.Minute):
+				return 0, fmt.Errorf("operations were not executed concurrently")
+			}
+		}),
+		dagql.Func("second", func(ctx context.Context, self Query, args struct{}) (int, error) {
+			return 2, nil
+		}),
+	}.Install(srv)
+
+	var streamedOnce sync.Once
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		dagql.NewDefaultHandler(srv).ServeHTTP(writeNotifier{w, func() {
+			streamedOnce.Do(func() { close(streamed) })
+		}}, req)
+		return w
+	}
+
+	type batchResponse struct {
+		Index  int
+		Data   json.RawMessage
+		Errors []struct {
+			Message string
+		}
+	}
+	responses := func(t *testing.T, w *httptest.ResponseRecorder) []batchResponse {
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		var res []batchResponse
+		dec := json.NewDecoder(w.Body)
+		for dec.More() {
+			var r batchResponse
+			require.NoError(t, dec.Decode(&r))
+			res = append(res, r)
+		}
+		return res
+	}
+
+	t.Run("streamed as they finish", func(t *testing.T) {
+		res := responses(t, post(` [
+			{"query": "query { first }"},
+			{"query": "query { second }"}
+		]`))
+		require.Len(t, res, 2)
+		require.Equal(t, 1, res[0].Index)
+		require.JSONEq(t, `{"second": 2}`, string(res[0].Data))
+		require.Equal(t, 0, res[1].Index)
+		require.JSONEq(t, `{"first": 1}`, string(res[1].Data))
+	})
+
+	t.Run("errors are per operation", func(t *testing.T) {
+		res := responses(t, post(`[
+			{"query": "query Point($x: Int!) { point(x: $x, y: 2) { x y } }", "variables": {"x": 1}},
+			{"query": "query { bogus }"},
+			null
+		]`))
+		require.Len(t, res, 3)
+		slices.SortFunc(res, func(a, b batchResponse) int {
+			return a.Index - b.Index
+		})
+		require.JSONEq(t, `{"point": {"x": 1, "y": 2}}`, string(res[0].Data))
+		require.Empty(t, res[0].Errors)
+		require.Len(t, res[1].Errors, 1)
+		require.Contains(t, res[1].Errors[0].Message, "bogus")
+		require.Len(t, res[2].Errors, 1)
+		require.Equal(t, "missing operation", res[2].Errors[0].Message)
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		w := post(`[{"query": "query { second }"}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Contains(t, w.Body.String(), "could not be decoded")
+	})
+
+	t.Run("single operations", func(t *testing.T) {
+		var res struct {
+			Point struct {
+				X, Y int
+			}
+		}
+		require.NoError(t, client.New(dagql.NewDefaultHandler(srv)).Post(`query { point(x: 3, y: 4) { x y } }`, &res))
+		require.Equal(t, 3, res.Point.X)
+		require.Equal(t, 4, res.Point.Y)
+	})
+}
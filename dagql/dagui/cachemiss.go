@@ -0,0 +1,64 @@
+package dagui
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// CacheMiss is a call that missed the cache, along with why.
+type CacheMiss struct {
+	// The chain of fields leading up to the call, e.g.
+	// container.from.withExec.
+	Path string
+
+	Span *Span
+
+	// Why the call missed the cache, e.g. which of its inputs changed.
+	Reason string
+}
+
+// CacheMisses returns the calls that explained why they missed the cache, in
+// the order they started.
+//
+// The engine only explains cache misses when the client asks it to.
+func (db *DB) CacheMisses() []*CacheMiss {
+	paths := map[string]string{}
+	seen := map[string]bool{}
+	var misses []*CacheMiss
+	for _, span := range db.Spans.Order {
+		if span.CacheMissReason == "" {
+			continue
+		}
+		path := span.Name
+		if call := span.Call(); call != nil {
+			if seen[call.Digest] {
+				continue
+			}
+			seen[call.Digest] = true
+			path = callPath(db, call, paths, 0)
+		}
+		misses = append(misses, &CacheMiss{
+			Path:   path,
+			Span:   span,
+			Reason: span.CacheMissReason,
+		})
+	}
+	return misses
+}
+
+// WriteCacheMisses writes a summary of the calls that explained why they
+// missed the cache, if any.
+func (db *DB) WriteCacheMisses(w io.Writer) error {
+	misses := db.CacheMisses()
+	if len(misses) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Cache misses (%d):\n", len(misses))
+	for _, miss := range misses {
+		fmt.Fprintf(tw, "  %s\t%s\n", miss.Path, miss.Reason)
+	}
+	return tw.Flush()
+}
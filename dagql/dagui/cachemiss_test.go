@@ -0,0 +1,51 @@
+package dagui
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dagger/dagger/dagql/call/callpbv1"
+)
+
+func TestCacheMisses(t *testing.T) {
+	db := NewDB()
+	epoch := time.Now()
+
+	addCall := func(id byte, digest, receiver, field, reason string) {
+		db.Calls[digest] = &callpbv1.Call{
+			Digest:         digest,
+			ReceiverDigest: receiver,
+			Field:          field,
+		}
+		span := db.newSpan(SpanID{trace.SpanID{id}})
+		span.Received = true
+		span.Name = field
+		span.CallDigest = digest
+		span.CacheMissReason = reason
+		span.StartTime = epoch.Add(time.Duration(id) * time.Millisecond)
+		span.EndTime = span.StartTime.Add(time.Second)
+		db.Spans.Add(span)
+		db.integrateSpan(span)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, db.WriteCacheMisses(&buf))
+	require.Empty(t, buf.String(), "nothing is written if no misses were explained")
+
+	addCall(1, "ctr", "", "container", "")
+	addCall(2, "exec", "ctr", "withExec", `argument "args" changed: ["a"] → ["b"]`)
+	// the same call again, e.g. from another client
+	addCall(3, "exec", "ctr", "withExec", "client ID changed: a → b")
+
+	misses := db.CacheMisses()
+	require.Len(t, misses, 1)
+	require.Equal(t, "container.withExec", misses[0].Path)
+
+	require.NoError(t, db.WriteCacheMisses(&buf))
+	require.Contains(t, buf.String(), "Cache misses (1):")
+	require.Contains(t, buf.String(), `container.withExec  argument "args" changed: ["a"] → ["b"]`)
+}
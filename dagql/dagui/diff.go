@@ -0,0 +1,443 @@
+package dagui
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dagger/dagger/dagql/call/callpbv1"
+)
+
+// RunDiff compares the calls made by two runs, e.g. to find out why one got
+// slower or stopped hitting the cache.
+//
+// Calls are matched up first by digest, and failing that by their position:
+// the chain of fields leading up to them, and how many times that chain was
+// called before.
+type RunDiff struct {
+	// Calls whose digest changed, along with why.
+	Changed []*CallDiff
+
+	// Calls with the same digest that hit the cache before, but not after.
+	LostCache []*CallDiff
+
+	// Calls with the same digest that took significantly more or less time.
+	DurationChanged []*CallDiff
+
+	// Calls only made by one run or the other.
+	Added, Removed []*CallDiff
+}
+
+// CallDiff is a call that differs between two runs.
+type CallDiff struct {
+	// The chain of fields leading up to the call, e.g.
+	// container.from.withExec.
+	Path string
+
+	// The call's span in each run, if it was made.
+	Before, After *Span
+
+	// Why the call's digest changed, if it did.
+	Changes []CallChange
+}
+
+// CallChange is a reason that a call's digest changed.
+type CallChange struct {
+	// The argument that changed, if any.
+	Arg string
+
+	// A description of the change.
+	Description string
+
+	// Whether the change is only the result of another call changing, e.g. the
+	// receiver or an object passed as an argument.
+	Downstream bool
+}
+
+// IsRootCause returns whether the call changed by itself, rather than only
+// as a result of another call changing.
+func (cd *CallDiff) IsRootCause() bool {
+	for _, change := range cd.Changes {
+		if !change.Downstream {
+			return true
+		}
+	}
+	return false
+}
+
+// A duration change must be at least this large, both relatively and
+// absolutely, to be considered significant.
+const (
+	significantDurationRatio = 1.5
+	significantDurationDelta = time.Second
+)
+
+// DiffRuns compares the calls made in the before and after runs.
+func DiffRuns(before, after *DB) *RunDiff {
+	diff := &RunDiff{}
+	beforeCalls := runCalls(before)
+	afterCalls := runCalls(after)
+
+	beforeByDigest := map[string]*runCall{}
+	beforeByPosition := map[string]*runCall{}
+	for _, rc := range beforeCalls {
+		beforeByDigest[rc.call.Digest] = rc
+		beforeByPosition[rc.position] = rc
+	}
+
+	matched := map[*runCall]bool{}
+	var unmatched []*runCall
+	for _, rc := range afterCalls {
+		prev := beforeByDigest[rc.call.Digest]
+		if prev == nil || matched[prev] {
+			unmatched = append(unmatched, rc)
+			continue
+		}
+		matched[prev] = true
+		cd := &CallDiff{
+			Path:   rc.path,
+			Before: prev.span,
+			After:  rc.span,
+		}
+		if prev.span.IsCached() && !rc.span.IsCached() {
+			diff.LostCache = append(diff.LostCache, cd)
+		} else if !prev.span.IsCached() && !rc.span.IsCached() &&
+			significantDurationChange(spanDuration(prev.span), spanDuration(rc.span)) {
+			diff.DurationChanged = append(diff.DurationChanged, cd)
+		}
+	}
+
+	for _, rc := range unmatched {
+		prev := beforeByPosition[rc.position]
+		if prev == nil || matched[prev] {
+			diff.Added = append(diff.Added, &CallDiff{
+				Path:  rc.path,
+				After: rc.span,
+			})
+			continue
+		}
+		matched[prev] = true
+		diff.Changed = append(diff.Changed, &CallDiff{
+			Path:    rc.path,
+			Before:  prev.span,
+			After:   rc.span,
+			Changes: diffCalls(before, after, prev.call, rc.call),
+		})
+	}
+
+	for _, rc := range beforeCalls {
+		if !matched[rc] {
+			diff.Removed = append(diff.Removed, &CallDiff{
+				Path:   rc.path,
+				Before: rc.span,
+			})
+		}
+	}
+
+	// show the biggest regressions first
+	slices.SortStableFunc(diff.LostCache, func(a, b *CallDiff) int {
+		return cmp.Compare(spanDuration(b.After), spanDuration(a.After))
+	})
+	slices.SortStableFunc(diff.DurationChanged, func(a, b *CallDiff) int {
+		return cmp.Compare(
+			spanDuration(b.After)-spanDuration(b.Before),
+			spanDuration(a.After)-spanDuration(a.Before),
+		)
+	})
+	return diff
+}
+
+type runCall struct {
+	span *Span
+	call *callpbv1.Call
+	path string
+	// the path, qualified by how many times it was called before
+	position string
+}
+
+// runCalls returns the first span of each call made during a run, in the
+// order they started.
+func runCalls(db *DB) []*runCall {
+	paths := map[string]string{}
+	seen := map[string]bool{}
+	occurrences := map[string]int{}
+	var calls []*runCall
+	for _, span := range db.Spans.Order {
+		call := span.Call()
+		if call == nil || span.Ignore || seen[call.Digest] {
+			continue
+		}
+		seen[call.Digest] = true
+		path := callPath(db, call, paths, 0)
+		calls = append(calls, &runCall{
+			span:     span,
+			call:     call,
+			path:     path,
+			position: path + "#" + strconv.Itoa(occurrences[path]),
+		})
+		occurrences[path]++
+	}
+	return calls
+}
+
+// Calls are unlikely to be chained this deeply; give up rather than recursing
+// forever if the receivers somehow form a cycle.
+const maxCallPathDepth = 1000
+
+func callPath(db *DB, call *callpbv1.Call, memo map[string]string, depth int) string {
+	if path, ok := memo[call.Digest]; ok {
+		return path
+	}
+	path := call.Field
+	if call.Nth != 0 {
+		path += fmt.Sprintf("[%d]", call.Nth)
+	}
+	if call.ReceiverDigest != "" && depth < maxCallPathDepth {
+		if receiver := db.Call(call.ReceiverDigest); receiver != nil {
+			path = callPath(db, receiver, memo, depth+1) + "." + path
+		}
+	}
+	memo[call.Digest] = path
+	return path
+}
+
+// diffCalls explains why two calls in the same position have different
+// digests.
+func diffCalls(before, after *DB, a, b *callpbv1.Call) []CallChange {
+	var changes []CallChange
+	if a.Field != b.Field {
+		changes = append(changes, CallChange{
+			Description: fmt.Sprintf("field changed: %s → %s", a.Field, b.Field),
+		})
+	}
+	if a.ReceiverDigest != b.ReceiverDigest {
+		changes = append(changes, CallChange{
+			Description: "receiver changed",
+			Downstream:  true,
+		})
+	}
+
+	var argNames []string
+	beforeArgs := map[string]*callpbv1.Literal{}
+	afterArgs := map[string]*callpbv1.Literal{}
+	for _, arg := range a.Args {
+		beforeArgs[arg.Name] = arg.Value
+		argNames = append(argNames, arg.Name)
+	}
+	for _, arg := range b.Args {
+		afterArgs[arg.Name] = arg.Value
+		if _, ok := beforeArgs[arg.Name]; !ok {
+			argNames = append(argNames, arg.Name)
+		}
+	}
+	for _, name := range argNames {
+		av, aok := beforeArgs[name]
+		bv, bok := afterArgs[name]
+		switch {
+		case !aok:
+			changes = append(changes, CallChange{
+				Arg:         name,
+				Description: "set to " + formatLiteral(after, bv),
+			})
+		case !bok:
+			changes = append(changes, CallChange{
+				Arg:         name,
+				Description: "unset; was " + formatLiteral(before, av),
+			})
+		case !proto.Equal(av, bv):
+			_, aCall := av.GetValue().(*callpbv1.Literal_CallDigest)
+			_, bCall := bv.GetValue().(*callpbv1.Literal_CallDigest)
+			if aCall && bCall {
+				changes = append(changes, CallChange{
+					Arg:         name,
+					Description: formatLiteral(after, bv) + " changed",
+					Downstream:  true,
+				})
+				continue
+			}
+			changes = append(changes, CallChange{
+				Arg: name,
+				Description: fmt.Sprintf("%s → %s",
+					formatLiteral(before, av),
+					formatLiteral(after, bv)),
+			})
+		}
+	}
+
+	if !proto.Equal(a.Module, b.Module) {
+		from, to := formatModule(a.Module), formatModule(b.Module)
+		desc := fmt.Sprintf("module changed: %s → %s", from, to)
+		if from == to {
+			desc = fmt.Sprintf("module %s changed", to)
+		}
+		changes = append(changes, CallChange{
+			Description: desc,
+		})
+	}
+	if a.View != b.View {
+		changes = append(changes, CallChange{
+			Description: fmt.Sprintf("view changed: %q → %q", a.View, b.View),
+		})
+	}
+	if len(changes) == 0 {
+		changes = append(changes, CallChange{
+			Description: "inputs not captured by its arguments changed, e.g. file contents",
+		})
+	}
+	return changes
+}
+
+func formatModule(mod *callpbv1.Module) string {
+	if mod == nil {
+		return "none"
+	}
+	if mod.Pin != "" {
+		return mod.Ref + "@" + mod.Pin
+	}
+	return mod.Ref
+}
+
+// The maximum length of a literal to show before eliding the rest.
+const maxDiffLiteralLen = 80
+
+func formatLiteral(db *DB, lit *callpbv1.Literal) string {
+	var buf strings.Builder
+	writeLiteral(&buf, db, lit)
+	str := []rune(buf.String())
+	if len(str) > maxDiffLiteralLen {
+		return string(str[:maxDiffLiteralLen]) + "…"
+	}
+	return string(str)
+}
+
+func writeLiteral(w *strings.Builder, db *DB, lit *callpbv1.Literal) {
+	switch val := lit.GetValue().(type) {
+	case *callpbv1.Literal_Bool:
+		fmt.Fprintf(w, "%v", val.Bool)
+	case *callpbv1.Literal_Int:
+		fmt.Fprintf(w, "%d", val.Int)
+	case *callpbv1.Literal_Float:
+		fmt.Fprintf(w, "%v", val.Float)
+	case *callpbv1.Literal_String_:
+		fmt.Fprintf(w, "%q", val.String_)
+	case *callpbv1.Literal_Enum:
+		w.WriteString(val.Enum)
+	case *callpbv1.Literal_Null:
+		w.WriteString("null")
+	case *callpbv1.Literal_CallDigest:
+		if call := db.Call(val.CallDigest); call != nil {
+			w.WriteString(callPath(db, call, map[string]string{}, 0))
+		} else {
+			w.WriteString(val.CallDigest)
+		}
+	case *callpbv1.Literal_List:
+		w.WriteString("[")
+		for i, item := range val.List.GetValues() {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			writeLiteral(w, db, item)
+		}
+		w.WriteString("]")
+	case *callpbv1.Literal_Object:
+		w.WriteString("{")
+		for i, item := range val.Object.GetValues() {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString(item.GetName() + ": ")
+			writeLiteral(w, db, item.GetValue())
+		}
+		w.WriteString("}")
+	}
+}
+
+func spanDuration(span *Span) time.Duration {
+	if span.EndTime.Before(span.StartTime) {
+		// never completed
+		return 0
+	}
+	return span.EndTime.Sub(span.StartTime)
+}
+
+func significantDurationChange(before, after time.Duration) bool {
+	delta := after - before
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta < significantDurationDelta {
+		return false
+	}
+	lo, hi := min(before, after), max(before, after)
+	if lo == 0 {
+		// didn't complete in one of the runs
+		return false
+	}
+	return float64(hi) >= float64(lo)*significantDurationRatio
+}
+
+// WriteSummary writes a human-readable report of the differences.
+func (diff *RunDiff) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	var rootCauses []*CallDiff
+	for _, cd := range diff.Changed {
+		if cd.IsRootCause() {
+			rootCauses = append(rootCauses, cd)
+		}
+	}
+	fmt.Fprintf(tw, "Changed calls (%d):\n", len(rootCauses))
+	for _, cd := range rootCauses {
+		fmt.Fprintf(tw, "  %s\n", cd.Path)
+		for _, change := range cd.Changes {
+			if change.Downstream {
+				continue
+			}
+			if change.Arg != "" {
+				fmt.Fprintf(tw, "    %s: %s\n", change.Arg, change.Description)
+			} else {
+				fmt.Fprintf(tw, "    %s\n", change.Description)
+			}
+		}
+	}
+	if downstream := len(diff.Changed) - len(rootCauses); downstream > 0 {
+		fmt.Fprintf(tw, "  (%d more calls changed as a result)\n", downstream)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Lost cache (%d):\n", len(diff.LostCache))
+	for _, cd := range diff.LostCache {
+		fmt.Fprintf(tw, "  %s\t%s\n", cd.Path, FormatDuration(spanDuration(cd.After)))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Duration changed (%d):\n", len(diff.DurationChanged))
+	for _, cd := range diff.DurationChanged {
+		before, after := spanDuration(cd.Before), spanDuration(cd.After)
+		fmt.Fprintf(tw, "  %s\t%s → %s\t(%+.0f%%)\n",
+			cd.Path,
+			FormatDuration(before),
+			FormatDuration(after),
+			(float64(after)/float64(before)-1)*100)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Added calls (%d):\n", len(diff.Added))
+	for _, cd := range diff.Added {
+		fmt.Fprintf(tw, "  %s\n", cd.Path)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Removed calls (%d):\n", len(diff.Removed))
+	for _, cd := range diff.Removed {
+		fmt.Fprintf(tw, "  %s\n", cd.Path)
+	}
+	return tw.Flush()
+}
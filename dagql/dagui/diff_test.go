@@ -0,0 +1,97 @@
+package dagui
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dagger/dagger/dagql/call/callpbv1"
+)
+
+func TestDiffRuns(t *testing.T) {
+	epoch := time.Now()
+
+	type testCall struct {
+		digest   string
+		receiver string
+		field    string
+		args     []*callpbv1.Argument
+		cached   bool
+		duration time.Duration
+	}
+	newRun := func(calls ...testCall) *DB {
+		db := NewDB()
+		for i, c := range calls {
+			db.Calls[c.digest] = &callpbv1.Call{
+				Digest:         c.digest,
+				ReceiverDigest: c.receiver,
+				Field:          c.field,
+				Args:           c.args,
+			}
+			span := db.newSpan(SpanID{trace.SpanID{byte(i + 1)}})
+			span.Received = true
+			span.Name = c.field
+			span.CallDigest = c.digest
+			span.Cached = c.cached
+			// start in order, so that positions match up
+			span.StartTime = epoch.Add(time.Duration(i) * time.Millisecond)
+			span.EndTime = span.StartTime.Add(c.duration)
+			db.Spans.Add(span)
+			db.integrateSpan(span)
+		}
+		return db
+	}
+	stringArg := func(name, val string) *callpbv1.Argument {
+		return &callpbv1.Argument{
+			Name:  name,
+			Value: &callpbv1.Literal{Value: &callpbv1.Literal_String_{String_: val}},
+		}
+	}
+
+	before := newRun(
+		testCall{digest: "ctr", field: "container", cached: true},
+		testCall{digest: "from", receiver: "ctr", field: "from", args: []*callpbv1.Argument{stringArg("address", "alpine:3.20")}, cached: true},
+		testCall{digest: "build", receiver: "from", field: "withExec", duration: 10 * time.Second},
+		testCall{digest: "test", receiver: "ctr", field: "withExec", args: []*callpbv1.Argument{stringArg("cmd", "test")}, cached: true},
+		testCall{digest: "lint", receiver: "ctr", field: "withEnvVariable", duration: time.Second},
+	)
+	after := newRun(
+		testCall{digest: "ctr", field: "container", cached: true},
+		testCall{digest: "from2", receiver: "ctr", field: "from", args: []*callpbv1.Argument{stringArg("address", "alpine:3.21")}, duration: 2 * time.Second},
+		testCall{digest: "build2", receiver: "from2", field: "withExec", duration: 10 * time.Second},
+		testCall{digest: "test", receiver: "ctr", field: "withExec", args: []*callpbv1.Argument{stringArg("cmd", "test")}, duration: 5 * time.Second},
+		testCall{digest: "lint", receiver: "ctr", field: "withEnvVariable", duration: 3 * time.Second},
+		testCall{digest: "sync", receiver: "ctr", field: "sync"},
+	)
+
+	diff := DiffRuns(before, after)
+
+	require.Len(t, diff.Changed, 2)
+	require.Equal(t, "container.from", diff.Changed[0].Path)
+	require.True(t, diff.Changed[0].IsRootCause())
+	require.Equal(t, []CallChange{{
+		Arg:         "address",
+		Description: `"alpine:3.20" → "alpine:3.21"`,
+	}}, diff.Changed[0].Changes)
+	require.Equal(t, "container.from.withExec", diff.Changed[1].Path)
+	require.False(t, diff.Changed[1].IsRootCause())
+
+	require.Len(t, diff.LostCache, 1)
+	require.Equal(t, "container.withExec", diff.LostCache[0].Path)
+
+	require.Len(t, diff.DurationChanged, 1)
+	require.Equal(t, "container.withEnvVariable", diff.DurationChanged[0].Path)
+
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, "container.sync", diff.Added[0].Path)
+	require.Empty(t, diff.Removed)
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteSummary(&buf))
+	require.Contains(t, buf.String(), "Changed calls (1):")
+	require.Contains(t, buf.String(), `address: "alpine:3.20" → "alpine:3.21"`)
+	require.Contains(t, buf.String(), "(1 more calls changed as a result)")
+}
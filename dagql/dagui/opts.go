@@ -44,6 +44,10 @@ type FrontendOpts struct {
 	// DotShowInternal indicates whether to include internal steps in the DOT output
 	DotShowInternal bool
 
+	// ProfileOutputFilePath is the path to write a profile of the run to after
+	// execution, if any
+	ProfileOutputFilePath string
+
 	// ZoomedSpan configures a span to be zoomed in on, revealing
 	// its child spans.
 	ZoomedSpan SpanID
@@ -0,0 +1,301 @@
+package dagui
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Profile summarizes where the time went during a run.
+type Profile struct {
+	// The total wall time of the run.
+	Duration time.Duration
+
+	// The chain of spans that determined how long the run took, in
+	// chronological order. Speeding up anything else won't make the run
+	// faster.
+	CriticalPath []*ProfileSpan
+
+	// Every call made during the run, slowest first.
+	Calls []*ProfileSpan
+
+	// The number of calls that hit and missed the cache.
+	CacheHits, CacheMisses int
+
+	// The wall time spent on calls that missed the cache, not counting calls
+	// nested beneath other misses.
+	CacheMissTime time.Duration
+
+	spans []*ProfileSpan
+}
+
+type ProfileSpan struct {
+	Span *Span
+
+	Start, End time.Time
+
+	// The depth of the span beneath the root span.
+	Depth int
+
+	// Whether the span is on the critical path.
+	Critical bool
+}
+
+func (ps *ProfileSpan) Duration() time.Duration {
+	return ps.End.Sub(ps.Start)
+}
+
+// Profile computes a profile of the run, starting from the primary span.
+func (db *DB) Profile() *Profile {
+	root := db.RootSpan
+	if primary := db.Spans.Map[db.PrimarySpan]; primary != nil {
+		root = primary
+	}
+	prof := &Profile{}
+	if root == nil {
+		return prof
+	}
+
+	now := time.Now()
+	bySpan := map[*Span]*ProfileSpan{}
+	var walk func(*Span, int)
+	walk = func(span *Span, depth int) {
+		ps := &ProfileSpan{
+			Span:  span,
+			Start: span.StartTime,
+			End:   span.EndTime,
+			Depth: depth,
+		}
+		if ps.End.Before(ps.Start) {
+			// still running, or never completed
+			ps.End = now
+		}
+		bySpan[span] = ps
+		prof.spans = append(prof.spans, ps)
+		for _, child := range span.ChildSpans.Order {
+			walk(child, depth+1)
+		}
+	}
+	walk(root, 0)
+
+	rootProf := bySpan[root]
+	prof.Duration = rootProf.Duration()
+	prof.CriticalPath = criticalPath(rootProf, bySpan)
+	for _, ps := range prof.CriticalPath {
+		ps.Critical = true
+	}
+
+	seenCalls := map[string]bool{}
+	for _, ps := range prof.spans {
+		call := ps.Span.Call()
+		if call == nil || ps.Span.Ignore {
+			continue
+		}
+		prof.Calls = append(prof.Calls, ps)
+		if seenCalls[call.Digest] {
+			// only count the first time a call was made towards the cache stats
+			continue
+		}
+		seenCalls[call.Digest] = true
+		if ps.Span.IsCached() {
+			prof.CacheHits++
+			continue
+		}
+		prof.CacheMisses++
+		if !beneathCacheMiss(ps.Span) {
+			prof.CacheMissTime += ps.Duration()
+		}
+	}
+	slices.SortStableFunc(prof.Calls, func(a, b *ProfileSpan) int {
+		return cmp.Compare(b.Duration(), a.Duration())
+	})
+	return prof
+}
+
+// criticalPath walks backwards from the end of the span, picking whichever
+// child finished last before the cursor and then continuing from its start,
+// recursing into each child along the way.
+func criticalPath(ps *ProfileSpan, bySpan map[*Span]*ProfileSpan) []*ProfileSpan {
+	var chain []*ProfileSpan
+	visited := map[*Span]bool{}
+	cursor := ps.End
+	for {
+		var last *ProfileSpan
+		for _, child := range ps.Span.ChildSpans.Order {
+			cp := bySpan[child]
+			if cp == nil || cp.End.After(cursor) || visited[child] {
+				continue
+			}
+			if last == nil || cp.End.After(last.End) {
+				last = cp
+			}
+		}
+		if last == nil {
+			break
+		}
+		visited[last.Span] = true
+		chain = append(chain, last)
+		cursor = last.Start
+	}
+	slices.Reverse(chain)
+
+	path := []*ProfileSpan{ps}
+	for _, child := range chain {
+		path = append(path, criticalPath(child, bySpan)...)
+	}
+	return path
+}
+
+func beneathCacheMiss(span *Span) bool {
+	for parent := span.ParentSpan; parent != nil; parent = parent.ParentSpan {
+		if parent.Call() != nil && !parent.Ignore && !parent.IsCached() {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteProfile writes the run's profile as Chrome trace event JSON, which
+// can be loaded into Perfetto, and writes a summary of it to summary.
+func (db *DB) WriteProfile(outputFilePath string, summary io.Writer) error {
+	if outputFilePath == "" {
+		return nil
+	}
+	prof := db.Profile()
+	out, err := os.Create(outputFilePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := prof.WriteChromeTrace(out); err != nil {
+		return fmt.Errorf("write profile: %w", err)
+	}
+	return prof.WriteSummary(summary)
+}
+
+type chromeTrace struct {
+	TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+	DisplayTimeUnit string             `json:"displayTimeUnit"`
+}
+
+type chromeTraceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the profile in the Chrome trace event format.
+func (prof *Profile) WriteChromeTrace(w io.Writer) error {
+	spans := slices.Clone(prof.spans)
+	// place parents before their children, so that they are laid out first
+	slices.SortStableFunc(spans, func(a, b *ProfileSpan) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Duration(), a.Duration())
+	})
+
+	var epoch time.Time
+	if len(spans) > 0 {
+		epoch = spans[0].Start
+	}
+
+	trace := chromeTrace{
+		TraceEvents:     []chromeTraceEvent{},
+		DisplayTimeUnit: "ms",
+	}
+	// complete events on the same thread must nest, so lay out parallel
+	// spans on separate threads, nesting spans only beneath their ancestors
+	var lanes [][]*ProfileSpan
+	for _, ps := range spans {
+		tid := -1
+		for i, stack := range lanes {
+			for len(stack) > 0 && !stack[len(stack)-1].End.After(ps.Start) {
+				stack = stack[:len(stack)-1]
+			}
+			lanes[i] = stack
+			if len(stack) == 0 {
+				tid = i
+				break
+			}
+			if top := stack[len(stack)-1]; !top.End.Before(ps.End) && ps.Span.HasParent(top.Span) {
+				tid = i
+				break
+			}
+		}
+		if tid == -1 {
+			tid = len(lanes)
+			lanes = append(lanes, nil)
+		}
+		lanes[tid] = append(lanes[tid], ps)
+
+		cat := "span"
+		args := map[string]any{
+			"critical": ps.Critical,
+		}
+		if call := ps.Span.Call(); call != nil {
+			cat = "call"
+			args["digest"] = call.Digest
+			args["cached"] = ps.Span.IsCached()
+		}
+		if ps.Span.IsFailed() {
+			args["failed"] = true
+		}
+		trace.TraceEvents = append(trace.TraceEvents, chromeTraceEvent{
+			Name: ps.Span.Name,
+			Cat:  cat,
+			Ph:   "X",
+			Ts:   ps.Start.Sub(epoch).Microseconds(),
+			Dur:  ps.Duration().Microseconds(),
+			Pid:  1,
+			Tid:  tid + 1,
+			Args: args,
+		})
+	}
+	enc := json.NewEncoder(w)
+	return enc.Encode(trace)
+}
+
+// The number of slowest calls to include in the summary.
+const profileSummaryCalls = 10
+
+// WriteSummary writes a human-readable summary of the profile.
+func (prof *Profile) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Critical path (%s):\n", FormatDuration(prof.Duration))
+	for _, ps := range prof.CriticalPath {
+		fmt.Fprintf(tw, "%s\t  %s%s\n",
+			FormatDuration(ps.Duration()),
+			strings.Repeat("  ", ps.Depth),
+			ps.Span.Name)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "Slowest calls:")
+	for _, ps := range prof.Calls[:min(len(prof.Calls), profileSummaryCalls)] {
+		status := "miss"
+		if ps.Span.IsCached() {
+			status = "cached"
+		}
+		fmt.Fprintf(tw, "%s\t  %s (%s)\n",
+			FormatDuration(ps.Duration()),
+			ps.Span.Name,
+			status)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Cache: %d hits, %d misses, %s spent on misses\n",
+		prof.CacheHits,
+		prof.CacheMisses,
+		FormatDuration(prof.CacheMissTime))
+	return tw.Flush()
+}
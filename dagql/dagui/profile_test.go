@@ -0,0 +1,70 @@
+package dagui
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestProfileCriticalPath(t *testing.T) {
+	db := NewDB()
+	epoch := time.Now()
+
+	addSpan := func(id byte, parent *Span, name string, start, end time.Duration) *Span {
+		span := db.newSpan(SpanID{trace.SpanID{id}})
+		span.Received = true
+		span.Name = name
+		span.StartTime = epoch.Add(start)
+		span.EndTime = epoch.Add(end)
+		if parent != nil {
+			span.ParentID = parent.ID
+			span.ParentSpan = parent
+		}
+		db.Spans.Add(span)
+		db.integrateSpan(span)
+		return span
+	}
+
+	root := addSpan(1, nil, "root", 0, 10*time.Second)
+	build := addSpan(2, root, "build", 0, 4*time.Second)
+	addSpan(3, root, "lint", time.Second, 3*time.Second)
+	test := addSpan(4, root, "test", 4*time.Second, 10*time.Second)
+	addSpan(5, build, "compile", 0, 4*time.Second)
+
+	prof := db.Profile()
+	require.Equal(t, 10*time.Second, prof.Duration)
+
+	var names []string
+	for _, ps := range prof.CriticalPath {
+		names = append(names, ps.Span.Name)
+	}
+	require.Equal(t, []string{"root", "build", "compile", "test"}, names)
+	require.Equal(t, 2, prof.CriticalPath[2].Depth)
+	require.Equal(t, test, prof.CriticalPath[3].Span)
+
+	var buf bytes.Buffer
+	require.NoError(t, prof.WriteChromeTrace(&buf))
+	var ct chromeTrace
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ct))
+	require.Len(t, ct.TraceEvents, 5)
+
+	tids := map[string]int{}
+	for _, ev := range ct.TraceEvents {
+		tids[ev.Name] = ev.Tid
+		require.Equal(t, "X", ev.Ph)
+	}
+	// nested and sequential spans share a thread, parallel ones don't
+	require.Equal(t, tids["root"], tids["build"])
+	require.Equal(t, tids["build"], tids["compile"])
+	require.Equal(t, tids["root"], tids["test"])
+	require.NotEqual(t, tids["build"], tids["lint"])
+
+	buf.Reset()
+	require.NoError(t, prof.WriteSummary(&buf))
+	require.Contains(t, buf.String(), "Critical path (10.0s):")
+	require.Contains(t, buf.String(), "Cache: 0 hits, 0 misses")
+}
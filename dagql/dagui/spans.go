@@ -233,6 +233,9 @@ type SpanSnapshot struct {
 	Canceled bool `json:",omitempty"`
 	Cached   bool `json:",omitempty"`
 
+	// Why the call missed the cache, if the client asked for an explanation.
+	CacheMissReason string `json:",omitempty"`
+
 	// An extra flag to indicate that a span was canceled because the root span
 	// completed while the span was still running.
 	LeftRunning bool `json:",omitempty"`
@@ -308,6 +311,9 @@ func (snapshot *SpanSnapshot) ProcessAttribute(name string, val any) { //nolint:
 	case telemetry.CachedAttr:
 		snapshot.Cached = val.(bool)
 
+	case telemetry.DagCacheMissReasonAttr:
+		snapshot.CacheMissReason = val.(string)
+
 	case telemetry.CanceledAttr:
 		snapshot.Canceled = val.(bool)
 
@@ -0,0 +1,265 @@
+package idtui
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/dagql/dagui"
+	"github.com/dagger/dagger/util/cleanups"
+)
+
+//go:embed report.html.tmpl
+var htmlReportTemplateSrc string
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(htmlReportTemplateSrc))
+
+// frontendHTML shows the same output as the report frontend, and once the run
+// completes, also writes a self-contained HTML report of it to a file, e.g. to
+// be archived as a CI artifact.
+type frontendHTML struct {
+	*frontendPretty
+
+	path string
+
+	logsMu sync.Mutex
+	logs   map[dagui.SpanID]*htmlSpanLogs
+}
+
+// htmlMaxSpanLogs is how much of each span's logs the HTML report keeps. The
+// end of the logs is kept, since that's usually where errors are.
+const htmlMaxSpanLogs = 64 * 1024
+
+// htmlSpanLogs holds the most recent logs of a span.
+type htmlSpanLogs struct {
+	buf []byte
+	// how many bytes were dropped from the start of buf
+	dropped int
+}
+
+func (logs *htmlSpanLogs) write(s string) {
+	logs.buf = append(logs.buf, s...)
+	// compact occasionally rather than on every write, to avoid copying the
+	// whole buffer for each line of a chatty span
+	if over := len(logs.buf) - htmlMaxSpanLogs; over > htmlMaxSpanLogs {
+		logs.buf = append(logs.buf[:0], logs.buf[over:]...)
+		logs.dropped += over
+	}
+}
+
+// tail returns the kept logs, and how many bytes were omitted before them.
+func (logs *htmlSpanLogs) tail() (string, int) {
+	buf, dropped := logs.buf, logs.dropped
+	if over := len(buf) - htmlMaxSpanLogs; over > 0 {
+		buf = buf[over:]
+		dropped += over
+	}
+	if dropped > 0 {
+		// start at a line boundary, rather than in the middle of a line (or
+		// of a multi-byte character)
+		if nl := bytes.IndexByte(buf, '\n'); nl != -1 {
+			buf = buf[nl+1:]
+			dropped += nl + 1
+		}
+	}
+	return string(buf), dropped
+}
+
+// NewHTML creates a frontend that prints a report at the end of the run, and
+// writes an HTML report with the span tree, logs, durations, cache status and
+// errors to the given path.
+func NewHTML(w io.Writer, path string) Frontend {
+	reporter := NewWithDB(w, dagui.NewDB())
+	reporter.reportOnly = true
+	return &frontendHTML{
+		frontendPretty: reporter,
+		path:           path,
+		logs:           make(map[dagui.SpanID]*htmlSpanLogs),
+	}
+}
+
+func (fe *frontendHTML) Run(ctx context.Context, opts dagui.FrontendOpts, run func(context.Context) (cleanups.CleanupF, error)) error {
+	err := fe.frontendPretty.Run(ctx, opts, run)
+	if writeErr := fe.writeReport(); writeErr != nil {
+		fmt.Fprintln(fe.writer, "failed to write HTML report:", writeErr)
+	}
+	return err
+}
+
+func (fe *frontendHTML) LogExporter() sdklog.Exporter {
+	return htmlLogExporter{fe}
+}
+
+type htmlLogExporter struct {
+	*frontendHTML
+}
+
+func (fe htmlLogExporter) Export(ctx context.Context, logs []sdklog.Record) error {
+	fe.logsMu.Lock()
+	for _, rec := range logs {
+		body := rec.Body().AsString()
+		if body == "" {
+			continue
+		}
+		var verbose bool
+		rec.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == telemetry.LogsVerboseAttr && kv.Value.AsBool() {
+				verbose = true
+				return false
+			}
+			return true
+		})
+		if verbose {
+			continue
+		}
+		spanID := dagui.SpanID{SpanID: rec.SpanID()}
+		spanLogs := fe.logs[spanID]
+		if spanLogs == nil {
+			spanLogs = new(htmlSpanLogs)
+			fe.logs[spanID] = spanLogs
+		}
+		spanLogs.write(ansi.Strip(body))
+	}
+	fe.logsMu.Unlock()
+	return fe.frontendPretty.LogExporter().Export(ctx, logs)
+}
+
+func (fe htmlLogExporter) ForceFlush(ctx context.Context) error {
+	return fe.frontendPretty.LogExporter().ForceFlush(ctx)
+}
+
+func (fe htmlLogExporter) Shutdown(ctx context.Context) error {
+	return fe.frontendPretty.LogExporter().Shutdown(ctx)
+}
+
+type htmlReport struct {
+	Title    string
+	Started  string
+	Duration string
+	Status   string
+	Error    string
+	// The profile summary, if --profile was given.
+	Profile string
+	Root    *htmlReportSpan
+}
+
+type htmlReportSpan struct {
+	Name     string
+	Duration string
+	// One of running, pending, failed, canceled, cached or done.
+	Status   string
+	Internal bool
+	// Whether to expand the span by default.
+	Open  bool
+	Error string
+	Logs  string
+	// How much of the start of the logs was left out of the report, if any.
+	LogsOmitted string
+	Children    []*htmlReportSpan
+}
+
+func (fe *frontendHTML) writeReport() error {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	fe.logsMu.Lock()
+	defer fe.logsMu.Unlock()
+
+	root := fe.db.RootSpan
+	if primary := fe.db.Spans.Map[fe.db.PrimarySpan]; primary != nil {
+		root = primary
+	}
+	report := htmlReport{
+		Title: "Dagger run",
+	}
+	if root != nil {
+		now := time.Now()
+		r := newRenderer(fe.db, plainMaxLiteralLen, fe.FrontendOpts, true)
+		report.Root = fe.reportSpan(r, root, now)
+		report.Root.Open = true
+		report.Title = report.Root.Name
+		report.Started = root.StartTime.Format(time.RFC3339)
+		report.Duration = report.Root.Duration
+		report.Status = report.Root.Status
+	}
+	if fe.err != nil {
+		report.Error = fe.err.Error()
+	}
+	if fe.ProfileOutputFilePath != "" {
+		var summary strings.Builder
+		if err := fe.db.Profile().WriteSummary(&summary); err != nil {
+			return fmt.Errorf("profile: %w", err)
+		}
+		report.Profile = summary.String()
+	}
+
+	out, err := os.Create(fe.path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return htmlReportTemplate.Execute(out, report)
+}
+
+func (fe *frontendHTML) reportSpan(r *renderer, span *dagui.Span, now time.Time) *htmlReportSpan {
+	var name strings.Builder
+	out := NewOutput(&name, termenv.WithProfile(termenv.Ascii))
+	if call := span.Call(); call != nil {
+		r.renderCall(out, span, call, "", false, 0, span.Internal, nil, false)
+	} else {
+		r.renderSpan(out, span, span.Name)
+	}
+
+	rs := &htmlReportSpan{
+		Name:     name.String(),
+		Duration: dagui.FormatDuration(span.Activity.Duration(now)),
+		Internal: span.Internal,
+	}
+	switch {
+	case span.IsRunningOrEffectsRunning():
+		rs.Status = "running"
+	case span.IsCached():
+		rs.Status = "cached"
+	case span.IsCanceled():
+		rs.Status = "canceled"
+	case span.IsFailedOrCausedFailure():
+		rs.Status = "failed"
+	case span.IsPending():
+		rs.Status = "pending"
+	default:
+		rs.Status = "done"
+	}
+	// expand failures, so they're visible without any clicking around
+	rs.Open = rs.Status == "failed"
+	if span.IsFailed() {
+		rs.Error = span.Status.Description
+	}
+	if logs := fe.logs[span.ID]; logs != nil {
+		var omitted int
+		rs.Logs, omitted = logs.tail()
+		if omitted > 0 {
+			rs.LogsOmitted = humanizeBytes(int64(omitted))
+		}
+	}
+
+	children, _ := span.ChildOrRevealedSpans(fe.FrontendOpts)
+	for _, child := range children.Order {
+		if !fe.ShouldShow(fe.db, child) {
+			continue
+		}
+		rs.Children = append(rs.Children, fe.reportSpan(r, child, now))
+	}
+	return rs
+}
@@ -0,0 +1,123 @@
+package idtui
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dagger/dagger/dagql/dagui"
+	"github.com/dagger/dagger/util/cleanups"
+)
+
+func TestHTMLReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	fe := NewHTML(io.Discard, path)
+
+	traceID := trace.TraceID{1}
+	root := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	})
+	child := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{2},
+	})
+	start := time.Now()
+
+	err := fe.Run(t.Context(), dagui.FrontendOpts{
+		Verbosity: dagui.ShowCompletedVerbosity,
+	}, func(ctx context.Context) (cleanups.CleanupF, error) {
+		return nil, fe.SpanExporter().ExportSpans(ctx, tracetest.SpanStubs{
+			{
+				Name:        "build <everything>",
+				SpanContext: root,
+				StartTime:   start,
+				EndTime:     start.Add(2 * time.Second),
+				Status:      sdktrace.Status{Code: codes.Error, Description: "child failed"},
+			},
+			{
+				Name:        "compile",
+				SpanContext: child,
+				Parent:      root,
+				StartTime:   start,
+				EndTime:     start.Add(time.Second),
+				Status:      sdktrace.Status{Code: codes.Error, Description: "exit code 1"},
+			},
+		}.Snapshots())
+	})
+	require.NoError(t, err)
+
+	html, err := os.ReadFile(path)
+	require.NoError(t, err)
+	// span names are escaped
+	require.Contains(t, string(html), "build &lt;everything&gt;")
+	require.Contains(t, string(html), "compile")
+	require.Contains(t, string(html), `<div class="error">exit code 1</div>`)
+	require.Contains(t, string(html), `<span class="badge failed">failed</span>`)
+}
+
+func TestHTMLReportProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.html")
+	profilePath := filepath.Join(dir, "profile.json")
+	fe := NewHTML(io.Discard, path)
+
+	root := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	start := time.Now()
+
+	err := fe.Run(t.Context(), dagui.FrontendOpts{
+		Verbosity:             dagui.ShowCompletedVerbosity,
+		ProfileOutputFilePath: profilePath,
+	}, func(ctx context.Context) (cleanups.CleanupF, error) {
+		return nil, fe.SpanExporter().ExportSpans(ctx, tracetest.SpanStubs{
+			{
+				Name:        "build",
+				SpanContext: root,
+				StartTime:   start,
+				EndTime:     start.Add(time.Second),
+			},
+		}.Snapshots())
+	})
+	require.NoError(t, err)
+
+	require.FileExists(t, profilePath)
+	html, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(html), "<h2>Profile</h2>")
+	require.Contains(t, string(html), "Critical path")
+}
+
+func TestHTMLSpanLogsTruncation(t *testing.T) {
+	logs := new(htmlSpanLogs)
+	logs.write("first line\n")
+	kept, omitted := logs.tail()
+	require.Equal(t, "first line\n", kept)
+	require.Zero(t, omitted)
+
+	line := strings.Repeat("x", 99) + "\n"
+	for range 3 * htmlMaxSpanLogs / len(line) {
+		logs.write(line)
+	}
+	logs.write("last line\n")
+
+	kept, omitted = logs.tail()
+	require.LessOrEqual(t, len(kept), htmlMaxSpanLogs)
+	require.True(t, strings.HasSuffix(kept, "\nlast line\n"))
+	// the kept logs start at a line boundary
+	require.True(t, strings.HasPrefix(kept, line))
+	require.Equal(t, len(logs.buf)+logs.dropped, omitted+len(kept))
+	require.NotContains(t, kept, "first line")
+}
@@ -209,6 +209,12 @@ func (fe *frontendPlain) Run(ctx context.Context, opts dagui.FrontendOpts, run f
 	fe.finalRender()
 
 	fe.db.WriteDot(opts.DotOutputFilePath, opts.DotFocusField, opts.DotShowInternal)
+	if err := fe.db.WriteProfile(opts.ProfileOutputFilePath, fe.output.Writer()); err != nil {
+		fmt.Fprintln(fe.output.Writer(), "failed to write profile:", err)
+	}
+	if err := fe.db.WriteCacheMisses(fe.output.Writer()); err != nil {
+		fmt.Fprintln(fe.output.Writer(), "failed to write cache misses:", err)
+	}
 
 	return runErr
 }
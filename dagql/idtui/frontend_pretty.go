@@ -354,6 +354,12 @@ func (fe *frontendPretty) Run(ctx context.Context, opts dagui.FrontendOpts, run
 	}
 
 	fe.db.WriteDot(opts.DotOutputFilePath, opts.DotFocusField, opts.DotShowInternal)
+	if err := fe.db.WriteProfile(opts.ProfileOutputFilePath, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write profile:", err)
+	}
+	if err := fe.db.WriteCacheMisses(os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write cache misses:", err)
+	}
 
 	// return original err
 	return fe.err
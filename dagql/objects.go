@@ -32,6 +32,7 @@ type Class[T Typed] struct {
 	fieldsL *sync.Mutex
 
 	invalidateSchemaCache func()
+	installConnection     func(*Connection, *ast.Type)
 }
 
 var _ ObjectType = Class[Typed]{}
@@ -59,6 +60,7 @@ func NewClass[T Typed](srv *Server, opts_ ...ClassOpts[T]) Class[T] {
 		fieldsL: new(sync.Mutex),
 
 		invalidateSchemaCache: srv.invalidateSchemaCache,
+		installConnection:     srv.installConnection,
 	}
 	if !opts.NoIDs {
 		class.Install(
@@ -125,6 +127,29 @@ func (class Class[T]) fieldLocked(name string, view call.View) (Field[T], bool)
 }
 
 func (class Class[T]) Install(fields ...Field[T]) {
+	// install the connection types of paginated fields before locking, since
+	// it may need to extend the root class
+	var connections []Field[T]
+	for _, field := range fields {
+		if !field.Spec.Paginated || field.Spec.extend {
+			continue
+		}
+		// never shadow a field explicitly defined with the same name
+		name := field.Spec.Name + "Connection"
+		if slices.ContainsFunc(fields, func(f Field[T]) bool { return f.Spec.Name == name }) {
+			continue
+		}
+		if _, ok := class.Field(name, ""); ok {
+			continue
+		}
+		conn, err := class.connectionField(field.Spec)
+		if err != nil {
+			panic(err)
+		}
+		connections = append(connections, conn)
+	}
+	fields = append(slices.Clip(fields), connections...)
+
 	class.fieldsL.Lock()
 	defer class.fieldsL.Unlock()
 	for _, field := range fields {
@@ -471,6 +496,7 @@ func (r ObjectResult[T]) Select(ctx context.Context, s *Server, sel Selector) (A
 		preselectResult.newID,
 		preselectResult.inputArgs,
 		preselectResult.cacheKey,
+		preselectResult.keyInputs,
 	)
 }
 
@@ -478,6 +504,9 @@ type preselectResult struct {
 	inputArgs map[string]Input
 	newID     *call.ID
 	cacheKey  CacheKey
+	// the inputs mixed into the cache key by GetCacheConfig, if cache misses
+	// are being explained
+	keyInputs []CacheKeyInput
 }
 
 // sortArgsToSchema sorts the arguments to match the schema definition order.
@@ -554,9 +583,13 @@ func (r ObjectResult[T]) preselect(ctx context.Context, s *Server, sel Selector)
 	)
 
 	cacheKey := newCacheKey(ctx, newID, field.Spec)
+	var keyInputs *cacheKeyInputs
 	if field.Spec.GetCacheConfig != nil {
 		cacheCfgCtx := idToContext(ctx, newID)
 		cacheCfgCtx = srvToContext(cacheCfgCtx, s)
+		if s.Cache.ExplainingMisses() {
+			cacheCfgCtx, keyInputs = withCacheKeyInputs(cacheCfgCtx)
+		}
 		cacheCfgResp, err := field.Spec.GetCacheConfig(cacheCfgCtx, r, inputArgs, view, GetCacheConfigRequest{
 			CacheKey: cacheKey,
 		})
@@ -607,6 +640,7 @@ func (r ObjectResult[T]) preselect(ctx context.Context, s *Server, sel Selector)
 		inputArgs: inputArgs,
 		newID:     newID,
 		cacheKey:  cacheKey,
+		keyInputs: keyInputs.recorded(),
 	}, nil
 }
 
@@ -647,7 +681,7 @@ func (r ObjectResult[T]) Call(ctx context.Context, s *Server, newID *call.ID) (A
 	}
 
 	cacheKey := newCacheKey(ctx, newID, field.Spec)
-	return r.call(ctx, s, newID, inputArgs, cacheKey)
+	return r.call(ctx, s, newID, inputArgs, cacheKey, nil)
 }
 
 func ExtractIDArgs(specs InputSpecs, id *call.ID) (map[string]Input, error) {
@@ -692,6 +726,7 @@ func (r ObjectResult[T]) call(
 	newID *call.ID,
 	inputArgs map[string]Input,
 	cacheKey CacheKey,
+	keyInputs []CacheKeyInput,
 ) (AnyResult, error) {
 	ctx = idToContext(ctx, newID)
 	ctx = srvToContext(ctx, s)
@@ -701,6 +736,12 @@ func (r ObjectResult[T]) call(
 			return s.telemetry(ctx, r, newID)
 		}))
 	}
+	if s.Cache.ExplainingMisses() {
+		opts = append(opts, WithCacheKeyInputs(
+			callPosition(newID),
+			callKeyInputs(newID, keyInputs),
+		))
+	}
 
 	res, err := s.Cache.GetOrInitializeWithCallbacks(ctx, cacheKey, func(ctx context.Context) (*CacheValWithCallbacks, error) {
 		valWithCallbacks, err := r.class.Call(ctx, s, r, newID.Field(), newID.View(), inputArgs)
@@ -964,6 +1005,10 @@ type FieldSpec struct {
 	// If set, the result of this field will be cached for the given TTL (in seconds).
 	TTL int64
 
+	// Paginated installs an additional "<name>Connection" field alongside this
+	// field, which must return a list, to select its elements in pages.
+	Paginated bool
+
 	// If set, this GetCacheConfig will be called before ID evaluation to make
 	// any dynamic adjustments to the cache key or args
 	GetCacheConfig GenericGetCacheConfigFunc
@@ -989,6 +1034,9 @@ func (spec FieldSpec) FieldDefinition(view call.View) *ast.FieldDefinition {
 	if spec.ExperimentalReason != "" {
 		def.Directives = append(def.Directives, experimental(spec.ExperimentalReason))
 	}
+	if _, ok := spec.Type.(AnyStream); ok {
+		def.Directives = append(def.Directives, streaming())
+	}
 	return def
 }
 
@@ -1351,6 +1399,16 @@ func (field Field[T]) View(view ViewFilter) Field[T] {
 	return field
 }
 
+// Paginated installs an additional "<name>Connection" field alongside this
+// one, selecting the elements of the list it returns in pages. See Connection.
+func (field Field[T]) Paginated() Field[T] {
+	if field.Spec.extend {
+		panic("cannot call on extended field")
+	}
+	field.Spec.Paginated = true
+	return field
+}
+
 // DoNotCache marks the field as not to be stored in the cache for the given reason why
 func (field Field[T]) DoNotCache(reason string, paras ...string) Field[T] {
 	if field.Spec.extend {
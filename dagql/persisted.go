@@ -0,0 +1,91 @@
+package dagql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	// persistedQueriesSize is the number of persisted queries kept in memory.
+	persistedQueriesSize = 10000
+
+	// queryCacheSize is the number of parsed queries kept for each server.
+	queryCacheSize = 1000
+)
+
+// persistedQueries maps the SHA-256 hash of persisted queries to their text,
+// following the automatic persisted queries protocol: clients send the hash
+// of a query, and fall back to sending its full text if it isn't known yet.
+//
+// Hashes are verified when a query is persisted and don't depend on the
+// schema, so all servers share them.
+var persistedQueries = newQueryCache[string](persistedQueriesSize)
+
+// PersistedQueries returns the handler extension resolving persisted queries
+// sent by clients.
+func PersistedQueries() graphql.HandlerExtension {
+	return extension.AutomaticPersistedQuery{
+		Cache: persistedQueries,
+	}
+}
+
+// queryCache is a graphql.Cache of parsed queries.
+type queryCache[T any] struct {
+	*lru.Cache[string, T]
+}
+
+var _ graphql.Cache[any] = queryCache[any]{}
+
+func newQueryCache[T any](size int) queryCache[T] {
+	cache, err := lru.New[string, T](size)
+	if err != nil {
+		// only returned for non-positive sizes
+		panic(err)
+	}
+	return queryCache[T]{cache}
+}
+
+func (c queryCache[T]) Get(_ context.Context, key string) (T, bool) {
+	return c.Cache.Get(key)
+}
+
+func (c queryCache[T]) Add(_ context.Context, key string, value T) {
+	c.Cache.Add(key, value)
+}
+
+// QueryCache returns the cache of parsed and validated queries for the
+// server's schema, which is cleared whenever the schema changes.
+func (s *Server) QueryCache() graphql.Cache[*ast.QueryDocument] {
+	return s.queryDocs
+}
+
+// selectionsKey returns the key of an operation's parsed selections in the
+// server's cache.
+func (s *Server) selectionsKey(rawQuery string, op *ast.OperationDefinition) string {
+	return string(s.View) + "\x00" + op.Name + "\x00" + rawQuery
+}
+
+// parseOpSelections parses the selections of an operation, reusing the
+// selections parsed for previous executions of the same query.
+//
+// Operations with variables are parsed every time, since their selections
+// depend on the values of the variables.
+func (s *Server) parseOpSelections(ctx context.Context, gqlOp *graphql.OperationContext, op *ast.OperationDefinition) ([]Selection, error) {
+	if len(op.VariableDefinitions) > 0 {
+		return s.parseASTSelections(ctx, gqlOp, s.root.Type(), op.SelectionSet)
+	}
+	key := s.selectionsKey(gqlOp.RawQuery, op)
+	if sels, ok := s.querySelections.Get(ctx, key); ok {
+		return sels, nil
+	}
+	sels, err := s.parseASTSelections(ctx, gqlOp, s.root.Type(), op.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+	s.querySelections.Add(ctx, key, sels)
+	return sels, nil
+}
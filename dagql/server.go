@@ -8,10 +8,13 @@ import (
 	"reflect"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/errcode"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/iancoleman/strcase"
 	"github.com/opencontainers/go-digest"
 	"github.com/sourcegraph/conc/pool"
@@ -42,6 +45,11 @@ type Server struct {
 	schemaOnces   map[call.View]*sync.Once
 	schemaLock    *sync.Mutex
 
+	// queryDocs and querySelections cache parsed queries for the current
+	// schema.
+	queryDocs       queryCache[*ast.QueryDocument]
+	querySelections queryCache[[]Selection]
+
 	installLock  *sync.Mutex
 	installHooks []InstallHook
 
@@ -117,6 +125,9 @@ func NewServer[T Typed](root T, c *SessionCache) *Server {
 		schemaDigests: make(map[call.View]digest.Digest),
 		schemaOnces:   make(map[call.View]*sync.Once),
 		schemaLock:    &sync.Mutex{},
+
+		queryDocs:       newQueryCache[*ast.QueryDocument](queryCacheSize),
+		querySelections: newQueryCache[[]Selection](queryCacheSize),
 	}
 	rootClass := NewClass(srv, ClassOpts[T]{
 		// NB: there's nothing actually stopping this from being a thing, except it
@@ -144,11 +155,36 @@ func (s *Server) invalidateSchemaCache() {
 	clear(s.schemaDigests)
 	clear(s.schemaOnces)
 	s.schemaLock.Unlock()
+
+	// queries validated and parsed against the previous schema may no longer
+	// be valid
+	s.queryDocs.Purge()
+	s.querySelections.Purge()
 }
 
 func NewDefaultHandler(es graphql.ExecutableSchema) *handler.Server {
-	// TODO: avoid this deprecated method, and customize the options
-	srv := handler.NewDefaultServer(es)
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	// before POST, which would reject a JSON array
+	srv.AddTransport(BatchPOST{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.Use(extension.Introspection{})
+	srv.Use(PersistedQueries())
+
+	// handlers are typically created for each request, so keep parsed queries
+	// on the server when possible
+	if dag, ok := es.(*Server); ok {
+		srv.SetQueryCache(dag.QueryCache())
+	} else {
+		srv.SetQueryCache(newQueryCache[*ast.QueryDocument](queryCacheSize))
+	}
 
 	srv.SetValidationRulesFn(func() *rules.Rules {
 		validationRules := rules.NewDefaultRules()
@@ -309,6 +345,22 @@ var coreDirectives = []DirectiveSpec{
 			DirectiveLocationFieldDefinition,
 		},
 	},
+	{
+		Name:        "prompt",
+		Description: FormatDescription(`Indicates that this function renders a prompt template.`),
+		Args:        NewInputSpecs(), // none
+		Locations: []DirectiveLocation{
+			DirectiveLocationFieldDefinition,
+		},
+	},
+	{
+		Name:        "streaming",
+		Description: FormatDescription(`Indicates that this field streams its values, and can only be selected in a subscription.`),
+		Args:        NewInputSpecs(), // none
+		Locations: []DirectiveLocation{
+			DirectiveLocationFieldDefinition,
+		},
+	},
 }
 
 // Root returns the root object of the server. It is suitable for passing to
@@ -462,6 +514,9 @@ func (s *Server) Schema() *ast.Schema {
 			def := definition(ast.Object, t, view)
 			if def.Name == queryType {
 				schema.Query = def
+				// subscriptions select a chain of fields from the root, ending in
+				// a streaming field
+				schema.Subscription = def
 			}
 			schema.AddTypes(def)
 			schema.AddPossibleType(def.Name, def)
@@ -511,7 +566,19 @@ type ExtendedError interface {
 
 // Exec implements graphql.ExecutableSchema.
 func (s *Server) Exec(ctx1 context.Context) graphql.ResponseHandler {
+	if gqlOp := graphql.GetOperationContext(ctx1); gqlOp.Operation != nil && gqlOp.Operation.Operation == ast.Subscription {
+		return s.execSubscription(ctx1, gqlOp)
+	}
+
+	// transports that support subscriptions keep calling the handler until it
+	// returns nil, so only respond once
+	var responded bool
 	return func(ctx context.Context) (res *graphql.Response) {
+		if responded {
+			return nil
+		}
+		responded = true
+
 		gqlOp := graphql.GetOperationContext(ctx)
 
 		if err := gqlOp.Validate(ctx); err != nil {
@@ -536,6 +603,57 @@ func (s *Server) Exec(ctx1 context.Context) graphql.ResponseHandler {
 	}
 }
 
+// execSubscription runs a subscription in the background, returning a handler
+// that responds with each of its events in turn.
+func (s *Server) execSubscription(ctx context.Context, gqlOp *graphql.OperationContext) graphql.ResponseHandler {
+	if err := gqlOp.Validate(ctx); err != nil {
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "validate: %s", err))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	responses := make(chan *graphql.Response)
+	send := func(res *graphql.Response) bool {
+		select {
+		case responses <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(responses)
+		err := s.Subscribe(ctx, gqlOp, func(results map[string]any) error {
+			data, err := json.Marshal(results)
+			if err != nil {
+				return fmt.Errorf("marshal: %w", err)
+			}
+			if !send(&graphql.Response{Data: json.RawMessage(data)}) {
+				return context.Cause(ctx)
+			}
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			send(&graphql.Response{
+				Errors: gqlErrs(err),
+			})
+		}
+	}()
+
+	return func(ctx context.Context) *graphql.Response {
+		select {
+		case res, ok := <-responses:
+			if !ok {
+				cancel()
+				return nil
+			}
+			return res
+		case <-ctx.Done():
+			cancel()
+			return nil
+		}
+	}
+}
+
 func gqlErrs(err error) (errs gqlerror.List) {
 	if list, ok := err.(gqlerror.List); ok {
 		return list
@@ -550,22 +668,32 @@ func gqlErrs(err error) (errs gqlerror.List) {
 	return
 }
 
-func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (map[string]any, error) {
-	if gqlOp.Doc == nil {
-		var err error
-		gqlOp.Doc, err = parser.ParseQuery(&ast.Source{Input: gqlOp.RawQuery})
-		if err != nil {
-			return nil, gqlErrs(err)
-		}
+// parseOp parses and validates the operation's query, if it hasn't been
+// already.
+func (s *Server) parseOp(gqlOp *graphql.OperationContext) error {
+	if gqlOp.Doc != nil {
+		return nil
+	}
+	var err error
+	gqlOp.Doc, err = parser.ParseQuery(&ast.Source{Input: gqlOp.RawQuery})
+	if err != nil {
+		return gqlErrs(err)
+	}
 
-		//nolint:staticcheck // annoying, but we can't easily switch to this without inconsistencies
-		listErr := validator.Validate(s.Schema(), gqlOp.Doc)
-		if len(listErr) != 0 {
-			for _, e := range listErr {
-				errcode.Set(e, errcode.ValidationFailed)
-			}
-			return nil, listErr
+	//nolint:staticcheck // annoying, but we can't easily switch to this without inconsistencies
+	listErr := validator.Validate(s.Schema(), gqlOp.Doc)
+	if len(listErr) != 0 {
+		for _, e := range listErr {
+			errcode.Set(e, errcode.ValidationFailed)
 		}
+		return listErr
+	}
+	return nil
+}
+
+func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (map[string]any, error) {
+	if err := s.parseOp(gqlOp); err != nil {
+		return nil, err
 	}
 	results := make(map[string]any)
 	for _, op := range gqlOp.Doc.Operations {
@@ -574,7 +702,7 @@ func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (m
 			if gqlOp.OperationName != "" && gqlOp.OperationName != op.Name {
 				continue
 			}
-			sels, err := s.parseASTSelections(ctx, gqlOp, s.root.Type(), op.SelectionSet)
+			sels, err := s.parseOpSelections(ctx, gqlOp, op)
 			if err != nil {
 				return nil, fmt.Errorf("query:\n%s\n\nerror: parse selections: %w", gqlOp.RawQuery, err)
 			}
@@ -586,13 +714,113 @@ func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (m
 			// TODO
 			return nil, fmt.Errorf("mutations not supported")
 		case ast.Subscription:
-			// TODO
-			return nil, fmt.Errorf("subscriptions not supported")
+			if gqlOp.OperationName != "" && gqlOp.OperationName != op.Name {
+				continue
+			}
+			return nil, fmt.Errorf("subscriptions must be executed with Subscribe")
 		}
 	}
 	return results, nil
 }
 
+// Subscribe executes a subscription, calling emit with the results for each
+// event until the stream ends or the context is canceled.
+//
+// A subscription selects a single chain of fields from the root, ending in a
+// field that returns a Stream. Each element of the stream is resolved against
+// the selections beneath that field.
+func (s *Server) Subscribe(ctx context.Context, gqlOp *graphql.OperationContext, emit func(map[string]any) error) error {
+	if err := s.parseOp(gqlOp); err != nil {
+		return err
+	}
+	var op *ast.OperationDefinition
+	for _, candidate := range gqlOp.Doc.Operations {
+		if candidate.Operation != ast.Subscription {
+			continue
+		}
+		if gqlOp.OperationName != "" && gqlOp.OperationName != candidate.Name {
+			continue
+		}
+		op = candidate
+		break
+	}
+	if op == nil {
+		return fmt.Errorf("no subscription found in query")
+	}
+	sels, err := s.parseASTSelections(ctx, gqlOp, s.root.Type(), op.SelectionSet)
+	if err != nil {
+		return fmt.Errorf("query:\n%s\n\nerror: parse selections: %w", gqlOp.RawQuery, err)
+	}
+	if len(sels) != 1 {
+		return fmt.Errorf("subscriptions must select exactly one field, got %d", len(sels))
+	}
+	sel := sels[0]
+	return s.subscribePath(ctx, s.root, sel, func(res any) error {
+		return emit(map[string]any{sel.Name(): res})
+	})
+}
+
+func (s *Server) subscribePath(ctx context.Context, self AnyObjectResult, sel Selection, emit func(any) error) (rerr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			rerr = PanicError{
+				Cause:     r,
+				Self:      self,
+				Selection: sel,
+				Stack:     debug.Stack(),
+			}
+		}
+
+		if rerr != nil {
+			rerr = gqlErr(rerr, append(idToPath(self.ID()), ast.PathName(sel.Name())))
+		}
+	}()
+
+	val, err := self.Select(ctx, s, sel.Selector)
+	if err != nil {
+		return err
+	}
+	if val == nil {
+		return fmt.Errorf("cannot subscribe to null value")
+	}
+
+	if stream, ok := val.Unwrap().(AnyStream); ok {
+		return stream.subscribe(ctx, val.ID(), func(elem AnyResult) error {
+			res, err := s.resolveEvent(ctx, elem, sel)
+			if err != nil {
+				return err
+			}
+			return emit(res)
+		})
+	}
+
+	if _, ok := val.Unwrap().(Enumerable); ok {
+		return fmt.Errorf("cannot subscribe to a field within a list")
+	}
+	if len(sel.Subselections) != 1 {
+		return fmt.Errorf("subscriptions must select exactly one field of %s, got %d", val.Type().Name(), len(sel.Subselections))
+	}
+	node, err := s.toSelectable(val)
+	if err != nil {
+		return fmt.Errorf("instantiate: %w", err)
+	}
+	subsel := sel.Subselections[0]
+	return s.subscribePath(ctx, node, subsel, func(res any) error {
+		return emit(map[string]any{subsel.Name(): res})
+	})
+}
+
+// resolveEvent resolves the selections for a single element of a stream.
+//
+// Each element is resolved with its own cache, so that a long-running stream
+// doesn't hold on to every element it has emitted, and without telemetry,
+// since the stream itself is already accounted for.
+func (s *Server) resolveEvent(ctx context.Context, elem AnyResult, sel Selection) (any, error) {
+	cache := NewSessionCache(s.Cache.cache)
+	defer cache.ReleaseAndClose(ctx)
+	return s.WithCache(cache).resolveValue(WithSkip(ctx), elem, sel)
+}
+
 // Resolve resolves the given selections on the given object.
 //
 // Each selection is resolved in parallel, and the results are returned in a
@@ -996,11 +1224,20 @@ func (s *Server) resolvePath(ctx context.Context, self AnyObjectResult, sel Sele
 		return nil, err
 	}
 
+	return s.resolveValue(ctx, val, sel)
+}
+
+// resolveValue resolves the subselections of a selected value.
+func (s *Server) resolveValue(ctx context.Context, val AnyResult, sel Selection) (any, error) {
 	if val == nil {
 		// a nil value ignores all sub-selections
 		return nil, nil
 	}
 
+	if _, ok := val.Unwrap().(AnyStream); ok {
+		return nil, fmt.Errorf("%s can only be selected in a subscription", sel.Selector.Field)
+	}
+
 	enum, ok := val.Unwrap().(Enumerable)
 	if ok {
 		// we're sub-selecting into an enumerable value, so we need to resolve each
@@ -2,10 +2,17 @@ package dagql
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine/cache"
+	"github.com/dagger/dagger/engine/slog"
+	"github.com/dagger/dagger/util/hashutil"
 )
 
 type CacheKeyType = string
@@ -31,6 +38,14 @@ type SessionCache struct {
 
 	// noCacheNext keeps track of keys for which the next cache attempt should bypass the cache.
 	noCacheNext sync.Map
+
+	// explainMisses indicates that the inputs to each call's key should be
+	// recorded, and that calls that miss the cache should explain why.
+	explainMisses bool
+
+	// explainScope identifies who the recorded inputs belong to, so that they
+	// are only ever compared against (and shown to) the same caller.
+	explainScope string
 }
 
 func NewSessionCache(
@@ -41,12 +56,36 @@ func NewSessionCache(
 	}
 }
 
+// ExplainMisses makes the cache record the inputs to each call's key, and
+// annotate the span of each call that misses the cache with the first input
+// that differs from the previous call at the same position.
+//
+// The recorded inputs can include client IDs and argument values, so they're
+// scoped to the given caller: a call is only ever compared against previous
+// calls recorded with the same scope.
+//
+// It must be called before the cache is used.
+func (c *SessionCache) ExplainMisses(scope string) {
+	c.explainMisses = true
+	c.explainScope = scope
+}
+
+// ExplainingMisses returns whether ExplainMisses was called.
+func (c *SessionCache) ExplainingMisses() bool {
+	return c.explainMisses
+}
+
 type CacheCallOpt interface {
 	SetCacheCallOpt(*CacheCallOpts)
 }
 
 type CacheCallOpts struct {
 	Telemetry TelemetryFunc
+
+	// The position of the call and the inputs to its key, for explaining
+	// cache misses.
+	KeyPosition string
+	KeyInputs   []CacheKeyInput
 }
 
 type TelemetryFunc func(context.Context) (context.Context, func(AnyResult, bool, *error))
@@ -67,6 +106,13 @@ func WithTelemetry(telemetry TelemetryFunc) CacheCallOpt {
 	})
 }
 
+func WithCacheKeyInputs(position string, inputs []CacheKeyInput) CacheCallOpt {
+	return CacheCallOptFunc(func(opts *CacheCallOpts) {
+		opts.KeyPosition = position
+		opts.KeyInputs = inputs
+	})
+}
+
 func (c *SessionCache) GetOrInitializeValue(
 	ctx context.Context,
 	key cache.CacheKey[CacheKeyType],
@@ -147,7 +193,8 @@ func (c *SessionCache) GetOrInitializeWithCallbacks(
 		keys = &c.seenKeys
 	}
 	_, seen := keys.LoadOrStore(key.CallKey, struct{}{})
-	if o.Telemetry != nil && (!seen || key.DoNotCache) {
+	traced := o.Telemetry != nil && (!seen || key.DoNotCache)
+	if traced {
 		// track keys globally in addition to any local key stores, otherwise we'll
 		// see dupes when e.g. IDs returned out of the "bubble" are loaded
 		c.seenKeys.Store(key.CallKey, struct{}{})
@@ -173,6 +220,13 @@ func (c *SessionCache) GetOrInitializeWithCallbacks(
 		forcedDoNotCache = true
 	}
 
+	explain := c.explainMisses && traced && o.KeyInputs != nil && !key.DoNotCache
+	var position string
+	if explain {
+		position = hashutil.HashStrings(c.explainScope, o.KeyPosition).String()
+		fn = c.explainingMiss(key.CallKey, position, o.KeyInputs, fn)
+	}
+
 	res, err = c.cache.GetOrInitializeWithCallbacks(ctx, key, fn)
 	if err != nil {
 		// mark that the next attempt should run with DoNotCache
@@ -183,6 +237,10 @@ func (c *SessionCache) GetOrInitializeWithCallbacks(
 	// success: we're in a good state now, allow normal caching again
 	c.noCacheNext.Delete(key.CallKey)
 
+	if explain {
+		c.recordKeyInputs(ctx, key.CallKey, position, o.KeyInputs)
+	}
+
 	// If we forced DoNotCache due to a prior failure, we need to re-insert the successful
 	// result into the underlying cache under the original key so subsequent calls find it.
 	// The call above used a random storage key (due to DoNotCache=true), so the result
@@ -213,6 +271,42 @@ func (c *SessionCache) GetOrInitializeWithCallbacks(
 	return res, nil
 }
 
+// explainingMiss wraps fn, which is only called if the call missed the cache,
+// to annotate the call's span with why it missed.
+func (c *SessionCache) explainingMiss(
+	callKey string,
+	position string,
+	inputs []CacheKeyInput,
+	fn func(context.Context) (*CacheValWithCallbacks, error),
+) func(context.Context) (*CacheValWithCallbacks, error) {
+	return func(ctx context.Context) (*CacheValWithCallbacks, error) {
+		prevs, err := c.cache.CallInputs(ctx, position)
+		if err != nil {
+			slog.Warn("failed to load previous cache key inputs", "err", err)
+		} else {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String(
+				telemetry.DagCacheMissReasonAttr,
+				explainCacheMiss(callKey, inputs, prevs),
+			))
+		}
+		return fn(ctx)
+	}
+}
+
+func (c *SessionCache) recordKeyInputs(ctx context.Context, callKey, position string, inputs []CacheKeyInput) {
+	payload, err := json.Marshal(inputs)
+	if err != nil {
+		slog.Warn("failed to encode cache key inputs", "err", err)
+		return
+	}
+	if err := c.cache.RecordCallInputs(ctx, position, cache.CallInputs{
+		CallKey: callKey,
+		Inputs:  payload,
+	}); err != nil {
+		slog.Warn("failed to record cache key inputs", "err", err)
+	}
+}
+
 func (c *SessionCache) ReleaseAndClose(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,72 @@
+package dagql
+
+import (
+	"context"
+	"crypto/rand"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/util/hashutil"
+)
+
+// Stream is a value that emits a sequence of values over time.
+//
+// A field returning a Stream has the type of its elements in the schema, and
+// can only be selected in a subscription, where each element is sent to the
+// client as it's emitted.
+type Stream[T Typed] struct {
+	// Subscribe calls emit for each element of the stream, returning once the
+	// stream ends or the context is canceled. If emit returns an error, the
+	// stream must stop and return it.
+	Subscribe func(ctx context.Context, emit func(T) error) error
+}
+
+// NewStream returns a Stream that emits values using the given function.
+func NewStream[T Typed](subscribe func(ctx context.Context, emit func(T) error) error) Stream[T] {
+	return Stream[T]{Subscribe: subscribe}
+}
+
+// AnyStream is a Stream of any type.
+type AnyStream interface {
+	Typed
+
+	// subscribe emits each element of the stream as a Result, with an ID
+	// derived from the stream's ID.
+	subscribe(ctx context.Context, id *call.ID, emit func(AnyResult) error) error
+}
+
+var _ AnyStream = Stream[Typed]{}
+
+// Type returns the type of the stream's elements.
+func (Stream[T]) Type() *ast.Type {
+	var zero T
+	return zero.Type()
+}
+
+func (s Stream[T]) subscribe(ctx context.Context, id *call.ID, emit func(AnyResult) error) error {
+	// each element is only ever seen by this subscriber, so make sure its ID
+	// doesn't collide with elements of any other subscription to the same
+	// stream
+	nonce := rand.Text()
+	var nth int
+	return s.Subscribe(ctx, func(val T) error {
+		nth++
+		res, err := NewResultForID(val, id.With(
+			call.WithReceiver(id),
+			call.WithNth(nth),
+			call.WithCustomDigest(hashutil.HashStrings(id.Digest().String(), nonce, strconv.Itoa(nth))),
+		))
+		if err != nil {
+			return err
+		}
+		return emit(res)
+	})
+}
+
+func streaming() *ast.Directive {
+	return &ast.Directive{
+		Name: "streaming",
+	}
+}
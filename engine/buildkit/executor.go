@@ -23,6 +23,7 @@ import (
 	"github.com/containerd/console"
 	runc "github.com/containerd/go-runc"
 	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/metrics"
 	"github.com/dagger/dagger/engine/server/resource"
 	"github.com/dagger/dagger/internal/buildkit/client/llb"
 	"github.com/dagger/dagger/internal/buildkit/executor"
@@ -199,10 +200,12 @@ func (w *Worker) run(
 	w.mu.Lock()
 	w.running[state.id] = state
 	w.mu.Unlock()
+	metrics.RunningExecs.Inc()
 	defer func() {
 		w.mu.Lock()
 		delete(w.running, state.id)
 		w.mu.Unlock()
+		metrics.RunningExecs.Dec()
 
 		close(state.done)
 		if err := state.cleanups.Run(); err != nil {
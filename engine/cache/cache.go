@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"sync"
 	"time"
 
@@ -46,6 +47,14 @@ type Cache[K KeyType, V any] interface {
 
 	// Run a blocking loop that periodically garbage collects expired entries from the cache db.
 	GCLoop(context.Context)
+
+	// Returns the inputs recorded for the most recent calls at the given position, most
+	// recent first.
+	CallInputs(ctx context.Context, position string) ([]CallInputs, error)
+
+	// Record the inputs to a call at the given position, so that later calls at the
+	// same position can be compared against it.
+	RecordCallInputs(ctx context.Context, position string, inputs CallInputs) error
 }
 
 type Result[K KeyType, V any] interface {
@@ -81,6 +90,13 @@ type CacheKey[K KeyType] struct {
 	DoNotCache bool
 }
 
+// CallInputs describes what went into a call's key, so that a later call can
+// explain why it missed the cache. The inputs are opaque to the cache.
+type CallInputs struct {
+	CallKey string
+	Inputs  []byte
+}
+
 type PostCallFunc = func(context.Context) error
 
 type OnReleaseFunc = func(context.Context) error
@@ -182,7 +198,11 @@ type cache[K KeyType, V any] struct {
 	completedCalls map[string]*result[K, V]
 
 	// db for persistence; currently only used for metadata supporting ttl-based expiration
+	// and the inputs to calls
 	db *cachedb.Queries
+
+	// inputs to calls keyed by position, most recent first; only used when there's no db
+	callInputs map[string][]CallInputs
 }
 
 type callConcurrencyKeys struct {
@@ -242,12 +262,17 @@ func (c *cache[K, V]) GCLoop(ctx context.Context) {
 		case <-ticker.C:
 		}
 
-		now := time.Now().Unix()
+		now := time.Now()
 		if err := c.db.GCExpiredCalls(ctx, cachedb.GCExpiredCallsParams{
-			Now: now,
+			Now: now.Unix(),
 		}); err != nil {
 			slog.Warn("failed to GC expired function calls", "err", err)
 		}
+		if err := c.db.GCCallInputs(ctx, cachedb.GCCallInputsParams{
+			Before: now.Add(-callInputsRetention).UnixNano(),
+		}); err != nil {
+			slog.Warn("failed to GC call inputs", "err", err)
+		}
 	}
 }
 
@@ -258,6 +283,61 @@ func (c *cache[K, V]) Size() int {
 	return len(c.ongoingCalls) + len(c.completedCalls)
 }
 
+// The number of calls to keep inputs for at each position when there's no db.
+const callInputsPerPosition = 16
+
+// How long to keep the inputs of calls that haven't been made since.
+const callInputsRetention = 7 * 24 * time.Hour
+
+func (c *cache[K, V]) CallInputs(ctx context.Context, position string) ([]CallInputs, error) {
+	if c.db == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return slices.Clone(c.callInputs[position]), nil
+	}
+	rows, err := c.db.SelectCallInputs(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]CallInputs, len(rows))
+	for i, row := range rows {
+		inputs[i] = CallInputs{
+			CallKey: row.CallKey,
+			Inputs:  row.Inputs,
+		}
+	}
+	return inputs, nil
+}
+
+func (c *cache[K, V]) RecordCallInputs(ctx context.Context, position string, inputs CallInputs) error {
+	if c.db == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.callInputs == nil {
+			c.callInputs = make(map[string][]CallInputs)
+		}
+		recent := slices.DeleteFunc(c.callInputs[position], func(prev CallInputs) bool {
+			return prev.CallKey == inputs.CallKey
+		})
+		recent = append([]CallInputs{inputs}, recent...)
+		if len(recent) > callInputsPerPosition {
+			recent = recent[:callInputsPerPosition]
+		}
+		c.callInputs[position] = recent
+		return nil
+	}
+	if inputs.Inputs == nil {
+		// the column is NOT NULL
+		inputs.Inputs = []byte{}
+	}
+	return c.db.SetCallInputs(ctx, cachedb.SetCallInputsParams{
+		Position: position,
+		CallKey:  inputs.CallKey,
+		Inputs:   inputs.Inputs,
+		Updated:  time.Now().UnixNano(),
+	})
+}
+
 func (c *cache[K, V]) GetOrInitializeValue(
 	ctx context.Context,
 	key CacheKey[K],
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -427,3 +428,48 @@ func TestSkipDedupe(t *testing.T) {
 		t.Fatal("timed out waiting for resCh2")
 	}
 }
+
+func TestCallInputs(t *testing.T) {
+	t.Parallel()
+	for name, dbPath := range map[string]string{
+		"memory": "",
+		"db":     filepath.Join(t.TempDir(), "cache.db"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			c, err := NewCache[string, int](ctx, dbPath)
+			assert.NilError(t, err)
+
+			inputs, err := c.CallInputs(ctx, "pos")
+			assert.NilError(t, err)
+			assert.Assert(t, is.Len(inputs, 0))
+
+			for i := range callInputsPerPosition + 2 {
+				assert.NilError(t, c.RecordCallInputs(ctx, "pos", CallInputs{
+					CallKey: fmt.Sprintf("key-%d", i),
+					Inputs:  []byte(fmt.Sprintf("inputs-%d", i)),
+				}))
+			}
+			// re-recording a call makes it the most recent
+			assert.NilError(t, c.RecordCallInputs(ctx, "pos", CallInputs{
+				CallKey: "key-5",
+				Inputs:  []byte("inputs-5-again"),
+			}))
+			assert.NilError(t, c.RecordCallInputs(ctx, "other", CallInputs{
+				CallKey: "other-key",
+			}))
+
+			inputs, err = c.CallInputs(ctx, "pos")
+			assert.NilError(t, err)
+			assert.Assert(t, is.Len(inputs, callInputsPerPosition))
+			assert.Equal(t, inputs[0].CallKey, "key-5")
+			assert.Equal(t, string(inputs[0].Inputs), "inputs-5-again")
+			assert.Equal(t, inputs[1].CallKey, fmt.Sprintf("key-%d", callInputsPerPosition+1))
+			for _, in := range inputs {
+				// the oldest calls were pruned
+				assert.Assert(t, in.CallKey != "key-0" && in.CallKey != "key-1")
+			}
+		})
+	}
+}
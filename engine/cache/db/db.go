@@ -30,6 +30,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.setExpirationStmt, err = db.PrepareContext(ctx, setExpiration); err != nil {
 		return nil, fmt.Errorf("error preparing query SetExpiration: %w", err)
 	}
+	if q.selectCallInputsStmt, err = db.PrepareContext(ctx, selectCallInputs); err != nil {
+		return nil, fmt.Errorf("error preparing query SelectCallInputs: %w", err)
+	}
+	if q.setCallInputsStmt, err = db.PrepareContext(ctx, setCallInputs); err != nil {
+		return nil, fmt.Errorf("error preparing query SetCallInputs: %w", err)
+	}
 	return &q, nil
 }
 
@@ -45,6 +51,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing setExpirationStmt: %w", cerr)
 		}
 	}
+	if q.selectCallInputsStmt != nil {
+		if cerr := q.selectCallInputsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing selectCallInputsStmt: %w", cerr)
+		}
+	}
+	if q.setCallInputsStmt != nil {
+		if cerr := q.setCallInputsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setCallInputsStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -59,6 +75,17 @@ func (q *Queries) exec(ctx context.Context, stmt *sql.Stmt, query string, args .
 	}
 }
 
+func (q *Queries) query(ctx context.Context, stmt *sql.Stmt, query string, args ...any) (*sql.Rows, error) {
+	switch {
+	case stmt != nil && q.tx != nil:
+		return q.tx.StmtContext(ctx, stmt).QueryContext(ctx, args...)
+	case stmt != nil:
+		return stmt.QueryContext(ctx, args...)
+	default:
+		return q.db.QueryContext(ctx, query, args...)
+	}
+}
+
 func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, args ...any) *sql.Row {
 	switch {
 	case stmt != nil && q.tx != nil:
@@ -71,17 +98,21 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                DBTX
-	tx                *sql.Tx
-	selectCallStmt    *sql.Stmt
-	setExpirationStmt *sql.Stmt
+	db                   DBTX
+	tx                   *sql.Tx
+	selectCallStmt       *sql.Stmt
+	setExpirationStmt    *sql.Stmt
+	selectCallInputsStmt *sql.Stmt
+	setCallInputsStmt    *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                tx,
-		tx:                tx,
-		selectCallStmt:    q.selectCallStmt,
-		setExpirationStmt: q.setExpirationStmt,
+		db:                   tx,
+		tx:                   tx,
+		selectCallStmt:       q.selectCallStmt,
+		setExpirationStmt:    q.setExpirationStmt,
+		selectCallInputsStmt: q.selectCallInputsStmt,
+		setCallInputsStmt:    q.setCallInputsStmt,
 	}
 }
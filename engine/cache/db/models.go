@@ -5,3 +5,10 @@ type Call struct {
 	StorageKey string
 	Expiration int64
 }
+
+type CallInputs struct {
+	Position string
+	CallKey  string
+	Inputs   []byte
+	Updated  int64
+}
@@ -71,3 +71,91 @@ func (q *Queries) GCExpiredCalls(ctx context.Context, arg GCExpiredCallsParams)
 	}
 	return nil
 }
+
+const selectCallInputs = `
+SELECT position, call_key, inputs, updated FROM call_inputs
+WHERE position = ?
+ORDER BY updated DESC
+LIMIT ` + callInputsPerPositionStr
+
+func (q *Queries) SelectCallInputs(ctx context.Context, position string) ([]*CallInputs, error) {
+	rows, err := q.query(ctx, q.selectCallInputsStmt, selectCallInputs, position)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CallInputs
+	for rows.Next() {
+		var i CallInputs
+		if err := rows.Scan(&i.Position, &i.CallKey, &i.Inputs, &i.Updated); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Only the most recent calls at each position are kept, which is enough to
+// tell apart sibling calls without the table growing forever.
+const callInputsPerPositionStr = "16"
+
+const setCallInputs = `
+INSERT INTO call_inputs (position, call_key, inputs, updated)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (position, call_key) DO UPDATE SET
+	inputs = EXCLUDED.inputs,
+	updated = EXCLUDED.updated
+`
+
+type SetCallInputsParams struct {
+	Position string
+	CallKey  string
+	Inputs   []byte
+	Updated  int64
+}
+
+func (q *Queries) SetCallInputs(ctx context.Context, arg SetCallInputsParams) error {
+	_, err := q.exec(ctx, q.setCallInputsStmt, setCallInputs,
+		arg.Position, arg.CallKey, arg.Inputs, arg.Updated,
+	)
+	return err
+}
+
+// Delete the inputs of calls that aren't among the most recent at their
+// position, or that haven't been seen since before the given time, in batches
+// like gcExpiredCalls.
+const gcCallInputs = `
+DELETE FROM call_inputs
+WHERE (position, call_key) IN (
+	SELECT position, call_key FROM (
+		SELECT position, call_key, updated,
+			ROW_NUMBER() OVER (PARTITION BY position ORDER BY updated DESC) AS recency
+		FROM call_inputs
+	)
+	WHERE recency > ` + callInputsPerPositionStr + ` OR updated < ?
+	LIMIT ` + gcBatchSizeStr + `
+)`
+
+type GCCallInputsParams struct {
+	Before int64
+}
+
+func (q *Queries) GCCallInputs(ctx context.Context, arg GCCallInputsParams) error {
+	for {
+		result, err := q.exec(ctx, nil, gcCallInputs, arg.Before)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected < gcBatchSize {
+			break
+		}
+	}
+	return nil
+}
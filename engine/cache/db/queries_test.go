@@ -0,0 +1,60 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	_ "modernc.org/sqlite"
+)
+
+func TestGCCallInputs(t *testing.T) {
+	ctx := t.Context()
+	sqlDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "cache.db"))
+	assert.NilError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	_, err = sqlDB.Exec(Schema)
+	assert.NilError(t, err)
+	q, err := Prepare(ctx, sqlDB)
+	assert.NilError(t, err)
+	t.Cleanup(func() { q.Close() })
+
+	count := func(position string) int {
+		var n int
+		assert.NilError(t, sqlDB.QueryRow(`SELECT COUNT(*) FROM call_inputs WHERE position = ?`, position).Scan(&n))
+		return n
+	}
+
+	// more calls than are kept at one position, all recent
+	for i := range 20 {
+		assert.NilError(t, q.SetCallInputs(ctx, SetCallInputsParams{
+			Position: "busy",
+			CallKey:  fmt.Sprintf("key-%d", i),
+			Inputs:   []byte{},
+			Updated:  int64(1000 + i),
+		}))
+	}
+	// a single call that hasn't been seen in a while
+	assert.NilError(t, q.SetCallInputs(ctx, SetCallInputsParams{
+		Position: "stale",
+		CallKey:  "key",
+		Inputs:   []byte{},
+		Updated:  10,
+	}))
+	// inserting doesn't prune
+	assert.Equal(t, count("busy"), 20)
+
+	assert.NilError(t, q.GCCallInputs(ctx, GCCallInputsParams{Before: 100}))
+	assert.Equal(t, count("busy"), 16)
+	assert.Equal(t, count("stale"), 0)
+
+	// the most recent calls are the ones kept
+	kept, err := q.SelectCallInputs(ctx, "busy")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(kept, 16))
+	assert.Equal(t, kept[0].CallKey, "key-19")
+	assert.Equal(t, kept[15].CallKey, "key-4")
+}
@@ -5,3 +5,14 @@ CREATE TABLE IF NOT EXISTS calls (
 ) STRICT, WITHOUT ROWID;
 
 CREATE INDEX IF NOT EXISTS calls_exp_idx ON calls(expiration);
+
+-- The inputs to recent calls at each position in a pipeline, recorded when
+-- explaining cache misses. Positions are scoped to the caller that made the
+-- calls, since the inputs may include client IDs and argument values.
+CREATE TABLE IF NOT EXISTS call_inputs (
+    position TEXT NOT NULL,
+    call_key TEXT NOT NULL,
+    inputs BLOB NOT NULL,
+    updated INTEGER NOT NULL,
+    PRIMARY KEY (position, call_key)
+) STRICT, WITHOUT ROWID;
@@ -112,8 +112,19 @@ type Params struct {
 	Function string
 	ExecCmd  []string
 
+	// The command the client is running, shown to engine operators
+	Command string
+
+	// The token allowing the client to watch every session on the engine, as
+	// configured in its security.operatorToken
+	OperatorToken string
+
 	EagerRuntime bool
 
+	// Explain why each call that missed the cache had to run, compared to the
+	// previous call at the same position.
+	ExplainCache bool
+
 	CloudAuth           *auth.Cloud
 	EnableCloudScaleOut bool
 }
@@ -432,7 +443,7 @@ func (c *Client) startEngine(ctx context.Context, params Params) (rerr error) {
 	c.bkVersion = bkInfo.BuildkitVersion.Version
 	c.bkName = bkInfo.BuildkitVersion.Revision
 
-	slog.Info("connected", "name", c.bkName, "client-version", engine.Version, "server-version", c.bkVersion)
+	slog.Info("connected", "name", c.bkName, "client", c.ID, "client-version", engine.Version, "server-version", c.bkVersion)
 
 	imageBackend := c.ImageLoaderBackend
 	if imageBackend == nil {
@@ -806,9 +817,11 @@ func (c *Client) Close() (rerr error) {
 type otlpConsumer struct {
 	httpClient *httpClient
 	path       string
-	traceID    trace.TraceID
-	clientID   string
-	eg         *errgroup.Group
+	// the client whose telemetry to consume, if not our own
+	target   string
+	traceID  trace.TraceID
+	clientID string
+	eg       *errgroup.Group
 }
 
 func (c *otlpConsumer) Consume(ctx context.Context, cb func([]byte) error) (rerr error) {
@@ -825,13 +838,19 @@ func (c *otlpConsumer) Consume(ctx context.Context, cb func([]byte) error) (rerr
 		}
 	}()
 
+	var query string
+	if c.target != "" {
+		query = url.Values{"client": []string{c.target}}.Encode()
+	}
+
 	sseConn, err := sse.Connect(c.httpClient, time.Second, func() *http.Request {
 		return (&http.Request{
 			Method: http.MethodGet,
 			URL: &url.URL{
-				Scheme: "http",
-				Host:   "dagger",
-				Path:   c.path,
+				Scheme:   "http",
+				Host:     "dagger",
+				Path:     c.path,
+				RawQuery: query,
 			},
 		}).WithContext(ctx)
 	})
@@ -890,26 +909,7 @@ func (c *Client) exportTraces(ctx context.Context, httpClient *httpClient) error
 		eg:         c.telemetry,
 	}
 
-	return exp.Consume(ctx, func(data []byte) error {
-		var req coltracepb.ExportTraceServiceRequest
-		if err := protojson.Unmarshal(data, &req); err != nil {
-			return fmt.Errorf("unmarshal: %w", err)
-		}
-
-		spans := telemetry.SpansFromPB(req.GetResourceSpans())
-
-		slog.ExtraDebug("received spans from engine", "len", len(spans))
-
-		for _, span := range spans {
-			slog.ExtraDebug("received span from engine", "span", span.Name(), "id", span.SpanContext().SpanID(), "endTime", span.EndTime())
-		}
-
-		if err := c.Params.EngineTrace.ExportSpans(ctx, spans); err != nil {
-			return fmt.Errorf("export %d spans: %w", len(spans), err)
-		}
-
-		return nil
-	})
+	return exp.Consume(ctx, reexportSpans(ctx, c.Params.EngineTrace))
 }
 
 func (c *Client) exportLogs(ctx context.Context, httpClient *httpClient) error {
@@ -925,16 +925,7 @@ func (c *Client) exportLogs(ctx context.Context, httpClient *httpClient) error {
 		eg:         c.telemetry,
 	}
 
-	return exp.Consume(ctx, func(data []byte) error {
-		var req collogspb.ExportLogsServiceRequest
-		if err := protojson.Unmarshal(data, &req); err != nil {
-			return fmt.Errorf("unmarshal spans: %w", err)
-		}
-		if err := telemetry.ReexportLogsFromPB(ctx, c.EngineLogs, &req); err != nil {
-			return fmt.Errorf("re-export logs: %w", err)
-		}
-		return nil
-	})
+	return exp.Consume(ctx, reexportLogs(ctx, c.EngineLogs))
 }
 
 func (c *Client) exportMetrics(ctx context.Context, httpClient *httpClient) error {
@@ -950,16 +941,88 @@ func (c *Client) exportMetrics(ctx context.Context, httpClient *httpClient) erro
 		eg:         c.telemetry,
 	}
 
-	return exp.Consume(ctx, func(data []byte) error {
+	return exp.Consume(ctx, reexportMetrics(ctx, c.EngineMetrics))
+}
+
+// WatchClient streams the telemetry of another client connected to the
+// engine to the given exporters, until the client goes away or ctx is
+// canceled. Only the main client of a session may watch other clients.
+func (c *Client) WatchClient(
+	ctx context.Context,
+	clientID string,
+	spans sdktrace.SpanExporter,
+	logs sdklog.Exporter,
+	metrics []sdkmetric.Exporter,
+) error {
+	httpClient := c.newTelemetryHTTPClient()
+	eg := new(errgroup.Group)
+	for path, cb := range map[string]func([]byte) error{
+		"/v1/traces":  reexportSpans(ctx, spans),
+		"/v1/logs":    reexportLogs(ctx, logs),
+		"/v1/metrics": reexportMetrics(ctx, metrics),
+	} {
+		exp := &otlpConsumer{
+			path:       path,
+			target:     clientID,
+			traceID:    trace.SpanContextFromContext(ctx).TraceID(),
+			clientID:   c.ID,
+			httpClient: httpClient,
+			eg:         eg,
+		}
+		if err := exp.Consume(ctx, cb); err != nil {
+			return errors.Join(err, eg.Wait())
+		}
+	}
+	return eg.Wait()
+}
+
+func reexportSpans(ctx context.Context, exp sdktrace.SpanExporter) func([]byte) error {
+	return func(data []byte) error {
+		var req coltracepb.ExportTraceServiceRequest
+		if err := protojson.Unmarshal(data, &req); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+
+		spans := telemetry.SpansFromPB(req.GetResourceSpans())
+
+		slog.ExtraDebug("received spans from engine", "len", len(spans))
+
+		for _, span := range spans {
+			slog.ExtraDebug("received span from engine", "span", span.Name(), "id", span.SpanContext().SpanID(), "endTime", span.EndTime())
+		}
+
+		if err := exp.ExportSpans(ctx, spans); err != nil {
+			return fmt.Errorf("export %d spans: %w", len(spans), err)
+		}
+
+		return nil
+	}
+}
+
+func reexportLogs(ctx context.Context, exp sdklog.Exporter) func([]byte) error {
+	return func(data []byte) error {
+		var req collogspb.ExportLogsServiceRequest
+		if err := protojson.Unmarshal(data, &req); err != nil {
+			return fmt.Errorf("unmarshal spans: %w", err)
+		}
+		if err := telemetry.ReexportLogsFromPB(ctx, exp, &req); err != nil {
+			return fmt.Errorf("re-export logs: %w", err)
+		}
+		return nil
+	}
+}
+
+func reexportMetrics(ctx context.Context, exps []sdkmetric.Exporter) func([]byte) error {
+	return func(data []byte) error {
 		var req colmetricspb.ExportMetricsServiceRequest
 		if err := protojson.Unmarshal(data, &req); err != nil {
 			return fmt.Errorf("unmarshal metrics: %w", err)
 		}
-		if err := enginetel.ReexportMetricsFromPB(ctx, c.EngineMetrics, &req); err != nil {
+		if err := enginetel.ReexportMetricsFromPB(ctx, exps, &req); err != nil {
 			return fmt.Errorf("re-export metrics: %w", err)
 		}
 		return nil
-	})
+	}
 }
 
 func (c *Client) init(ctx context.Context) error {
@@ -1359,6 +1422,8 @@ func (c *Client) clientMetadata() engine.ClientMetadata {
 		SessionID:                 c.SessionID,
 		ClientSecretToken:         c.SecretToken,
 		ClientHostname:            c.hostname,
+		ClientCommand:             c.Command,
+		OperatorToken:             c.OperatorToken,
 		ClientStableID:            c.stableClientID,
 		UpstreamCacheImportConfig: c.upstreamCacheImportOptions,
 		UpstreamCacheExportConfig: c.upstreamCacheExportOptions,
@@ -1370,6 +1435,7 @@ func (c *Client) clientMetadata() engine.ClientMetadata {
 		SSHAuthSocketPath:         sshAuthSock,
 		AllowedLLMModules:         c.AllowedLLMModules,
 		EagerRuntime:              c.EagerRuntime,
+		ExplainCache:              c.ExplainCache,
 		CloudAuth:                 c.CloudAuth,
 		EnableCloudScaleOut:       c.EnableCloudScaleOut,
 		CloudScaleOutEngineID:     remoteEngineID,
@@ -0,0 +1,271 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/client/imageload"
+	"github.com/dagger/dagger/engine/slog"
+	bkclient "github.com/dagger/dagger/internal/buildkit/client"
+)
+
+func init() {
+	register("pool", &poolDriver{})
+}
+
+// poolDriver spreads sessions across a pool of engines, picking one per
+// session, e.g.:
+//
+//	pool://?engine=tcp://engine-a:1234&engine=ssh://user@engine-b
+type poolDriver struct{}
+
+// The schemes that engines in a pool may use.
+var poolSchemes = []string{"tcp", "unix", "ssh", "kube-pod"}
+
+// How long to wait for an engine in a pool to report its load before
+// considering it unhealthy.
+var PoolProbeTimeout = 10 * time.Second
+
+// How many more clients the engine with the best cache affinity for a module
+// may have than the least loaded engine, before the least loaded engine is
+// picked instead.
+const poolAffinityMaxSkew = 4
+
+func (d *poolDriver) Available(ctx context.Context) (bool, error) {
+	return true, nil // assume always available
+}
+
+func (d *poolDriver) Provision(ctx context.Context, target *url.URL, opts *DriverOpts) (Connector, error) {
+	engines := target.Query()["engine"]
+	if len(engines) == 0 {
+		return nil, fmt.Errorf("no engines in pool; add them with ?engine=<url>")
+	}
+
+	members := make([]*poolMember, 0, len(engines))
+	for _, engineURL := range engines {
+		u, err := url.Parse(engineURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse pool engine %q: %w", engineURL, err)
+		}
+		if !slices.Contains(poolSchemes, u.Scheme) {
+			return nil, fmt.Errorf("unsupported pool engine %q: scheme must be one of %v", u.Redacted(), poolSchemes)
+		}
+		driver, err := GetDriver(ctx, u.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		connector, err := driver.Provision(ctx, u, opts)
+		if err != nil {
+			return nil, fmt.Errorf("provision pool engine %q: %w", u.Redacted(), err)
+		}
+		members = append(members, &poolMember{
+			url:       u,
+			connector: connector,
+		})
+	}
+
+	var wg sync.WaitGroup
+	for _, member := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			member.load, member.err = probePoolMember(ctx, member)
+		}()
+	}
+	wg.Wait()
+
+	slog := slog.SpanLogger(ctx, InstrumentationLibrary)
+	for _, member := range members {
+		if member.err != nil {
+			slog.Warn("pool engine is unhealthy", "engine", member.url.Redacted(), "error", member.err)
+		} else {
+			slog.Debug("pool engine is healthy", "engine", member.url.Redacted(), "clients", member.load)
+		}
+	}
+
+	// fall back to the client ID, so that engines with the same load are
+	// picked at random, rather than every client picking the same one
+	affinityKey := opts.Module
+	if affinityKey == "" {
+		affinityKey = opts.ClientID
+	}
+	ranked := rankPoolMembers(members, affinityKey, opts.Module != "")
+	if len(ranked) == 0 {
+		var errs error
+		for _, member := range members {
+			errs = errors.Join(errs, member.error())
+		}
+		return nil, fmt.Errorf("no healthy engines in pool: %w", errs)
+	}
+	return &poolConnector{members: ranked}, nil
+}
+
+func (d *poolDriver) ImageLoader(ctx context.Context) imageload.Backend {
+	return nil
+}
+
+type poolMember struct {
+	url       *url.URL
+	connector Connector
+
+	// The number of clients connected to the engine.
+	load int
+
+	// Why the engine is unhealthy, if it is.
+	err error
+}
+
+func (member *poolMember) error() error {
+	return fmt.Errorf("%s: %w", member.url.Redacted(), member.err)
+}
+
+// probePoolMember checks that an engine is reachable, and returns the number
+// of clients connected to it.
+func probePoolMember(ctx context.Context, member *poolMember) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, PoolProbeTimeout)
+	defer cancel()
+
+	c, err := bkclient.New(ctx, member.url.String(),
+		bkclient.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return member.connector.Connect(ctx)
+		}))
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	workers, err := c.ListWorkers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var load int
+	for _, w := range workers {
+		// older engines don't report their load; treat them as idle
+		if v, ok := w.Labels[engine.ConnectedClientsLabel]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s label %q: %w", engine.ConnectedClientsLabel, v, err)
+			}
+			load += n
+		}
+	}
+	return load, nil
+}
+
+// rankPoolMembers returns the healthy members of a pool in the order they
+// should be tried: least loaded first, with ties broken by affinity.
+//
+// If preferAffinity is set, the member with the best affinity goes first as
+// long as it isn't much busier than the least loaded one, so that calls to the
+// same module tend to land on the engine that has it cached.
+func rankPoolMembers(members []*poolMember, affinityKey string, preferAffinity bool) []*poolMember {
+	var healthy []*poolMember
+	for _, member := range members {
+		if member.err == nil {
+			healthy = append(healthy, member)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	weights := make(map[*poolMember]uint64, len(healthy))
+	for _, member := range healthy {
+		weights[member] = affinityWeight(affinityKey, member.url)
+	}
+	slices.SortStableFunc(healthy, func(a, b *poolMember) int {
+		if a.load != b.load {
+			return a.load - b.load
+		}
+		// higher weight first
+		switch {
+		case weights[a] > weights[b]:
+			return -1
+		case weights[a] < weights[b]:
+			return 1
+		}
+		return 0
+	})
+
+	if preferAffinity {
+		best := 0
+		for i, member := range healthy {
+			if weights[member] > weights[healthy[best]] {
+				best = i
+			}
+		}
+		if healthy[best].load-healthy[0].load <= poolAffinityMaxSkew {
+			preferred := healthy[best]
+			healthy = slices.Delete(healthy, best, best+1)
+			healthy = slices.Insert(healthy, 0, preferred)
+		}
+	}
+	return healthy
+}
+
+// affinityWeight uses rendezvous hashing to score how much a key belongs to
+// an engine, so that the same key prefers the same engine even as engines are
+// added to or removed from the pool.
+func affinityWeight(key string, engineURL *url.URL) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(engineURL.String()))
+	return h.Sum64()
+}
+
+// poolConnector connects to the first reachable engine in a pool, failing over
+// to the next one if it can't be reached. Once connected, it sticks with the
+// same engine, since the session lives there.
+type poolConnector struct {
+	members []*poolMember
+
+	mu        sync.Mutex
+	current   int
+	connected bool
+}
+
+func (c *poolConnector) Connect(ctx context.Context) (net.Conn, error) {
+	c.mu.Lock()
+	if c.connected {
+		member := c.members[c.current]
+		c.mu.Unlock()
+		return member.connector.Connect(ctx)
+	}
+	defer c.mu.Unlock()
+
+	var errs error
+	for ; c.current < len(c.members); c.current++ {
+		member := c.members[c.current]
+		conn, err := member.connector.Connect(ctx)
+		if err != nil {
+			member.err = err
+			errs = errors.Join(errs, member.error())
+			slog.Warn("failed to connect to pool engine, failing over", "engine", member.url.Redacted(), "error", err)
+			continue
+		}
+		c.connected = true
+		return conn, nil
+	}
+	// start over on the next attempt, in case it was a temporary blip
+	c.current = 0
+	return nil, fmt.Errorf("no reachable engines in pool: %w", errs)
+}
+
+func (c *poolConnector) EngineID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return ""
+	}
+	return c.members[c.current].connector.EngineID()
+}
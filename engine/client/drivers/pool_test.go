@@ -0,0 +1,124 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConnector struct {
+	id  string
+	err error
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (net.Conn, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func (c *fakeConnector) EngineID() string {
+	return c.id
+}
+
+func fakePoolMember(t *testing.T, engineURL string, load int, err error) *poolMember {
+	t.Helper()
+	u, parseErr := url.Parse(engineURL)
+	require.NoError(t, parseErr)
+	return &poolMember{
+		url:       u,
+		connector: &fakeConnector{id: engineURL},
+		load:      load,
+		err:       err,
+	}
+}
+
+func poolMemberURLs(members []*poolMember) []string {
+	urls := make([]string, len(members))
+	for i, member := range members {
+		urls[i] = member.url.String()
+	}
+	return urls
+}
+
+func TestRankPoolMembers(t *testing.T) {
+	a := fakePoolMember(t, "tcp://a:1234", 3, nil)
+	b := fakePoolMember(t, "tcp://b:1234", 1, nil)
+	c := fakePoolMember(t, "tcp://c:1234", 2, nil)
+	down := fakePoolMember(t, "tcp://down:1234", 0, errors.New("connection refused"))
+	members := []*poolMember{a, b, c, down}
+
+	t.Run("least loaded first", func(t *testing.T) {
+		ranked := rankPoolMembers(members, "client", false)
+		require.Equal(t, []string{"tcp://b:1234", "tcp://c:1234", "tcp://a:1234"}, poolMemberURLs(ranked))
+	})
+
+	t.Run("affinity", func(t *testing.T) {
+		const module = "github.com/dagger/dagger"
+		var preferred *poolMember
+		for _, member := range []*poolMember{a, b, c} {
+			if preferred == nil || affinityWeight(module, member.url) > affinityWeight(module, preferred.url) {
+				preferred = member
+			}
+		}
+		ranked := rankPoolMembers(members, module, true)
+		require.Len(t, ranked, 3)
+		require.Equal(t, preferred.url.String(), ranked[0].url.String())
+		// the same module is sent to the same engine every time
+		require.Equal(t, poolMemberURLs(ranked), poolMemberURLs(rankPoolMembers(members, module, true)))
+	})
+
+	t.Run("affinity loses to load", func(t *testing.T) {
+		const module = "github.com/dagger/dagger"
+		busy := []*poolMember{
+			fakePoolMember(t, a.url.String(), 0, nil),
+			fakePoolMember(t, b.url.String(), 0, nil),
+			fakePoolMember(t, c.url.String(), 0, nil),
+		}
+		preferred := rankPoolMembers(busy, module, true)[0]
+		preferred.load = poolAffinityMaxSkew + 1
+		ranked := rankPoolMembers(busy, module, true)
+		require.NotEqual(t, preferred.url.String(), ranked[0].url.String())
+	})
+
+	t.Run("none healthy", func(t *testing.T) {
+		require.Empty(t, rankPoolMembers([]*poolMember{down}, "client", false))
+	})
+}
+
+func TestPoolConnectorFailover(t *testing.T) {
+	ctx := context.Background()
+
+	down := fakePoolMember(t, "tcp://down:1234", 0, nil)
+	down.connector.(*fakeConnector).err = errors.New("connection refused")
+	up := fakePoolMember(t, "tcp://up:1234", 0, nil)
+	other := fakePoolMember(t, "tcp://other:1234", 0, nil)
+
+	connector := &poolConnector{members: []*poolMember{down, up, other}}
+	require.Empty(t, connector.EngineID())
+
+	conn, err := connector.Connect(ctx)
+	require.NoError(t, err)
+	conn.Close()
+	require.Equal(t, "tcp://up:1234", connector.EngineID())
+
+	// once connected, it sticks with the same engine, even if it goes away
+	up.connector.(*fakeConnector).err = errors.New("connection reset")
+	_, err = connector.Connect(ctx)
+	require.ErrorContains(t, err, "connection reset")
+	require.Equal(t, "tcp://up:1234", connector.EngineID())
+
+	t.Run("none reachable", func(t *testing.T) {
+		connector := &poolConnector{members: []*poolMember{down}}
+		_, err := connector.Connect(ctx)
+		require.ErrorContains(t, err, "no reachable engines in pool")
+		require.ErrorContains(t, err, "tcp://down:1234: connection refused")
+	})
+}
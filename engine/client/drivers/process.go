@@ -0,0 +1,409 @@
+//go:build linux
+
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/adrg/xdg"
+	"github.com/gofrs/flock"
+	"go.opentelemetry.io/otel"
+
+	"github.com/dagger/dagger/engine/client/imageload"
+	"github.com/dagger/dagger/engine/slog"
+	"github.com/dagger/dagger/util/hashutil"
+)
+
+func init() {
+	register("process", &processDriver{})
+}
+
+// processDriver runs the engine binary directly as a child process, for hosts
+// without a container runtime, e.g.:
+//
+//	process:///usr/local/bin/dagger-engine?state=/var/lib/dagger
+//
+// The engine is left running when the CLI exits, and reused by later
+// invocations that use the same binary and state directory.
+type processDriver struct{}
+
+const (
+	// The engine binary to run if none is given.
+	processEngineBinary = "dagger-engine"
+
+	processLockFile   = "process.lock"
+	processPIDFile    = "engine.pid"
+	processIDFile     = "engine.id"
+	processLogFile    = "engine.log"
+	processSocketFile = "engine.sock"
+	processRootDir    = "root"
+)
+
+var (
+	// How long to wait for a newly started engine to start listening.
+	ProcessStartTimeout = time.Minute
+
+	// How long to wait for an old engine to stop before killing it.
+	ProcessStopTimeout = 30 * time.Second
+
+	// How long to wait for an old engine to exit after killing it.
+	ProcessKillTimeout = 10 * time.Second
+
+	// Whether the CLI may run the engine without RootlessKit.
+	processPrivileged = func() bool { return os.Geteuid() == 0 }
+)
+
+func (d *processDriver) Available(ctx context.Context) (bool, error) {
+	return true, nil // checked when provisioning, to give a useful error
+}
+
+func (d *processDriver) Provision(ctx context.Context, target *url.URL, opts *DriverOpts) (Connector, error) {
+	// unlike the container drivers, an old engine can't be left running
+	// alongside a new one, since they'd share the state directory; so rather
+	// than DAGGER_LEAVE_OLD_ENGINE and cleanup=false, which keep the old
+	// engine and start a new one, replace=false refuses to touch the old one
+	replace := true
+	if val := target.Query().Get("replace"); val != "" {
+		var err error
+		replace, err = strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid replace value %q: %w", val, err)
+		}
+	}
+
+	binary := target.Host + target.Path
+	if binary == "" {
+		binary = processEngineBinary
+	}
+	binary, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("find engine binary: %w", err)
+	}
+
+	stateDir := target.Query().Get("state")
+	if stateDir == "" {
+		stateDir = filepath.Join(xdg.StateHome, "dagger", "engine")
+	}
+	stateDir, err = filepath.Abs(stateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rootless := os.Geteuid() != 0
+	if val := target.Query().Get("rootless"); val != "" {
+		rootless, err = strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rootless value %q: %w", val, err)
+		}
+	}
+
+	proc := &engineProcess{
+		binary:   binary,
+		stateDir: stateDir,
+		rootless: rootless,
+	}
+	if err := proc.ensure(ctx, replace, opts); err != nil {
+		return nil, err
+	}
+	return processConnector{socket: proc.socket()}, nil
+}
+
+func (d *processDriver) ImageLoader(ctx context.Context) imageload.Backend {
+	return nil
+}
+
+type processConnector struct {
+	socket string
+}
+
+func (d processConnector) Connect(ctx context.Context) (net.Conn, error) {
+	return defaultDialer(ctx, "unix://"+d.socket)
+}
+
+func (d processConnector) EngineID() string {
+	// not supported yet
+	return ""
+}
+
+// engineProcess is an engine running as a process on the host, with all of
+// its state in a single directory.
+type engineProcess struct {
+	binary   string
+	stateDir string
+	rootless bool
+}
+
+func (proc *engineProcess) path(name string) string {
+	return filepath.Join(proc.stateDir, name)
+}
+
+func (proc *engineProcess) socket() string {
+	return proc.path(processSocketFile)
+}
+
+// command returns the command to run the engine, wrapped in RootlessKit if
+// it's rootless. If RootlessKit isn't installed but the CLI is privileged, the
+// engine runs without it.
+func (proc *engineProcess) command() ([]string, error) {
+	args := []string{
+		proc.binary,
+		"--root", proc.path(processRootDir),
+		"--addr", "unix://" + proc.socket(),
+	}
+	if !proc.rootless {
+		return args, nil
+	}
+	rootlesskit, err := exec.LookPath("rootlesskit")
+	if err != nil {
+		if processPrivileged() {
+			return args, nil
+		}
+		return nil, fmt.Errorf("running the engine without root requires rootlesskit, "+
+			"which can be installed from your distribution's rootlesskit package or "+
+			"https://github.com/rootless-containers/rootlesskit/releases: %w", err)
+	}
+	return append([]string{
+		rootlesskit,
+		"--state-dir", proc.path("rootlesskit"),
+		"--net=slirp4netns",
+		"--copy-up=/etc",
+		"--disable-host-loopback",
+	}, append(args, "--rootless")...), nil
+}
+
+// id identifies the engine by its binary and command, so that an engine
+// started from a different binary (e.g. after an upgrade) isn't reused.
+func (proc *engineProcess) id(cmd []string) (string, error) {
+	fi, err := os.Stat(proc.binary)
+	if err != nil {
+		return "", err
+	}
+	h := hashutil.NewHasher()
+	for _, arg := range cmd {
+		h = h.WithString(arg)
+	}
+	return h.
+		WithInt64(fi.Size()).
+		WithInt64(fi.ModTime().UnixNano()).
+		DigestAndClose(), nil
+}
+
+// ensure makes sure the engine is running, reusing it if it's already
+// running from the same binary, and replacing it otherwise if allowed.
+func (proc *engineProcess) ensure(ctx context.Context, replace bool, opts *DriverOpts) (rerr error) {
+	ctx, span := otel.Tracer("").Start(ctx, "start engine process")
+	defer telemetry.EndWithCause(span, &rerr)
+	slog := slog.SpanLogger(ctx, InstrumentationLibrary)
+
+	if err := os.MkdirAll(proc.stateDir, 0o700); err != nil {
+		return err
+	}
+
+	// make sure concurrent CLI invocations don't both start an engine
+	lock := flock.New(proc.path(processLockFile))
+	if _, err := lock.TryLockContext(ctx, 100*time.Millisecond); err != nil {
+		return fmt.Errorf("lock %s: %w", proc.stateDir, err)
+	}
+	defer lock.Unlock()
+
+	cmd, err := proc.command()
+	if err != nil {
+		return err
+	}
+	id, err := proc.id(cmd)
+	if err != nil {
+		return err
+	}
+
+	if proc.running(ctx) {
+		runningID, _ := os.ReadFile(proc.path(processIDFile))
+		if string(runningID) == id {
+			slog.Debug("reusing engine process", "state", proc.stateDir)
+			return nil
+		}
+		if !replace {
+			return fmt.Errorf("a different engine is already running in %s", proc.stateDir)
+		}
+		slog.Info("stopping old engine process", "state", proc.stateDir)
+		if err := proc.stop(ctx); err != nil {
+			return fmt.Errorf("stop old engine: %w", err)
+		}
+	}
+
+	return proc.start(ctx, cmd, id, opts)
+}
+
+// running checks whether an engine is listening on the socket.
+func (proc *engineProcess) running(ctx context.Context) bool {
+	conn, err := defaultDialer(ctx, "unix://"+proc.socket())
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func (proc *engineProcess) start(ctx context.Context, cmdArgs []string, id string, opts *DriverOpts) error {
+	// clean up after an engine that didn't exit cleanly
+	if err := os.Remove(proc.socket()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	logFile, err := os.OpenFile(proc.path(processLogFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	// the engine outlives the CLI, so it must not be tied to its context
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...) //nolint:gosec
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = os.Environ()
+	if opts.DaggerCloudToken != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", EnvDaggerCloudToken, opts.DaggerCloudToken))
+	}
+	if opts.GPUSupport != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", EnvGPUSupport, opts.GPUSupport))
+	}
+	// start a new session, so that the engine isn't killed along with the
+	// CLI's process group
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start engine: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	if err := os.WriteFile(proc.path(processPIDFile), []byte(strconv.Itoa(cmd.Process.Pid)), 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(proc.path(processIDFile), []byte(id), 0o600); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ProcessStartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for !proc.running(ctx) {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("engine exited: %w\n%s", err, proc.logTail())
+		case <-ctx.Done():
+			cmd.Process.Kill()
+			return fmt.Errorf("engine did not start listening on %s: %w", proc.socket(), context.Cause(ctx))
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// stop gracefully stops the running engine, killing it if it doesn't stop in
+// time.
+func (proc *engineProcess) stop(ctx context.Context) error {
+	pidStr, err := os.ReadFile(proc.path(processPIDFile))
+	if err != nil {
+		return fmt.Errorf("read pid: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidStr)))
+	if err != nil {
+		return fmt.Errorf("invalid pid %q: %w", pidStr, err)
+	}
+	// the PID file may be stale, and its PID reused by an unrelated process
+	owned, err := proc.owns(pid)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !owned {
+		return fmt.Errorf("process %d is not the engine in %s", pid, proc.stateDir)
+	}
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		return err
+	}
+	termCtx, cancel := context.WithTimeout(ctx, ProcessStopTimeout)
+	defer cancel()
+	if waitForExit(termCtx, pid) == nil {
+		return nil
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		return err
+	}
+	// SIGKILL can't be ignored, but the process may take a moment to exit,
+	// and a new engine can't start until it has released the state directory
+	killCtx, cancel := context.WithTimeout(ctx, ProcessKillTimeout)
+	defer cancel()
+	if err := waitForExit(killCtx, pid); err != nil {
+		return fmt.Errorf("engine process %d did not exit after being killed: %w", pid, err)
+	}
+	return nil
+}
+
+// owns reports whether the process is this engine, i.e. whether it was started
+// to listen on this engine's socket. For a rootless engine, that's RootlessKit,
+// which is passed the engine's arguments.
+func (proc *engineProcess) owns(pid int) (bool, error) {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return false, fmt.Errorf("read process %d command line: %w", pid, err)
+	}
+	args := strings.Split(string(cmdline), "\x00")
+	return slices.Contains(args, "unix://"+proc.socket()), nil
+}
+
+// waitForExit polls until the process no longer exists.
+func waitForExit(ctx context.Context, pid int) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		// signal 0 checks whether the process still exists
+		if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-ticker.C:
+		}
+	}
+}
+
+// The number of bytes at the end of the engine's log to include in errors.
+const processLogTailLen = 4096
+
+func (proc *engineProcess) logTail() string {
+	logs, err := os.ReadFile(proc.path(processLogFile))
+	if err != nil {
+		return ""
+	}
+	if len(logs) > processLogTailLen {
+		logs = logs[len(logs)-processLogTailLen:]
+	}
+	return string(bytes.TrimSpace(logs))
+}
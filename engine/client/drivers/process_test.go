@@ -0,0 +1,200 @@
+//go:build linux
+
+package drivers
+
+import (
+	"flag"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const fakeEngineEnv = "DAGGER_TEST_FAKE_ENGINE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEngineEnv) != "" {
+		runFakeEngine()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeEngine stands in for the engine binary, accepting connections on the
+// --addr socket until it's terminated.
+func runFakeEngine() {
+	flags := flag.NewFlagSet("engine", flag.ExitOnError)
+	flags.String("root", "", "")
+	addr := flags.String("addr", "", "")
+	flags.Parse(os.Args[1:])
+
+	l, err := net.Listen("unix", strings.TrimPrefix(*addr, "unix://"))
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM)
+	<-sigs
+	l.Close()
+}
+
+func TestProcessDriver(t *testing.T) {
+	ctx := t.Context()
+	t.Setenv(fakeEngineEnv, "1")
+
+	binary, err := os.Executable()
+	require.NoError(t, err)
+	stateDir := t.TempDir()
+
+	target := &url.URL{
+		Scheme: "process",
+		Path:   binary,
+		RawQuery: url.Values{
+			"state":    {stateDir},
+			"rootless": {"false"},
+		}.Encode(),
+	}
+	pid := func() int {
+		pidStr, err := os.ReadFile(filepath.Join(stateDir, processPIDFile))
+		require.NoError(t, err)
+		pid, err := strconv.Atoi(string(pidStr))
+		require.NoError(t, err)
+		return pid
+	}
+	t.Cleanup(func() {
+		proc := &engineProcess{stateDir: stateDir}
+		proc.stop(ctx)
+	})
+
+	driver := &processDriver{}
+	connector, err := driver.Provision(ctx, target, &DriverOpts{})
+	require.NoError(t, err)
+	conn, err := connector.Connect(ctx)
+	require.NoError(t, err)
+	conn.Close()
+	firstPID := pid()
+
+	t.Run("reuse", func(t *testing.T) {
+		_, err := driver.Provision(ctx, target, &DriverOpts{})
+		require.NoError(t, err)
+		require.Equal(t, firstPID, pid())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		// pretend the running engine is from an older binary
+		require.NoError(t, os.WriteFile(filepath.Join(stateDir, processIDFile), []byte("old"), 0o600))
+
+		connector, err := driver.Provision(ctx, target, &DriverOpts{})
+		require.NoError(t, err)
+		require.NotEqual(t, firstPID, pid())
+		conn, err := connector.Connect(ctx)
+		require.NoError(t, err)
+		conn.Close()
+
+		// the old engine was stopped
+		require.ErrorIs(t, syscall.Kill(firstPID, 0), syscall.ESRCH)
+	})
+
+	t.Run("don't replace", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(stateDir, processIDFile), []byte("old"), 0o600))
+
+		noReplace := *target
+		query := noReplace.Query()
+		query.Set("replace", "false")
+		noReplace.RawQuery = query.Encode()
+		_, err := driver.Provision(ctx, &noReplace, &DriverOpts{})
+		require.ErrorContains(t, err, "a different engine is already running")
+	})
+}
+
+func TestProcessStopKill(t *testing.T) {
+	stopTimeout := ProcessStopTimeout
+	ProcessStopTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { ProcessStopTimeout = stopTimeout })
+
+	proc := &engineProcess{stateDir: t.TempDir()}
+
+	// an engine that ignores SIGTERM
+	cmd := exec.Command("sh", "-c", `trap "" TERM; echo ready; while true; do sleep 1; done`,
+		"engine", "--addr", "unix://"+proc.socket())
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	exited := make(chan struct{})
+	go func() {
+		// reap the process, so it doesn't linger as a zombie
+		cmd.Wait()
+		close(exited)
+	}()
+	// wait for the trap to be set
+	_, err = stdout.Read(make([]byte, 1))
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(proc.path(processPIDFile), []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+	require.NoError(t, proc.stop(t.Context()))
+
+	// stop only returns once the process is gone
+	require.ErrorIs(t, syscall.Kill(cmd.Process.Pid, 0), syscall.ESRCH)
+	<-exited
+}
+
+func TestProcessStopUnrelated(t *testing.T) {
+	// a stale PID file, whose PID was reused by an unrelated process
+	cmd := exec.Command("sleep", "60")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	proc := &engineProcess{stateDir: t.TempDir()}
+	require.NoError(t, os.WriteFile(proc.path(processPIDFile), []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+	require.ErrorContains(t, proc.stop(t.Context()), "is not the engine")
+
+	// the process wasn't signalled
+	require.NoError(t, syscall.Kill(cmd.Process.Pid, 0))
+}
+
+func TestProcessCommandWithoutRootlessKit(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	privileged := processPrivileged
+	t.Cleanup(func() { processPrivileged = privileged })
+
+	proc := &engineProcess{
+		binary:   "/usr/local/bin/dagger-engine",
+		stateDir: t.TempDir(),
+		rootless: true,
+	}
+
+	t.Run("privileged", func(t *testing.T) {
+		processPrivileged = func() bool { return true }
+		cmd, err := proc.command()
+		require.NoError(t, err)
+		require.Equal(t, proc.binary, cmd[0])
+		require.NotContains(t, cmd, "--rootless")
+	})
+
+	t.Run("unprivileged", func(t *testing.T) {
+		processPrivileged = func() bool { return false }
+		_, err := proc.command()
+		require.ErrorContains(t, err, "requires rootlesskit, which can be installed")
+	})
+}
@@ -77,39 +77,11 @@ func (dbs *DBs) Open(ctx context.Context, clientID string) (_ *DB, rerr error) {
 			return nil, fmt.Errorf("mkdir %s: %w", filepath.Dir(dbPath), err)
 		}
 
-		// check whether the file exists already
-		_, statErr := os.Lstat(dbPath)
-		alreadyExists := statErr == nil
-
-		connURL := &url.URL{
-			Scheme: "file",
-			Host:   "",
-			Path:   dbPath,
-			RawQuery: url.Values{
-				"_pragma": []string{
-					"foreign_keys=ON",    // we don't use em yet, but makes sense anyway
-					"journal_mode=WAL",   // readers don't block writers and vice versa
-					"synchronous=OFF",    // we don't care about durability and don't want to be surprised by syncs
-					"busy_timeout=10000", // wait up to 10s when there are concurrent writers
-				},
-				"_txlock": []string{"immediate"}, // use BEGIN IMMEDIATE for transactions
-			}.Encode(),
-		}
-		sqlDB, err := sql.Open("sqlite", connURL.String())
+		sqlDB, err := openSQLite(dbPath)
 		if err != nil {
-			return nil, fmt.Errorf("open %s: %w", connURL, err)
-		}
-		if err := sqlDB.Ping(); err != nil {
-			return nil, fmt.Errorf("ping %s: %w", connURL, err)
+			return nil, err
 		}
-
 		db.inner = sqlDB
-
-		if !alreadyExists {
-			if _, err := db.inner.Exec(Schema); err != nil {
-				return nil, fmt.Errorf("migrate: %w", err)
-			}
-		}
 	} else {
 		lg.ExtraDebug("reusing open client DB", "clientID", clientID)
 	}
@@ -204,6 +176,56 @@ func (dbs *DBs) GC(keep map[string]bool) error {
 	return errs
 }
 
+// openSQLite opens the database at the given path, creating it with the
+// schema if it doesn't exist yet.
+func openSQLite(dbPath string) (*sql.DB, error) {
+	// check whether the file exists already
+	_, statErr := os.Lstat(dbPath)
+	alreadyExists := statErr == nil
+
+	connURL := &url.URL{
+		Scheme: "file",
+		Host:   "",
+		Path:   dbPath,
+		RawQuery: url.Values{
+			"_pragma": []string{
+				"foreign_keys=ON",    // we don't use em yet, but makes sense anyway
+				"journal_mode=WAL",   // readers don't block writers and vice versa
+				"synchronous=OFF",    // we don't care about durability and don't want to be surprised by syncs
+				"busy_timeout=10000", // wait up to 10s when there are concurrent writers
+			},
+			"_txlock": []string{"immediate"}, // use BEGIN IMMEDIATE for transactions
+		}.Encode(),
+	}
+	sqlDB, err := sql.Open("sqlite", connURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", connURL, err)
+	}
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("ping %s: %w", connURL, err)
+	}
+
+	if !alreadyExists {
+		if _, err := sqlDB.Exec(Schema); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+	}
+	return sqlDB, nil
+}
+
+// Exists returns whether a database is stored for the given clientID, e.g.
+// for a client that has since disconnected.
+func (dbs *DBs) Exists(clientID string) bool {
+	if clientID == "" || filepath.Base(clientID) != clientID {
+		// not a valid client ID
+		return false
+	}
+	_, err := os.Stat(dbs.path(clientID))
+	return err == nil
+}
+
 func (dbs *DBs) path(clientID string) string {
 	return filepath.Join(dbs.Root, clientID+".db")
 }
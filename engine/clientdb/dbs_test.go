@@ -69,3 +69,18 @@ func TestDBRefCount(t *testing.T) {
 	require.Nil(t, d2a.Queries)
 	require.Equal(t, d2a.refCount, 0)
 }
+
+func TestDBExists(t *testing.T) {
+	dbs := NewDBs(t.TempDir())
+
+	require.False(t, dbs.Exists("client1"))
+
+	db, err := dbs.Open(t.Context(), "client1")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	require.True(t, dbs.Exists("client1"))
+
+	for _, invalid := range []string{"", ".", "..", "../client1", "sub/client1"} {
+		require.False(t, dbs.Exists(invalid), invalid)
+	}
+}
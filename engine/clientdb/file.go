@@ -0,0 +1,205 @@
+package clientdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"dagger.io/dagger/telemetry"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	enginetel "github.com/dagger/dagger/engine/telemetry"
+)
+
+// File is a standalone telemetry database, e.g. one saved with `dagger trace
+// export` to be viewed later.
+type File struct {
+	inner *sql.DB
+	*Queries
+}
+
+// OpenFile opens the telemetry database at the given path, creating it if it
+// doesn't exist.
+func OpenFile(ctx context.Context, path string) (*File, error) {
+	sqlDB, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	queries, err := Prepare(ctx, sqlDB)
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("prepare queries: %w", err)
+	}
+	return &File{
+		inner:   sqlDB,
+		Queries: queries,
+	}, nil
+}
+
+func (f *File) Close() error {
+	return errors.Join(f.Queries.Close(), f.inner.Close())
+}
+
+// The number of rows to fetch at a time when replaying.
+const replayBatchSize = 1000
+
+// Replay sends everything stored in the file to the given exporters, in the
+// order it was recorded.
+func (f *File) Replay(ctx context.Context, spans sdktrace.SpanExporter, logs sdklog.Exporter, metrics sdkmetric.Exporter) error {
+	for lastID := int64(0); ; {
+		dbSpans, err := f.SelectSpansSince(ctx, SelectSpansSinceParams{
+			ID:    lastID,
+			Limit: replayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("select spans: %w", err)
+		}
+		if len(dbSpans) == 0 {
+			break
+		}
+		roSpans := make([]sdktrace.ReadOnlySpan, len(dbSpans))
+		for i, span := range dbSpans {
+			roSpans[i] = span.ReadOnly()
+		}
+		if err := spans.ExportSpans(ctx, roSpans); err != nil {
+			return fmt.Errorf("export spans: %w", err)
+		}
+		lastID = dbSpans[len(dbSpans)-1].ID
+	}
+
+	for lastID := int64(0); ; {
+		dbLogs, err := f.SelectLogsSince(ctx, SelectLogsSinceParams{
+			ID:    lastID,
+			Limit: replayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("select logs: %w", err)
+		}
+		if len(dbLogs) == 0 {
+			break
+		}
+		if err := telemetry.ReexportLogsFromPB(ctx, logs, &collogspb.ExportLogsServiceRequest{
+			ResourceLogs: LogsToPB(dbLogs),
+		}); err != nil {
+			return fmt.Errorf("export logs: %w", err)
+		}
+		lastID = dbLogs[len(dbLogs)-1].ID
+	}
+
+	if metrics == nil {
+		return nil
+	}
+	for lastID := int64(0); ; {
+		dbMetrics, err := f.SelectMetricsSince(ctx, SelectMetricsSinceParams{
+			ID:    lastID,
+			Limit: replayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("select metrics: %w", err)
+		}
+		if len(dbMetrics) == 0 {
+			break
+		}
+		if err := enginetel.ReexportMetricsFromPB(ctx, []sdkmetric.Exporter{metrics}, &colmetricspb.ExportMetricsServiceRequest{
+			ResourceMetrics: MetricsToPB(dbMetrics),
+		}); err != nil {
+			return fmt.Errorf("export metrics: %w", err)
+		}
+		lastID = dbMetrics[len(dbMetrics)-1].ID
+	}
+	return nil
+}
+
+// SpanExporter returns an exporter that records spans to the file.
+func (f *File) SpanExporter() sdktrace.SpanExporter {
+	return fileSpans{f}
+}
+
+type fileSpans struct {
+	*File
+}
+
+func (f fileSpans) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		insert, err := SpanParams(span)
+		if err != nil {
+			return fmt.Errorf("prepare span: %w", err)
+		}
+		if _, err := f.InsertSpan(ctx, *insert); err != nil {
+			return fmt.Errorf("insert span: %w", err)
+		}
+	}
+	return nil
+}
+
+func (f fileSpans) Shutdown(context.Context) error { return nil }
+
+// LogExporter returns an exporter that records logs to the file.
+func (f *File) LogExporter() sdklog.Exporter {
+	return fileLogs{f}
+}
+
+type fileLogs struct {
+	*File
+}
+
+func (f fileLogs) Export(ctx context.Context, logs []sdklog.Record) error {
+	for _, rec := range logs {
+		insert, err := LogParams(&rec)
+		if err != nil {
+			return fmt.Errorf("prepare log record: %w", err)
+		}
+		if _, err := f.InsertLog(ctx, *insert); err != nil {
+			return fmt.Errorf("insert log record: %w", err)
+		}
+	}
+	return nil
+}
+
+func (f fileLogs) ForceFlush(context.Context) error { return nil }
+func (f fileLogs) Shutdown(context.Context) error   { return nil }
+
+// MetricExporter returns an exporter that records metrics to the file.
+func (f *File) MetricExporter() sdkmetric.Exporter {
+	return fileMetrics{f}
+}
+
+type fileMetrics struct {
+	*File
+}
+
+func (f fileMetrics) Export(ctx context.Context, metrics *metricdata.ResourceMetrics) error {
+	if len(metrics.ScopeMetrics) == 0 {
+		return nil
+	}
+	pbMetrics, err := telemetry.ResourceMetricsToPB(metrics)
+	if err != nil {
+		return fmt.Errorf("convert metrics to pb: %w", err)
+	}
+	data, err := protojson.Marshal(pbMetrics)
+	if err != nil {
+		return fmt.Errorf("marshal metrics: %w", err)
+	}
+	if _, err := f.InsertMetric(ctx, data); err != nil {
+		return fmt.Errorf("insert metrics: %w", err)
+	}
+	return nil
+}
+
+func (f fileMetrics) Temporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.DeltaTemporality
+}
+
+func (f fileMetrics) Aggregation(sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.AggregationDefault{}
+}
+
+func (f fileMetrics) ForceFlush(context.Context) error { return nil }
+func (f fileMetrics) Shutdown(context.Context) error   { return nil }
@@ -0,0 +1,79 @@
+package clientdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFileReplay(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "trace.db")
+
+	f, err := OpenFile(ctx, path)
+	require.NoError(t, err)
+
+	traceID := trace.TraceID{1}
+	root := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	})
+	child := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{2},
+	})
+	start := time.Now()
+	res := resource.NewSchemaless(attribute.String("service.name", "test"))
+	require.NoError(t, f.SpanExporter().ExportSpans(ctx, tracetest.SpanStubs{
+		{
+			Name:        "root",
+			SpanContext: root,
+			StartTime:   start,
+			Resource:    res,
+		},
+		{
+			Name:        "child",
+			SpanContext: child,
+			Parent:      root,
+			StartTime:   start,
+			EndTime:     start.Add(time.Second),
+			Attributes:  []attribute.KeyValue{attribute.String("foo", "bar")},
+			Resource:    res,
+		},
+	}.Snapshots()))
+	require.NoError(t, f.Close())
+
+	// reopen the file, as if viewing it later
+	f, err = OpenFile(ctx, path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	spans := tracetest.NewInMemoryExporter()
+	require.NoError(t, f.Replay(ctx, spans, nopLogExporter{}, nil))
+
+	stubs := spans.GetSpans()
+	require.Len(t, stubs, 2)
+	require.Equal(t, "root", stubs[0].Name)
+	require.False(t, stubs[0].Parent.IsValid())
+	require.Equal(t, "child", stubs[1].Name)
+	require.Equal(t, root.SpanID(), stubs[1].Parent.SpanID())
+	require.Equal(t, child.SpanID(), stubs[1].SpanContext.SpanID())
+	require.Equal(t, []attribute.KeyValue{attribute.String("foo", "bar")}, stubs[1].Attributes)
+	require.Equal(t, start.Add(time.Second).UnixNano(), stubs[1].EndTime.UnixNano())
+}
+
+type nopLogExporter struct{}
+
+var _ sdklog.Exporter = nopLogExporter{}
+
+func (nopLogExporter) Export(ctx context.Context, logs []sdklog.Record) error { return nil }
+func (nopLogExporter) ForceFlush(context.Context) error                       { return nil }
+func (nopLogExporter) Shutdown(context.Context) error                         { return nil }
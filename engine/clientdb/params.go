@@ -0,0 +1,144 @@
+package clientdb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dagger.io/dagger/telemetry"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	otlpcommonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// SpanParams converts a span into the parameters for inserting it.
+func SpanParams(span sdktrace.ReadOnlySpan) (*InsertSpanParams, error) {
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+	traceState := span.SpanContext().TraceState().String()
+	parentSpanID := span.Parent().SpanID().String()
+	flags := int64(span.SpanContext().TraceFlags())
+	name := span.Name()
+	kind := span.SpanKind().String()
+	startTime := span.StartTime().UnixNano()
+	endTime := sql.NullInt64{
+		Int64: span.EndTime().UnixNano(),
+		Valid: !span.EndTime().IsZero(),
+	}
+	if span.EndTime().Before(span.StartTime()) {
+		endTime.Int64 = 0
+		endTime.Valid = false
+	}
+	attributes, err := MarshalProtoJSONs(telemetry.KeyValues(span.Attributes()))
+	if err != nil {
+		return nil, fmt.Errorf("marshal attributes: %w", err)
+	}
+	droppedAttributesCount := int64(span.DroppedAttributes())
+	events, err := MarshalProtoJSONs(telemetry.SpanEventsToPB(span.Events()))
+	if err != nil {
+		return nil, fmt.Errorf("marshal events: %w", err)
+	}
+	droppedEventsCount := int64(span.DroppedEvents())
+	links, err := MarshalProtoJSONs(telemetry.SpanLinksToPB(span.Links()))
+	if err != nil {
+		return nil, fmt.Errorf("marshal links: %w", err)
+	}
+	droppedLinksCount := int64(span.DroppedLinks())
+	statusCode := int64(span.Status().Code)
+	statusMessage := span.Status().Description
+	instrumentationScope, err := protojson.Marshal(telemetry.InstrumentationScopeToPB(span.InstrumentationScope()))
+	if err != nil {
+		return nil, fmt.Errorf("marshal instrumentation scope: %w", err)
+	}
+	resource, err := protojson.Marshal(telemetry.ResourcePtrToPB(span.Resource()))
+	if err != nil {
+		return nil, fmt.Errorf("marshal resource: %w", err)
+	}
+
+	return &InsertSpanParams{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceState: traceState,
+		ParentSpanID: sql.NullString{
+			String: parentSpanID,
+			Valid:  span.Parent().IsValid(),
+		},
+		Flags:                  flags,
+		Name:                   name,
+		Kind:                   kind,
+		StartTime:              startTime,
+		EndTime:                endTime,
+		Attributes:             attributes,
+		DroppedAttributesCount: droppedAttributesCount,
+		Events:                 events,
+		DroppedEventsCount:     droppedEventsCount,
+		Links:                  links,
+		DroppedLinksCount:      droppedLinksCount,
+		StatusCode:             statusCode,
+		StatusMessage:          statusMessage,
+		InstrumentationScope:   instrumentationScope,
+		Resource:               resource,
+	}, nil
+}
+
+// LogParams converts a log record into the parameters for inserting it.
+func LogParams(rec *sdklog.Record) (*InsertLogParams, error) {
+	traceID := rec.TraceID().String()
+	spanID := rec.SpanID().String()
+	timestamp := rec.Timestamp().UnixNano()
+	severity := int64(rec.Severity())
+
+	var body []byte
+	if !rec.Body().Empty() {
+		var err error
+		body, err = proto.Marshal(telemetry.LogValueToPB(rec.Body()))
+		if err != nil {
+			return nil, fmt.Errorf("marshal log record body: %w", err)
+		}
+	}
+
+	attrs := []*otlpcommonv1.KeyValue{}
+	rec.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs = append(attrs, &otlpcommonv1.KeyValue{
+			Key:   kv.Key,
+			Value: telemetry.LogValueToPB(kv.Value),
+		})
+		return true
+	})
+	attributes, err := MarshalProtoJSONs(attrs)
+	if err != nil {
+		return nil, fmt.Errorf("marshal log record attributes: %w", err)
+	}
+
+	scope, err := protojson.Marshal(telemetry.InstrumentationScopeToPB(rec.InstrumentationScope()))
+	if err != nil {
+		return nil, fmt.Errorf("marshal log record instrumentation scope: %w", err)
+	}
+
+	res := rec.Resource()
+	resource, err := protojson.Marshal(telemetry.ResourcePtrToPB(res))
+	if err != nil {
+		return nil, fmt.Errorf("marshal log record resource: %w", err)
+	}
+
+	return &InsertLogParams{
+		TraceID: sql.NullString{
+			String: traceID,
+			Valid:  rec.TraceID().IsValid(),
+		},
+		SpanID: sql.NullString{
+			String: spanID,
+			Valid:  rec.SpanID().IsValid(),
+		},
+		Timestamp:            timestamp,
+		SeverityNumber:       severity,
+		SeverityText:         rec.SeverityText(),
+		Body:                 body,
+		Attributes:           attributes,
+		InstrumentationScope: scope,
+		Resource:             resource,
+		ResourceSchemaUrl:    res.SchemaURL(),
+	}, nil
+}
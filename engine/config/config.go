@@ -33,6 +33,10 @@ type Config struct {
 	// Registries configures custom registry mirrors, root CAs, and
 	// insecure/HTTP access.
 	Registries map[string]RegistryConfig `json:"registries,omitempty"`
+
+	// Telemetry configures where the engine sends its own telemetry, in
+	// addition to any clients and Dagger Cloud.
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
 }
 
 type LogLevel string
@@ -227,4 +231,73 @@ type Security struct {
 	// Disabling this option ensures that dagger build containers do not run as
 	// privileged, and is a basic form of security hardening.
 	InsecureRootCapabilities *bool `json:"insecureRootCapabilities,omitempty"`
+
+	// OperatorToken lets clients presenting it (e.g. `dagger watch` run with
+	// DAGGER_OPERATOR_TOKEN set) watch the clients of every session connected
+	// to the engine. Without it, clients may only watch their own session.
+	OperatorToken string `json:"operatorToken,omitempty"`
+}
+
+type TelemetryConfig struct {
+	// Exporters are OTLP endpoints that the engine sends all of its spans to,
+	// e.g. a Jaeger or Tempo instance.
+	Exporters []TelemetryExporter `json:"exporters,omitempty"`
+}
+
+type TelemetryExporter struct {
+	// Endpoint is the URL of the OTLP traces endpoint, e.g.
+	// "http://tempo:4318/v1/traces", or "http://tempo:4317" for gRPC.
+	Endpoint string `json:"endpoint"`
+
+	// Protocol is the OTLP protocol to use - it defaults to "http/protobuf".
+	Protocol string `json:"protocol,omitempty" jsonschema:"enum=http/protobuf,enum=grpc"`
+
+	// Headers are sent along with every export request, e.g. for
+	// authentication.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Sampling configures which traces are exported - by default, all of them
+	// are.
+	Sampling *TelemetrySampling `json:"sampling,omitempty"`
+
+	// Redact configures span attributes to hide before they're exported.
+	Redact *TelemetryRedaction `json:"redact,omitempty"`
+
+	// DropInternal drops spans that are internal to Dagger, which are
+	// normally hidden from the UI.
+	DropInternal bool `json:"dropInternal,omitempty"`
+}
+
+type TelemetrySampling struct {
+	// Ratio is the fraction of traces to export, from 0 to 1 - it defaults to
+	// 1.
+	Ratio *float64 `json:"ratio,omitempty" jsonschema:"minimum=0,maximum=1"`
+
+	// Rules override the ratio for spans with matching names. The first
+	// matching rule applies.
+	//
+	// Rules apply to each span on its own, not to whole traces: a span whose
+	// rule has a different ratio than its parent's can be exported without
+	// its parent, or the other way around. Give related spans the same ratio
+	// to keep their traces whole.
+	Rules []TelemetrySamplingRule `json:"rules,omitempty"`
+}
+
+type TelemetrySamplingRule struct {
+	// Name matches span names, either exactly, or by prefix if it ends with
+	// "*" (e.g. "Container.*").
+	Name string `json:"name"`
+
+	// Ratio is the fraction of traces to export spans from, from 0 to 1.
+	Ratio float64 `json:"ratio" jsonschema:"minimum=0,maximum=1"`
+}
+
+type TelemetryRedaction struct {
+	// Attributes are the keys of span attributes whose values are always
+	// redacted.
+	Attributes []string `json:"attributes,omitempty"`
+
+	// Patterns are regular expressions whose matches are redacted from all
+	// string span attributes.
+	Patterns []string `json:"patterns,omitempty"`
 }
@@ -15,6 +15,10 @@ const (
 
 	CloudRunnerHostPrefix  = "dagger-cloud://"
 	DefaultCloudRunnerHost = CloudRunnerHostPrefix + "default-engine-config.dagger.cloud"
+
+	// ConnectedClientsLabel is the worker label reporting how many clients
+	// are connected to the engine, used for load balancing.
+	ConnectedClientsLabel = "dagger.io/engine.connected-clients"
 )
 
 const (
@@ -0,0 +1,74 @@
+// Package metrics holds the engine's Prometheus collectors that are updated
+// as things happen, rather than computed when scraped.
+//
+// Labels are kept low-cardinality: API fields and module names are bounded by
+// the schemas in use, whereas IDs and digests would grow without bound.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	OperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "dagger_operation_duration_seconds",
+		Help: "Time taken to evaluate API calls that missed the cache, by field and module",
+		// 1ms to ~4m
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"field", "module"})
+
+	Operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dagger_operations_total",
+		Help: "Number of API calls evaluated, by module and whether they hit the cache",
+	}, []string{"module", "cached"})
+
+	RunningExecs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "dagger_running_execs",
+		Help: "Number of containers currently running, including services",
+	})
+
+	GCRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dagger_gc_runs_total",
+		Help: "Number of times the local cache has been pruned, by whether it was automatic or requested",
+	}, []string{"trigger"})
+
+	GCReclaimedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dagger_gc_reclaimed_bytes_total",
+		Help: "Disk space reclaimed by pruning the local cache in bytes, by whether it was automatic or requested",
+	}, []string{"trigger"})
+
+	RegistryPullBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dagger_registry_pull_bytes_total",
+		Help: "Bytes fetched from container registries",
+	})
+
+	SessionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "dagger_session_duration_seconds",
+		Help: "How long sessions were connected to the engine",
+		// 1s to ~4.5h
+		Buckets: prometheus.ExponentialBuckets(1, 2, 15),
+	})
+)
+
+const (
+	GCTriggerAuto   = "auto"
+	GCTriggerManual = "manual"
+)
+
+// Register registers all of the collectors with the given registerer.
+func Register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{
+		OperationDuration,
+		Operations,
+		RunningExecs,
+		GCRuns,
+		GCReclaimedBytes,
+		RegistryPullBytes,
+		SessionDuration,
+	} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
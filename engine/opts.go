@@ -62,6 +62,14 @@ type ClientMetadata struct {
 	// ClientVersion is the version string of the client that make the request.
 	ClientVersion string `json:"client_version"`
 
+	// ClientCommand is the command the client is running, if any. It's used to
+	// help identify clients, e.g. in `dagger watch`; nothing functional.
+	ClientCommand string `json:"client_command,omitempty"`
+
+	// OperatorToken is the token the client presents to watch the clients of
+	// every session, if the engine is configured with one.
+	OperatorToken string `json:"operator_token,omitempty"`
+
 	// (Optional) Pipeline labels for e.g. vcs info like branch, commit, etc.
 	Labels map[string]string `json:"labels"`
 
@@ -92,6 +100,10 @@ type ClientMetadata struct {
 	// Disable lazy loading on module runtime.
 	EagerRuntime bool `json:"eager_runtime"`
 
+	// Record the inputs to each call's cache key, and explain why calls that
+	// miss the cache had to run.
+	ExplainCache bool `json:"explain_cache,omitempty"`
+
 	// If set, the auth for cloud requests; used for PARC and scale-out
 	CloudAuth *auth.Cloud `json:"cloud_auth,omitempty"`
 
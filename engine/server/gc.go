@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/dagger/dagger/engine/config"
+	"github.com/dagger/dagger/engine/metrics"
 	bkclient "github.com/dagger/dagger/internal/buildkit/client"
 	bkconfig "github.com/dagger/dagger/internal/buildkit/cmd/buildkitd/config"
 	"github.com/dagger/dagger/internal/buildkit/util/bklog"
@@ -81,6 +82,11 @@ func (srv *Server) PruneEngineLocalCacheEntries(ctx context.Context, useDefaultP
 	close(ch)
 	wg.Wait()
 
+	metrics.GCRuns.WithLabelValues(metrics.GCTriggerManual).Inc()
+	for _, r := range pruned {
+		metrics.GCReclaimedBytes.WithLabelValues(metrics.GCTriggerManual).Add(float64(r.Size))
+	}
+
 	if len(pruned) == 0 {
 		return &core.EngineCacheEntrySet{}, nil
 	}
@@ -140,6 +146,10 @@ func (srv *Server) gc() {
 	if err != nil {
 		bklog.G(ctx).Errorf("gc error: %+v", err)
 	}
+	if len(srv.baseWorker.GCPolicy()) > 0 {
+		metrics.GCRuns.WithLabelValues(metrics.GCTriggerAuto).Inc()
+		metrics.GCReclaimedBytes.WithLabelValues(metrics.GCTriggerAuto).Add(float64(size))
+	}
 	if size > 0 {
 		bklog.G(ctx).Debugf("gc cleaned up %d bytes", size)
 		go srv.throttledReleaseUnreferenced()
@@ -158,6 +158,7 @@ type Server struct {
 	apparmorProfile  string
 	selinux          bool
 	entitlements     entitlements.Set
+	operatorToken    string
 	parallelismSem   *semaphore.Weighted
 	enabledPlatforms []ocispecs.Platform
 	defaultPlatform  ocispecs.Platform
@@ -340,6 +341,8 @@ func NewServer(ctx context.Context, opts *NewServerOpts) (*Server, error) {
 	//
 
 	if cfg.Security != nil {
+		srv.operatorToken = cfg.Security.OperatorToken
+
 		// prioritize out config first if it's set
 		if cfg.Security.InsecureRootCapabilities == nil || *cfg.Security.InsecureRootCapabilities {
 			srv.entitlements[entitlements.EntitlementSecurityInsecure] = struct{}{}
@@ -839,10 +842,15 @@ func (srv *Server) Info(context.Context, *controlapi.InfoRequest) (*controlapi.I
 }
 
 func (srv *Server) ListWorkers(context.Context, *controlapi.ListWorkersRequest) (*controlapi.ListWorkersResponse, error) {
+	labels := maps.Clone(srv.worker.Labels())
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[engine.ConnectedClientsLabel] = strconv.Itoa(srv.ConnectedClients())
 	resp := &controlapi.ListWorkersResponse{
 		Record: []*apitypes.WorkerRecord{{
 			ID:        srv.worker.ID(),
-			Labels:    srv.worker.Labels(),
+			Labels:    labels,
 			Platforms: pb.PlatformsFromSpec(srv.enabledPlatforms),
 		}},
 	}
@@ -868,6 +876,24 @@ func (srv *Server) ConnectedClients() int {
 	return len(srv.daggerSessions)
 }
 
+// RunningServices returns the number of services running across all sessions
+func (srv *Server) RunningServices() int {
+	srv.daggerSessionsMu.RLock()
+	defer srv.daggerSessionsMu.RUnlock()
+	var n int
+	for _, sess := range srv.daggerSessions {
+		// the state lock is held while the session initializes or is removed,
+		// neither of which should have services running; don't wait on it
+		if sess.stateMu.TryRLock() {
+			if sess.state == sessionStateInitialized {
+				n += sess.services.Running()
+			}
+			sess.stateMu.RUnlock()
+		}
+	}
+	return n
+}
+
 func (srv *Server) CorruptDBReset() bool {
 	return srv.corruptDBReset
 }
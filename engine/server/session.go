@@ -1,7 +1,9 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -54,6 +56,7 @@ import (
 	"github.com/dagger/dagger/engine/cache/cachemanager"
 	engineclient "github.com/dagger/dagger/engine/client"
 	"github.com/dagger/dagger/engine/clientdb"
+	"github.com/dagger/dagger/engine/metrics"
 	"github.com/dagger/dagger/engine/server/resource"
 	"github.com/dagger/dagger/engine/slog"
 	enginetel "github.com/dagger/dagger/engine/telemetry"
@@ -83,6 +86,9 @@ type daggerSession struct {
 
 	services *core.Services
 
+	// when the session was initialized
+	startTime time.Time
+
 	analytics analytics.Tracker
 
 	authProvider *auth.RegistryAuthProvider
@@ -119,6 +125,9 @@ type daggerClient struct {
 	secretToken    string
 	clientMetadata *engine.ClientMetadata
 
+	// when the client first connected
+	startTime time.Time
+
 	// closed after the shutdown endpoint is called
 	shutdownCh        chan struct{}
 	closeShutdownOnce sync.Once
@@ -260,10 +269,16 @@ func (srv *Server) initializeDaggerSession(
 	sess.endpoints = map[string]http.Handler{}
 	sess.shutdownCh = make(chan struct{})
 	sess.services = core.NewServices()
+	sess.startTime = time.Now()
 	sess.authProvider = auth.NewRegistryAuthProvider()
 	sess.refs = map[buildkit.Reference]struct{}{}
 	sess.containers = map[bkgw.Container]struct{}{}
 	sess.dagqlCache = dagql.NewSessionCache(srv.baseDagqlCache)
+	if clientMetadata.ExplainCache {
+		// compare against previous runs by the same user on the same host,
+		// falling back to only this session if the client has no stable ID
+		sess.dagqlCache.ExplainMisses(cmp.Or(clientMetadata.ClientStableID, clientMetadata.SessionID))
+	}
 	sess.telemetryPubSub = srv.telemetryPubSub
 	sess.interactive = clientMetadata.Interactive
 	sess.interactiveCommand = clientMetadata.InteractiveCommand
@@ -333,6 +348,7 @@ func (srv *Server) removeDaggerSession(ctx context.Context, sess *daggerSession)
 	srv.daggerSessionsMu.Unlock()
 
 	sess.state = sessionStateRemoved
+	metrics.SessionDuration.Observe(time.Since(sess.startTime).Seconds())
 
 	var errs error
 
@@ -747,6 +763,99 @@ func (srv *Server) clientFromIDs(sessID, clientID string) (*daggerClient, error)
 	return client, nil
 }
 
+// mayWatch reports whether the client may see the details and telemetry of
+// another client: clients in the same session are visible to each other, and
+// operators presenting the engine's operator token can see every client.
+func (srv *Server) mayWatch(client, other *daggerClient) bool {
+	if client.daggerSession == other.daggerSession {
+		return true
+	}
+	return srv.isOperator(client)
+}
+
+// isOperator reports whether the client presented the operator token
+// configured in the engine's security settings.
+func (srv *Server) isOperator(client *daggerClient) bool {
+	if srv.operatorToken == "" || client.clientMetadata == nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(
+		[]byte(client.clientMetadata.OperatorToken),
+		[]byte(srv.operatorToken),
+	) == 1
+}
+
+// clientByID finds a client in any session.
+func (srv *Server) clientByID(clientID string) (*daggerClient, error) {
+	srv.daggerSessionsMu.RLock()
+	defer srv.daggerSessionsMu.RUnlock()
+	for _, sess := range srv.daggerSessions {
+		sess.clientMu.RLock()
+		client, ok := sess.clients[clientID]
+		sess.clientMu.RUnlock()
+		if ok {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("client %q not found", clientID)
+}
+
+// EngineClients returns the details of every client connected to the engine
+// that the calling client may watch, across all sessions.
+func (srv *Server) EngineClients(ctx context.Context) ([]*core.EngineClient, error) {
+	watcher, err := srv.clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	srv.daggerSessionsMu.RLock()
+	defer srv.daggerSessionsMu.RUnlock()
+
+	var clients []*core.EngineClient
+	for sessID, sess := range srv.daggerSessions {
+		sess.clientMu.RLock()
+		for _, client := range sess.clients {
+			if !srv.mayWatch(watcher, client) {
+				continue
+			}
+			info := &core.EngineClient{
+				ClientID:            client.clientID,
+				SessionID:           sessID,
+				Main:                client.clientID == sess.mainClientCallerID,
+				Version:             client.clientVersion,
+				StartedTimeUnixNano: int(client.startTime.UnixNano()),
+				Status:              "starting",
+			}
+			if md := client.clientMetadata; md != nil {
+				info.Hostname = md.ClientHostname
+				info.Command = md.ClientCommand
+			}
+			// the state lock is held while the client initializes, which can take
+			// a while; don't wait on it
+			if client.stateMu.TryRLock() {
+				if client.state == clientStateInitialized {
+					info.Status = "running"
+				}
+				if client.mod != nil {
+					info.Module = client.mod.Name()
+				}
+				client.stateMu.RUnlock()
+			}
+			select {
+			case <-client.shutdownCh:
+				info.Status = "stopping"
+			default:
+			}
+			clients = append(clients, info)
+		}
+		sess.clientMu.RUnlock()
+	}
+	slices.SortFunc(clients, func(a, b *core.EngineClient) int {
+		return cmp.Compare(a.StartedTimeUnixNano, b.StartedTimeUnixNano)
+	})
+	return clients, nil
+}
+
 // initialize session+client if needed, return:
 // * the initialized client
 // * a cleanup func to run when the call is done
@@ -820,6 +929,7 @@ func (srv *Server) getOrInitClient(
 			secretToken:    token,
 			shutdownCh:     make(chan struct{}),
 			clientMetadata: opts.ClientMetadata,
+			startTime:      time.Now(),
 		}
 		sess.clients[clientID] = client
 
@@ -0,0 +1,57 @@
+package pipe
+
+import (
+	"io"
+	"net"
+	"time"
+)
+
+// NewConn adapts the two halves of a pipe into a net.Conn, so that
+// connection-oriented protocols (i.e. HTTP/2) can be spoken over it.
+//
+// Deadlines are not supported and are silently ignored.
+func NewConn(r io.Reader, w io.Writer, closer io.Closer) net.Conn {
+	return &conn{Reader: r, Writer: w, closer: closer}
+}
+
+type conn struct {
+	io.Reader
+	io.Writer
+	closer io.Closer
+}
+
+var _ net.Conn = (*conn)(nil)
+
+func (c *conn) Close() error {
+	return c.closer.Close()
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *conn) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (c *conn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *conn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string {
+	return "pipe"
+}
+
+func (pipeAddr) String() string {
+	return "pipe"
+}
@@ -0,0 +1,282 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/engine/config"
+)
+
+// The value that redacted attributes are replaced with, same as secrets
+// scrubbed from exec output.
+const redacted = "***"
+
+// ConfiguredExporters returns span exporters for each of the OTLP exporters
+// in the engine config, applying their sampling, redaction, and filtering.
+//
+// Exporters that fail to be configured are skipped, and their errors are
+// returned along with the rest.
+func ConfiguredExporters(ctx context.Context, cfg *config.TelemetryConfig) ([]sdktrace.SpanExporter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	exps := make([]sdktrace.SpanExporter, 0, len(cfg.Exporters))
+	var errs error
+	for i, expCfg := range cfg.Exporters {
+		exp, err := NewExporter(ctx, expCfg)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("telemetry exporter %d: %w", i, err))
+			continue
+		}
+		exps = append(exps, exp)
+	}
+	return exps, errs
+}
+
+// NewExporter returns a span exporter that sends spans to the configured OTLP
+// endpoint.
+func NewExporter(ctx context.Context, cfg config.TelemetryExporter) (sdktrace.SpanExporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("no endpoint configured")
+	}
+	filter, err := NewSpanFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var exp sdktrace.SpanExporter
+	switch cfg.Protocol {
+	case "", "http/protobuf", "http":
+		exp, err = otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(cfg.Endpoint),
+			otlptracehttp.WithHeaders(cfg.Headers))
+	case "grpc":
+		exp, err = otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpointURL(cfg.Endpoint),
+			otlptracegrpc.WithHeaders(cfg.Headers))
+	default:
+		err = fmt.Errorf("unknown OTLP protocol: %s", cfg.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &FilteringSpanExporter{
+		// spans may be proxied out of the engine before they've finished
+		SpanExporter: telemetry.FilterLiveSpansExporter{SpanExporter: exp},
+		Filter:       filter,
+	}, nil
+}
+
+// FilteringSpanExporter is a SpanExporter that samples, redacts, and drops
+// spans before passing them along.
+type FilteringSpanExporter struct {
+	sdktrace.SpanExporter
+	Filter *SpanFilter
+}
+
+func (exp *FilteringSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	filtered := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+	for _, span := range spans {
+		if span, ok := exp.Filter.Apply(span); ok {
+			filtered = append(filtered, span)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return exp.SpanExporter.ExportSpans(ctx, filtered)
+}
+
+// SpanFilter decides which spans to export, and what to redact from them.
+type SpanFilter struct {
+	dropInternal bool
+
+	ratio float64
+	rules []samplingRule
+
+	redactKeys     map[attribute.Key]bool
+	redactPatterns []*regexp.Regexp
+}
+
+type samplingRule struct {
+	name   string
+	prefix bool
+	ratio  float64
+}
+
+func (rule samplingRule) matches(name string) bool {
+	if rule.prefix {
+		return strings.HasPrefix(name, rule.name)
+	}
+	return name == rule.name
+}
+
+// NewSpanFilter returns a filter for the sampling, redaction, and filtering
+// settings of an exporter.
+func NewSpanFilter(cfg config.TelemetryExporter) (*SpanFilter, error) {
+	filter := &SpanFilter{
+		dropInternal: cfg.DropInternal,
+		ratio:        1,
+	}
+	if sampling := cfg.Sampling; sampling != nil {
+		if sampling.Ratio != nil {
+			if err := checkRatio(*sampling.Ratio); err != nil {
+				return nil, fmt.Errorf("sampling: %w", err)
+			}
+			filter.ratio = *sampling.Ratio
+		}
+		for _, rule := range sampling.Rules {
+			if err := checkRatio(rule.Ratio); err != nil {
+				return nil, fmt.Errorf("sampling rule %q: %w", rule.Name, err)
+			}
+			name, prefix := strings.CutSuffix(rule.Name, "*")
+			filter.rules = append(filter.rules, samplingRule{
+				name:   name,
+				prefix: prefix,
+				ratio:  rule.Ratio,
+			})
+		}
+	}
+	if redact := cfg.Redact; redact != nil {
+		filter.redactKeys = make(map[attribute.Key]bool, len(redact.Attributes))
+		for _, key := range redact.Attributes {
+			filter.redactKeys[attribute.Key(key)] = true
+		}
+		for _, pattern := range redact.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("redact pattern %q: %w", pattern, err)
+			}
+			filter.redactPatterns = append(filter.redactPatterns, re)
+		}
+	}
+	return filter, nil
+}
+
+func checkRatio(ratio float64) error {
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("ratio must be between 0 and 1, got %v", ratio)
+	}
+	return nil
+}
+
+// Apply returns the span to export, with any attributes redacted, or false if
+// the span should be dropped.
+func (filter *SpanFilter) Apply(span sdktrace.ReadOnlySpan) (sdktrace.ReadOnlySpan, bool) {
+	if filter.dropInternal && isInternal(span) {
+		return nil, false
+	}
+	if !filter.sampled(span) {
+		return nil, false
+	}
+	if len(filter.redactKeys) == 0 && len(filter.redactPatterns) == 0 {
+		return span, true
+	}
+	events := slices.Clone(span.Events())
+	for i, event := range events {
+		events[i].Attributes = filter.redact(event.Attributes)
+	}
+	return redactedSpan{
+		ReadOnlySpan: span,
+		attributes:   filter.redact(span.Attributes()),
+		events:       events,
+	}, true
+}
+
+func isInternal(span sdktrace.ReadOnlySpan) bool {
+	for _, attr := range span.Attributes() {
+		if attr.Key == telemetry.UIInternalAttr {
+			return attr.Value.AsBool()
+		}
+	}
+	return false
+}
+
+// sampled decides whether to export a span based on its trace ID, so that
+// spans sampled at the same ratio are kept or dropped together.
+//
+// Rules can't be applied to whole traces, keyed on their root span: the
+// engine exports spans as they end, before their parents, and the root span
+// of a trace usually lives in the client, so the engine never sees it. Spans
+// matching rules with different ratios than their parents can thus be
+// exported without them.
+func (filter *SpanFilter) sampled(span sdktrace.ReadOnlySpan) bool {
+	ratio := filter.ratio
+	for _, rule := range filter.rules {
+		if rule.matches(span.Name()) {
+			ratio = rule.ratio
+			break
+		}
+	}
+	return sampleTrace(span.SpanContext().TraceID(), ratio)
+}
+
+// sampleTrace follows the same algorithm as sdktrace.TraceIDRatioBased.
+func sampleTrace(traceID trace.TraceID, ratio float64) bool {
+	switch {
+	case ratio >= 1:
+		return true
+	case ratio <= 0:
+		return false
+	}
+	bound := uint64(ratio * (1 << 63))
+	x := binary.BigEndian.Uint64(traceID[8:16]) >> 1
+	return x < bound
+}
+
+func (filter *SpanFilter) redact(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if len(attrs) == 0 {
+		return attrs
+	}
+	redactedAttrs := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		switch {
+		case filter.redactKeys[attr.Key]:
+			attr = attr.Key.String(redacted)
+		case attr.Value.Type() == attribute.STRING:
+			attr = attr.Key.String(filter.redactString(attr.Value.AsString()))
+		case attr.Value.Type() == attribute.STRINGSLICE:
+			vals := slices.Clone(attr.Value.AsStringSlice())
+			for j, val := range vals {
+				vals[j] = filter.redactString(val)
+			}
+			attr = attr.Key.StringSlice(vals)
+		}
+		redactedAttrs[i] = attr
+	}
+	return redactedAttrs
+}
+
+func (filter *SpanFilter) redactString(val string) string {
+	for _, re := range filter.redactPatterns {
+		val = re.ReplaceAllString(val, redacted)
+	}
+	return val
+}
+
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	attributes []attribute.KeyValue
+	events     []sdktrace.Event
+}
+
+func (span redactedSpan) Attributes() []attribute.KeyValue {
+	return span.attributes
+}
+
+func (span redactedSpan) Events() []sdktrace.Event {
+	return span.events
+}
@@ -0,0 +1,115 @@
+package telemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/engine/config"
+	enginetel "github.com/dagger/dagger/engine/telemetry"
+)
+
+func TestSpanFilter(t *testing.T) {
+	// the low bits of the trace ID decide whether it's sampled
+	lowTrace := trace.TraceID{15: 1}
+	highTrace := trace.TraceID{8: 0xff, 15: 1}
+
+	span := func(name string, traceID trace.TraceID, attrs ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+		return tracetest.SpanStub{
+			Name: name,
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: traceID,
+				SpanID:  trace.SpanID{1},
+			}),
+			Attributes: attrs,
+			Events: []sdktrace.Event{{
+				Name:       "exception",
+				Attributes: attrs,
+			}},
+		}.Snapshot()
+	}
+
+	t.Run("drop internal", func(t *testing.T) {
+		filter, err := enginetel.NewSpanFilter(config.TelemetryExporter{
+			DropInternal: true,
+		})
+		require.NoError(t, err)
+
+		_, ok := filter.Apply(span("internal", lowTrace, attribute.Bool(telemetry.UIInternalAttr, true)))
+		require.False(t, ok)
+		_, ok = filter.Apply(span("visible", lowTrace))
+		require.True(t, ok)
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		ratio := 0.5
+		filter, err := enginetel.NewSpanFilter(config.TelemetryExporter{
+			Sampling: &config.TelemetrySampling{
+				Ratio: &ratio,
+				Rules: []config.TelemetrySamplingRule{
+					{Name: "Container.withExec", Ratio: 1},
+					{Name: "Host.*", Ratio: 0},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			name    string
+			traceID trace.TraceID
+			sampled bool
+		}{
+			{"Container.from", lowTrace, true},
+			{"Container.from", highTrace, false},
+			{"Container.withExec", highTrace, true},
+			{"Host.directory", lowTrace, false},
+		} {
+			_, ok := filter.Apply(span(tc.name, tc.traceID))
+			require.Equal(t, tc.sampled, ok, "%s in trace %s", tc.name, tc.traceID)
+		}
+	})
+
+	t.Run("redact", func(t *testing.T) {
+		filter, err := enginetel.NewSpanFilter(config.TelemetryExporter{
+			Redact: &config.TelemetryRedaction{
+				Attributes: []string{"http.request.header.authorization"},
+				Patterns:   []string{`ghp_[A-Za-z0-9]+`},
+			},
+		})
+		require.NoError(t, err)
+
+		redacted, ok := filter.Apply(span("withExec", lowTrace,
+			attribute.String("http.request.header.authorization", "Bearer hunter2"),
+			attribute.String("cmd", "git clone https://ghp_abc123@github.com/foo/bar"),
+			attribute.StringSlice("args", []string{"--token", "ghp_def456"}),
+			attribute.Int("exit", 1),
+		))
+		require.True(t, ok)
+		expected := []attribute.KeyValue{
+			attribute.String("http.request.header.authorization", "***"),
+			attribute.String("cmd", "git clone https://***@github.com/foo/bar"),
+			attribute.StringSlice("args", []string{"--token", "***"}),
+			attribute.Int("exit", 1),
+		}
+		require.Equal(t, expected, redacted.Attributes())
+		require.Equal(t, expected, redacted.Events()[0].Attributes)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ratio := 2.0
+		_, err := enginetel.NewSpanFilter(config.TelemetryExporter{
+			Sampling: &config.TelemetrySampling{Ratio: &ratio},
+		})
+		require.ErrorContains(t, err, "ratio must be between 0 and 1")
+
+		_, err = enginetel.NewSpanFilter(config.TelemetryExporter{
+			Redact: &config.TelemetryRedaction{Patterns: []string{"("}},
+		})
+		require.ErrorContains(t, err, "redact pattern")
+	})
+}
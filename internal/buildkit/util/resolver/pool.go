@@ -3,6 +3,7 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/remotes"
 	"github.com/containerd/containerd/v2/core/remotes/docker"
+	"github.com/dagger/dagger/engine/metrics"
 	"github.com/dagger/dagger/internal/buildkit/session"
 	"github.com/dagger/dagger/internal/buildkit/solver/pb"
 	log "github.com/dagger/dagger/internal/buildkit/util/bklog"
@@ -215,7 +217,44 @@ func (r *Resolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, er
 	if atomic.LoadInt64(&r.handler.counter) == 0 {
 		r.Resolve(ctx, ref)
 	}
-	return r.Resolver.Fetcher(ctx, ref)
+	fetcher, err := r.Resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return countingFetcher{fetcher}, nil
+}
+
+// countingFetcher records the bytes fetched from registries in the engine's
+// metrics.
+type countingFetcher struct {
+	remotes.Fetcher
+}
+
+func (f countingFetcher) Fetch(ctx context.Context, desc ocispecs.Descriptor) (io.ReadCloser, error) {
+	rc, err := f.Fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	crc := &countingReadCloser{ReadCloser: rc}
+	if s, ok := rc.(io.Seeker); ok {
+		return &countingReadCloserSeeker{crc, s}, nil
+	}
+	return crc, nil
+}
+
+type countingReadCloserSeeker struct {
+	*countingReadCloser
+	io.Seeker
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+}
+
+func (rc *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := rc.ReadCloser.Read(p)
+	metrics.RegistryPullBytes.Add(float64(n))
+	return n, err
 }
 
 // Resolve attempts to resolve the reference into a name and descriptor.
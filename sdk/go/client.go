@@ -100,7 +100,7 @@ func Connect(ctx context.Context, opts ...ClientOpt) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	gql := errorWrappedClient{graphql.NewClient("http://"+conn.Host()+"/query", conn)}
+	gql := errorWrappedClient{graphql.NewClient("http://"+conn.Host()+"/query", querybuilder.PersistedQueries(conn))}
 
 	c := &Client{
 		query:  querybuilder.Query().Client(gql),
@@ -142,6 +142,14 @@ func Error(message string) *dagger.Error {
 	return client.Error(message)
 }
 
+// Initialize an evaluation of an agent
+//
+// Experimental: Evals are not yet stabilized
+func Eval(prompt string, opts ...dagger.EvalOpts) *dagger.Eval {
+	client := initClient()
+	return client.Eval(prompt, opts...)
+}
+
 // Creates a file with the specified contents.
 func File(name string, contents string, opts ...dagger.FileOpts) *dagger.File {
 	client := initClient()
@@ -252,6 +260,12 @@ func LoadDirectoryFromID(id dagger.DirectoryID) *dagger.Directory {
 	return client.LoadDirectoryFromID(id)
 }
 
+// Load a EngineCacheEntryConnection from its ID.
+func LoadEngineCacheEntryConnectionFromID(id dagger.EngineCacheEntryConnectionID) *dagger.EngineCacheEntryConnection {
+	client := initClient()
+	return client.LoadEngineCacheEntryConnectionFromID(id)
+}
+
 // Load a EngineCacheEntry from its ID.
 func LoadEngineCacheEntryFromID(id dagger.EngineCacheEntryID) *dagger.EngineCacheEntry {
 	client := initClient()
@@ -270,6 +284,12 @@ func LoadEngineCacheFromID(id dagger.EngineCacheID) *dagger.EngineCache {
 	return client.LoadEngineCacheFromID(id)
 }
 
+// Load a EngineClient from its ID.
+func LoadEngineClientFromID(id dagger.EngineClientID) *dagger.EngineClient {
+	client := initClient()
+	return client.LoadEngineClientFromID(id)
+}
+
 // Load a Engine from its ID.
 func LoadEngineFromID(id dagger.EngineID) *dagger.Engine {
 	client := initClient()
@@ -318,6 +338,24 @@ func LoadErrorValueFromID(id dagger.ErrorValueID) *dagger.ErrorValue {
 	return client.LoadErrorValueFromID(id)
 }
 
+// Load a Eval from its ID.
+func LoadEvalFromID(id dagger.EvalID) *dagger.Eval {
+	client := initClient()
+	return client.LoadEvalFromID(id)
+}
+
+// Load a EvalModelReport from its ID.
+func LoadEvalModelReportFromID(id dagger.EvalModelReportID) *dagger.EvalModelReport {
+	client := initClient()
+	return client.LoadEvalModelReportFromID(id)
+}
+
+// Load a EvalReport from its ID.
+func LoadEvalReportFromID(id dagger.EvalReportID) *dagger.EvalReport {
+	client := initClient()
+	return client.LoadEvalReportFromID(id)
+}
+
 // Load a FieldTypeDef from its ID.
 func LoadFieldTypeDefFromID(id dagger.FieldTypeDefID) *dagger.FieldTypeDef {
 	client := initClient()
@@ -396,6 +434,12 @@ func LoadJSONValueFromID(id dagger.JSONValueID) *dagger.JSONValue {
 	return client.LoadJSONValueFromID(id)
 }
 
+// Load a LLMEvent from its ID.
+func LoadLLMEventFromID(id dagger.LLMEventID) *dagger.LLMEvent {
+	client := initClient()
+	return client.LoadLLMEventFromID(id)
+}
+
 // Load a LLM from its ID.
 func LoadLLMFromID(id dagger.LLMID) *dagger.LLM {
 	client := initClient()
@@ -420,6 +464,18 @@ func LoadListTypeDefFromID(id dagger.ListTypeDefID) *dagger.ListTypeDef {
 	return client.LoadListTypeDefFromID(id)
 }
 
+// Load a LogEntry from its ID.
+func LoadLogEntryFromID(id dagger.LogEntryID) *dagger.LogEntry {
+	client := initClient()
+	return client.LoadLogEntryFromID(id)
+}
+
+// Load a MapTypeDef from its ID.
+func LoadMapTypeDefFromID(id dagger.MapTypeDefID) *dagger.MapTypeDef {
+	client := initClient()
+	return client.LoadMapTypeDefFromID(id)
+}
+
 // Load a ModuleConfigClient from its ID.
 func LoadModuleConfigClientFromID(id dagger.ModuleConfigClientID) *dagger.ModuleConfigClient {
 	client := initClient()
@@ -504,6 +560,12 @@ func LoadStatFromID(id dagger.StatID) *dagger.Stat {
 	return client.LoadStatFromID(id)
 }
 
+// Load a StringConnection from its ID.
+func LoadStringConnectionFromID(id dagger.StringConnectionID) *dagger.StringConnection {
+	client := initClient()
+	return client.LoadStringConnectionFromID(id)
+}
+
 // Load a Terminal from its ID.
 func LoadTerminalFromID(id dagger.TerminalID) *dagger.Terminal {
 	client := initClient()
@@ -516,6 +578,12 @@ func LoadTypeDefFromID(id dagger.TypeDefID) *dagger.TypeDef {
 	return client.LoadTypeDefFromID(id)
 }
 
+// Load a UnionTypeDef from its ID.
+func LoadUnionTypeDefFromID(id dagger.UnionTypeDefID) *dagger.UnionTypeDef {
+	client := initClient()
+	return client.LoadUnionTypeDefFromID(id)
+}
+
 // Create a new module.
 func Module() *dagger.Module {
 	client := initClient()
@@ -160,6 +160,9 @@ type CurrentModuleID string
 // The `DirectoryID` scalar type represents an identifier for an object of type Directory.
 type DirectoryID string
 
+// The `EngineCacheEntryConnectionID` scalar type represents an identifier for an object of type EngineCacheEntryConnection.
+type EngineCacheEntryConnectionID string
+
 // The `EngineCacheEntryID` scalar type represents an identifier for an object of type EngineCacheEntry.
 type EngineCacheEntryID string
 
@@ -169,6 +172,9 @@ type EngineCacheEntrySetID string
 // The `EngineCacheID` scalar type represents an identifier for an object of type EngineCache.
 type EngineCacheID string
 
+// The `EngineClientID` scalar type represents an identifier for an object of type EngineClient.
+type EngineClientID string
+
 // The `EngineID` scalar type represents an identifier for an object of type Engine.
 type EngineID string
 
@@ -193,6 +199,15 @@ type ErrorID string
 // The `ErrorValueID` scalar type represents an identifier for an object of type ErrorValue.
 type ErrorValueID string
 
+// The `EvalID` scalar type represents an identifier for an object of type Eval.
+type EvalID string
+
+// The `EvalModelReportID` scalar type represents an identifier for an object of type EvalModelReport.
+type EvalModelReportID string
+
+// The `EvalReportID` scalar type represents an identifier for an object of type EvalReport.
+type EvalReportID string
+
 // The `FieldTypeDefID` scalar type represents an identifier for an object of type FieldTypeDef.
 type FieldTypeDefID string
 
@@ -235,6 +250,9 @@ type JSON string
 // The `JSONValueID` scalar type represents an identifier for an object of type JSONValue.
 type JSONValueID string
 
+// The `LLMEventID` scalar type represents an identifier for an object of type LLMEvent.
+type LLMEventID string
+
 // The `LLMID` scalar type represents an identifier for an object of type LLM.
 type LLMID string
 
@@ -247,6 +265,12 @@ type LabelID string
 // The `ListTypeDefID` scalar type represents an identifier for an object of type ListTypeDef.
 type ListTypeDefID string
 
+// The `LogEntryID` scalar type represents an identifier for an object of type LogEntry.
+type LogEntryID string
+
+// The `MapTypeDefID` scalar type represents an identifier for an object of type MapTypeDef.
+type MapTypeDefID string
+
 // The `ModuleConfigClientID` scalar type represents an identifier for an object of type ModuleConfigClient.
 type ModuleConfigClientID string
 
@@ -294,12 +318,18 @@ type SourceMapID string
 // The `StatID` scalar type represents an identifier for an object of type Stat.
 type StatID string
 
+// The `StringConnectionID` scalar type represents an identifier for an object of type StringConnection.
+type StringConnectionID string
+
 // The `TerminalID` scalar type represents an identifier for an object of type Terminal.
 type TerminalID string
 
 // The `TypeDefID` scalar type represents an identifier for an object of type TypeDef.
 type TypeDefID string
 
+// The `UnionTypeDefID` scalar type represents an identifier for an object of type UnionTypeDef.
+type UnionTypeDefID string
+
 // The absence of a value.
 //
 // A Null Void is used as a placeholder for resolvers that do not return anything.
@@ -621,6 +651,24 @@ func (r *Binding) AsDirectory() *Directory {
 	}
 }
 
+// Retrieve the binding value, as type EngineCacheEntryConnection
+func (r *Binding) AsEngineCacheEntryConnection() *EngineCacheEntryConnection {
+	q := r.query.Select("asEngineCacheEntryConnection")
+
+	return &EngineCacheEntryConnection{
+		query: q,
+	}
+}
+
+// Retrieve the binding value, as type EngineClient
+func (r *Binding) AsEngineClient() *EngineClient {
+	q := r.query.Select("asEngineClient")
+
+	return &EngineClient{
+		query: q,
+	}
+}
+
 // Retrieve the binding value, as type Env
 func (r *Binding) AsEnv() *Env {
 	q := r.query.Select("asEnv")
@@ -639,6 +687,33 @@ func (r *Binding) AsEnvFile() *EnvFile {
 	}
 }
 
+// Retrieve the binding value, as type Eval
+func (r *Binding) AsEval() *Eval {
+	q := r.query.Select("asEval")
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// Retrieve the binding value, as type EvalModelReport
+func (r *Binding) AsEvalModelReport() *EvalModelReport {
+	q := r.query.Select("asEvalModelReport")
+
+	return &EvalModelReport{
+		query: q,
+	}
+}
+
+// Retrieve the binding value, as type EvalReport
+func (r *Binding) AsEvalReport() *EvalReport {
+	q := r.query.Select("asEvalReport")
+
+	return &EvalReport{
+		query: q,
+	}
+}
+
 // Retrieve the binding value, as type File
 func (r *Binding) AsFile() *File {
 	q := r.query.Select("asFile")
@@ -675,6 +750,15 @@ func (r *Binding) AsJSONValue() *JSONValue {
 	}
 }
 
+// Retrieve the binding value, as type LLMEvent
+func (r *Binding) AsLLMEvent() *LLMEvent {
+	q := r.query.Select("asLLMEvent")
+
+	return &LLMEvent{
+		query: q,
+	}
+}
+
 // Retrieve the binding value, as type Module
 func (r *Binding) AsModule() *Module {
 	q := r.query.Select("asModule")
@@ -769,6 +853,15 @@ func (r *Binding) AsString(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// Retrieve the binding value, as type StringConnection
+func (r *Binding) AsStringConnection() *StringConnection {
+	q := r.query.Select("asStringConnection")
+
+	return &StringConnection{
+		query: q,
+	}
+}
+
 // Returns the digest of the binding value
 func (r *Binding) Digest(ctx context.Context) (string, error) {
 	if r.digest != nil {
@@ -3661,6 +3754,39 @@ func (r *Directory) Entries(ctx context.Context, opts ...DirectoryEntriesOpts) (
 	return response, q.Execute(ctx)
 }
 
+// DirectoryEntriesConnectionOpts contains options for Directory.EntriesConnection
+type DirectoryEntriesConnectionOpts struct {
+	// Location of the directory to look at (e.g., "/src").
+	Path string
+	// The maximum number of elements to return. All remaining elements are returned if unset.
+	First int
+	// Only return elements after the given cursor, as returned by endCursor.
+	After string
+}
+
+// A page of the elements returned by entries, selected by a cursor.
+func (r *Directory) EntriesConnection(opts ...DirectoryEntriesConnectionOpts) *StringConnection {
+	q := r.query.Select("entriesConnection")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `path` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Path) {
+			q = q.Arg("path", opts[i].Path)
+		}
+		// `first` optional argument
+		if !querybuilder.IsZeroValue(opts[i].First) {
+			q = q.Arg("first", opts[i].First)
+		}
+		// `after` optional argument
+		if !querybuilder.IsZeroValue(opts[i].After) {
+			q = q.Arg("after", opts[i].After)
+		}
+	}
+
+	return &StringConnection{
+		query: q,
+	}
+}
+
 // DirectoryExistsOpts contains options for Directory.Exists
 type DirectoryExistsOpts struct {
 	// If specified, also validate the type of file (e.g. "REGULAR_TYPE", "DIRECTORY_TYPE", or "SYMLINK_TYPE").
@@ -3788,6 +3914,34 @@ func (r *Directory) Glob(ctx context.Context, pattern string) ([]string, error)
 	return response, q.Execute(ctx)
 }
 
+// DirectoryGlobConnectionOpts contains options for Directory.GlobConnection
+type DirectoryGlobConnectionOpts struct {
+	// The maximum number of elements to return. All remaining elements are returned if unset.
+	First int
+	// Only return elements after the given cursor, as returned by endCursor.
+	After string
+}
+
+// A page of the elements returned by glob, selected by a cursor.
+func (r *Directory) GlobConnection(pattern string, opts ...DirectoryGlobConnectionOpts) *StringConnection {
+	q := r.query.Select("globConnection")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `first` optional argument
+		if !querybuilder.IsZeroValue(opts[i].First) {
+			q = q.Arg("first", opts[i].First)
+		}
+		// `after` optional argument
+		if !querybuilder.IsZeroValue(opts[i].After) {
+			q = q.Arg("after", opts[i].After)
+		}
+	}
+	q = q.Arg("pattern", pattern)
+
+	return &StringConnection{
+		query: q,
+	}
+}
+
 // A unique identifier for this Directory.
 func (r *Directory) ID(ctx context.Context) (DirectoryID, error) {
 	if r.id != nil {
@@ -4280,6 +4434,41 @@ func (r *Engine) WithGraphQLQuery(q *querybuilder.Selection) *Engine {
 	}
 }
 
+// The clients connected to the engine that the current client may watch, across all sessions
+//
+// Experimental: Subject to change while dagger watch is developed
+func (r *Engine) ActiveClients(ctx context.Context) ([]EngineClient, error) {
+	q := r.query.Select("activeClients")
+
+	q = q.Select("id")
+
+	type activeClients struct {
+		Id EngineClientID
+	}
+
+	convert := func(fields []activeClients) []EngineClient {
+		out := []EngineClient{}
+
+		for i := range fields {
+			val := EngineClient{id: &fields[i].Id}
+			val.query = q.Root().Select("loadEngineClientFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []activeClients
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 // The list of connected client IDs
 func (r *Engine) Clients(ctx context.Context) ([]string, error) {
 	q := r.query.Select("clients")
@@ -4627,6 +4816,136 @@ func (r *EngineCacheEntry) MostRecentUseTimeUnixNano(ctx context.Context) (int,
 	return response, q.Execute(ctx)
 }
 
+// A page of a list of EngineCacheEntry, selected by a cursor.
+type EngineCacheEntryConnection struct {
+	query *querybuilder.Selection
+
+	endCursor   *string
+	hasNextPage *bool
+	id          *EngineCacheEntryConnectionID
+	totalCount  *int
+}
+
+func (r *EngineCacheEntryConnection) WithGraphQLQuery(q *querybuilder.Selection) *EngineCacheEntryConnection {
+	return &EngineCacheEntryConnection{
+		query: q,
+	}
+}
+
+// The cursor of the last element of the page, to pass as "after" to select the next page.
+//
+// Null if the page is empty.
+func (r *EngineCacheEntryConnection) EndCursor(ctx context.Context) (string, error) {
+	if r.endCursor != nil {
+		return *r.endCursor, nil
+	}
+	q := r.query.Select("endCursor")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Whether there are elements after this page.
+func (r *EngineCacheEntryConnection) HasNextPage(ctx context.Context) (bool, error) {
+	if r.hasNextPage != nil {
+		return *r.hasNextPage, nil
+	}
+	q := r.query.Select("hasNextPage")
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this EngineCacheEntryConnection.
+func (r *EngineCacheEntryConnection) ID(ctx context.Context) (EngineCacheEntryConnectionID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response EngineCacheEntryConnectionID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EngineCacheEntryConnection) XXX_GraphQLType() string {
+	return "EngineCacheEntryConnection"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EngineCacheEntryConnection) XXX_GraphQLIDType() string {
+	return "EngineCacheEntryConnectionID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EngineCacheEntryConnection) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EngineCacheEntryConnection) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The elements of the page.
+func (r *EngineCacheEntryConnection) Nodes(ctx context.Context) ([]EngineCacheEntry, error) {
+	q := r.query.Select("nodes")
+
+	q = q.Select("id")
+
+	type nodes struct {
+		Id EngineCacheEntryID
+	}
+
+	convert := func(fields []nodes) []EngineCacheEntry {
+		out := []EngineCacheEntry{}
+
+		for i := range fields {
+			val := EngineCacheEntry{id: &fields[i].Id}
+			val.query = q.Root().Select("loadEngineCacheEntryFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []nodes
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// The number of elements in the whole list.
+func (r *EngineCacheEntryConnection) TotalCount(ctx context.Context) (int, error) {
+	if r.totalCount != nil {
+		return *r.totalCount, nil
+	}
+	q := r.query.Select("totalCount")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A set of cache entries returned by a query to a cache
 type EngineCacheEntrySet struct {
 	query *querybuilder.Selection
@@ -4688,6 +5007,33 @@ func (r *EngineCacheEntrySet) Entries(ctx context.Context) ([]EngineCacheEntry,
 	return convert(response), nil
 }
 
+// EngineCacheEntrySetEntriesConnectionOpts contains options for EngineCacheEntrySet.EntriesConnection
+type EngineCacheEntrySetEntriesConnectionOpts struct {
+	// The maximum number of elements to return. All remaining elements are returned if unset.
+	First int
+	// Only return elements after the given cursor, as returned by endCursor.
+	After string
+}
+
+// A page of the elements returned by entries, selected by a cursor.
+func (r *EngineCacheEntrySet) EntriesConnection(opts ...EngineCacheEntrySetEntriesConnectionOpts) *EngineCacheEntryConnection {
+	q := r.query.Select("entriesConnection")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `first` optional argument
+		if !querybuilder.IsZeroValue(opts[i].First) {
+			q = q.Arg("first", opts[i].First)
+		}
+		// `after` optional argument
+		if !querybuilder.IsZeroValue(opts[i].After) {
+			q = q.Arg("after", opts[i].After)
+		}
+	}
+
+	return &EngineCacheEntryConnection{
+		query: q,
+	}
+}
+
 // The number of cache entries in this set.
 func (r *EngineCacheEntrySet) EntryCount(ctx context.Context) (int, error) {
 	if r.entryCount != nil {
@@ -4741,28 +5087,34 @@ func (r *EngineCacheEntrySet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
-// A definition of a custom enum defined in a Module.
-type EnumTypeDef struct {
+// A client connected to the Dagger engine
+type EngineClient struct {
 	query *querybuilder.Selection
 
-	description      *string
-	id               *EnumTypeDefID
-	name             *string
-	sourceModuleName *string
+	clientID            *string
+	command             *string
+	hostname            *string
+	id                  *EngineClientID
+	main                *bool
+	module              *string
+	sessionID           *string
+	startedTimeUnixNano *int
+	status              *string
+	version             *string
 }
 
-func (r *EnumTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *EnumTypeDef {
-	return &EnumTypeDef{
+func (r *EngineClient) WithGraphQLQuery(q *querybuilder.Selection) *EngineClient {
+	return &EngineClient{
 		query: q,
 	}
 }
 
-// A doc string for the enum, if any.
-func (r *EnumTypeDef) Description(ctx context.Context) (string, error) {
-	if r.description != nil {
-		return *r.description, nil
+// The ID of the client.
+func (r *EngineClient) ClientID(ctx context.Context) (string, error) {
+	if r.clientID != nil {
+		return *r.clientID, nil
 	}
-	q := r.query.Select("description")
+	q := r.query.Select("clientID")
 
 	var response string
 
@@ -4770,31 +5122,57 @@ func (r *EnumTypeDef) Description(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
-// A unique identifier for this EnumTypeDef.
-func (r *EnumTypeDef) ID(ctx context.Context) (EnumTypeDefID, error) {
+// The command the client is running, if known.
+func (r *EngineClient) Command(ctx context.Context) (string, error) {
+	if r.command != nil {
+		return *r.command, nil
+	}
+	q := r.query.Select("command")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The hostname of the machine the client is running on.
+func (r *EngineClient) Hostname(ctx context.Context) (string, error) {
+	if r.hostname != nil {
+		return *r.hostname, nil
+	}
+	q := r.query.Select("hostname")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this EngineClient.
+func (r *EngineClient) ID(ctx context.Context) (EngineClientID, error) {
 	if r.id != nil {
 		return *r.id, nil
 	}
 	q := r.query.Select("id")
 
-	var response EnumTypeDefID
+	var response EngineClientID
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx)
 }
 
 // XXX_GraphQLType is an internal function. It returns the native GraphQL type name
-func (r *EnumTypeDef) XXX_GraphQLType() string {
-	return "EnumTypeDef"
+func (r *EngineClient) XXX_GraphQLType() string {
+	return "EngineClient"
 }
 
 // XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
-func (r *EnumTypeDef) XXX_GraphQLIDType() string {
-	return "EnumTypeDefID"
+func (r *EngineClient) XXX_GraphQLIDType() string {
+	return "EngineClientID"
 }
 
 // XXX_GraphQLID is an internal function. It returns the underlying type ID
-func (r *EnumTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+func (r *EngineClient) XXX_GraphQLID(ctx context.Context) (string, error) {
 	id, err := r.ID(ctx)
 	if err != nil {
 		return "", err
@@ -4802,7 +5180,7 @@ func (r *EnumTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
 	return string(id), nil
 }
 
-func (r *EnumTypeDef) MarshalJSON() ([]byte, error) {
+func (r *EngineClient) MarshalJSON() ([]byte, error) {
 	id, err := r.ID(marshalCtx)
 	if err != nil {
 		return nil, err
@@ -4810,14 +5188,161 @@ func (r *EnumTypeDef) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
-// The members of the enum.
-func (r *EnumTypeDef) Members(ctx context.Context) ([]EnumValueTypeDef, error) {
-	q := r.query.Select("members")
+// Whether the client started its session, as opposed to being nested within it.
+func (r *EngineClient) Main(ctx context.Context) (bool, error) {
+	if r.main != nil {
+		return *r.main, nil
+	}
+	q := r.query.Select("main")
 
-	q = q.Select("id")
+	var response bool
 
-	type members struct {
-		Id EnumValueTypeDefID
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The name of the module the client is running, if any.
+func (r *EngineClient) Module(ctx context.Context) (string, error) {
+	if r.module != nil {
+		return *r.module, nil
+	}
+	q := r.query.Select("module")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The ID of the session the client belongs to.
+func (r *EngineClient) SessionID(ctx context.Context) (string, error) {
+	if r.sessionID != nil {
+		return *r.sessionID, nil
+	}
+	q := r.query.Select("sessionID")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The time the client connected, in Unix nanoseconds.
+func (r *EngineClient) StartedTimeUnixNano(ctx context.Context) (int, error) {
+	if r.startedTimeUnixNano != nil {
+		return *r.startedTimeUnixNano, nil
+	}
+	q := r.query.Select("startedTimeUnixNano")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The status of the client: starting, running or stopping.
+func (r *EngineClient) Status(ctx context.Context) (string, error) {
+	if r.status != nil {
+		return *r.status, nil
+	}
+	q := r.query.Select("status")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The version of the client.
+func (r *EngineClient) Version(ctx context.Context) (string, error) {
+	if r.version != nil {
+		return *r.version, nil
+	}
+	q := r.query.Select("version")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A definition of a custom enum defined in a Module.
+type EnumTypeDef struct {
+	query *querybuilder.Selection
+
+	description      *string
+	id               *EnumTypeDefID
+	name             *string
+	sourceModuleName *string
+}
+
+func (r *EnumTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *EnumTypeDef {
+	return &EnumTypeDef{
+		query: q,
+	}
+}
+
+// A doc string for the enum, if any.
+func (r *EnumTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.query.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this EnumTypeDef.
+func (r *EnumTypeDef) ID(ctx context.Context) (EnumTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response EnumTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EnumTypeDef) XXX_GraphQLType() string {
+	return "EnumTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EnumTypeDef) XXX_GraphQLIDType() string {
+	return "EnumTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EnumTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EnumTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The members of the enum.
+func (r *EnumTypeDef) Members(ctx context.Context) ([]EnumValueTypeDef, error) {
+	q := r.query.Select("members")
+
+	q = q.Select("id")
+
+	type members struct {
+		Id EnumValueTypeDefID
 	}
 
 	convert := func(fields []members) []EnumValueTypeDef {
@@ -5378,6 +5903,54 @@ func (r *Env) WithDirectoryOutput(name string, description string) *Env {
 	}
 }
 
+// Create or update a binding of type EngineCacheEntryConnection in the environment
+func (r *Env) WithEngineCacheEntryConnectionInput(name string, value *EngineCacheEntryConnection, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withEngineCacheEntryConnectionInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired EngineCacheEntryConnection output to be assigned in the environment
+func (r *Env) WithEngineCacheEntryConnectionOutput(name string, description string) *Env {
+	q := r.query.Select("withEngineCacheEntryConnectionOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Create or update a binding of type EngineClient in the environment
+func (r *Env) WithEngineClientInput(name string, value *EngineClient, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withEngineClientInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired EngineClient output to be assigned in the environment
+func (r *Env) WithEngineClientOutput(name string, description string) *Env {
+	q := r.query.Select("withEngineClientOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
 // Create or update a binding of type EnvFile in the environment
 func (r *Env) WithEnvFileInput(name string, value *EnvFile, description string) *Env {
 	assertNotNil("value", value)
@@ -5426,6 +5999,78 @@ func (r *Env) WithEnvOutput(name string, description string) *Env {
 	}
 }
 
+// Create or update a binding of type Eval in the environment
+func (r *Env) WithEvalInput(name string, value *Eval, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withEvalInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Create or update a binding of type EvalModelReport in the environment
+func (r *Env) WithEvalModelReportInput(name string, value *EvalModelReport, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withEvalModelReportInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired EvalModelReport output to be assigned in the environment
+func (r *Env) WithEvalModelReportOutput(name string, description string) *Env {
+	q := r.query.Select("withEvalModelReportOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired Eval output to be assigned in the environment
+func (r *Env) WithEvalOutput(name string, description string) *Env {
+	q := r.query.Select("withEvalOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Create or update a binding of type EvalReport in the environment
+func (r *Env) WithEvalReportInput(name string, value *EvalReport, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withEvalReportInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired EvalReport output to be assigned in the environment
+func (r *Env) WithEvalReportOutput(name string, description string) *Env {
+	q := r.query.Select("withEvalReportOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
 // Create or update a binding of type File in the environment
 func (r *Env) WithFileInput(name string, value *File, description string) *Env {
 	assertNotNil("value", value)
@@ -5522,6 +6167,30 @@ func (r *Env) WithJSONValueOutput(name string, description string) *Env {
 	}
 }
 
+// Create or update a binding of type LLMEvent in the environment
+func (r *Env) WithLLMEventInput(name string, value *LLMEvent, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withLLMEventInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired LLMEvent output to be assigned in the environment
+func (r *Env) WithLLMEventOutput(name string, description string) *Env {
+	q := r.query.Select("withLLMEventOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
 // Installs a module into the environment, exposing its functions to the model
 //
 // Contextual path arguments will be populated using the environment's workspace.
@@ -5751,6 +6420,30 @@ func (r *Env) WithStatOutput(name string, description string) *Env {
 	}
 }
 
+// Create or update a binding of type StringConnection in the environment
+func (r *Env) WithStringConnectionInput(name string, value *StringConnection, description string) *Env {
+	assertNotNil("value", value)
+	q := r.query.Select("withStringConnectionInput")
+	q = q.Arg("name", name)
+	q = q.Arg("value", value)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
+// Declare a desired StringConnection output to be assigned in the environment
+func (r *Env) WithStringConnectionOutput(name string, description string) *Env {
+	q := r.query.Select("withStringConnectionOutput")
+	q = q.Arg("name", name)
+	q = q.Arg("description", description)
+
+	return &Env{
+		query: q,
+	}
+}
+
 // Provides a string input binding to the environment
 func (r *Env) WithStringInput(name string, value string, description string) *Env {
 	q := r.query.Select("withStringInput")
@@ -6269,21 +6962,522 @@ func (r *ErrorValue) Value(ctx context.Context) (JSON, error) {
 	return response, q.Execute(ctx)
 }
 
-// A definition of a field on a custom object defined in a Module.
+// An evaluation of an agent against a prompt and environment.
 //
-// A field on an object has a static value, as opposed to a function on an object whose value is computed by invoking code (and can accept arguments).
-type FieldTypeDef struct {
+// Each model is given the prompt a number of times, and each attempt is checked against a set of assertions.
+type Eval struct {
 	query *querybuilder.Selection
 
-	deprecated  *string
-	description *string
-	id          *FieldTypeDefID
-	name        *string
+	attempts    *int
+	id          *EvalID
+	maxAPICalls *int
+	prompt      *string
 }
+type WithEvalFunc func(r *Eval) *Eval
 
-func (r *FieldTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *FieldTypeDef {
-	return &FieldTypeDef{
-		query: q,
+// With calls the provided function with current Eval.
+//
+// This is useful for reusability and readability by not breaking the calling chain.
+func (r *Eval) With(f WithEvalFunc) *Eval {
+	return f(r)
+}
+
+func (r *Eval) WithGraphQLQuery(q *querybuilder.Selection) *Eval {
+	return &Eval{
+		query: q,
+	}
+}
+
+// The number of attempts to run for each model.
+func (r *Eval) Attempts(ctx context.Context) (int, error) {
+	if r.attempts != nil {
+		return *r.attempts, nil
+	}
+	q := r.query.Select("attempts")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this Eval.
+func (r *Eval) ID(ctx context.Context) (EvalID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response EvalID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *Eval) XXX_GraphQLType() string {
+	return "Eval"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *Eval) XXX_GraphQLIDType() string {
+	return "EvalID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *Eval) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *Eval) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The maximum number of API calls per attempt, or 0 for no limit.
+func (r *Eval) MaxAPICalls(ctx context.Context) (int, error) {
+	if r.maxAPICalls != nil {
+		return *r.maxAPICalls, nil
+	}
+	q := r.query.Select("maxAPICalls")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The prompt given to the agent.
+func (r *Eval) Prompt(ctx context.Context) (string, error) {
+	if r.prompt != nil {
+		return *r.prompt, nil
+	}
+	q := r.query.Select("prompt")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Run every attempt for every model, and report the outcomes
+func (r *Eval) Run() *EvalReport {
+	q := r.query.Select("run")
+
+	return &EvalReport{
+		query: q,
+	}
+}
+
+// Set the number of attempts to run for each model
+func (r *Eval) WithAttempts(attempts int) *Eval {
+	q := r.query.Select("withAttempts")
+	q = q.Arg("attempts", attempts)
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// EvalWithChangesetAssertionOpts contains options for Eval.WithChangesetAssertion
+type EvalWithChangesetAssertionOpts struct {
+	// Paths or glob patterns that must have been added
+	Added []string
+	// Paths or glob patterns that must have been modified
+	Modified []string
+	// Paths or glob patterns that must have been removed
+	Removed []string
+}
+
+// Assert that the agent changed its environment's workspace
+func (r *Eval) WithChangesetAssertion(opts ...EvalWithChangesetAssertionOpts) *Eval {
+	q := r.query.Select("withChangesetAssertion")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `added` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Added) {
+			q = q.Arg("added", opts[i].Added)
+		}
+		// `modified` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Modified) {
+			q = q.Arg("modified", opts[i].Modified)
+		}
+		// `removed` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Removed) {
+			q = q.Arg("removed", opts[i].Removed)
+		}
+	}
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// Run the agent in an environment
+func (r *Eval) WithEnv(env *Env) *Eval {
+	assertNotNil("env", env)
+	q := r.query.Select("withEnv")
+	q = q.Arg("env", env)
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// Cap the number of API calls for each attempt
+func (r *Eval) WithMaxAPICalls(maxAPICalls int) *Eval {
+	q := r.query.Select("withMaxAPICalls")
+	q = q.Arg("maxAPICalls", maxAPICalls)
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// Add a model to evaluate. If no model is added, the default model is evaluated.
+func (r *Eval) WithModel(model string) *Eval {
+	q := r.query.Select("withModel")
+	q = q.Arg("model", model)
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// EvalWithOutputAssertionOpts contains options for Eval.WithOutputAssertion
+type EvalWithOutputAssertionOpts struct {
+	// A string that the output must contain
+	Contains string
+	// A regular expression that the output must match
+	Matches string
+}
+
+// Assert that the agent saved an output in its environment.
+//
+// String outputs and the contents of File outputs may also be checked against a string and/or a pattern.
+func (r *Eval) WithOutputAssertion(name string, opts ...EvalWithOutputAssertionOpts) *Eval {
+	q := r.query.Select("withOutputAssertion")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `contains` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Contains) {
+			q = q.Arg("contains", opts[i].Contains)
+		}
+		// `matches` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Matches) {
+			q = q.Arg("matches", opts[i].Matches)
+		}
+	}
+	q = q.Arg("name", name)
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// EvalWithReplyAssertionOpts contains options for Eval.WithReplyAssertion
+type EvalWithReplyAssertionOpts struct {
+	// A string that the reply must contain
+	Contains string
+	// A regular expression that the reply must match
+	Matches string
+}
+
+// Assert that the agent's last reply contains a string and/or matches a pattern
+func (r *Eval) WithReplyAssertion(opts ...EvalWithReplyAssertionOpts) *Eval {
+	q := r.query.Select("withReplyAssertion")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `contains` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Contains) {
+			q = q.Arg("contains", opts[i].Contains)
+		}
+		// `matches` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Matches) {
+			q = q.Arg("matches", opts[i].Matches)
+		}
+	}
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// The outcome of an evaluation for a single model.
+type EvalModelReport struct {
+	query *querybuilder.Selection
+
+	attempts    *int
+	id          *EvalModelReportID
+	meanLatency *float64
+	model       *string
+	successRate *float64
+	successes   *int
+}
+
+func (r *EvalModelReport) WithGraphQLQuery(q *querybuilder.Selection) *EvalModelReport {
+	return &EvalModelReport{
+		query: q,
+	}
+}
+
+// The number of attempts.
+func (r *EvalModelReport) Attempts(ctx context.Context) (int, error) {
+	if r.attempts != nil {
+		return *r.attempts, nil
+	}
+	q := r.query.Select("attempts")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Why each failed attempt failed.
+func (r *EvalModelReport) Failures(ctx context.Context) ([]string, error) {
+	q := r.query.Select("failures")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this EvalModelReport.
+func (r *EvalModelReport) ID(ctx context.Context) (EvalModelReportID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response EvalModelReportID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EvalModelReport) XXX_GraphQLType() string {
+	return "EvalModelReport"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EvalModelReport) XXX_GraphQLIDType() string {
+	return "EvalModelReportID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EvalModelReport) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EvalModelReport) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The mean duration of an attempt, in seconds.
+func (r *EvalModelReport) MeanLatency(ctx context.Context) (float64, error) {
+	if r.meanLatency != nil {
+		return *r.meanLatency, nil
+	}
+	q := r.query.Select("meanLatency")
+
+	var response float64
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The model that was evaluated.
+func (r *EvalModelReport) Model(ctx context.Context) (string, error) {
+	if r.model != nil {
+		return *r.model, nil
+	}
+	q := r.query.Select("model")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The fraction of attempts that succeeded, between 0 and 1.
+func (r *EvalModelReport) SuccessRate(ctx context.Context) (float64, error) {
+	if r.successRate != nil {
+		return *r.successRate, nil
+	}
+	q := r.query.Select("successRate")
+
+	var response float64
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The number of attempts that satisfied every assertion.
+func (r *EvalModelReport) Successes(ctx context.Context) (int, error) {
+	if r.successes != nil {
+		return *r.successes, nil
+	}
+	q := r.query.Select("successes")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The tokens used across all attempts.
+func (r *EvalModelReport) TokenUsage() *LLMTokenUsage {
+	q := r.query.Select("tokenUsage")
+
+	return &LLMTokenUsage{
+		query: q,
+	}
+}
+
+// The outcome of an evaluation, for each model.
+type EvalReport struct {
+	query *querybuilder.Selection
+
+	id    *EvalReportID
+	json  *JSON
+	table *string
+}
+
+func (r *EvalReport) WithGraphQLQuery(q *querybuilder.Selection) *EvalReport {
+	return &EvalReport{
+		query: q,
+	}
+}
+
+// A unique identifier for this EvalReport.
+func (r *EvalReport) ID(ctx context.Context) (EvalReportID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response EvalReportID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EvalReport) XXX_GraphQLType() string {
+	return "EvalReport"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EvalReport) XXX_GraphQLIDType() string {
+	return "EvalReportID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EvalReport) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EvalReport) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// Render the report as JSON
+func (r *EvalReport) JSON(ctx context.Context) (JSON, error) {
+	if r.json != nil {
+		return *r.json, nil
+	}
+	q := r.query.Select("json")
+
+	var response JSON
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The results for each model.
+func (r *EvalReport) Models(ctx context.Context) ([]EvalModelReport, error) {
+	q := r.query.Select("models")
+
+	q = q.Select("id")
+
+	type models struct {
+		Id EvalModelReportID
+	}
+
+	convert := func(fields []models) []EvalModelReport {
+		out := []EvalModelReport{}
+
+		for i := range fields {
+			val := EvalModelReport{id: &fields[i].Id}
+			val.query = q.Root().Select("loadEvalModelReportFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []models
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// Render the report as a markdown table
+func (r *EvalReport) Table(ctx context.Context) (string, error) {
+	if r.table != nil {
+		return *r.table, nil
+	}
+	q := r.query.Select("table")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A definition of a field on a custom object defined in a Module.
+//
+// A field on an object has a static value, as opposed to a function on an object whose value is computed by invoking code (and can accept arguments).
+type FieldTypeDef struct {
+	query *querybuilder.Selection
+
+	deprecated  *string
+	description *string
+	id          *FieldTypeDefID
+	name        *string
+}
+
+func (r *FieldTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *FieldTypeDef {
+	return &FieldTypeDef{
+		query: q,
 	}
 }
 
@@ -7053,6 +8247,28 @@ func (r *Function) WithDescription(description string) *Function {
 	}
 }
 
+// Returns the function with a flag indicating the list it returns can be selected in pages.
+//
+// A "<name>Connection" field is installed alongside the function, with first and after arguments selecting a page of the list.
+func (r *Function) WithPaginated() *Function {
+	q := r.query.Select("withPaginated")
+
+	return &Function{
+		query: q,
+	}
+}
+
+// Returns the function with a flag indicating it renders a prompt template.
+//
+// Prompt functions are exposed as prompts by MCP servers.
+func (r *Function) WithPrompt() *Function {
+	q := r.query.Select("withPrompt")
+
+	return &Function{
+		query: q,
+	}
+}
+
 // Returns the function with the given source map.
 func (r *Function) WithSourceMap(sourceMap *SourceMap) *Function {
 	assertNotNil("sourceMap", sourceMap)
@@ -7840,10 +9056,43 @@ func (r *GitRepository) Tags(ctx context.Context, opts ...GitRepositoryTagsOpts)
 		}
 	}
 
-	var response []string
-
-	q = q.Bind(&response)
-	return response, q.Execute(ctx)
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// GitRepositoryTagsConnectionOpts contains options for GitRepository.TagsConnection
+type GitRepositoryTagsConnectionOpts struct {
+	// Glob patterns (e.g., "refs/tags/v*").
+	Patterns []string
+	// The maximum number of elements to return. All remaining elements are returned if unset.
+	First int
+	// Only return elements after the given cursor, as returned by endCursor.
+	After string
+}
+
+// A page of the elements returned by tags, selected by a cursor.
+func (r *GitRepository) TagsConnection(opts ...GitRepositoryTagsConnectionOpts) *StringConnection {
+	q := r.query.Select("tagsConnection")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `patterns` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Patterns) {
+			q = q.Arg("patterns", opts[i].Patterns)
+		}
+		// `first` optional argument
+		if !querybuilder.IsZeroValue(opts[i].First) {
+			q = q.Arg("first", opts[i].First)
+		}
+		// `after` optional argument
+		if !querybuilder.IsZeroValue(opts[i].After) {
+			q = q.Arg("after", opts[i].After)
+		}
+	}
+
+	return &StringConnection{
+		query: q,
+	}
 }
 
 // Returns the changeset of uncommitted changes in the git repository.
@@ -8811,6 +10060,30 @@ func (r *LLM) WithBlockedFunction(typeName string, function string) *LLM {
 	}
 }
 
+// LLMWithContextPolicyOpts contains options for LLM.WithContextPolicy
+type LLMWithContextPolicyOpts struct {
+	// How to compact the message history
+	//
+	// Default: SUMMARIZE
+	Strategy LLMContextStrategy
+}
+
+// Compact the message history automatically whenever it grows beyond a token budget
+func (r *LLM) WithContextPolicy(maxTokens int, opts ...LLMWithContextPolicyOpts) *LLM {
+	q := r.query.Select("withContextPolicy")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `strategy` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Strategy) {
+			q = q.Arg("strategy", opts[i].Strategy)
+		}
+	}
+	q = q.Arg("maxTokens", maxTokens)
+
+	return &LLM{
+		query: q,
+	}
+}
+
 // allow the LLM to interact with an environment via MCP
 func (r *LLM) WithEnv(env *Env) *LLM {
 	assertNotNil("env", env)
@@ -8874,6 +10147,27 @@ func (r *LLM) WithStaticTools() *LLM {
 	}
 }
 
+// LLMWithSubagentsOpts contains options for LLM.WithSubagents
+type LLMWithSubagentsOpts struct {
+	// The model for sub-agents to use. Defaults to the LLM's current model.
+	Model string
+}
+
+// Allow the LLM to delegate tasks to sub-agents, each with its own environment and system prompt
+func (r *LLM) WithSubagents(opts ...LLMWithSubagentsOpts) *LLM {
+	q := r.query.Select("withSubagents")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `model` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Model) {
+			q = q.Arg("model", opts[i].Model)
+		}
+	}
+
+	return &LLM{
+		query: q,
+	}
+}
+
 // Add a system prompt to the LLM's environment
 func (r *LLM) WithSystemPrompt(prompt string) *LLM {
 	q := r.query.Select("withSystemPrompt")
@@ -8911,6 +10205,129 @@ func (r *LLM) WithoutSystemPrompts() *LLM {
 	}
 }
 
+// A message added to an LLM's history while it runs.
+type LLMEvent struct {
+	query *querybuilder.Selection
+
+	content     *string
+	id          *LLMEventID
+	role        *string
+	toolCallId  *string
+	toolCalls   *JSON
+	toolErrored *bool
+}
+
+func (r *LLMEvent) WithGraphQLQuery(q *querybuilder.Selection) *LLMEvent {
+	return &LLMEvent{
+		query: q,
+	}
+}
+
+// The text content of the message.
+func (r *LLMEvent) Content(ctx context.Context) (string, error) {
+	if r.content != nil {
+		return *r.content, nil
+	}
+	q := r.query.Select("content")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this LLMEvent.
+func (r *LLMEvent) ID(ctx context.Context) (LLMEventID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response LLMEventID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *LLMEvent) XXX_GraphQLType() string {
+	return "LLMEvent"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *LLMEvent) XXX_GraphQLIDType() string {
+	return "LLMEventID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *LLMEvent) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *LLMEvent) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The role of the message's author: user, assistant, or system.
+func (r *LLMEvent) Role(ctx context.Context) (string, error) {
+	if r.role != nil {
+		return *r.role, nil
+	}
+	q := r.query.Select("role")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The ID of the tool call that this message is the result of, if any.
+func (r *LLMEvent) ToolCallID(ctx context.Context) (string, error) {
+	if r.toolCallId != nil {
+		return *r.toolCallId, nil
+	}
+	q := r.query.Select("toolCallId")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The tools called by the assistant, as a JSON array.
+func (r *LLMEvent) ToolCalls(ctx context.Context) (JSON, error) {
+	if r.toolCalls != nil {
+		return *r.toolCalls, nil
+	}
+	q := r.query.Select("toolCalls")
+
+	var response JSON
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Whether the tool call that this message is the result of failed.
+func (r *LLMEvent) ToolErrored(ctx context.Context) (bool, error) {
+	if r.toolErrored != nil {
+		return *r.toolErrored, nil
+	}
+	q := r.query.Select("toolErrored")
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 type LLMTokenUsage struct {
 	query *querybuilder.Selection
 
@@ -9028,46 +10445,202 @@ func (r *LLMTokenUsage) TotalTokens(ctx context.Context) (int, error) {
 	return response, q.Execute(ctx)
 }
 
-// A simple key value object that represents a label.
-type Label struct {
+// A simple key value object that represents a label.
+type Label struct {
+	query *querybuilder.Selection
+
+	id    *LabelID
+	name  *string
+	value *string
+}
+
+func (r *Label) WithGraphQLQuery(q *querybuilder.Selection) *Label {
+	return &Label{
+		query: q,
+	}
+}
+
+// A unique identifier for this Label.
+func (r *Label) ID(ctx context.Context) (LabelID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response LabelID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *Label) XXX_GraphQLType() string {
+	return "Label"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *Label) XXX_GraphQLIDType() string {
+	return "LabelID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *Label) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *Label) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The label name.
+func (r *Label) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The label value.
+func (r *Label) Value(ctx context.Context) (string, error) {
+	if r.value != nil {
+		return *r.value, nil
+	}
+	q := r.query.Select("value")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A definition of a list type in a Module.
+type ListTypeDef struct {
+	query *querybuilder.Selection
+
+	id *ListTypeDefID
+}
+
+func (r *ListTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *ListTypeDef {
+	return &ListTypeDef{
+		query: q,
+	}
+}
+
+// The type of the elements in the list.
+func (r *ListTypeDef) ElementTypeDef() *TypeDef {
+	q := r.query.Select("elementTypeDef")
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// A unique identifier for this ListTypeDef.
+func (r *ListTypeDef) ID(ctx context.Context) (ListTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response ListTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *ListTypeDef) XXX_GraphQLType() string {
+	return "ListTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *ListTypeDef) XXX_GraphQLIDType() string {
+	return "ListTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *ListTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *ListTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// A chunk of output written by a process.
+type LogEntry struct {
 	query *querybuilder.Selection
 
-	id    *LabelID
-	name  *string
-	value *string
+	data   *string
+	id     *LogEntryID
+	stream *LogStream
 }
 
-func (r *Label) WithGraphQLQuery(q *querybuilder.Selection) *Label {
-	return &Label{
+func (r *LogEntry) WithGraphQLQuery(q *querybuilder.Selection) *LogEntry {
+	return &LogEntry{
 		query: q,
 	}
 }
 
-// A unique identifier for this Label.
-func (r *Label) ID(ctx context.Context) (LabelID, error) {
+// The output, which may include partial lines.
+func (r *LogEntry) Data(ctx context.Context) (string, error) {
+	if r.data != nil {
+		return *r.data, nil
+	}
+	q := r.query.Select("data")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this LogEntry.
+func (r *LogEntry) ID(ctx context.Context) (LogEntryID, error) {
 	if r.id != nil {
 		return *r.id, nil
 	}
 	q := r.query.Select("id")
 
-	var response LabelID
+	var response LogEntryID
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx)
 }
 
 // XXX_GraphQLType is an internal function. It returns the native GraphQL type name
-func (r *Label) XXX_GraphQLType() string {
-	return "Label"
+func (r *LogEntry) XXX_GraphQLType() string {
+	return "LogEntry"
 }
 
 // XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
-func (r *Label) XXX_GraphQLIDType() string {
-	return "LabelID"
+func (r *LogEntry) XXX_GraphQLIDType() string {
+	return "LogEntryID"
 }
 
 // XXX_GraphQLID is an internal function. It returns the underlying type ID
-func (r *Label) XXX_GraphQLID(ctx context.Context) (string, error) {
+func (r *LogEntry) XXX_GraphQLID(ctx context.Context) (string, error) {
 	id, err := r.ID(ctx)
 	if err != nil {
 		return "", err
@@ -9075,7 +10648,7 @@ func (r *Label) XXX_GraphQLID(ctx context.Context) (string, error) {
 	return string(id), nil
 }
 
-func (r *Label) MarshalJSON() ([]byte, error) {
+func (r *LogEntry) MarshalJSON() ([]byte, error) {
 	id, err := r.ID(marshalCtx)
 	if err != nil {
 		return nil, err
@@ -9083,79 +10656,59 @@ func (r *Label) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
-// The label name.
-func (r *Label) Name(ctx context.Context) (string, error) {
-	if r.name != nil {
-		return *r.name, nil
-	}
-	q := r.query.Select("name")
-
-	var response string
-
-	q = q.Bind(&response)
-	return response, q.Execute(ctx)
-}
-
-// The label value.
-func (r *Label) Value(ctx context.Context) (string, error) {
-	if r.value != nil {
-		return *r.value, nil
+// The stream that the output was written to.
+func (r *LogEntry) Stream(ctx context.Context) (LogStream, error) {
+	if r.stream != nil {
+		return *r.stream, nil
 	}
-	q := r.query.Select("value")
+	q := r.query.Select("stream")
 
-	var response string
+	var response LogStream
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx)
 }
 
-// A definition of a list type in a Module.
-type ListTypeDef struct {
+// A definition of a map type in a Module.
+//
+// Maps have string keys, and are represented as JSON objects in the GraphQL schema.
+type MapTypeDef struct {
 	query *querybuilder.Selection
 
-	id *ListTypeDefID
-}
-
-func (r *ListTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *ListTypeDef {
-	return &ListTypeDef{
-		query: q,
-	}
+	id *MapTypeDefID
 }
 
-// The type of the elements in the list.
-func (r *ListTypeDef) ElementTypeDef() *TypeDef {
-	q := r.query.Select("elementTypeDef")
-
-	return &TypeDef{
+func (r *MapTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *MapTypeDef {
+	return &MapTypeDef{
 		query: q,
 	}
 }
 
-// A unique identifier for this ListTypeDef.
-func (r *ListTypeDef) ID(ctx context.Context) (ListTypeDefID, error) {
+// A unique identifier for this MapTypeDef.
+func (r *MapTypeDef) ID(ctx context.Context) (MapTypeDefID, error) {
 	if r.id != nil {
 		return *r.id, nil
 	}
 	q := r.query.Select("id")
 
-	var response ListTypeDefID
+	var response MapTypeDefID
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx)
 }
 
 // XXX_GraphQLType is an internal function. It returns the native GraphQL type name
-func (r *ListTypeDef) XXX_GraphQLType() string {
-	return "ListTypeDef"
+func (r *MapTypeDef) XXX_GraphQLType() string {
+	return "MapTypeDef"
 }
 
 // XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
-func (r *ListTypeDef) XXX_GraphQLIDType() string {
-	return "ListTypeDefID"
+func (r *MapTypeDef) XXX_GraphQLIDType() string {
+	return "MapTypeDefID"
 }
 
 // XXX_GraphQLID is an internal function. It returns the underlying type ID
-func (r *ListTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+func (r *MapTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
 	id, err := r.ID(ctx)
 	if err != nil {
 		return "", err
@@ -9163,7 +10716,7 @@ func (r *ListTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
 	return string(id), nil
 }
 
-func (r *ListTypeDef) MarshalJSON() ([]byte, error) {
+func (r *MapTypeDef) MarshalJSON() ([]byte, error) {
 	id, err := r.ID(marshalCtx)
 	if err != nil {
 		return nil, err
@@ -9171,6 +10724,15 @@ func (r *ListTypeDef) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// The type of the values in the map. Keys are always strings.
+func (r *MapTypeDef) ValueTypeDef() *TypeDef {
+	q := r.query.Select("valueTypeDef")
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
 // A Dagger module.
 type Module struct {
 	query *querybuilder.Selection
@@ -9515,6 +11077,39 @@ func (r *Module) Sync(ctx context.Context) (*Module, error) {
 	}, nil
 }
 
+// Unions served by this module.
+func (r *Module) Unions(ctx context.Context) ([]TypeDef, error) {
+	q := r.query.Select("unions")
+
+	q = q.Select("id")
+
+	type unions struct {
+		Id TypeDefID
+	}
+
+	convert := func(fields []unions) []TypeDef {
+		out := []TypeDef{}
+
+		for i := range fields {
+			val := TypeDef{id: &fields[i].Id}
+			val.query = q.Root().Select("loadTypeDefFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []unions
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 // User-defined default values, loaded from local .env files.
 func (r *Module) UserDefaults() *EnvFile {
 	q := r.query.Select("userDefaults")
@@ -9567,6 +11162,17 @@ func (r *Module) WithObject(object *TypeDef) *Module {
 	}
 }
 
+// This module plus the given Union type
+func (r *Module) WithUnion(union *TypeDef) *Module {
+	assertNotNil("union", union)
+	q := r.query.Select("withUnion")
+	q = q.Arg("union", union)
+
+	return &Module{
+		query: q,
+	}
+}
+
 // The client generated for the module.
 type ModuleConfigClient struct {
 	query *querybuilder.Selection
@@ -10859,6 +12465,32 @@ func (r *Client) Error(message string) *Error {
 	}
 }
 
+// EvalOpts contains options for Client.Eval
+type EvalOpts struct {
+	// The number of attempts to run for each model
+	//
+	// Default: 1
+	Attempts int
+}
+
+// Initialize an evaluation of an agent
+//
+// Experimental: Evals are not yet stabilized
+func (r *Client) Eval(prompt string, opts ...EvalOpts) *Eval {
+	q := r.query.Select("eval")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `attempts` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Attempts) {
+			q = q.Arg("attempts", opts[i].Attempts)
+		}
+	}
+	q = q.Arg("prompt", prompt)
+
+	return &Eval{
+		query: q,
+	}
+}
+
 // FileOpts contains options for Client.File
 type FileOpts struct {
 	// Permissions of the new file. Example: 0600
@@ -11155,6 +12787,16 @@ func (r *Client) LoadDirectoryFromID(id DirectoryID) *Directory {
 	}
 }
 
+// Load a EngineCacheEntryConnection from its ID.
+func (r *Client) LoadEngineCacheEntryConnectionFromID(id EngineCacheEntryConnectionID) *EngineCacheEntryConnection {
+	q := r.query.Select("loadEngineCacheEntryConnectionFromID")
+	q = q.Arg("id", id)
+
+	return &EngineCacheEntryConnection{
+		query: q,
+	}
+}
+
 // Load a EngineCacheEntry from its ID.
 func (r *Client) LoadEngineCacheEntryFromID(id EngineCacheEntryID) *EngineCacheEntry {
 	q := r.query.Select("loadEngineCacheEntryFromID")
@@ -11185,6 +12827,16 @@ func (r *Client) LoadEngineCacheFromID(id EngineCacheID) *EngineCache {
 	}
 }
 
+// Load a EngineClient from its ID.
+func (r *Client) LoadEngineClientFromID(id EngineClientID) *EngineClient {
+	q := r.query.Select("loadEngineClientFromID")
+	q = q.Arg("id", id)
+
+	return &EngineClient{
+		query: q,
+	}
+}
+
 // Load a Engine from its ID.
 func (r *Client) LoadEngineFromID(id EngineID) *Engine {
 	q := r.query.Select("loadEngineFromID")
@@ -11265,6 +12917,36 @@ func (r *Client) LoadErrorValueFromID(id ErrorValueID) *ErrorValue {
 	}
 }
 
+// Load a Eval from its ID.
+func (r *Client) LoadEvalFromID(id EvalID) *Eval {
+	q := r.query.Select("loadEvalFromID")
+	q = q.Arg("id", id)
+
+	return &Eval{
+		query: q,
+	}
+}
+
+// Load a EvalModelReport from its ID.
+func (r *Client) LoadEvalModelReportFromID(id EvalModelReportID) *EvalModelReport {
+	q := r.query.Select("loadEvalModelReportFromID")
+	q = q.Arg("id", id)
+
+	return &EvalModelReport{
+		query: q,
+	}
+}
+
+// Load a EvalReport from its ID.
+func (r *Client) LoadEvalReportFromID(id EvalReportID) *EvalReport {
+	q := r.query.Select("loadEvalReportFromID")
+	q = q.Arg("id", id)
+
+	return &EvalReport{
+		query: q,
+	}
+}
+
 // Load a FieldTypeDef from its ID.
 func (r *Client) LoadFieldTypeDefFromID(id FieldTypeDefID) *FieldTypeDef {
 	q := r.query.Select("loadFieldTypeDefFromID")
@@ -11395,6 +13077,16 @@ func (r *Client) LoadJSONValueFromID(id JSONValueID) *JSONValue {
 	}
 }
 
+// Load a LLMEvent from its ID.
+func (r *Client) LoadLLMEventFromID(id LLMEventID) *LLMEvent {
+	q := r.query.Select("loadLLMEventFromID")
+	q = q.Arg("id", id)
+
+	return &LLMEvent{
+		query: q,
+	}
+}
+
 // Load a LLM from its ID.
 func (r *Client) LoadLLMFromID(id LLMID) *LLM {
 	q := r.query.Select("loadLLMFromID")
@@ -11435,6 +13127,26 @@ func (r *Client) LoadListTypeDefFromID(id ListTypeDefID) *ListTypeDef {
 	}
 }
 
+// Load a LogEntry from its ID.
+func (r *Client) LoadLogEntryFromID(id LogEntryID) *LogEntry {
+	q := r.query.Select("loadLogEntryFromID")
+	q = q.Arg("id", id)
+
+	return &LogEntry{
+		query: q,
+	}
+}
+
+// Load a MapTypeDef from its ID.
+func (r *Client) LoadMapTypeDefFromID(id MapTypeDefID) *MapTypeDef {
+	q := r.query.Select("loadMapTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &MapTypeDef{
+		query: q,
+	}
+}
+
 // Load a ModuleConfigClient from its ID.
 func (r *Client) LoadModuleConfigClientFromID(id ModuleConfigClientID) *ModuleConfigClient {
 	q := r.query.Select("loadModuleConfigClientFromID")
@@ -11575,6 +13287,16 @@ func (r *Client) LoadStatFromID(id StatID) *Stat {
 	}
 }
 
+// Load a StringConnection from its ID.
+func (r *Client) LoadStringConnectionFromID(id StringConnectionID) *StringConnection {
+	q := r.query.Select("loadStringConnectionFromID")
+	q = q.Arg("id", id)
+
+	return &StringConnection{
+		query: q,
+	}
+}
+
 // Load a Terminal from its ID.
 func (r *Client) LoadTerminalFromID(id TerminalID) *Terminal {
 	q := r.query.Select("loadTerminalFromID")
@@ -11595,6 +13317,16 @@ func (r *Client) LoadTypeDefFromID(id TypeDefID) *TypeDef {
 	}
 }
 
+// Load a UnionTypeDef from its ID.
+func (r *Client) LoadUnionTypeDefFromID(id UnionTypeDefID) *UnionTypeDef {
+	q := r.query.Select("loadUnionTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &UnionTypeDef{
+		query: q,
+	}
+}
+
 // Create a new module.
 func (r *Client) Module() *Module {
 	q := r.query.Select("module")
@@ -12739,25 +14471,132 @@ func (r *Stat) Name(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
-// permission bits
-func (r *Stat) Permissions(ctx context.Context) (int, error) {
-	if r.permissions != nil {
-		return *r.permissions, nil
+// permission bits
+func (r *Stat) Permissions(ctx context.Context) (int, error) {
+	if r.permissions != nil {
+		return *r.permissions, nil
+	}
+	q := r.query.Select("permissions")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// file size
+func (r *Stat) Size(ctx context.Context) (int, error) {
+	if r.size != nil {
+		return *r.size, nil
+	}
+	q := r.query.Select("size")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A page of a list of String, selected by a cursor.
+type StringConnection struct {
+	query *querybuilder.Selection
+
+	endCursor   *string
+	hasNextPage *bool
+	id          *StringConnectionID
+	totalCount  *int
+}
+
+func (r *StringConnection) WithGraphQLQuery(q *querybuilder.Selection) *StringConnection {
+	return &StringConnection{
+		query: q,
+	}
+}
+
+// The cursor of the last element of the page, to pass as "after" to select the next page.
+//
+// Null if the page is empty.
+func (r *StringConnection) EndCursor(ctx context.Context) (string, error) {
+	if r.endCursor != nil {
+		return *r.endCursor, nil
+	}
+	q := r.query.Select("endCursor")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Whether there are elements after this page.
+func (r *StringConnection) HasNextPage(ctx context.Context) (bool, error) {
+	if r.hasNextPage != nil {
+		return *r.hasNextPage, nil
+	}
+	q := r.query.Select("hasNextPage")
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this StringConnection.
+func (r *StringConnection) ID(ctx context.Context) (StringConnectionID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response StringConnectionID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *StringConnection) XXX_GraphQLType() string {
+	return "StringConnection"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *StringConnection) XXX_GraphQLIDType() string {
+	return "StringConnectionID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *StringConnection) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *StringConnection) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
 	}
-	q := r.query.Select("permissions")
+	return json.Marshal(id)
+}
 
-	var response int
+// The elements of the page.
+func (r *StringConnection) Nodes(ctx context.Context) ([]string, error) {
+	q := r.query.Select("nodes")
+
+	var response []string
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx)
 }
 
-// file size
-func (r *Stat) Size(ctx context.Context) (int, error) {
-	if r.size != nil {
-		return *r.size, nil
+// The number of elements in the whole list.
+func (r *StringConnection) TotalCount(ctx context.Context) (int, error) {
+	if r.totalCount != nil {
+		return *r.totalCount, nil
 	}
-	q := r.query.Select("size")
+	q := r.query.Select("totalCount")
 
 	var response int
 
@@ -12893,6 +14732,15 @@ func (r *TypeDef) AsList() *ListTypeDef {
 	}
 }
 
+// If kind is MAP, the map-specific type definition. If kind is not MAP, this will be null.
+func (r *TypeDef) AsMap() *MapTypeDef {
+	q := r.query.Select("asMap")
+
+	return &MapTypeDef{
+		query: q,
+	}
+}
+
 // If kind is OBJECT, the object-specific type definition. If kind is not OBJECT, this will be null.
 func (r *TypeDef) AsObject() *ObjectTypeDef {
 	q := r.query.Select("asObject")
@@ -12911,6 +14759,15 @@ func (r *TypeDef) AsScalar() *ScalarTypeDef {
 	}
 }
 
+// If kind is UNION, the union-specific type definition. If kind is not UNION, this will be null.
+func (r *TypeDef) AsUnion() *UnionTypeDef {
+	q := r.query.Select("asUnion")
+
+	return &UnionTypeDef{
+		query: q,
+	}
+}
+
 // A unique identifier for this TypeDef.
 func (r *TypeDef) ID(ctx context.Context) (TypeDefID, error) {
 	if r.id != nil {
@@ -13189,6 +15046,19 @@ func (r *TypeDef) WithListOf(elementType *TypeDef) *TypeDef {
 	}
 }
 
+// Returns a TypeDef of kind Map with string keys and the provided type for its values.
+//
+// Maps are represented as JSON objects, so their values can't be objects, interfaces or unions.
+func (r *TypeDef) WithMapOf(valueType *TypeDef) *TypeDef {
+	assertNotNil("valueType", valueType)
+	q := r.query.Select("withMapOf")
+	q = q.Arg("valueType", valueType)
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
 // TypeDefWithObjectOpts contains options for TypeDef.WithObject
 type TypeDefWithObjectOpts struct {
 	Description string
@@ -13255,6 +15125,184 @@ func (r *TypeDef) WithScalar(name string, opts ...TypeDefWithScalarOpts) *TypeDe
 	}
 }
 
+// TypeDefWithUnionOpts contains options for TypeDef.WithUnion
+type TypeDefWithUnionOpts struct {
+	// A doc string for the union, if any
+	Description string
+	// The source map for the union definition.
+	SourceMap *SourceMap
+}
+
+// Returns a TypeDef of kind Union with the provided name.
+//
+// Note that a union's members may be omitted if the intent is only to refer to a union. This is how functions are able to return their own, or any other circular reference.
+func (r *TypeDef) WithUnion(name string, opts ...TypeDefWithUnionOpts) *TypeDef {
+	q := r.query.Select("withUnion")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `description` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Description) {
+			q = q.Arg("description", opts[i].Description)
+		}
+		// `sourceMap` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SourceMap) {
+			q = q.Arg("sourceMap", opts[i].SourceMap)
+		}
+	}
+	q = q.Arg("name", name)
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// Adds a member type to a Union TypeDef, failing if the type is not a union or the member is not an object.
+func (r *TypeDef) WithUnionMember(member *TypeDef) *TypeDef {
+	assertNotNil("member", member)
+	q := r.query.Select("withUnionMember")
+	q = q.Arg("member", member)
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// A definition of a custom union of object types defined in a Module.
+type UnionTypeDef struct {
+	query *querybuilder.Selection
+
+	description      *string
+	id               *UnionTypeDefID
+	name             *string
+	sourceModuleName *string
+}
+
+func (r *UnionTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *UnionTypeDef {
+	return &UnionTypeDef{
+		query: q,
+	}
+}
+
+// A doc string for the union, if any.
+func (r *UnionTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.query.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this UnionTypeDef.
+func (r *UnionTypeDef) ID(ctx context.Context) (UnionTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response UnionTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *UnionTypeDef) XXX_GraphQLType() string {
+	return "UnionTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *UnionTypeDef) XXX_GraphQLIDType() string {
+	return "UnionTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *UnionTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *UnionTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The object types that a value of the union can be.
+func (r *UnionTypeDef) Members(ctx context.Context) ([]TypeDef, error) {
+	q := r.query.Select("members")
+
+	q = q.Select("id")
+
+	type members struct {
+		Id TypeDefID
+	}
+
+	convert := func(fields []members) []TypeDef {
+		out := []TypeDef{}
+
+		for i := range fields {
+			val := TypeDef{id: &fields[i].Id}
+			val.query = q.Root().Select("loadTypeDefFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []members
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// The name of the union.
+func (r *UnionTypeDef) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The location of this union declaration.
+func (r *UnionTypeDef) SourceMap() *SourceMap {
+	q := r.query.Select("sourceMap")
+
+	return &SourceMap{
+		query: q,
+	}
+}
+
+// If this UnionTypeDef is associated with a Module, the name of the module. Unset otherwise.
+func (r *UnionTypeDef) SourceModuleName(ctx context.Context) (string, error) {
+	if r.sourceModuleName != nil {
+		return *r.sourceModuleName, nil
+	}
+	q := r.query.Select("sourceModuleName")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Sharing mode of the cache volume.
 type CacheSharingMode string
 
@@ -13658,6 +15706,118 @@ const (
 	ImageMediaTypesDocker           ImageMediaTypes = ImageMediaTypesDockerMediaTypes
 )
 
+// Strategy for compacting an LLM's context once it exceeds its token budget.
+type LLMContextStrategy string
+
+func (LLMContextStrategy) IsEnum() {}
+
+func (v LLMContextStrategy) Name() string {
+	switch v {
+	case LLMContextStrategySummarize:
+		return "SUMMARIZE"
+	case LLMContextStrategyTruncateToolOutputs:
+		return "TRUNCATE_TOOL_OUTPUTS"
+	default:
+		return ""
+	}
+}
+
+func (v LLMContextStrategy) Value() string {
+	return string(v)
+}
+
+func (v *LLMContextStrategy) MarshalJSON() ([]byte, error) {
+	if *v == "" {
+		return []byte(`""`), nil
+	}
+	name := v.Name()
+	if name == "" {
+		return nil, fmt.Errorf("invalid enum value %q", *v)
+	}
+	return json.Marshal(name)
+}
+
+func (v *LLMContextStrategy) UnmarshalJSON(dt []byte) error {
+	var s string
+	if err := json.Unmarshal(dt, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "":
+		*v = ""
+	case "SUMMARIZE":
+		*v = LLMContextStrategySummarize
+	case "TRUNCATE_TOOL_OUTPUTS":
+		*v = LLMContextStrategyTruncateToolOutputs
+	default:
+		return fmt.Errorf("invalid enum value %q", s)
+	}
+	return nil
+}
+
+const (
+	// Replace older messages with a summary written by the model
+	LLMContextStrategySummarize LLMContextStrategy = "SUMMARIZE"
+
+	// Truncate the outputs of older tool calls
+	LLMContextStrategyTruncateToolOutputs LLMContextStrategy = "TRUNCATE_TOOL_OUTPUTS"
+)
+
+// The standard stream that a process wrote output to.
+type LogStream string
+
+func (LogStream) IsEnum() {}
+
+func (v LogStream) Name() string {
+	switch v {
+	case LogStreamStdout:
+		return "STDOUT"
+	case LogStreamStderr:
+		return "STDERR"
+	default:
+		return ""
+	}
+}
+
+func (v LogStream) Value() string {
+	return string(v)
+}
+
+func (v *LogStream) MarshalJSON() ([]byte, error) {
+	if *v == "" {
+		return []byte(`""`), nil
+	}
+	name := v.Name()
+	if name == "" {
+		return nil, fmt.Errorf("invalid enum value %q", *v)
+	}
+	return json.Marshal(name)
+}
+
+func (v *LogStream) UnmarshalJSON(dt []byte) error {
+	var s string
+	if err := json.Unmarshal(dt, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "":
+		*v = ""
+	case "STDERR":
+		*v = LogStreamStderr
+	case "STDOUT":
+		*v = LogStreamStdout
+	default:
+		return fmt.Errorf("invalid enum value %q", s)
+	}
+	return nil
+}
+
+const (
+	LogStreamStdout LogStream = "STDOUT"
+
+	LogStreamStderr LogStream = "STDERR"
+)
+
 // Experimental features of a module
 type ModuleSourceExperimentalFeature string
 
@@ -13926,6 +16086,10 @@ func (v TypeDefKind) Name() string {
 		return "VOID_KIND"
 	case TypeDefKindEnumKind:
 		return "ENUM_KIND"
+	case TypeDefKindMapKind:
+		return "MAP_KIND"
+	case TypeDefKindUnionKind:
+		return "UNION_KIND"
 	default:
 		return ""
 	}
@@ -13982,6 +16146,8 @@ func (v *TypeDefKind) UnmarshalJSON(dt []byte) error {
 		*v = TypeDefKindList
 	case "LIST_KIND":
 		*v = TypeDefKindListKind
+	case "MAP_KIND":
+		*v = TypeDefKindMapKind
 	case "OBJECT":
 		*v = TypeDefKindObject
 	case "OBJECT_KIND":
@@ -13994,6 +16160,8 @@ func (v *TypeDefKind) UnmarshalJSON(dt []byte) error {
 		*v = TypeDefKindString
 	case "STRING_KIND":
 		*v = TypeDefKindStringKind
+	case "UNION_KIND":
+		*v = TypeDefKindUnionKind
 	case "VOID":
 		*v = TypeDefKindVoid
 	case "VOID_KIND":
@@ -14079,4 +16247,14 @@ const (
 	//
 	// Always paired with an EnumTypeDef.
 	TypeDefKindEnum TypeDefKind = TypeDefKindEnumKind
+
+	// A map of string keys to values all having the same type.
+	//
+	// Always paired with a MapTypeDef.
+	TypeDefKindMapKind TypeDefKind = "MAP_KIND"
+
+	// A named type whose values can be any one of a set of object types.
+	//
+	// Always paired with a UnionTypeDef.
+	TypeDefKindUnionKind TypeDefKind = "UNION_KIND"
 )
@@ -6,7 +6,7 @@ import (
 
 // These are exported so that they can be used by codegen.
 
-//go:embed querybuilder/marshal.go querybuilder/querybuilder.go
+//go:embed querybuilder/marshal.go querybuilder/querybuilder.go querybuilder/persisted.go querybuilder/mock.go
 var QueryBuilder embed.FS
 
 //go:embed telemetry/*.go
@@ -21,7 +21,7 @@ var GoMod []byte
 //go:embed go.sum
 var GoSum []byte
 
-//go:embed engineconn/*.go querybuilder/marshal.go querybuilder/querybuilder.go go.mod go.sum client.go dagger.gen.go telemetry/*.go
+//go:embed engineconn/*.go querybuilder/marshal.go querybuilder/querybuilder.go querybuilder/persisted.go querybuilder/mock.go go.mod go.sum client.go dagger.gen.go telemetry/*.go
 var GoSDK embed.FS
 
 //go:embed dagger.gen.go
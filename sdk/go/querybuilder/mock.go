@@ -0,0 +1,256 @@
+package querybuilder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Mock is a graphql.Client answering queries with stubbed results instead of
+// sending them to the engine, so code using the API can be tested without one.
+//
+// Calls are identified by their chain of fields, displayed like call IDs,
+// e.g. `container.from(address: "alpine").withExec(args: ["echo","hi"]).stdout`.
+// Arguments are displayed in alphabetical order, and objects passed as
+// arguments are displayed as `{<chain>}`.
+//
+// Objects are identified by the chain that selected them: selecting the ID of
+// an object returns its chain, and loading an object from such an ID resumes
+// the chain, so objects passed around keep their history.
+type Mock struct {
+	mu    sync.Mutex
+	stubs map[string]mockStub
+	ids   map[string]struct{}
+	calls []string
+}
+
+var _ graphql.Client = (*Mock)(nil)
+
+type mockStub struct {
+	value any
+	err   error
+}
+
+// NewMock returns a Mock without any stub.
+func NewMock() *Mock {
+	return &Mock{
+		stubs: map[string]mockStub{},
+		ids:   map[string]struct{}{},
+	}
+}
+
+// Stub sets the result of the call with the given chain.
+//
+// Lists of objects are stubbed with a slice of the same length; their
+// elements are then selected through the `<chain>#<n>` chain, starting at 1.
+func (m *Mock) Stub(chain string, value any) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs[chain] = mockStub{value: value}
+	return m
+}
+
+// StubError sets the error returned by the call with the given chain.
+func (m *Mock) StubError(chain string, err error) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs[chain] = mockStub{err: err}
+	return m
+}
+
+// Chain returns the chain of an object selected through the mock, to stub its
+// fields with the same arguments as the code under test.
+func (m *Mock) Chain(ctx context.Context, obj GraphQLMarshaller) (string, error) {
+	return obj.XXX_GraphQLID(ctx)
+}
+
+// Calls returns the chains of the calls made so far, in order.
+//
+// Selections of IDs aren't recorded, since they are only used to pass
+// objects around.
+func (m *Mock) Calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.calls)
+}
+
+// Reset removes all stubs and recorded calls.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs = map[string]mockStub{}
+	m.ids = map[string]struct{}{}
+	m.calls = nil
+}
+
+func (m *Mock) MakeRequest(_ context.Context, req *graphql.Request, resp *graphql.Response) error {
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+	if err != nil {
+		return err
+	}
+	if len(doc.Operations) != 1 {
+		return fmt.Errorf("expected a single operation, got %d", len(doc.Operations))
+	}
+
+	m.mu.Lock()
+	data, err := m.resolve("", doc.Operations[0].SelectionSet)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// round-trip through JSON, like responses from the engine
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, resp.Data)
+}
+
+// resolve builds the result of the given selections on the object selected by
+// the given chain.
+func (m *Mock) resolve(chain string, sels ast.SelectionSet) (map[string]any, error) {
+	res := map[string]any{}
+	for _, sel := range sels {
+		field, ok := sel.(*ast.Field)
+		if !ok {
+			return nil, fmt.Errorf("unsupported selection %T", sel)
+		}
+		key := field.Alias
+		if key == "" {
+			key = field.Name
+		}
+		val, err := m.resolveField(chain, field)
+		if err != nil {
+			return nil, err
+		}
+		res[key] = val
+	}
+	return res, nil
+}
+
+func (m *Mock) resolveField(chain string, field *ast.Field) (any, error) {
+	fieldChain := m.fieldChain(chain, field)
+	stub, stubbed := m.stubs[fieldChain]
+
+	if len(field.SelectionSet) == 0 {
+		if field.Name != "id" {
+			m.calls = append(m.calls, fieldChain)
+		}
+		if stubbed {
+			return stub.value, stub.err
+		}
+		switch field.Name {
+		case "id", "sync":
+			// objects are identified by their chain
+			m.ids[chain] = struct{}{}
+			return chain, nil
+		}
+		return nil, fmt.Errorf("no stub for %s", fieldChain)
+	}
+
+	if !stubbed {
+		return m.resolve(fieldChain, field.SelectionSet)
+	}
+	if stub.err != nil {
+		m.calls = append(m.calls, fieldChain)
+		return nil, stub.err
+	}
+	list := reflect.ValueOf(stub.value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil, fmt.Errorf("stub for %s must be a list of objects, got %T", fieldChain, stub.value)
+	}
+	elems := make([]any, list.Len())
+	for i := range elems {
+		elem, err := m.resolve(fmt.Sprintf("%s#%d", fieldChain, i+1), field.SelectionSet)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = elem
+	}
+	return elems, nil
+}
+
+// fieldChain returns the chain selecting the given field.
+func (m *Mock) fieldChain(chain string, field *ast.Field) string {
+	if chain == "" && isLoadFromID(field) {
+		// resume the chain of objects loaded from mock IDs
+		if id := field.Arguments.ForName("id"); id.Value.Kind == ast.StringValue {
+			if _, ok := m.ids[id.Value.Raw]; ok {
+				return id.Value.Raw
+			}
+		}
+	}
+
+	var b strings.Builder
+	if chain != "" {
+		b.WriteString(chain)
+		b.WriteRune('.')
+	}
+	b.WriteString(field.Name)
+
+	args := slices.Clone(field.Arguments)
+	slices.SortFunc(args, func(a, b *ast.Argument) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i, arg := range args {
+		if i == 0 {
+			b.WriteRune('(')
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(arg.Name)
+		b.WriteString(": ")
+		b.WriteString(m.displayValue(arg.Value))
+		if i == len(args)-1 {
+			b.WriteRune(')')
+		}
+	}
+	return b.String()
+}
+
+func isLoadFromID(field *ast.Field) bool {
+	return strings.HasPrefix(field.Name, "load") &&
+		strings.HasSuffix(field.Name, "FromID") &&
+		len(field.Arguments) == 1 &&
+		field.Arguments.ForName("id") != nil
+}
+
+// displayValue displays an argument value like call IDs do.
+func (m *Mock) displayValue(val *ast.Value) string {
+	switch val.Kind {
+	case ast.StringValue, ast.BlockValue:
+		if _, ok := m.ids[val.Raw]; ok {
+			return "{" + val.Raw + "}"
+		}
+		return strconv.Quote(val.Raw)
+	case ast.FloatValue:
+		if f, err := strconv.ParseFloat(val.Raw, 64); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return val.Raw
+	case ast.ListValue:
+		elems := make([]string, len(val.Children))
+		for i, child := range val.Children {
+			elems[i] = m.displayValue(child.Value)
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+	case ast.ObjectValue:
+		fields := make([]string, len(val.Children))
+		for i, child := range val.Children {
+			fields[i] = child.Name + ": " + m.displayValue(child.Value)
+		}
+		return "{" + strings.Join(fields, ",") + "}"
+	default:
+		return val.Raw
+	}
+}
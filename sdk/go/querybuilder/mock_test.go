@@ -0,0 +1,179 @@
+package querybuilder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMock(t *testing.T) {
+	ctx := context.Background()
+
+	mock := NewMock().
+		Stub(`container.from(address: "alpine").withExec(args: ["echo","hi"]).stdout`, "hi\n").
+		Stub(`container.from(address: "alpine").withExec(args: ["echo","hi"]).exitCode`, 0)
+	ctr := Query().Client(mock).
+		Select("container").
+		Select("from").Arg("address", "alpine").
+		Select("withExec").Arg("args", []string{"echo", "hi"})
+
+	var stdout string
+	require.NoError(t, ctr.Select("stdout").Bind(&stdout).Execute(ctx))
+	require.Equal(t, "hi\n", stdout)
+
+	var exitCode int
+	require.NoError(t, ctr.Select("exitCode").Bind(&exitCode).Execute(ctx))
+	require.Equal(t, 0, exitCode)
+
+	var stderr string
+	err := ctr.Select("stderr").Bind(&stderr).Execute(ctx)
+	require.ErrorContains(t, err, `no stub for container.from(address: "alpine").withExec(args: ["echo","hi"]).stderr`)
+
+	require.Equal(t, []string{
+		`container.from(address: "alpine").withExec(args: ["echo","hi"]).stdout`,
+		`container.from(address: "alpine").withExec(args: ["echo","hi"]).exitCode`,
+		`container.from(address: "alpine").withExec(args: ["echo","hi"]).stderr`,
+	}, mock.Calls())
+}
+
+func TestMockArgs(t *testing.T) {
+	ctx := context.Background()
+
+	mock := NewMock().
+		Stub(`a(float: 1.5, int: 2, list: [true,false], obj: {name: "x"}, str: "s").b`, "ok")
+
+	var res string
+	err := Query().Client(mock).
+		Select("a").
+		Arg("str", "s").
+		Arg("int", 2).
+		Arg("float", 1.5).
+		Arg("list", []bool{true, false}).
+		Arg("obj", struct {
+			Name string `json:"name"`
+		}{"x"}).
+		Select("b").Bind(&res).Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "ok", res)
+}
+
+func TestMockIDs(t *testing.T) {
+	ctx := context.Background()
+
+	mock := NewMock().
+		Stub(`container.withDirectory(directory: {directory.withNewFile(contents: "hi", path: "a")}, path: "/src").stdout`, "hi")
+	q := Query().Client(mock)
+
+	var dirID string
+	err := q.Select("directory").
+		Select("withNewFile").Arg("path", "a").Arg("contents", "hi").
+		Select("id").Bind(&dirID).Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, `directory.withNewFile(contents: "hi", path: "a")`, dirID)
+
+	var ctrID string
+	err = q.Select("container").
+		Select("withDirectory").Arg("path", "/src").Arg("directory", dirID).
+		Select("sync").Bind(&ctrID).Execute(ctx)
+	require.NoError(t, err)
+
+	var stdout string
+	err = q.Select("loadContainerFromID").Arg("id", ctrID).
+		Select("stdout").Bind(&stdout).Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hi", stdout)
+
+	require.Equal(t, []string{
+		`container.withDirectory(directory: {directory.withNewFile(contents: "hi", path: "a")}, path: "/src").sync`,
+		`container.withDirectory(directory: {directory.withNewFile(contents: "hi", path: "a")}, path: "/src").stdout`,
+	}, mock.Calls())
+}
+
+func TestMockLists(t *testing.T) {
+	ctx := context.Background()
+
+	mock := NewMock().
+		Stub(`container.envVariables`, make([]any, 2)).
+		Stub(`container.envVariables#2.name`, "FOO")
+	q := Query().Client(mock)
+
+	var vars []struct {
+		ID string `json:"id"`
+	}
+	err := q.Select("container").Select("envVariables").Select("id").Bind(&vars).Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, vars, 2)
+	require.Equal(t, "container.envVariables#1", vars[0].ID)
+	require.Equal(t, "container.envVariables#2", vars[1].ID)
+
+	var name string
+	err = q.Select("loadEnvVariableFromID").Arg("id", vars[1].ID).
+		Select("name").Bind(&name).Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "FOO", name)
+}
+
+func TestMockErrors(t *testing.T) {
+	ctx := context.Background()
+
+	boom := errors.New("boom")
+	mock := NewMock().StubError(`container.sync`, boom)
+
+	var id string
+	err := Query().Client(mock).Select("container").Select("sync").Bind(&id).Execute(ctx)
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, []string{`container.sync`}, mock.Calls())
+
+	mock.Reset()
+	require.Empty(t, mock.Calls())
+}
+
+// mockObject is an object selected through a Mock, like SDK objects.
+type mockObject struct {
+	query *Selection
+}
+
+func (o mockObject) XXX_GraphQLType() string   { return "Container" }
+func (o mockObject) XXX_GraphQLIDType() string { return "ContainerID" }
+func (o mockObject) XXX_GraphQLID(ctx context.Context) (string, error) {
+	var id string
+	err := o.query.Select("id").Bind(&id).Execute(ctx)
+	return id, err
+}
+func (o mockObject) MarshalJSON() ([]byte, error) {
+	id, err := o.XXX_GraphQLID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+func TestMockChain(t *testing.T) {
+	ctx := context.Background()
+
+	mock := NewMock()
+	ctr := mockObject{
+		query: Query().Client(mock).Select("container").Select("withEnvVariable").Arg("name", "A").Arg("value", "b"),
+	}
+
+	chain, err := mock.Chain(ctx, ctr)
+	require.NoError(t, err)
+	require.Equal(t, `container.withEnvVariable(name: "A", value: "b")`, chain)
+	mock.Stub(chain+".stdout", "b")
+
+	var stdout string
+	require.NoError(t, ctr.query.Select("stdout").Bind(&stdout).Execute(ctx))
+	require.Equal(t, "b", stdout)
+
+	// objects passed as arguments are displayed as their chain
+	var out string
+	mock.Stub(`container.withMountedCache(cache: {container.withEnvVariable(name: "A", value: "b")}, path: "/cache").stdout`, "cached")
+	err = Query().Client(mock).Select("container").
+		Select("withMountedCache").Arg("path", "/cache").Arg("cache", ctr).
+		Select("stdout").Bind(&out).Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "cached", out)
+}
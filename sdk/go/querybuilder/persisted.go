@@ -0,0 +1,140 @@
+package querybuilder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Khan/genqlient/graphql"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// persistedQueriesSize is the number of hashes of persisted queries
+// remembered, same as the number of queries the engine keeps.
+const persistedQueriesSize = 10000
+
+// PersistedQueries wraps a graphql.Doer to only send the hash of queries the
+// engine has already seen, instead of their full text, following the automatic
+// persisted queries protocol.
+//
+// If the engine doesn't know the hash (e.g. because it was restarted), the
+// request is sent again with the full query.
+func PersistedQueries(doer graphql.Doer) graphql.Doer {
+	persisted, err := lru.New[string, struct{}](persistedQueriesSize)
+	if err != nil {
+		// only returned for non-positive sizes
+		panic(err)
+	}
+	return &persistedQueriesDoer{Doer: doer, persisted: persisted}
+}
+
+type persistedQueriesDoer struct {
+	graphql.Doer
+
+	// persisted is the set of hashes of queries recently sent to the engine.
+	persisted *lru.Cache[string, struct{}]
+}
+
+// persistedQueryNotFound is the error returned by the engine when it doesn't
+// know the hash of a query.
+const persistedQueryNotFound = "PersistedQueryNotFound"
+
+type persistedQueryRequest struct {
+	Query      string          `json:"query,omitempty"`
+	Variables  json.RawMessage `json:"variables,omitempty"`
+	OpName     string          `json:"operationName,omitempty"`
+	Extensions map[string]any  `json:"extensions,omitempty"`
+}
+
+func (d *persistedQueriesDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || req.Body == nil {
+		return d.Doer.Do(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var params persistedQueryRequest
+	if err := json.Unmarshal(body, &params); err != nil || params.Query == "" || params.Extensions != nil {
+		// not something we know how to persist; send it as-is
+		return d.send(req, body)
+	}
+
+	sum := sha256.Sum256([]byte(params.Query))
+	hash := hex.EncodeToString(sum[:])
+	params.Extensions = map[string]any{
+		"persistedQuery": map[string]any{
+			"version":    1,
+			"sha256Hash": hash,
+		},
+	}
+
+	if _, ok := d.persisted.Get(hash); ok {
+		query := params.Query
+		params.Query = ""
+		resp, err := d.sendParams(req, params)
+		if err != nil {
+			return nil, err
+		}
+		notFound, err := isPersistedQueryNotFound(resp)
+		if err != nil || !notFound {
+			return resp, err
+		}
+		d.persisted.Remove(hash)
+		params.Query = query
+	}
+
+	resp, err := d.sendParams(req, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		d.persisted.Add(hash, struct{}{})
+	}
+	return resp, nil
+}
+
+func (d *persistedQueriesDoer) sendParams(req *http.Request, params persistedQueryRequest) (*http.Response, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return d.send(req, body)
+}
+
+func (d *persistedQueriesDoer) send(req *http.Request, body []byte) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return d.Doer.Do(req)
+}
+
+// isPersistedQueryNotFound checks if the response is the error returned for
+// unknown hashes, leaving the response body readable.
+func isPersistedQueryNotFound(resp *http.Response) (bool, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var res struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return false, nil
+	}
+	for _, e := range res.Errors {
+		if e.Message == persistedQueryNotFound {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,112 @@
+package querybuilder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePersistedQueriesEngine implements the automatic persisted queries
+// protocol, recording the requests it receives.
+type fakePersistedQueriesEngine struct {
+	persisted map[string]string
+	requests  []persistedQueryRequest
+}
+
+func (e *fakePersistedQueriesEngine) Do(req *http.Request) (*http.Response, error) {
+	var params persistedQueryRequest
+	if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+		return nil, err
+	}
+	e.requests = append(e.requests, params)
+
+	rec := httptest.NewRecorder()
+	hash := params.Extensions["persistedQuery"].(map[string]any)["sha256Hash"].(string)
+	query := params.Query
+	if query == "" {
+		var ok bool
+		query, ok = e.persisted[hash]
+		if !ok {
+			rec.WriteHeader(http.StatusOK)
+			rec.WriteString(`{"errors":[{"message":"PersistedQueryNotFound"}]}`)
+			return rec.Result(), nil
+		}
+	} else {
+		e.persisted[hash] = query
+	}
+	rec.WriteHeader(http.StatusOK)
+	json.NewEncoder(rec).Encode(map[string]any{
+		"data": map[string]any{"query": query},
+	})
+	return rec.Result(), nil
+}
+
+func TestPersistedQueries(t *testing.T) {
+	ctx := context.Background()
+
+	engine := &fakePersistedQueriesEngine{persisted: map[string]string{}}
+	client := graphql.NewClient("http://dagger/query", PersistedQueries(engine))
+
+	query := "query{container{id}}"
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	do := func() string {
+		t.Helper()
+		var data struct {
+			Query string `json:"query"`
+		}
+		err := client.MakeRequest(ctx, &graphql.Request{Query: query}, &graphql.Response{Data: &data})
+		require.NoError(t, err)
+		return data.Query
+	}
+
+	// the first request sends the full query along with its hash
+	require.Equal(t, query, do())
+	require.Len(t, engine.requests, 1)
+	require.Equal(t, query, engine.requests[0].Query)
+	require.Equal(t, query, engine.persisted[hash])
+
+	// later requests only send the hash
+	require.Equal(t, query, do())
+	require.Len(t, engine.requests, 2)
+	require.Empty(t, engine.requests[1].Query)
+
+	// the full query is sent again if the engine forgot it
+	delete(engine.persisted, hash)
+	require.Equal(t, query, do())
+	require.Len(t, engine.requests, 4)
+	require.Empty(t, engine.requests[2].Query)
+	require.Equal(t, query, engine.requests[3].Query)
+}
+
+func TestPersistedQueriesBounded(t *testing.T) {
+	ctx := context.Background()
+
+	engine := &fakePersistedQueriesEngine{persisted: map[string]string{}}
+	doer := PersistedQueries(engine)
+	client := graphql.NewClient("http://dagger/query", doer)
+
+	do := func(query string) {
+		t.Helper()
+		err := client.MakeRequest(ctx, &graphql.Request{Query: query}, &graphql.Response{Data: &struct{}{}})
+		require.NoError(t, err)
+	}
+
+	for i := range persistedQueriesSize + 10 {
+		do(fmt.Sprintf("query{q%d:container{id}}", i))
+	}
+	require.Equal(t, persistedQueriesSize, doer.(*persistedQueriesDoer).persisted.Len())
+
+	// the oldest queries were forgotten, so they're sent in full again
+	do("query{q0:container{id}}")
+	require.Equal(t, "query{q0:container{id}}", engine.requests[len(engine.requests)-1].Query)
+}
@@ -32,6 +32,12 @@ const (
 	// recognizable value to the user.
 	DagOutputAttr = "dagger.io/dag.output"
 
+	// Why the call missed the cache, e.g. which of its inputs changed since the
+	// last time it was called.
+	//
+	// This is only set when the client asked for cache misses to be explained.
+	DagCacheMissReasonAttr = "dagger.io/dag.cache_miss_reason"
+
 	// Indicates that this span is "internal" and can be hidden by default.
 	//
 	// Internal spans may typically be revealed with a toggle.
@@ -61,6 +61,11 @@ class DirectoryID(Scalar):
     object of type Directory."""
 
 
+class EngineCacheEntryConnectionID(Scalar):
+    """The `EngineCacheEntryConnectionID` scalar type represents an
+    identifier for an object of type EngineCacheEntryConnection."""
+
+
 class EngineCacheEntryID(Scalar):
     """The `EngineCacheEntryID` scalar type represents an identifier for
     an object of type EngineCacheEntry."""
@@ -76,6 +81,11 @@ class EngineCacheID(Scalar):
     object of type EngineCache."""
 
 
+class EngineClientID(Scalar):
+    """The `EngineClientID` scalar type represents an identifier for an
+    object of type EngineClient."""
+
+
 class EngineID(Scalar):
     """The `EngineID` scalar type represents an identifier for an object
     of type Engine."""
@@ -116,6 +126,21 @@ class ErrorValueID(Scalar):
     object of type ErrorValue."""
 
 
+class EvalID(Scalar):
+    """The `EvalID` scalar type represents an identifier for an object of
+    type Eval."""
+
+
+class EvalModelReportID(Scalar):
+    """The `EvalModelReportID` scalar type represents an identifier for an
+    object of type EvalModelReport."""
+
+
+class EvalReportID(Scalar):
+    """The `EvalReportID` scalar type represents an identifier for an
+    object of type EvalReport."""
+
+
 class FieldTypeDefID(Scalar):
     """The `FieldTypeDefID` scalar type represents an identifier for an
     object of type FieldTypeDef."""
@@ -185,6 +210,11 @@ class JSONValueID(Scalar):
     object of type JSONValue."""
 
 
+class LLMEventID(Scalar):
+    """The `LLMEventID` scalar type represents an identifier for an object
+    of type LLMEvent."""
+
+
 class LLMID(Scalar):
     """The `LLMID` scalar type represents an identifier for an object of
     type LLM."""
@@ -205,6 +235,16 @@ class ListTypeDefID(Scalar):
     object of type ListTypeDef."""
 
 
+class LogEntryID(Scalar):
+    """The `LogEntryID` scalar type represents an identifier for an object
+    of type LogEntry."""
+
+
+class MapTypeDefID(Scalar):
+    """The `MapTypeDefID` scalar type represents an identifier for an
+    object of type MapTypeDef."""
+
+
 class ModuleConfigClientID(Scalar):
     """The `ModuleConfigClientID` scalar type represents an identifier for
     an object of type ModuleConfigClient."""
@@ -281,6 +321,11 @@ class StatID(Scalar):
     type Stat."""
 
 
+class StringConnectionID(Scalar):
+    """The `StringConnectionID` scalar type represents an identifier for
+    an object of type StringConnection."""
+
+
 class TerminalID(Scalar):
     """The `TerminalID` scalar type represents an identifier for an object
     of type Terminal."""
@@ -291,6 +336,11 @@ class TypeDefID(Scalar):
     of type TypeDef."""
 
 
+class UnionTypeDefID(Scalar):
+    """The `UnionTypeDefID` scalar type represents an identifier for an
+    object of type UnionTypeDef."""
+
+
 class Void(Scalar):
     """The absence of a value.  A Null Void is used as a placeholder for
     resolvers that do not return anything."""
@@ -380,6 +430,25 @@ class ImageMediaTypes(Enum):
     OCI = "OCIMediaTypes"
 
 
+class LLMContextStrategy(Enum):
+    """Strategy for compacting an LLM's context once it exceeds its token
+    budget."""
+
+    SUMMARIZE = "SUMMARIZE"
+    """Replace older messages with a summary written by the model"""
+
+    TRUNCATE_TOOL_OUTPUTS = "TRUNCATE_TOOL_OUTPUTS"
+    """Truncate the outputs of older tool calls"""
+
+
+class LogStream(Enum):
+    """The standard stream that a process wrote output to."""
+
+    STDERR = "STDERR"
+
+    STDOUT = "STDOUT"
+
+
 class ModuleSourceExperimentalFeature(Enum):
     """Experimental features of a module"""
 
@@ -477,6 +546,12 @@ class TypeDefKind(Enum):
     A list of values all having the same type.
     """
 
+    MAP_KIND = "MAP_KIND"
+    """A map of string keys to values all having the same type.
+
+    Always paired with a MapTypeDef.
+    """
+
     OBJECT_KIND = "OBJECT_KIND"
     """Always paired with an ObjectTypeDef.
 
@@ -498,6 +573,12 @@ class TypeDefKind(Enum):
     STRING = "STRING_KIND"
     """A string value."""
 
+    UNION_KIND = "UNION_KIND"
+    """A named type whose values can be any one of a set of object types.
+
+    Always paired with a UnionTypeDef.
+    """
+
     VOID_KIND = "VOID_KIND"
     """A special kind used to signify that no value is returned.
 
@@ -723,6 +804,18 @@ class Binding(Type):
         _ctx = self._select("asDirectory", _args)
         return Directory(_ctx)
 
+    def as_engine_cache_entry_connection(self) -> "EngineCacheEntryConnection":
+        """Retrieve the binding value, as type EngineCacheEntryConnection"""
+        _args: list[Arg] = []
+        _ctx = self._select("asEngineCacheEntryConnection", _args)
+        return EngineCacheEntryConnection(_ctx)
+
+    def as_engine_client(self) -> "EngineClient":
+        """Retrieve the binding value, as type EngineClient"""
+        _args: list[Arg] = []
+        _ctx = self._select("asEngineClient", _args)
+        return EngineClient(_ctx)
+
     def as_env(self) -> "Env":
         """Retrieve the binding value, as type Env"""
         _args: list[Arg] = []
@@ -735,6 +828,24 @@ class Binding(Type):
         _ctx = self._select("asEnvFile", _args)
         return EnvFile(_ctx)
 
+    def as_eval(self) -> "Eval":
+        """Retrieve the binding value, as type Eval"""
+        _args: list[Arg] = []
+        _ctx = self._select("asEval", _args)
+        return Eval(_ctx)
+
+    def as_eval_model_report(self) -> "EvalModelReport":
+        """Retrieve the binding value, as type EvalModelReport"""
+        _args: list[Arg] = []
+        _ctx = self._select("asEvalModelReport", _args)
+        return EvalModelReport(_ctx)
+
+    def as_eval_report(self) -> "EvalReport":
+        """Retrieve the binding value, as type EvalReport"""
+        _args: list[Arg] = []
+        _ctx = self._select("asEvalReport", _args)
+        return EvalReport(_ctx)
+
     def as_file(self) -> "File":
         """Retrieve the binding value, as type File"""
         _args: list[Arg] = []
@@ -759,6 +870,12 @@ class Binding(Type):
         _ctx = self._select("asJSONValue", _args)
         return JSONValue(_ctx)
 
+    def as_llm_event(self) -> "LLMEvent":
+        """Retrieve the binding value, as type LLMEvent"""
+        _args: list[Arg] = []
+        _ctx = self._select("asLLMEvent", _args)
+        return LLMEvent(_ctx)
+
     def as_module(self) -> "Module":
         """Retrieve the binding value, as type Module"""
         _args: list[Arg] = []
@@ -834,6 +951,12 @@ class Binding(Type):
         _ctx = self._select("asString", _args)
         return await _ctx.execute(str | None)
 
+    def as_string_connection(self) -> "StringConnection":
+        """Retrieve the binding value, as type StringConnection"""
+        _args: list[Arg] = []
+        _ctx = self._select("asStringConnection", _args)
+        return StringConnection(_ctx)
+
     async def digest(self) -> str:
         """Returns the digest of the binding value
 
@@ -1640,6 +1763,19 @@ class Container(Type):
         _ctx = self._select("envVariables", _args)
         return await _ctx.execute_object_list(EnvVariable)
 
+    def exec_logs(self) -> "LogEntry":
+        """Evaluate the container, streaming the output of its commands as they
+        run.
+
+        Commands that have already run aren't run again, so their output isn't
+        streamed.
+
+        Can only be selected in a subscription.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("execLogs", _args)
+        return LogEntry(_ctx)
+
     async def exists(
         self,
         path: str,
@@ -3810,6 +3946,34 @@ class Directory(Type):
         _ctx = self._select("entries", _args)
         return await _ctx.execute(list[str])
 
+    def entries_connection(
+        self,
+        *,
+        path: str | None = None,
+        first: int | None = None,
+        after: str | None = None,
+    ) -> "StringConnection":
+        """A page of the elements returned by entries, selected by a cursor.
+
+        Parameters
+        ----------
+        path:
+            Location of the directory to look at (e.g., "/src").
+        first:
+            The maximum number of elements to return. All remaining elements
+            are returned if unset.
+        after:
+            Only return elements after the given cursor, as returned by
+            endCursor.
+        """
+        _args = [
+            Arg("path", path, None),
+            Arg("first", first, None),
+            Arg("after", after, None),
+        ]
+        _ctx = self._select("entriesConnection", _args)
+        return StringConnection(_ctx)
+
     async def exists(
         self,
         path: str,
@@ -3993,6 +4157,34 @@ class Directory(Type):
         _ctx = self._select("glob", _args)
         return await _ctx.execute(list[str])
 
+    def glob_connection(
+        self,
+        pattern: str,
+        *,
+        first: int | None = None,
+        after: str | None = None,
+    ) -> "StringConnection":
+        """A page of the elements returned by glob, selected by a cursor.
+
+        Parameters
+        ----------
+        pattern:
+            Pattern to match (e.g., "*.md").
+        first:
+            The maximum number of elements to return. All remaining elements
+            are returned if unset.
+        after:
+            Only return elements after the given cursor, as returned by
+            endCursor.
+        """
+        _args = [
+            Arg("pattern", pattern),
+            Arg("first", first, None),
+            Arg("after", after, None),
+        ]
+        _ctx = self._select("globConnection", _args)
+        return StringConnection(_ctx)
+
     async def id(self) -> DirectoryID:
         """A unique identifier for this Directory.
 
@@ -4487,6 +4679,17 @@ class Directory(Type):
 class Engine(Type):
     """The Dagger engine configuration and state"""
 
+    async def active_clients(self) -> list["EngineClient"]:
+        """The clients connected to the engine that the current client may watch,
+        across all sessions
+
+        .. caution::
+            Experimental: Subject to change while dagger watch is developed
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("activeClients", _args)
+        return await _ctx.execute_object_list(EngineClient)
+
     async def clients(self) -> list[str]:
         """The list of connected client IDs
 
@@ -4846,6 +5049,105 @@ class EngineCacheEntry(Type):
         return await _ctx.execute(int)
 
 
+@typecheck
+class EngineCacheEntryConnection(Type):
+    """A page of a list of EngineCacheEntry, selected by a cursor."""
+
+    async def end_cursor(self) -> str | None:
+        """The cursor of the last element of the page, to pass as "after" to
+        select the next page.
+
+        Null if the page is empty.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("endCursor", _args)
+        return await _ctx.execute(str | None)
+
+    async def has_next_page(self) -> bool:
+        """Whether there are elements after this page.
+
+        Returns
+        -------
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("hasNextPage", _args)
+        return await _ctx.execute(bool)
+
+    async def id(self) -> EngineCacheEntryConnectionID:
+        """A unique identifier for this EngineCacheEntryConnection.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EngineCacheEntryConnectionID
+            The `EngineCacheEntryConnectionID` scalar type represents an
+            identifier for an object of type EngineCacheEntryConnection.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EngineCacheEntryConnectionID)
+
+    async def nodes(self) -> list[EngineCacheEntry]:
+        """The elements of the page."""
+        _args: list[Arg] = []
+        _ctx = self._select("nodes", _args)
+        return await _ctx.execute_object_list(EngineCacheEntry)
+
+    async def total_count(self) -> int:
+        """The number of elements in the whole list.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("totalCount", _args)
+        return await _ctx.execute(int)
+
+
 @typecheck
 class EngineCacheEntrySet(Type):
     """A set of cache entries returned by a query to a cache"""
@@ -4877,6 +5179,30 @@ class EngineCacheEntrySet(Type):
         _ctx = self._select("entries", _args)
         return await _ctx.execute_object_list(EngineCacheEntry)
 
+    def entries_connection(
+        self,
+        *,
+        first: int | None = None,
+        after: str | None = None,
+    ) -> EngineCacheEntryConnection:
+        """A page of the elements returned by entries, selected by a cursor.
+
+        Parameters
+        ----------
+        first:
+            The maximum number of elements to return. All remaining elements
+            are returned if unset.
+        after:
+            Only return elements after the given cursor, as returned by
+            endCursor.
+        """
+        _args = [
+            Arg("first", first, None),
+            Arg("after", after, None),
+        ]
+        _ctx = self._select("entriesConnection", _args)
+        return EngineCacheEntryConnection(_ctx)
+
     async def entry_count(self) -> int:
         """The number of cache entries in this set.
 
@@ -4924,11 +5250,11 @@ class EngineCacheEntrySet(Type):
 
 
 @typecheck
-class EnumTypeDef(Type):
-    """A definition of a custom enum defined in a Module."""
+class EngineClient(Type):
+    """A client connected to the Dagger engine"""
 
-    async def description(self) -> str:
-        """A doc string for the enum, if any.
+    async def client_id(self) -> str:
+        """The ID of the client.
 
         Returns
         -------
@@ -4945,21 +5271,18 @@ class EnumTypeDef(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("description", _args)
+        _ctx = self._select("clientID", _args)
         return await _ctx.execute(str)
 
-    async def id(self) -> EnumTypeDefID:
-        """A unique identifier for this EnumTypeDef.
-
-        Note
-        ----
-        This is lazily evaluated, no operation is actually run.
+    async def command(self) -> str:
+        """The command the client is running, if known.
 
         Returns
         -------
-        EnumTypeDefID
-            The `EnumTypeDefID` scalar type represents an identifier for an
-            object of type EnumTypeDef.
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
         Raises
         ------
@@ -4969,17 +5292,11 @@ class EnumTypeDef(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("id", _args)
-        return await _ctx.execute(EnumTypeDefID)
-
-    async def members(self) -> list["EnumValueTypeDef"]:
-        """The members of the enum."""
-        _args: list[Arg] = []
-        _ctx = self._select("members", _args)
-        return await _ctx.execute_object_list(EnumValueTypeDef)
+        _ctx = self._select("command", _args)
+        return await _ctx.execute(str)
 
-    async def name(self) -> str:
-        """The name of the enum.
+    async def hostname(self) -> str:
+        """The hostname of the machine the client is running on.
 
         Returns
         -------
@@ -4996,25 +5313,21 @@ class EnumTypeDef(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("name", _args)
+        _ctx = self._select("hostname", _args)
         return await _ctx.execute(str)
 
-    def source_map(self) -> "SourceMap":
-        """The location of this enum declaration."""
-        _args: list[Arg] = []
-        _ctx = self._select("sourceMap", _args)
-        return SourceMap(_ctx)
+    async def id(self) -> EngineClientID:
+        """A unique identifier for this EngineClient.
 
-    async def source_module_name(self) -> str:
-        """If this EnumTypeDef is associated with a Module, the name of the
-        module. Unset otherwise.
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
 
         Returns
         -------
-        str
-            The `String` scalar type represents textual data, represented as
-            UTF-8 character sequences. The String type is most often used by
-            GraphQL to represent free-form human-readable text.
+        EngineClientID
+            The `EngineClientID` scalar type represents an identifier for an
+            object of type EngineClient.
 
         Raises
         ------
@@ -5024,36 +5337,17 @@ class EnumTypeDef(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("sourceModuleName", _args)
-        return await _ctx.execute(str)
-
-    async def values(self) -> list["EnumValueTypeDef"]:
-        """.. deprecated::
-        use members instead
-        """
-        warnings.warn(
-            'Method "values" is deprecated: use members instead',
-            DeprecationWarning,
-            stacklevel=4,
-        )
-        _args: list[Arg] = []
-        _ctx = self._select("values", _args)
-        return await _ctx.execute_object_list(EnumValueTypeDef)
-
-
-@typecheck
-class EnumValueTypeDef(Type):
-    """A definition of a value in a custom enum defined in a Module."""
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EngineClientID)
 
-    async def deprecated(self) -> str | None:
-        """The reason this enum member is deprecated, if any.
+    async def main(self) -> bool:
+        """Whether the client started its session, as opposed to being nested
+        within it.
 
         Returns
         -------
-        str | None
-            The `String` scalar type represents textual data, represented as
-            UTF-8 character sequences. The String type is most often used by
-            GraphQL to represent free-form human-readable text.
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
 
         Raises
         ------
@@ -5063,11 +5357,11 @@ class EnumValueTypeDef(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("deprecated", _args)
-        return await _ctx.execute(str | None)
+        _ctx = self._select("main", _args)
+        return await _ctx.execute(bool)
 
-    async def description(self) -> str:
-        """A doc string for the enum member, if any.
+    async def module(self) -> str:
+        """The name of the module the client is running, if any.
 
         Returns
         -------
@@ -5084,13 +5378,262 @@ class EnumValueTypeDef(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("description", _args)
+        _ctx = self._select("module", _args)
         return await _ctx.execute(str)
 
-    async def id(self) -> EnumValueTypeDefID:
-        """A unique identifier for this EnumValueTypeDef.
+    async def session_id(self) -> str:
+        """The ID of the session the client belongs to.
 
-        Note
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("sessionID", _args)
+        return await _ctx.execute(str)
+
+    async def started_time_unix_nano(self) -> int:
+        """The time the client connected, in Unix nanoseconds.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("startedTimeUnixNano", _args)
+        return await _ctx.execute(int)
+
+    async def status(self) -> str:
+        """The status of the client: starting, running or stopping.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("status", _args)
+        return await _ctx.execute(str)
+
+    async def version(self) -> str:
+        """The version of the client.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("version", _args)
+        return await _ctx.execute(str)
+
+
+@typecheck
+class EnumTypeDef(Type):
+    """A definition of a custom enum defined in a Module."""
+
+    async def description(self) -> str:
+        """A doc string for the enum, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("description", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> EnumTypeDefID:
+        """A unique identifier for this EnumTypeDef.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EnumTypeDefID
+            The `EnumTypeDefID` scalar type represents an identifier for an
+            object of type EnumTypeDef.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EnumTypeDefID)
+
+    async def members(self) -> list["EnumValueTypeDef"]:
+        """The members of the enum."""
+        _args: list[Arg] = []
+        _ctx = self._select("members", _args)
+        return await _ctx.execute_object_list(EnumValueTypeDef)
+
+    async def name(self) -> str:
+        """The name of the enum.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    def source_map(self) -> "SourceMap":
+        """The location of this enum declaration."""
+        _args: list[Arg] = []
+        _ctx = self._select("sourceMap", _args)
+        return SourceMap(_ctx)
+
+    async def source_module_name(self) -> str:
+        """If this EnumTypeDef is associated with a Module, the name of the
+        module. Unset otherwise.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("sourceModuleName", _args)
+        return await _ctx.execute(str)
+
+    async def values(self) -> list["EnumValueTypeDef"]:
+        """.. deprecated::
+        use members instead
+        """
+        warnings.warn(
+            'Method "values" is deprecated: use members instead',
+            DeprecationWarning,
+            stacklevel=4,
+        )
+        _args: list[Arg] = []
+        _ctx = self._select("values", _args)
+        return await _ctx.execute_object_list(EnumValueTypeDef)
+
+
+@typecheck
+class EnumValueTypeDef(Type):
+    """A definition of a value in a custom enum defined in a Module."""
+
+    async def deprecated(self) -> str | None:
+        """The reason this enum member is deprecated, if any.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("deprecated", _args)
+        return await _ctx.execute(str | None)
+
+    async def description(self) -> str:
+        """A doc string for the enum member, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("description", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> EnumValueTypeDefID:
+        """A unique identifier for this EnumValueTypeDef.
+
+        Note
         ----
         This is lazily evaluated, no operation is actually run.
 
@@ -5561,20 +6104,21 @@ class Env(Type):
         _ctx = self._select("withDirectoryOutput", _args)
         return Env(_ctx)
 
-    def with_env_file_input(
+    def with_engine_cache_entry_connection_input(
         self,
         name: str,
-        value: "EnvFile",
+        value: EngineCacheEntryConnection,
         description: str,
     ) -> Self:
-        """Create or update a binding of type EnvFile in the environment
+        """Create or update a binding of type EngineCacheEntryConnection in the
+        environment
 
         Parameters
         ----------
         name:
             The name of the binding
         value:
-            The EnvFile value to assign to the binding
+            The EngineCacheEntryConnection value to assign to the binding
         description:
             The purpose of the input
         """
@@ -5583,11 +6127,14 @@ class Env(Type):
             Arg("value", value),
             Arg("description", description),
         ]
-        _ctx = self._select("withEnvFileInput", _args)
+        _ctx = self._select("withEngineCacheEntryConnectionInput", _args)
         return Env(_ctx)
 
-    def with_env_file_output(self, name: str, description: str) -> Self:
-        """Declare a desired EnvFile output to be assigned in the environment
+    def with_engine_cache_entry_connection_output(
+        self, name: str, description: str
+    ) -> Self:
+        """Declare a desired EngineCacheEntryConnection output to be assigned in
+        the environment
 
         Parameters
         ----------
@@ -5600,23 +6147,108 @@ class Env(Type):
             Arg("name", name),
             Arg("description", description),
         ]
-        _ctx = self._select("withEnvFileOutput", _args)
+        _ctx = self._select("withEngineCacheEntryConnectionOutput", _args)
         return Env(_ctx)
 
-    def with_env_input(
+    def with_engine_client_input(
         self,
         name: str,
-        value: Self,
+        value: EngineClient,
         description: str,
     ) -> Self:
-        """Create or update a binding of type Env in the environment
+        """Create or update a binding of type EngineClient in the environment
 
         Parameters
         ----------
         name:
             The name of the binding
         value:
-            The Env value to assign to the binding
+            The EngineClient value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEngineClientInput", _args)
+        return Env(_ctx)
+
+    def with_engine_client_output(self, name: str, description: str) -> Self:
+        """Declare a desired EngineClient output to be assigned in the
+        environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEngineClientOutput", _args)
+        return Env(_ctx)
+
+    def with_env_file_input(
+        self,
+        name: str,
+        value: "EnvFile",
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type EnvFile in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The EnvFile value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEnvFileInput", _args)
+        return Env(_ctx)
+
+    def with_env_file_output(self, name: str, description: str) -> Self:
+        """Declare a desired EnvFile output to be assigned in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEnvFileOutput", _args)
+        return Env(_ctx)
+
+    def with_env_input(
+        self,
+        name: str,
+        value: Self,
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type Env in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The Env value to assign to the binding
         description:
             The purpose of the input
         """
@@ -5645,6 +6277,133 @@ class Env(Type):
         _ctx = self._select("withEnvOutput", _args)
         return Env(_ctx)
 
+    def with_eval_input(
+        self,
+        name: str,
+        value: "Eval",
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type Eval in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The Eval value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEvalInput", _args)
+        return Env(_ctx)
+
+    def with_eval_model_report_input(
+        self,
+        name: str,
+        value: "EvalModelReport",
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type EvalModelReport in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The EvalModelReport value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEvalModelReportInput", _args)
+        return Env(_ctx)
+
+    def with_eval_model_report_output(self, name: str, description: str) -> Self:
+        """Declare a desired EvalModelReport output to be assigned in the
+        environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEvalModelReportOutput", _args)
+        return Env(_ctx)
+
+    def with_eval_output(self, name: str, description: str) -> Self:
+        """Declare a desired Eval output to be assigned in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEvalOutput", _args)
+        return Env(_ctx)
+
+    def with_eval_report_input(
+        self,
+        name: str,
+        value: "EvalReport",
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type EvalReport in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The EvalReport value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEvalReportInput", _args)
+        return Env(_ctx)
+
+    def with_eval_report_output(self, name: str, description: str) -> Self:
+        """Declare a desired EvalReport output to be assigned in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withEvalReportOutput", _args)
+        return Env(_ctx)
+
     def with_file_input(
         self,
         name: str,
@@ -5814,6 +6573,48 @@ class Env(Type):
         _ctx = self._select("withJSONValueOutput", _args)
         return Env(_ctx)
 
+    def with_llm_event_input(
+        self,
+        name: str,
+        value: "LLMEvent",
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type LLMEvent in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The LLMEvent value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withLLMEventInput", _args)
+        return Env(_ctx)
+
+    def with_llm_event_output(self, name: str, description: str) -> Self:
+        """Declare a desired LLMEvent output to be assigned in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withLLMEventOutput", _args)
+        return Env(_ctx)
+
     def with_module(self, module: "Module") -> Self:
         """Installs a module into the environment, exposing its functions to the
         model
@@ -6210,6 +7011,49 @@ class Env(Type):
         _ctx = self._select("withStatOutput", _args)
         return Env(_ctx)
 
+    def with_string_connection_input(
+        self,
+        name: str,
+        value: "StringConnection",
+        description: str,
+    ) -> Self:
+        """Create or update a binding of type StringConnection in the environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        value:
+            The StringConnection value to assign to the binding
+        description:
+            The purpose of the input
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withStringConnectionInput", _args)
+        return Env(_ctx)
+
+    def with_string_connection_output(self, name: str, description: str) -> Self:
+        """Declare a desired StringConnection output to be assigned in the
+        environment
+
+        Parameters
+        ----------
+        name:
+            The name of the binding
+        description:
+            A description of the desired value of the binding
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description),
+        ]
+        _ctx = self._select("withStringConnectionOutput", _args)
+        return Env(_ctx)
+
     def with_string_input(
         self,
         name: str,
@@ -6368,9 +7212,390 @@ class EnvFile(Type):
 
         Returns
         -------
-        EnvFileID
-            The `EnvFileID` scalar type represents an identifier for an object
-            of type EnvFile.
+        EnvFileID
+            The `EnvFileID` scalar type represents an identifier for an object
+            of type EnvFile.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EnvFileID)
+
+    def namespace(self, prefix: str) -> Self:
+        """Filters variables by prefix and removes the pref from keys. Variables
+        without the prefix are excluded. For example, with the prefix
+        "MY_APP_" and variables: MY_APP_TOKEN=topsecret MY_APP_NAME=hello
+        FOO=bar the resulting environment will contain: TOKEN=topsecret
+        NAME=hello
+
+        Parameters
+        ----------
+        prefix:
+            The prefix to filter by
+        """
+        _args = [
+            Arg("prefix", prefix),
+        ]
+        _ctx = self._select("namespace", _args)
+        return EnvFile(_ctx)
+
+    async def variables(self, *, raw: bool | None = None) -> list["EnvVariable"]:
+        """Return all variables
+
+        Parameters
+        ----------
+        raw:
+            Return values exactly as written to the file. No quote removal or
+            variable expansion
+        """
+        _args = [
+            Arg("raw", raw, None),
+        ]
+        _ctx = self._select("variables", _args)
+        return await _ctx.execute_object_list(EnvVariable)
+
+    def with_variable(self, name: str, value: str) -> Self:
+        """Add a variable
+
+        Parameters
+        ----------
+        name:
+            Variable name
+        value:
+            Variable value
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+        ]
+        _ctx = self._select("withVariable", _args)
+        return EnvFile(_ctx)
+
+    def without_variable(self, name: str) -> Self:
+        """Remove all occurrences of the named variable
+
+        Parameters
+        ----------
+        name:
+            Variable name
+        """
+        _args = [
+            Arg("name", name),
+        ]
+        _ctx = self._select("withoutVariable", _args)
+        return EnvFile(_ctx)
+
+    def with_(self, cb: Callable[["EnvFile"], "EnvFile"]) -> "EnvFile":
+        """Call the provided callable with current EnvFile.
+
+        This is useful for reusability and readability by not breaking the calling chain.
+        """
+        return cb(self)
+
+
+@typecheck
+class EnvVariable(Type):
+    """An environment variable name and value."""
+
+    async def id(self) -> EnvVariableID:
+        """A unique identifier for this EnvVariable.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EnvVariableID
+            The `EnvVariableID` scalar type represents an identifier for an
+            object of type EnvVariable.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EnvVariableID)
+
+    async def name(self) -> str:
+        """The environment variable name.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    async def value(self) -> str:
+        """The environment variable value.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("value", _args)
+        return await _ctx.execute(str)
+
+
+@typecheck
+class Error(Type):
+    async def id(self) -> ErrorID:
+        """A unique identifier for this Error.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        ErrorID
+            The `ErrorID` scalar type represents an identifier for an object
+            of type Error.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(ErrorID)
+
+    async def message(self) -> str:
+        """A description of the error.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("message", _args)
+        return await _ctx.execute(str)
+
+    async def values(self) -> list["ErrorValue"]:
+        """The extensions of the error."""
+        _args: list[Arg] = []
+        _ctx = self._select("values", _args)
+        return await _ctx.execute_object_list(ErrorValue)
+
+    def with_value(self, name: str, value: JSON) -> Self:
+        """Add a value to the error.
+
+        Parameters
+        ----------
+        name:
+            The name of the value.
+        value:
+            The value to store on the error.
+        """
+        _args = [
+            Arg("name", name),
+            Arg("value", value),
+        ]
+        _ctx = self._select("withValue", _args)
+        return Error(_ctx)
+
+    def with_(self, cb: Callable[["Error"], "Error"]) -> "Error":
+        """Call the provided callable with current Error.
+
+        This is useful for reusability and readability by not breaking the calling chain.
+        """
+        return cb(self)
+
+
+@typecheck
+class ErrorValue(Type):
+    async def id(self) -> ErrorValueID:
+        """A unique identifier for this ErrorValue.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        ErrorValueID
+            The `ErrorValueID` scalar type represents an identifier for an
+            object of type ErrorValue.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(ErrorValueID)
+
+    async def name(self) -> str:
+        """The name of the value.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    async def value(self) -> JSON:
+        """The value.
+
+        Returns
+        -------
+        JSON
+            An arbitrary JSON-encoded value.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("value", _args)
+        return await _ctx.execute(JSON)
+
+
+@typecheck
+class Eval(Type):
+    """An evaluation of an agent against a prompt and environment.  Each
+    model is given the prompt a number of times, and each attempt is
+    checked against a set of assertions."""
+
+    async def attempts(self) -> int:
+        """The number of attempts to run for each model.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("attempts", _args)
+        return await _ctx.execute(int)
+
+    async def id(self) -> EvalID:
+        """A unique identifier for this Eval.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        EvalID
+            The `EvalID` scalar type represents an identifier for an object of
+            type Eval.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EvalID)
+
+    async def max_api_calls(self) -> int:
+        """The maximum number of API calls per attempt, or 0 for no limit.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("maxAPICalls", _args)
+        return await _ctx.execute(int)
+
+    async def prompt(self) -> str:
+        """The prompt given to the agent.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
         Raises
         ------
@@ -6380,75 +7605,152 @@ class EnvFile(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("id", _args)
-        return await _ctx.execute(EnvFileID)
+        _ctx = self._select("prompt", _args)
+        return await _ctx.execute(str)
 
-    def namespace(self, prefix: str) -> Self:
-        """Filters variables by prefix and removes the pref from keys. Variables
-        without the prefix are excluded. For example, with the prefix
-        "MY_APP_" and variables: MY_APP_TOKEN=topsecret MY_APP_NAME=hello
-        FOO=bar the resulting environment will contain: TOKEN=topsecret
-        NAME=hello
+    def run(self) -> "EvalReport":
+        """Run every attempt for every model, and report the outcomes"""
+        _args: list[Arg] = []
+        _ctx = self._select("run", _args)
+        return EvalReport(_ctx)
+
+    def with_attempts(self, attempts: int) -> Self:
+        """Set the number of attempts to run for each model
 
         Parameters
         ----------
-        prefix:
-            The prefix to filter by
+        attempts:
+            The number of attempts
         """
         _args = [
-            Arg("prefix", prefix),
+            Arg("attempts", attempts),
         ]
-        _ctx = self._select("namespace", _args)
-        return EnvFile(_ctx)
+        _ctx = self._select("withAttempts", _args)
+        return Eval(_ctx)
 
-    async def variables(self, *, raw: bool | None = None) -> list["EnvVariable"]:
-        """Return all variables
+    def with_changeset_assertion(
+        self,
+        *,
+        added: list[str] | None = None,
+        modified: list[str] | None = None,
+        removed: list[str] | None = None,
+    ) -> Self:
+        """Assert that the agent changed its environment's workspace
 
         Parameters
         ----------
-        raw:
-            Return values exactly as written to the file. No quote removal or
-            variable expansion
+        added:
+            Paths or glob patterns that must have been added
+        modified:
+            Paths or glob patterns that must have been modified
+        removed:
+            Paths or glob patterns that must have been removed
         """
         _args = [
-            Arg("raw", raw, None),
+            Arg("added", [] if added is None else added, []),
+            Arg("modified", [] if modified is None else modified, []),
+            Arg("removed", [] if removed is None else removed, []),
         ]
-        _ctx = self._select("variables", _args)
-        return await _ctx.execute_object_list(EnvVariable)
+        _ctx = self._select("withChangesetAssertion", _args)
+        return Eval(_ctx)
 
-    def with_variable(self, name: str, value: str) -> Self:
-        """Add a variable
+    def with_env(self, env: Env) -> Self:
+        """Run the agent in an environment
 
         Parameters
         ----------
-        name:
-            Variable name
-        value:
-            Variable value
+        env:
+            The environment to give the agent
         """
         _args = [
-            Arg("name", name),
-            Arg("value", value),
+            Arg("env", env),
         ]
-        _ctx = self._select("withVariable", _args)
-        return EnvFile(_ctx)
+        _ctx = self._select("withEnv", _args)
+        return Eval(_ctx)
 
-    def without_variable(self, name: str) -> Self:
-        """Remove all occurrences of the named variable
+    def with_max_api_calls(self, max_api_calls: int) -> Self:
+        """Cap the number of API calls for each attempt
+
+        Parameters
+        ----------
+        max_api_calls:
+            The maximum number of API calls, or 0 for no limit
+        """
+        _args = [
+            Arg("maxAPICalls", max_api_calls),
+        ]
+        _ctx = self._select("withMaxAPICalls", _args)
+        return Eval(_ctx)
+
+    def with_model(self, model: str) -> Self:
+        """Add a model to evaluate. If no model is added, the default model is
+        evaluated.
+
+        Parameters
+        ----------
+        model:
+            The model to evaluate
+        """
+        _args = [
+            Arg("model", model),
+        ]
+        _ctx = self._select("withModel", _args)
+        return Eval(_ctx)
+
+    def with_output_assertion(
+        self,
+        name: str,
+        *,
+        contains: str | None = "",
+        matches: str | None = "",
+    ) -> Self:
+        """Assert that the agent saved an output in its environment.
+
+        String outputs and the contents of File outputs may also be checked
+        against a string and/or a pattern.
 
         Parameters
         ----------
         name:
-            Variable name
+            The name of the output
+        contains:
+            A string that the output must contain
+        matches:
+            A regular expression that the output must match
         """
         _args = [
             Arg("name", name),
+            Arg("contains", contains, ""),
+            Arg("matches", matches, ""),
         ]
-        _ctx = self._select("withoutVariable", _args)
-        return EnvFile(_ctx)
+        _ctx = self._select("withOutputAssertion", _args)
+        return Eval(_ctx)
 
-    def with_(self, cb: Callable[["EnvFile"], "EnvFile"]) -> "EnvFile":
-        """Call the provided callable with current EnvFile.
+    def with_reply_assertion(
+        self,
+        *,
+        contains: str | None = "",
+        matches: str | None = "",
+    ) -> Self:
+        """Assert that the agent's last reply contains a string and/or matches a
+        pattern
+
+        Parameters
+        ----------
+        contains:
+            A string that the reply must contain
+        matches:
+            A regular expression that the reply must match
+        """
+        _args = [
+            Arg("contains", contains, ""),
+            Arg("matches", matches, ""),
+        ]
+        _ctx = self._select("withReplyAssertion", _args)
+        return Eval(_ctx)
+
+    def with_(self, cb: Callable[["Eval"], "Eval"]) -> "Eval":
+        """Call the provided callable with current Eval.
 
         This is useful for reusability and readability by not breaking the calling chain.
         """
@@ -6456,21 +7758,18 @@ class EnvFile(Type):
 
 
 @typecheck
-class EnvVariable(Type):
-    """An environment variable name and value."""
-
-    async def id(self) -> EnvVariableID:
-        """A unique identifier for this EnvVariable.
+class EvalModelReport(Type):
+    """The outcome of an evaluation for a single model."""
 
-        Note
-        ----
-        This is lazily evaluated, no operation is actually run.
+    async def attempts(self) -> int:
+        """The number of attempts.
 
         Returns
         -------
-        EnvVariableID
-            The `EnvVariableID` scalar type represents an identifier for an
-            object of type EnvVariable.
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
 
         Raises
         ------
@@ -6480,15 +7779,15 @@ class EnvVariable(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("id", _args)
-        return await _ctx.execute(EnvVariableID)
+        _ctx = self._select("attempts", _args)
+        return await _ctx.execute(int)
 
-    async def name(self) -> str:
-        """The environment variable name.
+    async def failures(self) -> list[str]:
+        """Why each failed attempt failed.
 
         Returns
         -------
-        str
+        list[str]
             The `String` scalar type represents textual data, represented as
             UTF-8 character sequences. The String type is most often used by
             GraphQL to represent free-form human-readable text.
@@ -6501,18 +7800,21 @@ class EnvVariable(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("name", _args)
-        return await _ctx.execute(str)
+        _ctx = self._select("failures", _args)
+        return await _ctx.execute(list[str])
 
-    async def value(self) -> str:
-        """The environment variable value.
+    async def id(self) -> EvalModelReportID:
+        """A unique identifier for this EvalModelReport.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
 
         Returns
         -------
-        str
-            The `String` scalar type represents textual data, represented as
-            UTF-8 character sequences. The String type is most often used by
-            GraphQL to represent free-form human-readable text.
+        EvalModelReportID
+            The `EvalModelReportID` scalar type represents an identifier for
+            an object of type EvalModelReport.
 
         Raises
         ------
@@ -6522,24 +7824,18 @@ class EnvVariable(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("value", _args)
-        return await _ctx.execute(str)
-
-
-@typecheck
-class Error(Type):
-    async def id(self) -> ErrorID:
-        """A unique identifier for this Error.
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(EvalModelReportID)
 
-        Note
-        ----
-        This is lazily evaluated, no operation is actually run.
+    async def mean_latency(self) -> float:
+        """The mean duration of an attempt, in seconds.
 
         Returns
         -------
-        ErrorID
-            The `ErrorID` scalar type represents an identifier for an object
-            of type Error.
+        float
+            The `Float` scalar type represents signed double-precision
+            fractional values as specified by [IEEE
+            754](http://en.wikipedia.org/wiki/IEEE_floating_point).
 
         Raises
         ------
@@ -6549,11 +7845,11 @@ class Error(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("id", _args)
-        return await _ctx.execute(ErrorID)
+        _ctx = self._select("meanLatency", _args)
+        return await _ctx.execute(float)
 
-    async def message(self) -> str:
-        """A description of the error.
+    async def model(self) -> str:
+        """The model that was evaluated.
 
         Returns
         -------
@@ -6570,44 +7866,64 @@ class Error(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("message", _args)
+        _ctx = self._select("model", _args)
         return await _ctx.execute(str)
 
-    async def values(self) -> list["ErrorValue"]:
-        """The extensions of the error."""
-        _args: list[Arg] = []
-        _ctx = self._select("values", _args)
-        return await _ctx.execute_object_list(ErrorValue)
+    async def success_rate(self) -> float:
+        """The fraction of attempts that succeeded, between 0 and 1.
 
-    def with_value(self, name: str, value: JSON) -> Self:
-        """Add a value to the error.
+        Returns
+        -------
+        float
+            The `Float` scalar type represents signed double-precision
+            fractional values as specified by [IEEE
+            754](http://en.wikipedia.org/wiki/IEEE_floating_point).
 
-        Parameters
-        ----------
-        name:
-            The name of the value.
-        value:
-            The value to store on the error.
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        _args = [
-            Arg("name", name),
-            Arg("value", value),
-        ]
-        _ctx = self._select("withValue", _args)
-        return Error(_ctx)
+        _args: list[Arg] = []
+        _ctx = self._select("successRate", _args)
+        return await _ctx.execute(float)
 
-    def with_(self, cb: Callable[["Error"], "Error"]) -> "Error":
-        """Call the provided callable with current Error.
+    async def successes(self) -> int:
+        """The number of attempts that satisfied every assertion.
 
-        This is useful for reusability and readability by not breaking the calling chain.
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        return cb(self)
+        _args: list[Arg] = []
+        _ctx = self._select("successes", _args)
+        return await _ctx.execute(int)
+
+    def token_usage(self) -> "LLMTokenUsage":
+        """The tokens used across all attempts."""
+        _args: list[Arg] = []
+        _ctx = self._select("tokenUsage", _args)
+        return LLMTokenUsage(_ctx)
 
 
 @typecheck
-class ErrorValue(Type):
-    async def id(self) -> ErrorValueID:
-        """A unique identifier for this ErrorValue.
+class EvalReport(Type):
+    """The outcome of an evaluation, for each model."""
+
+    async def id(self) -> EvalReportID:
+        """A unique identifier for this EvalReport.
 
         Note
         ----
@@ -6615,9 +7931,9 @@ class ErrorValue(Type):
 
         Returns
         -------
-        ErrorValueID
-            The `ErrorValueID` scalar type represents an identifier for an
-            object of type ErrorValue.
+        EvalReportID
+            The `EvalReportID` scalar type represents an identifier for an
+            object of type EvalReport.
 
         Raises
         ------
@@ -6628,17 +7944,15 @@ class ErrorValue(Type):
         """
         _args: list[Arg] = []
         _ctx = self._select("id", _args)
-        return await _ctx.execute(ErrorValueID)
+        return await _ctx.execute(EvalReportID)
 
-    async def name(self) -> str:
-        """The name of the value.
+    async def json(self) -> JSON:
+        """Render the report as JSON
 
         Returns
         -------
-        str
-            The `String` scalar type represents textual data, represented as
-            UTF-8 character sequences. The String type is most often used by
-            GraphQL to represent free-form human-readable text.
+        JSON
+            An arbitrary JSON-encoded value.
 
         Raises
         ------
@@ -6648,16 +7962,24 @@ class ErrorValue(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("name", _args)
-        return await _ctx.execute(str)
+        _ctx = self._select("json", _args)
+        return await _ctx.execute(JSON)
 
-    async def value(self) -> JSON:
-        """The value.
+    async def models(self) -> list[EvalModelReport]:
+        """The results for each model."""
+        _args: list[Arg] = []
+        _ctx = self._select("models", _args)
+        return await _ctx.execute_object_list(EvalModelReport)
+
+    async def table(self) -> str:
+        """Render the report as a markdown table
 
         Returns
         -------
-        JSON
-            An arbitrary JSON-encoded value.
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
         Raises
         ------
@@ -6667,8 +7989,8 @@ class ErrorValue(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("value", _args)
-        return await _ctx.execute(JSON)
+        _ctx = self._select("table", _args)
+        return await _ctx.execute(str)
 
 
 @typecheck
@@ -7371,6 +8693,27 @@ class Function(Type):
         _ctx = self._select("withDescription", _args)
         return Function(_ctx)
 
+    def with_paginated(self) -> Self:
+        """Returns the function with a flag indicating the list it returns can be
+        selected in pages.
+
+        A "<name>Connection" field is installed alongside the function, with
+        first and after arguments selecting a page of the list.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("withPaginated", _args)
+        return Function(_ctx)
+
+    def with_prompt(self) -> Self:
+        """Returns the function with a flag indicating it renders a prompt
+        template.
+
+        Prompt functions are exposed as prompts by MCP servers.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("withPrompt", _args)
+        return Function(_ctx)
+
     def with_source_map(self, source_map: "SourceMap") -> Self:
         """Returns the function with the given source map.
 
@@ -8164,6 +9507,34 @@ class GitRepository(Type):
         _ctx = self._select("tags", _args)
         return await _ctx.execute(list[str])
 
+    def tags_connection(
+        self,
+        *,
+        patterns: list[str] | None = None,
+        first: int | None = None,
+        after: str | None = None,
+    ) -> "StringConnection":
+        """A page of the elements returned by tags, selected by a cursor.
+
+        Parameters
+        ----------
+        patterns:
+            Glob patterns (e.g., "refs/tags/v*").
+        first:
+            The maximum number of elements to return. All remaining elements
+            are returned if unset.
+        after:
+            Only return elements after the given cursor, as returned by
+            endCursor.
+        """
+        _args = [
+            Arg("patterns", patterns, None),
+            Arg("first", first, None),
+            Arg("after", after, None),
+        ]
+        _ctx = self._select("tagsConnection", _args)
+        return StringConnection(_ctx)
+
     def uncommitted(self) -> Changeset:
         """Returns the changeset of uncommitted changes in the git repository."""
         _args: list[Arg] = []
@@ -8837,6 +10208,16 @@ class LLM(Type):
         _ctx = self._select("env", _args)
         return Env(_ctx)
 
+    def events(self) -> "LLMEvent":
+        """Submit the queued prompt and keep going until the model ends its turn,
+        streaming each message added to the history
+
+        Can only be selected in a subscription.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("events", _args)
+        return LLMEvent(_ctx)
+
     async def has_prompt(self) -> bool:
         """Indicates whether there are any queued prompts or tool results to send
         to the model
@@ -9068,6 +10449,29 @@ class LLM(Type):
         _ctx = self._select("withBlockedFunction", _args)
         return LLM(_ctx)
 
+    def with_context_policy(
+        self,
+        max_tokens: int,
+        *,
+        strategy: LLMContextStrategy | None = LLMContextStrategy.SUMMARIZE,
+    ) -> Self:
+        """Compact the message history automatically whenever it grows beyond a
+        token budget
+
+        Parameters
+        ----------
+        max_tokens:
+            The maximum number of tokens to send to the model
+        strategy:
+            How to compact the message history
+        """
+        _args = [
+            Arg("maxTokens", max_tokens),
+            Arg("strategy", strategy, LLMContextStrategy.SUMMARIZE),
+        ]
+        _ctx = self._select("withContextPolicy", _args)
+        return LLM(_ctx)
+
     def with_env(self, env: Env) -> Self:
         """allow the LLM to interact with an environment via MCP"""
         _args = [
@@ -9143,6 +10547,22 @@ class LLM(Type):
         _ctx = self._select("withStaticTools", _args)
         return LLM(_ctx)
 
+    def with_subagents(self, *, model: str | None = "") -> Self:
+        """Allow the LLM to delegate tasks to sub-agents, each with its own
+        environment and system prompt
+
+        Parameters
+        ----------
+        model:
+            The model for sub-agents to use. Defaults to the LLM's current
+            model.
+        """
+        _args = [
+            Arg("model", model, ""),
+        ]
+        _ctx = self._select("withSubagents", _args)
+        return LLM(_ctx)
+
     def with_system_prompt(self, prompt: str) -> Self:
         """Add a system prompt to the LLM's environment
 
@@ -9183,6 +10603,136 @@ class LLM(Type):
         return cb(self)
 
 
+@typecheck
+class LLMEvent(Type):
+    """A message added to an LLM's history while it runs."""
+
+    async def content(self) -> str:
+        """The text content of the message.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("content", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> LLMEventID:
+        """A unique identifier for this LLMEvent.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        LLMEventID
+            The `LLMEventID` scalar type represents an identifier for an
+            object of type LLMEvent.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(LLMEventID)
+
+    async def role(self) -> str:
+        """The role of the message's author: user, assistant, or system.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("role", _args)
+        return await _ctx.execute(str)
+
+    async def tool_call_id(self) -> str:
+        """The ID of the tool call that this message is the result of, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("toolCallId", _args)
+        return await _ctx.execute(str)
+
+    async def tool_calls(self) -> JSON:
+        """The tools called by the assistant, as a JSON array.
+
+        Returns
+        -------
+        JSON
+            An arbitrary JSON-encoded value.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("toolCalls", _args)
+        return await _ctx.execute(JSON)
+
+    async def tool_errored(self) -> bool:
+        """Whether the tool call that this message is the result of failed.
+
+        Returns
+        -------
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("toolErrored", _args)
+        return await _ctx.execute(bool)
+
+
 @typecheck
 class LLMTokenUsage(Type):
     async def cached_token_reads(self) -> int:
@@ -9411,6 +10961,111 @@ class ListTypeDef(Type):
         return await _ctx.execute(ListTypeDefID)
 
 
+@typecheck
+class LogEntry(Type):
+    """A chunk of output written by a process."""
+
+    async def data(self) -> str:
+        """The output, which may include partial lines.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("data", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> LogEntryID:
+        """A unique identifier for this LogEntry.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        LogEntryID
+            The `LogEntryID` scalar type represents an identifier for an
+            object of type LogEntry.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(LogEntryID)
+
+    async def stream(self) -> LogStream:
+        """The stream that the output was written to.
+
+        Returns
+        -------
+        LogStream
+            The standard stream that a process wrote output to.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("stream", _args)
+        return await _ctx.execute(LogStream)
+
+
+@typecheck
+class MapTypeDef(Type):
+    """A definition of a map type in a Module.  Maps have string keys, and
+    are represented as JSON objects in the GraphQL schema."""
+
+    async def id(self) -> MapTypeDefID:
+        """A unique identifier for this MapTypeDef.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        MapTypeDefID
+            The `MapTypeDefID` scalar type represents an identifier for an
+            object of type MapTypeDef.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(MapTypeDefID)
+
+    def value_type_def(self) -> "TypeDef":
+        """The type of the values in the map. Keys are always strings."""
+        _args: list[Arg] = []
+        _ctx = self._select("valueTypeDef", _args)
+        return TypeDef(_ctx)
+
+
 @typecheck
 class Module(Type):
     """A Dagger module."""
@@ -9638,6 +11293,12 @@ class Module(Type):
     def __await__(self):
         return self.sync().__await__()
 
+    async def unions(self) -> list["TypeDef"]:
+        """Unions served by this module."""
+        _args: list[Arg] = []
+        _ctx = self._select("unions", _args)
+        return await _ctx.execute_object_list(TypeDef)
+
     def user_defaults(self) -> EnvFile:
         """User-defined default values, loaded from local .env files."""
         _args: list[Arg] = []
@@ -9682,6 +11343,14 @@ class Module(Type):
         _ctx = self._select("withObject", _args)
         return Module(_ctx)
 
+    def with_union(self, union: "TypeDef") -> Self:
+        """This module plus the given Union type"""
+        _args = [
+            Arg("union", union),
+        ]
+        _ctx = self._select("withUnion", _args)
+        return Module(_ctx)
+
     def with_(self, cb: Callable[["Module"], "Module"]) -> "Module":
         """Call the provided callable with current Module.
 
@@ -10989,6 +12658,31 @@ class Client(Root):
         _ctx = self._select("error", _args)
         return Error(_ctx)
 
+    def eval(
+        self,
+        prompt: str,
+        *,
+        attempts: int | None = 1,
+    ) -> Eval:
+        """Initialize an evaluation of an agent
+
+        .. caution::
+            Experimental: Evals are not yet stabilized
+
+        Parameters
+        ----------
+        prompt:
+            The prompt to give the agent
+        attempts:
+            The number of attempts to run for each model
+        """
+        _args = [
+            Arg("prompt", prompt),
+            Arg("attempts", attempts, 1),
+        ]
+        _ctx = self._select("eval", _args)
+        return Eval(_ctx)
+
     def file(
         self,
         name: str,
@@ -11247,6 +12941,16 @@ class Client(Root):
         _ctx = self._select("loadDirectoryFromID", _args)
         return Directory(_ctx)
 
+    def load_engine_cache_entry_connection_from_id(
+        self, id: EngineCacheEntryConnectionID
+    ) -> EngineCacheEntryConnection:
+        """Load a EngineCacheEntryConnection from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadEngineCacheEntryConnectionFromID", _args)
+        return EngineCacheEntryConnection(_ctx)
+
     def load_engine_cache_entry_from_id(
         self, id: EngineCacheEntryID
     ) -> EngineCacheEntry:
@@ -11275,6 +12979,14 @@ class Client(Root):
         _ctx = self._select("loadEngineCacheFromID", _args)
         return EngineCache(_ctx)
 
+    def load_engine_client_from_id(self, id: EngineClientID) -> EngineClient:
+        """Load a EngineClient from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadEngineClientFromID", _args)
+        return EngineClient(_ctx)
+
     def load_engine_from_id(self, id: EngineID) -> Engine:
         """Load a Engine from its ID."""
         _args = [
@@ -11325,21 +13037,45 @@ class Client(Root):
         _ctx = self._select("loadEnvVariableFromID", _args)
         return EnvVariable(_ctx)
 
-    def load_error_from_id(self, id: ErrorID) -> Error:
-        """Load a Error from its ID."""
+    def load_error_from_id(self, id: ErrorID) -> Error:
+        """Load a Error from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadErrorFromID", _args)
+        return Error(_ctx)
+
+    def load_error_value_from_id(self, id: ErrorValueID) -> ErrorValue:
+        """Load a ErrorValue from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadErrorValueFromID", _args)
+        return ErrorValue(_ctx)
+
+    def load_eval_from_id(self, id: EvalID) -> Eval:
+        """Load a Eval from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadEvalFromID", _args)
+        return Eval(_ctx)
+
+    def load_eval_model_report_from_id(self, id: EvalModelReportID) -> EvalModelReport:
+        """Load a EvalModelReport from its ID."""
         _args = [
             Arg("id", id),
         ]
-        _ctx = self._select("loadErrorFromID", _args)
-        return Error(_ctx)
+        _ctx = self._select("loadEvalModelReportFromID", _args)
+        return EvalModelReport(_ctx)
 
-    def load_error_value_from_id(self, id: ErrorValueID) -> ErrorValue:
-        """Load a ErrorValue from its ID."""
+    def load_eval_report_from_id(self, id: EvalReportID) -> EvalReport:
+        """Load a EvalReport from its ID."""
         _args = [
             Arg("id", id),
         ]
-        _ctx = self._select("loadErrorValueFromID", _args)
-        return ErrorValue(_ctx)
+        _ctx = self._select("loadEvalReportFromID", _args)
+        return EvalReport(_ctx)
 
     def load_field_type_def_from_id(self, id: FieldTypeDefID) -> FieldTypeDef:
         """Load a FieldTypeDef from its ID."""
@@ -11449,6 +13185,14 @@ class Client(Root):
         _ctx = self._select("loadJSONValueFromID", _args)
         return JSONValue(_ctx)
 
+    def load_llm_event_from_id(self, id: LLMEventID) -> LLMEvent:
+        """Load a LLMEvent from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadLLMEventFromID", _args)
+        return LLMEvent(_ctx)
+
     def load_llm_from_id(self, id: LLMID) -> LLM:
         """Load a LLM from its ID."""
         _args = [
@@ -11481,6 +13225,22 @@ class Client(Root):
         _ctx = self._select("loadListTypeDefFromID", _args)
         return ListTypeDef(_ctx)
 
+    def load_log_entry_from_id(self, id: LogEntryID) -> LogEntry:
+        """Load a LogEntry from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadLogEntryFromID", _args)
+        return LogEntry(_ctx)
+
+    def load_map_type_def_from_id(self, id: MapTypeDefID) -> MapTypeDef:
+        """Load a MapTypeDef from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadMapTypeDefFromID", _args)
+        return MapTypeDef(_ctx)
+
     def load_module_config_client_from_id(
         self, id: ModuleConfigClientID
     ) -> ModuleConfigClient:
@@ -11595,6 +13355,16 @@ class Client(Root):
         _ctx = self._select("loadStatFromID", _args)
         return Stat(_ctx)
 
+    def load_string_connection_from_id(
+        self, id: StringConnectionID
+    ) -> "StringConnection":
+        """Load a StringConnection from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadStringConnectionFromID", _args)
+        return StringConnection(_ctx)
+
     def load_terminal_from_id(self, id: TerminalID) -> "Terminal":
         """Load a Terminal from its ID."""
         _args = [
@@ -11611,6 +13381,14 @@ class Client(Root):
         _ctx = self._select("loadTypeDefFromID", _args)
         return TypeDef(_ctx)
 
+    def load_union_type_def_from_id(self, id: UnionTypeDefID) -> "UnionTypeDef":
+        """Load a UnionTypeDef from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadUnionTypeDefFromID", _args)
+        return UnionTypeDef(_ctx)
+
     def module(self) -> Module:
         """Create a new module."""
         _args: list[Arg] = []
@@ -12314,6 +14092,16 @@ class Service(Type):
         _ctx = self._select("id", _args)
         return await _ctx.execute(ServiceID)
 
+    def logs(self) -> LogEntry:
+        """Start the service if it isn't running already, and stream its output
+        until it exits.
+
+        Can only be selected in a subscription.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("logs", _args)
+        return LogEntry(_ctx)
+
     async def ports(self) -> list[Port]:
         """Retrieves the list of ports provided by the service."""
         _args: list[Arg] = []
@@ -12714,6 +14502,120 @@ class Stat(Type):
         return await _ctx.execute(int)
 
 
+@typecheck
+class StringConnection(Type):
+    """A page of a list of String, selected by a cursor."""
+
+    async def end_cursor(self) -> str | None:
+        """The cursor of the last element of the page, to pass as "after" to
+        select the next page.
+
+        Null if the page is empty.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("endCursor", _args)
+        return await _ctx.execute(str | None)
+
+    async def has_next_page(self) -> bool:
+        """Whether there are elements after this page.
+
+        Returns
+        -------
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("hasNextPage", _args)
+        return await _ctx.execute(bool)
+
+    async def id(self) -> StringConnectionID:
+        """A unique identifier for this StringConnection.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        StringConnectionID
+            The `StringConnectionID` scalar type represents an identifier for
+            an object of type StringConnection.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(StringConnectionID)
+
+    async def nodes(self) -> list[str]:
+        """The elements of the page.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("nodes", _args)
+        return await _ctx.execute(list[str])
+
+    async def total_count(self) -> int:
+        """The number of elements in the whole list.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("totalCount", _args)
+        return await _ctx.execute(int)
+
+
 @typecheck
 class Terminal(Type):
     """An interactive terminal that clients can connect to."""
@@ -12797,6 +14699,14 @@ class TypeDef(Type):
         _ctx = self._select("asList", _args)
         return ListTypeDef(_ctx)
 
+    def as_map(self) -> MapTypeDef:
+        """If kind is MAP, the map-specific type definition. If kind is not MAP,
+        this will be null.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("asMap", _args)
+        return MapTypeDef(_ctx)
+
     def as_object(self) -> ObjectTypeDef:
         """If kind is OBJECT, the object-specific type definition. If kind is not
         OBJECT, this will be null.
@@ -12813,6 +14723,14 @@ class TypeDef(Type):
         _ctx = self._select("asScalar", _args)
         return ScalarTypeDef(_ctx)
 
+    def as_union(self) -> "UnionTypeDef":
+        """If kind is UNION, the union-specific type definition. If kind is not
+        UNION, this will be null.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("asUnion", _args)
+        return UnionTypeDef(_ctx)
+
     async def id(self) -> TypeDefID:
         """A unique identifier for this TypeDef.
 
@@ -13068,6 +14986,19 @@ class TypeDef(Type):
         _ctx = self._select("withListOf", _args)
         return TypeDef(_ctx)
 
+    def with_map_of(self, value_type: Self) -> Self:
+        """Returns a TypeDef of kind Map with string keys and the provided type
+        for its values.
+
+        Maps are represented as JSON objects, so their values can't be
+        objects, interfaces or unions.
+        """
+        _args = [
+            Arg("valueType", value_type),
+        ]
+        _ctx = self._select("withMapOf", _args)
+        return TypeDef(_ctx)
+
     def with_object(
         self,
         name: str,
@@ -13113,6 +15044,51 @@ class TypeDef(Type):
         _ctx = self._select("withScalar", _args)
         return TypeDef(_ctx)
 
+    def with_union(
+        self,
+        name: str,
+        *,
+        description: str | None = "",
+        source_map: SourceMap | None = None,
+    ) -> Self:
+        """Returns a TypeDef of kind Union with the provided name.
+
+        Note that a union's members may be omitted if the intent is only to
+        refer to a union. This is how functions are able to return their own,
+        or any other circular reference.
+
+        Parameters
+        ----------
+        name:
+            The name of the union
+        description:
+            A doc string for the union, if any
+        source_map:
+            The source map for the union definition.
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description, ""),
+            Arg("sourceMap", source_map, None),
+        ]
+        _ctx = self._select("withUnion", _args)
+        return TypeDef(_ctx)
+
+    def with_union_member(self, member: Self) -> Self:
+        """Adds a member type to a Union TypeDef, failing if the type is not a
+        union or the member is not an object.
+
+        Parameters
+        ----------
+        member:
+            The object type to add to the union
+        """
+        _args = [
+            Arg("member", member),
+        ]
+        _ctx = self._select("withUnionMember", _args)
+        return TypeDef(_ctx)
+
     def with_(self, cb: Callable[["TypeDef"], "TypeDef"]) -> "TypeDef":
         """Call the provided callable with current TypeDef.
 
@@ -13121,6 +15097,112 @@ class TypeDef(Type):
         return cb(self)
 
 
+@typecheck
+class UnionTypeDef(Type):
+    """A definition of a custom union of object types defined in a
+    Module."""
+
+    async def description(self) -> str:
+        """A doc string for the union, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("description", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> UnionTypeDefID:
+        """A unique identifier for this UnionTypeDef.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        UnionTypeDefID
+            The `UnionTypeDefID` scalar type represents an identifier for an
+            object of type UnionTypeDef.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(UnionTypeDefID)
+
+    async def members(self) -> list[TypeDef]:
+        """The object types that a value of the union can be."""
+        _args: list[Arg] = []
+        _ctx = self._select("members", _args)
+        return await _ctx.execute_object_list(TypeDef)
+
+    async def name(self) -> str:
+        """The name of the union.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    def source_map(self) -> SourceMap:
+        """The location of this union declaration."""
+        _args: list[Arg] = []
+        _ctx = self._select("sourceMap", _args)
+        return SourceMap(_ctx)
+
+    async def source_module_name(self) -> str:
+        """If this UnionTypeDef is associated with a Module, the name of the
+        module. Unset otherwise.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("sourceModuleName", _args)
+        return await _ctx.execute(str)
+
+
 dag = Client()
 """The global client instance."""
 
@@ -13154,10 +15236,14 @@ __all__ = [
     "Engine",
     "EngineCache",
     "EngineCacheEntry",
+    "EngineCacheEntryConnection",
+    "EngineCacheEntryConnectionID",
     "EngineCacheEntryID",
     "EngineCacheEntrySet",
     "EngineCacheEntrySetID",
     "EngineCacheID",
+    "EngineClient",
+    "EngineClientID",
     "EngineID",
     "EnumTypeDef",
     "EnumTypeDefID",
@@ -13173,6 +15259,12 @@ __all__ = [
     "ErrorID",
     "ErrorValue",
     "ErrorValueID",
+    "Eval",
+    "EvalID",
+    "EvalModelReport",
+    "EvalModelReportID",
+    "EvalReport",
+    "EvalReportID",
     "ExistsType",
     "FieldTypeDef",
     "FieldTypeDefID",
@@ -13204,12 +15296,20 @@ __all__ = [
     "InterfaceTypeDefID",
     "JSONValue",
     "JSONValueID",
+    "LLMContextStrategy",
+    "LLMEvent",
+    "LLMEventID",
     "LLMTokenUsage",
     "LLMTokenUsageID",
     "Label",
     "LabelID",
     "ListTypeDef",
     "ListTypeDefID",
+    "LogEntry",
+    "LogEntryID",
+    "LogStream",
+    "MapTypeDef",
+    "MapTypeDefID",
     "Module",
     "ModuleConfigClient",
     "ModuleConfigClientID",
@@ -13245,11 +15345,15 @@ __all__ = [
     "SourceMapID",
     "Stat",
     "StatID",
+    "StringConnection",
+    "StringConnectionID",
     "Terminal",
     "TerminalID",
     "TypeDef",
     "TypeDefID",
     "TypeDefKind",
+    "UnionTypeDef",
+    "UnionTypeDefID",
     "Void",
     "dag",
 ]
@@ -16,6 +16,7 @@ function = _default_mod.function
 field = _default_mod.field
 interface = _default_mod.interface
 object_type = _default_mod.object_type
+prompt = _default_mod.prompt
 
 
 def default_module() -> Module:
@@ -36,4 +37,5 @@ __all__ = [
     "function",
     "interface",
     "object_type",
+    "prompt",
 ]
@@ -54,6 +54,7 @@ OBJECT_DEF_KEY: typing.Final[str] = "__dagger_object__"
 FIELD_DEF_KEY: typing.Final[str] = "__dagger_field__"
 FUNCTION_DEF_KEY: typing.Final[str] = "__dagger_function__"
 CHECK_DEF_KEY: typing.Final[str] = "__dagger_check__"
+PROMPT_DEF_KEY: typing.Final[str] = "__dagger_prompt__"
 MODULE_NAME: typing.Final[str] = os.getenv("DAGGER_MODULE", "")
 MAIN_OBJECT: typing.Final[str] = os.getenv("DAGGER_MAIN_OBJECT", "")
 TYPE_DEF_FILE: typing.Final[str] = os.getenv("DAGGER_MODULE_FILE", "/module.json")
@@ -208,6 +209,8 @@ class Module:
                     func_def = func_def.with_deprecated(reason=deprecated)
                 if func.check:
                     func_def = func_def.with_check()
+                if func.prompt:
+                    func_def = func_def.with_prompt()
 
                 for param in func.parameters.values():
                     arg_def = to_typedef(
@@ -643,6 +646,38 @@ class Module:
 
         return wrapper(func) if func else wrapper
 
+    def prompt(
+        self,
+        func: Func[P, R] | None = None,
+    ) -> Func[P, R] | Callable[[Func[P, R]], Func[P, R]]:
+        """Mark a function as a prompt.
+
+        Prompts are functions that take and return strings, and are exposed
+        as MCP prompts by ``dagger mcp``. This decorator can be combined with
+        :py:meth:`function`.
+
+        Example usage::
+
+            @object_type
+            class MyModule:
+                @function
+                @prompt
+                def review(self, code: str) -> str:
+                    return f"Review this code:\n{code}"
+
+        Parameters
+        ----------
+        func:
+            The function to mark as a prompt. Should be an instance method in
+            a class decorated with :py:meth:`object_type`.
+        """
+
+        def wrapper(fn: Func[P, R]) -> Func[P, R]:
+            setattr(fn, PROMPT_DEF_KEY, True)
+            return fn
+
+        return wrapper(func) if func else wrapper
+
     @overload
     def function(
         self,
@@ -705,6 +740,8 @@ class Module:
 
             # Check if function is marked as a check
             check = getattr(func, CHECK_DEF_KEY, False)
+            # Check if function is marked as a prompt
+            prompt = getattr(func, PROMPT_DEF_KEY, False)
 
             meta = FunctionDefinition(
                 name=name,
@@ -712,6 +749,7 @@ class Module:
                 cache=cache,
                 deprecated=deprecated,
                 check=check,
+                prompt=prompt,
             )
 
             if inspect.isclass(func):
@@ -38,6 +38,7 @@ from dagger.mod._utils import (
 )
 
 CHECK_DEF_KEY: str = "__dagger_check__"
+PROMPT_DEF_KEY: str = "__dagger_prompt__"
 
 logger = logging.getLogger(__package__)
 
@@ -102,6 +103,12 @@ class Function(Generic[P, R]):
         # Check both the metadata and the attribute to support either decorator order
         return self.meta.check or getattr(self.wrapped, CHECK_DEF_KEY, False)
 
+    @property
+    def prompt(self) -> bool:
+        """Indicates whether the function is configured as a prompt."""
+        # Check both the metadata and the attribute to support either decorator order
+        return self.meta.prompt or getattr(self.wrapped, PROMPT_DEF_KEY, False)
+
     @cached_property
     def cache_policy(self):
         return self.meta.cache
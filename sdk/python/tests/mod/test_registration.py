@@ -119,6 +119,35 @@ def test_check_decorator_order():
     assert function_first_fn.check is True
 
 
+def test_prompt_decorator_order():
+    """Test that @prompt works whether applied before or after @function."""
+    mod = Module()
+
+    @mod.object_type
+    class Foo:
+        @mod.prompt
+        @mod.function
+        def prompt_first(self, code: str) -> str:
+            """Prompt applied before function."""
+            return code
+
+        @mod.function
+        @mod.prompt
+        def function_first(self, code: str) -> str:
+            """Prompt applied after function."""
+            return code
+
+        @mod.function
+        def regular(self) -> str:
+            """Regular function."""
+            return ""
+
+    functions = mod.get_object("Foo").functions
+    assert functions["prompt_first"].prompt is True
+    assert functions["function_first"].prompt is True
+    assert functions["regular"].prompt is False
+
+
 def test_function_argument_deprecated_metadata():
     mod = Module()
 
@@ -19,6 +19,7 @@ export type ArgumentOptions = {
 
 export function func(alias?: string): MethodDecorator
 export function check(): MethodDecorator
+export function prompt(): MethodDecorator
 export function argument(opts?: ArgumentOptions): ParameterDecorator
 export function object(): ClassDecorator
 export function field(alias?: string): PropertyDecorator
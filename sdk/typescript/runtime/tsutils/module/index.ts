@@ -4,6 +4,7 @@ export {
   Context,
   func,
   check,
+  prompt,
   argument,
   object,
   field,
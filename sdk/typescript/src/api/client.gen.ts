@@ -824,6 +824,23 @@ export type DirectoryEntriesOpts = {
   path?: string
 }
 
+export type DirectoryEntriesConnectionOpts = {
+  /**
+   * Location of the directory to look at (e.g., "/src").
+   */
+  path?: string
+
+  /**
+   * The maximum number of elements to return. All remaining elements are returned if unset.
+   */
+  first?: number
+
+  /**
+   * Only return elements after the given cursor, as returned by endCursor.
+   */
+  after?: string
+}
+
 export type DirectoryExistsOpts = {
   /**
    * If specified, also validate the type of file (e.g. "REGULAR_TYPE", "DIRECTORY_TYPE", or "SYMLINK_TYPE").
@@ -860,6 +877,18 @@ export type DirectoryFilterOpts = {
   gitignore?: boolean
 }
 
+export type DirectoryGlobConnectionOpts = {
+  /**
+   * The maximum number of elements to return. All remaining elements are returned if unset.
+   */
+  first?: number
+
+  /**
+   * Only return elements after the given cursor, as returned by endCursor.
+   */
+  after?: string
+}
+
 export type DirectorySearchOpts = {
   /**
    * Directory or file paths to search
@@ -1025,11 +1054,30 @@ export type EngineCachePruneOpts = {
   useDefaultPolicy?: boolean
 }
 
+/**
+ * The `EngineCacheEntryConnectionID` scalar type represents an identifier for an object of type EngineCacheEntryConnection.
+ */
+export type EngineCacheEntryConnectionID = string & {
+  __EngineCacheEntryConnectionID: never
+}
+
 /**
  * The `EngineCacheEntryID` scalar type represents an identifier for an object of type EngineCacheEntry.
  */
 export type EngineCacheEntryID = string & { __EngineCacheEntryID: never }
 
+export type EngineCacheEntrySetEntriesConnectionOpts = {
+  /**
+   * The maximum number of elements to return. All remaining elements are returned if unset.
+   */
+  first?: number
+
+  /**
+   * Only return elements after the given cursor, as returned by endCursor.
+   */
+  after?: string
+}
+
 /**
  * The `EngineCacheEntrySetID` scalar type represents an identifier for an object of type EngineCacheEntrySet.
  */
@@ -1040,6 +1088,11 @@ export type EngineCacheEntrySetID = string & { __EngineCacheEntrySetID: never }
  */
 export type EngineCacheID = string & { __EngineCacheID: never }
 
+/**
+ * The `EngineClientID` scalar type represents an identifier for an object of type EngineClient.
+ */
+export type EngineClientID = string & { __EngineClientID: never }
+
 /**
  * The `EngineID` scalar type represents an identifier for an object of type Engine.
  */
@@ -1094,6 +1147,62 @@ export type ErrorID = string & { __ErrorID: never }
  */
 export type ErrorValueID = string & { __ErrorValueID: never }
 
+export type EvalWithChangesetAssertionOpts = {
+  /**
+   * Paths or glob patterns that must have been added
+   */
+  added?: string[]
+
+  /**
+   * Paths or glob patterns that must have been modified
+   */
+  modified?: string[]
+
+  /**
+   * Paths or glob patterns that must have been removed
+   */
+  removed?: string[]
+}
+
+export type EvalWithOutputAssertionOpts = {
+  /**
+   * A string that the output must contain
+   */
+  contains?: string
+
+  /**
+   * A regular expression that the output must match
+   */
+  matches?: string
+}
+
+export type EvalWithReplyAssertionOpts = {
+  /**
+   * A string that the reply must contain
+   */
+  contains?: string
+
+  /**
+   * A regular expression that the reply must match
+   */
+  matches?: string
+}
+
+/**
+ * The `EvalID` scalar type represents an identifier for an object of type Eval.
+ */
+export type EvalID = string & { __EvalID: never }
+
+/**
+ * The `EvalModelReportID` scalar type represents an identifier for an object of type EvalModelReport.
+ */
+export type EvalModelReportID = string & { __EvalModelReportID: never }
+
+/**
+ * The `EvalReportID` scalar type represents an identifier for an object of type EvalReport.
+ */
+export type EvalReportID = string & { __EvalReportID: never }
+
 /**
  * File type.
  */
@@ -1471,6 +1580,23 @@ export type GitRepositoryTagsOpts = {
   patterns?: string[]
 }
 
+export type GitRepositoryTagsConnectionOpts = {
+  /**
+   * Glob patterns (e.g., "refs/tags/v*").
+   */
+  patterns?: string[]
+
+  /**
+   * The maximum number of elements to return. All remaining elements are returned if unset.
+   */
+  first?: number
+
+  /**
+   * Only return elements after the given cursor, as returned by endCursor.
+   */
+  after?: string
+}
+
 /**
  * The `GitRepositoryID` scalar type represents an identifier for an object of type GitRepository.
  */
@@ -1662,6 +1788,69 @@ export type JSONValueContentsOpts = {
  */
 export type JSONValueID = string & { __JSONValueID: never }
 
+export type LLMWithContextPolicyOpts = {
+  /**
+   * How to compact the message history
+   */
+  strategy?: LLMContextStrategy
+}
+
+export type LLMWithSubagentsOpts = {
+  /**
+   * The model for sub-agents to use. Defaults to the LLM's current model.
+   */
+  model?: string
+}
+
+/**
+ * Strategy for compacting an LLM's context once it exceeds its token budget.
+ */
+export enum LLMContextStrategy {
+  /**
+   * Replace older messages with a summary written by the model
+   */
+  Summarize = "SUMMARIZE",
+
+  /**
+   * Truncate the outputs of older tool calls
+   */
+  TruncateToolOutputs = "TRUNCATE_TOOL_OUTPUTS",
+}
+
+/**
+ * Utility function to convert a LLMContextStrategy value to its name so
+ * it can be uses as argument to call a exposed function.
+ */
+function LlmcontextStrategyValueToName(value: LLMContextStrategy): string {
+  switch (value) {
+    case LLMContextStrategy.Summarize:
+      return "SUMMARIZE"
+    case LLMContextStrategy.TruncateToolOutputs:
+      return "TRUNCATE_TOOL_OUTPUTS"
+    default:
+      return value
+  }
+}
+
+/**
+ * Utility function to convert a LLMContextStrategy name to its value so
+ * it can be properly used inside the module runtime.
+ */
+function LlmcontextStrategyNameToValue(name: string): LLMContextStrategy {
+  switch (name) {
+    case "SUMMARIZE":
+      return LLMContextStrategy.Summarize
+    case "TRUNCATE_TOOL_OUTPUTS":
+      return LLMContextStrategy.TruncateToolOutputs
+    default:
+      return name as LLMContextStrategy
+  }
+}
+/**
+ * The `LLMEventID` scalar type represents an identifier for an object of type LLMEvent.
+ */
+export type LLMEventID = string & { __LLMEventID: never }
+
 /**
  * The `LLMID` scalar type represents an identifier for an object of type LLM.
  */
@@ -1682,6 +1871,53 @@ export type LabelID = string & { __LabelID: never }
  */
 export type ListTypeDefID = string & { __ListTypeDefID: never }
 
+/**
+ * The `LogEntryID` scalar type represents an identifier for an object of type LogEntry.
+ */
+export type LogEntryID = string & { __LogEntryID: never }
+
+/**
+ * The standard stream that a process wrote output to.
+ */
+export enum LogStream {
+  Stderr = "STDERR",
+  Stdout = "STDOUT",
+}
+
+/**
+ * Utility function to convert a LogStream value to its name so
+ * it can be uses as argument to call a exposed function.
+ */
+function LogStreamValueToName(value: LogStream): string {
+  switch (value) {
+    case LogStream.Stderr:
+      return "STDERR"
+    case LogStream.Stdout:
+      return "STDOUT"
+    default:
+      return value
+  }
+}
+
+/**
+ * Utility function to convert a LogStream name to its value so
+ * it can be properly used inside the module runtime.
+ */
+function LogStreamNameToValue(name: string): LogStream {
+  switch (name) {
+    case "STDERR":
+      return LogStream.Stderr
+    case "STDOUT":
+      return LogStream.Stdout
+    default:
+      return name as LogStream
+  }
+}
+/**
+ * The `MapTypeDefID` scalar type represents an identifier for an object of type MapTypeDef.
+ */
+export type MapTypeDefID = string & { __MapTypeDefID: never }
+
 export type ModuleChecksOpts = {
   /**
    * Only include checks matching the specified patterns
@@ -1906,6 +2142,13 @@ export type ClientEnvFileOpts = {
   expand?: boolean
 }
 
+export type ClientEvalOpts = {
+  /**
+   * The number of attempts to run for each model
+   */
+  attempts?: number
+}
+
 export type ClientFileOpts = {
   /**
    * Permissions of the new file. Example: 0600
@@ -2154,6 +2397,11 @@ export type SourceMapID = string & { __SourceMapID: never }
  */
 export type StatID = string & { __StatID: never }
 
+/**
+ * The `StringConnectionID` scalar type represents an identifier for an object of type StringConnection.
+ */
+export type StringConnectionID = string & { __StringConnectionID: never }
+
 /**
  * The `TerminalID` scalar type represents an identifier for an object of type Terminal.
  */
@@ -2242,6 +2490,18 @@ export type TypeDefWithScalarOpts = {
   description?: string
 }
 
+export type TypeDefWithUnionOpts = {
+  /**
+   * A doc string for the union, if any
+   */
+  description?: string
+
+  /**
+   * The source map for the union definition.
+   */
+  sourceMap?: SourceMap
+}
+
 /**
  * The `TypeDefID` scalar type represents an identifier for an object of type TypeDef.
  */
@@ -2333,6 +2593,13 @@ export enum TypeDefKind {
    */
   ListKind = TypeDefKind.List,
 
+  /**
+   * A map of string keys to values all having the same type.
+   *
+   * Always paired with a MapTypeDef.
+   */
+  MapKind = "MAP_KIND",
+
   /**
    * Always paired with an ObjectTypeDef.
    *
@@ -2367,6 +2634,13 @@ export enum TypeDefKind {
    */
   StringKind = TypeDefKind.String,
 
+  /**
+   * A named type whose values can be any one of a set of object types.
+   *
+   * Always paired with a UnionTypeDef.
+   */
+  UnionKind = "UNION_KIND",
+
   /**
    * A special kind used to signify that no value is returned.
    *
@@ -2402,12 +2676,16 @@ function TypeDefKindValueToName(value: TypeDefKind): string {
       return "INTERFACE"
     case TypeDefKind.List:
       return "LIST"
+    case TypeDefKind.MapKind:
+      return "MAP_KIND"
     case TypeDefKind.Object:
       return "OBJECT"
     case TypeDefKind.Scalar:
       return "SCALAR"
     case TypeDefKind.String:
       return "STRING"
+    case TypeDefKind.UnionKind:
+      return "UNION_KIND"
     case TypeDefKind.Void:
       return "VOID"
     default:
@@ -2435,18 +2713,27 @@ function TypeDefKindNameToValue(name: string): TypeDefKind {
       return TypeDefKind.Interface
     case "LIST":
       return TypeDefKind.List
+    case "MAP_KIND":
+      return TypeDefKind.MapKind
     case "OBJECT":
       return TypeDefKind.Object
     case "SCALAR":
       return TypeDefKind.Scalar
     case "STRING":
       return TypeDefKind.String
+    case "UNION_KIND":
+      return TypeDefKind.UnionKind
     case "VOID":
       return TypeDefKind.Void
     default:
       return name as TypeDefKind
   }
 }
+/**
+ * The `UnionTypeDefID` scalar type represents an identifier for an object of type UnionTypeDef.
+ */
+export type UnionTypeDefID = string & { __UnionTypeDefID: never }
+
 /**
  * The absence of a value.
  *
@@ -2695,6 +2982,22 @@ export class Binding extends BaseClient {
     return new Directory(ctx)
   }
 
+  /**
+   * Retrieve the binding value, as type EngineCacheEntryConnection
+   */
+  asEngineCacheEntryConnection = (): EngineCacheEntryConnection => {
+    const ctx = this._ctx.select("asEngineCacheEntryConnection")
+    return new EngineCacheEntryConnection(ctx)
+  }
+
+  /**
+   * Retrieve the binding value, as type EngineClient
+   */
+  asEngineClient = (): EngineClient => {
+    const ctx = this._ctx.select("asEngineClient")
+    return new EngineClient(ctx)
+  }
+
   /**
    * Retrieve the binding value, as type Env
    */
@@ -2711,6 +3014,30 @@ export class Binding extends BaseClient {
     return new EnvFile(ctx)
   }
 
+  /**
+   * Retrieve the binding value, as type Eval
+   */
+  asEval = (): Eval => {
+    const ctx = this._ctx.select("asEval")
+    return new Eval(ctx)
+  }
+
+  /**
+   * Retrieve the binding value, as type EvalModelReport
+   */
+  asEvalModelReport = (): EvalModelReport => {
+    const ctx = this._ctx.select("asEvalModelReport")
+    return new EvalModelReport(ctx)
+  }
+
+  /**
+   * Retrieve the binding value, as type EvalReport
+   */
+  asEvalReport = (): EvalReport => {
+    const ctx = this._ctx.select("asEvalReport")
+    return new EvalReport(ctx)
+  }
+
   /**
    * Retrieve the binding value, as type File
    */
@@ -2743,6 +3070,14 @@ export class Binding extends BaseClient {
     return new JSONValue(ctx)
   }
 
+  /**
+   * Retrieve the binding value, as type LLMEvent
+   */
+  asLLMEvent = (): LLMEvent => {
+    const ctx = this._ctx.select("asLLMEvent")
+    return new LLMEvent(ctx)
+  }
+
   /**
    * Retrieve the binding value, as type Module
    */
@@ -2830,6 +3165,14 @@ export class Binding extends BaseClient {
     return response
   }
 
+  /**
+   * Retrieve the binding value, as type StringConnection
+   */
+  asStringConnection = (): StringConnection => {
+    const ctx = this._ctx.select("asStringConnection")
+    return new StringConnection(ctx)
+  }
+
   /**
    * Returns the digest of the binding value
    */
@@ -4907,6 +5250,19 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * A page of the elements returned by entries, selected by a cursor.
+   * @param opts.path Location of the directory to look at (e.g., "/src").
+   * @param opts.first The maximum number of elements to return. All remaining elements are returned if unset.
+   * @param opts.after Only return elements after the given cursor, as returned by endCursor.
+   */
+  entriesConnection = (
+    opts?: DirectoryEntriesConnectionOpts,
+  ): StringConnection => {
+    const ctx = this._ctx.select("entriesConnection", { ...opts })
+    return new StringConnection(ctx)
+  }
+
   /**
    * check if a file or directory exists
    * @param path Path to check (e.g., "/file.txt").
@@ -5005,6 +5361,20 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * A page of the elements returned by glob, selected by a cursor.
+   * @param pattern Pattern to match (e.g., "*.md").
+   * @param opts.first The maximum number of elements to return. All remaining elements are returned if unset.
+   * @param opts.after Only return elements after the given cursor, as returned by endCursor.
+   */
+  globConnection = (
+    pattern: string,
+    opts?: DirectoryGlobConnectionOpts,
+  ): StringConnection => {
+    const ctx = this._ctx.select("globConnection", { pattern, ...opts })
+    return new StringConnection(ctx)
+  }
+
   /**
    * Returns the name of the directory.
    */
@@ -5296,6 +5666,24 @@ export class Engine extends BaseClient {
     return response
   }
 
+  /**
+   * The clients connected to the engine that the current client may watch, across all sessions
+   * @experimental
+   */
+  activeClients = async (): Promise<EngineClient[]> => {
+    type activeClients = {
+      id: EngineClientID
+    }
+
+    const ctx = this._ctx.select("activeClients").select("id")
+
+    const response: Awaited<activeClients[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadEngineClientFromID(r.id),
+    )
+  }
+
   /**
    * The list of connected client IDs
    */
@@ -5587,70 +5975,90 @@ export class EngineCacheEntry extends BaseClient {
 }
 
 /**
- * A set of cache entries returned by a query to a cache
+ * A page of a list of EngineCacheEntry, selected by a cursor.
  */
-export class EngineCacheEntrySet extends BaseClient {
-  private readonly _id?: EngineCacheEntrySetID = undefined
-  private readonly _diskSpaceBytes?: number = undefined
-  private readonly _entryCount?: number = undefined
+export class EngineCacheEntryConnection extends BaseClient {
+  private readonly _id?: EngineCacheEntryConnectionID = undefined
+  private readonly _endCursor?: string = undefined
+  private readonly _hasNextPage?: boolean = undefined
+  private readonly _totalCount?: number = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
   constructor(
     ctx?: Context,
-    _id?: EngineCacheEntrySetID,
-    _diskSpaceBytes?: number,
-    _entryCount?: number,
+    _id?: EngineCacheEntryConnectionID,
+    _endCursor?: string,
+    _hasNextPage?: boolean,
+    _totalCount?: number,
   ) {
     super(ctx)
 
     this._id = _id
-    this._diskSpaceBytes = _diskSpaceBytes
-    this._entryCount = _entryCount
+    this._endCursor = _endCursor
+    this._hasNextPage = _hasNextPage
+    this._totalCount = _totalCount
   }
 
   /**
-   * A unique identifier for this EngineCacheEntrySet.
+   * A unique identifier for this EngineCacheEntryConnection.
    */
-  id = async (): Promise<EngineCacheEntrySetID> => {
+  id = async (): Promise<EngineCacheEntryConnectionID> => {
     if (this._id) {
       return this._id
     }
 
     const ctx = this._ctx.select("id")
 
-    const response: Awaited<EngineCacheEntrySetID> = await ctx.execute()
+    const response: Awaited<EngineCacheEntryConnectionID> = await ctx.execute()
 
     return response
   }
 
   /**
-   * The total disk space used by the cache entries in this set.
+   * The cursor of the last element of the page, to pass as "after" to select the next page.
+   *
+   * Null if the page is empty.
    */
-  diskSpaceBytes = async (): Promise<number> => {
-    if (this._diskSpaceBytes) {
-      return this._diskSpaceBytes
+  endCursor = async (): Promise<string> => {
+    if (this._endCursor) {
+      return this._endCursor
     }
 
-    const ctx = this._ctx.select("diskSpaceBytes")
+    const ctx = this._ctx.select("endCursor")
 
-    const response: Awaited<number> = await ctx.execute()
+    const response: Awaited<string> = await ctx.execute()
 
     return response
   }
 
   /**
-   * The list of individual cache entries in the set
+   * Whether there are elements after this page.
    */
-  entries = async (): Promise<EngineCacheEntry[]> => {
-    type entries = {
+  hasNextPage = async (): Promise<boolean> => {
+    if (this._hasNextPage) {
+      return this._hasNextPage
+    }
+
+    const ctx = this._ctx.select("hasNextPage")
+
+    const response: Awaited<boolean> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The elements of the page.
+   */
+  nodes = async (): Promise<EngineCacheEntry[]> => {
+    type nodes = {
       id: EngineCacheEntryID
     }
 
-    const ctx = this._ctx.select("entries").select("id")
+    const ctx = this._ctx.select("nodes").select("id")
 
-    const response: Awaited<entries[]> = await ctx.execute()
+    const response: Awaited<nodes[]> = await ctx.execute()
 
     return response.map((r) =>
       new Client(ctx.copy()).loadEngineCacheEntryFromID(r.id),
@@ -5658,9 +6066,108 @@ export class EngineCacheEntrySet extends BaseClient {
   }
 
   /**
-   * The number of cache entries in this set.
+   * The number of elements in the whole list.
    */
-  entryCount = async (): Promise<number> => {
+  totalCount = async (): Promise<number> => {
+    if (this._totalCount) {
+      return this._totalCount
+    }
+
+    const ctx = this._ctx.select("totalCount")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+}
+
+/**
+ * A set of cache entries returned by a query to a cache
+ */
+export class EngineCacheEntrySet extends BaseClient {
+  private readonly _id?: EngineCacheEntrySetID = undefined
+  private readonly _diskSpaceBytes?: number = undefined
+  private readonly _entryCount?: number = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: EngineCacheEntrySetID,
+    _diskSpaceBytes?: number,
+    _entryCount?: number,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._diskSpaceBytes = _diskSpaceBytes
+    this._entryCount = _entryCount
+  }
+
+  /**
+   * A unique identifier for this EngineCacheEntrySet.
+   */
+  id = async (): Promise<EngineCacheEntrySetID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<EngineCacheEntrySetID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The total disk space used by the cache entries in this set.
+   */
+  diskSpaceBytes = async (): Promise<number> => {
+    if (this._diskSpaceBytes) {
+      return this._diskSpaceBytes
+    }
+
+    const ctx = this._ctx.select("diskSpaceBytes")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The list of individual cache entries in the set
+   */
+  entries = async (): Promise<EngineCacheEntry[]> => {
+    type entries = {
+      id: EngineCacheEntryID
+    }
+
+    const ctx = this._ctx.select("entries").select("id")
+
+    const response: Awaited<entries[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadEngineCacheEntryFromID(r.id),
+    )
+  }
+
+  /**
+   * A page of the elements returned by entries, selected by a cursor.
+   * @param opts.first The maximum number of elements to return. All remaining elements are returned if unset.
+   * @param opts.after Only return elements after the given cursor, as returned by endCursor.
+   */
+  entriesConnection = (
+    opts?: EngineCacheEntrySetEntriesConnectionOpts,
+  ): EngineCacheEntryConnection => {
+    const ctx = this._ctx.select("entriesConnection", { ...opts })
+    return new EngineCacheEntryConnection(ctx)
+  }
+
+  /**
+   * The number of cache entries in this set.
+   */
+  entryCount = async (): Promise<number> => {
     if (this._entryCount) {
       return this._entryCount
     }
@@ -5673,6 +6180,202 @@ export class EngineCacheEntrySet extends BaseClient {
   }
 }
 
+/**
+ * A client connected to the Dagger engine
+ */
+export class EngineClient extends BaseClient {
+  private readonly _id?: EngineClientID = undefined
+  private readonly _clientID?: string = undefined
+  private readonly _command?: string = undefined
+  private readonly _hostname?: string = undefined
+  private readonly _main?: boolean = undefined
+  private readonly _module?: string = undefined
+  private readonly _sessionID?: string = undefined
+  private readonly _startedTimeUnixNano?: number = undefined
+  private readonly _status?: string = undefined
+  private readonly _version?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: EngineClientID,
+    _clientID?: string,
+    _command?: string,
+    _hostname?: string,
+    _main?: boolean,
+    _module?: string,
+    _sessionID?: string,
+    _startedTimeUnixNano?: number,
+    _status?: string,
+    _version?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._clientID = _clientID
+    this._command = _command
+    this._hostname = _hostname
+    this._main = _main
+    this._module = _module
+    this._sessionID = _sessionID
+    this._startedTimeUnixNano = _startedTimeUnixNano
+    this._status = _status
+    this._version = _version
+  }
+
+  /**
+   * A unique identifier for this EngineClient.
+   */
+  id = async (): Promise<EngineClientID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<EngineClientID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The ID of the client.
+   */
+  clientID = async (): Promise<string> => {
+    if (this._clientID) {
+      return this._clientID
+    }
+
+    const ctx = this._ctx.select("clientID")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The command the client is running, if known.
+   */
+  command = async (): Promise<string> => {
+    if (this._command) {
+      return this._command
+    }
+
+    const ctx = this._ctx.select("command")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The hostname of the machine the client is running on.
+   */
+  hostname = async (): Promise<string> => {
+    if (this._hostname) {
+      return this._hostname
+    }
+
+    const ctx = this._ctx.select("hostname")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Whether the client started its session, as opposed to being nested within it.
+   */
+  main = async (): Promise<boolean> => {
+    if (this._main) {
+      return this._main
+    }
+
+    const ctx = this._ctx.select("main")
+
+    const response: Awaited<boolean> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The name of the module the client is running, if any.
+   */
+  module_ = async (): Promise<string> => {
+    if (this._module) {
+      return this._module
+    }
+
+    const ctx = this._ctx.select("module")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The ID of the session the client belongs to.
+   */
+  sessionID = async (): Promise<string> => {
+    if (this._sessionID) {
+      return this._sessionID
+    }
+
+    const ctx = this._ctx.select("sessionID")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The time the client connected, in Unix nanoseconds.
+   */
+  startedTimeUnixNano = async (): Promise<number> => {
+    if (this._startedTimeUnixNano) {
+      return this._startedTimeUnixNano
+    }
+
+    const ctx = this._ctx.select("startedTimeUnixNano")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The status of the client: starting, running or stopping.
+   */
+  status = async (): Promise<string> => {
+    if (this._status) {
+      return this._status
+    }
+
+    const ctx = this._ctx.select("status")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The version of the client.
+   */
+  version = async (): Promise<string> => {
+    if (this._version) {
+      return this._version
+    }
+
+    const ctx = this._ctx.select("version")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+}
+
 /**
  * A definition of a custom enum defined in a Module.
  */
@@ -6217,17 +6920,17 @@ export class Env extends BaseClient {
   }
 
   /**
-   * Create or update a binding of type EnvFile in the environment
+   * Create or update a binding of type EngineCacheEntryConnection in the environment
    * @param name The name of the binding
-   * @param value The EnvFile value to assign to the binding
+   * @param value The EngineCacheEntryConnection value to assign to the binding
    * @param description The purpose of the input
    */
-  withEnvFileInput = (
+  withEngineCacheEntryConnectionInput = (
     name: string,
-    value: EnvFile,
+    value: EngineCacheEntryConnection,
     description: string,
   ): Env => {
-    const ctx = this._ctx.select("withEnvFileInput", {
+    const ctx = this._ctx.select("withEngineCacheEntryConnectionInput", {
       name,
       value,
       description,
@@ -6236,35 +6939,184 @@ export class Env extends BaseClient {
   }
 
   /**
-   * Declare a desired EnvFile output to be assigned in the environment
+   * Declare a desired EngineCacheEntryConnection output to be assigned in the environment
    * @param name The name of the binding
    * @param description A description of the desired value of the binding
    */
-  withEnvFileOutput = (name: string, description: string): Env => {
-    const ctx = this._ctx.select("withEnvFileOutput", { name, description })
+  withEngineCacheEntryConnectionOutput = (
+    name: string,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withEngineCacheEntryConnectionOutput", {
+      name,
+      description,
+    })
     return new Env(ctx)
   }
 
   /**
-   * Create or update a binding of type Env in the environment
+   * Create or update a binding of type EngineClient in the environment
    * @param name The name of the binding
-   * @param value The Env value to assign to the binding
+   * @param value The EngineClient value to assign to the binding
    * @param description The purpose of the input
    */
-  withEnvInput = (name: string, value: Env, description: string): Env => {
-    const ctx = this._ctx.select("withEnvInput", { name, value, description })
+  withEngineClientInput = (
+    name: string,
+    value: EngineClient,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withEngineClientInput", {
+      name,
+      value,
+      description,
+    })
     return new Env(ctx)
   }
 
   /**
-   * Declare a desired Env output to be assigned in the environment
+   * Declare a desired EngineClient output to be assigned in the environment
    * @param name The name of the binding
    * @param description A description of the desired value of the binding
    */
-  withEnvOutput = (name: string, description: string): Env => {
-    const ctx = this._ctx.select("withEnvOutput", { name, description })
-    return new Env(ctx)
-  }
+  withEngineClientOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withEngineClientOutput", {
+      name,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Create or update a binding of type EnvFile in the environment
+   * @param name The name of the binding
+   * @param value The EnvFile value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withEnvFileInput = (
+    name: string,
+    value: EnvFile,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withEnvFileInput", {
+      name,
+      value,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired EnvFile output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withEnvFileOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withEnvFileOutput", { name, description })
+    return new Env(ctx)
+  }
+
+  /**
+   * Create or update a binding of type Env in the environment
+   * @param name The name of the binding
+   * @param value The Env value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withEnvInput = (name: string, value: Env, description: string): Env => {
+    const ctx = this._ctx.select("withEnvInput", { name, value, description })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired Env output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withEnvOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withEnvOutput", { name, description })
+    return new Env(ctx)
+  }
+
+  /**
+   * Create or update a binding of type Eval in the environment
+   * @param name The name of the binding
+   * @param value The Eval value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withEvalInput = (name: string, value: Eval, description: string): Env => {
+    const ctx = this._ctx.select("withEvalInput", { name, value, description })
+    return new Env(ctx)
+  }
+
+  /**
+   * Create or update a binding of type EvalModelReport in the environment
+   * @param name The name of the binding
+   * @param value The EvalModelReport value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withEvalModelReportInput = (
+    name: string,
+    value: EvalModelReport,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withEvalModelReportInput", {
+      name,
+      value,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired EvalModelReport output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withEvalModelReportOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withEvalModelReportOutput", {
+      name,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired Eval output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withEvalOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withEvalOutput", { name, description })
+    return new Env(ctx)
+  }
+
+  /**
+   * Create or update a binding of type EvalReport in the environment
+   * @param name The name of the binding
+   * @param value The EvalReport value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withEvalReportInput = (
+    name: string,
+    value: EvalReport,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withEvalReportInput", {
+      name,
+      value,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired EvalReport output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withEvalReportOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withEvalReportOutput", { name, description })
+    return new Env(ctx)
+  }
 
   /**
    * Create or update a binding of type File in the environment
@@ -6373,6 +7225,35 @@ export class Env extends BaseClient {
     return new Env(ctx)
   }
 
+  /**
+   * Create or update a binding of type LLMEvent in the environment
+   * @param name The name of the binding
+   * @param value The LLMEvent value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withLLMEventInput = (
+    name: string,
+    value: LLMEvent,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withLLMEventInput", {
+      name,
+      value,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired LLMEvent output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withLLMEventOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withLLMEventOutput", { name, description })
+    return new Env(ctx)
+  }
+
   /**
    * Installs a module into the environment, exposing its functions to the model
    *
@@ -6642,6 +7523,38 @@ export class Env extends BaseClient {
     return new Env(ctx)
   }
 
+  /**
+   * Create or update a binding of type StringConnection in the environment
+   * @param name The name of the binding
+   * @param value The StringConnection value to assign to the binding
+   * @param description The purpose of the input
+   */
+  withStringConnectionInput = (
+    name: string,
+    value: StringConnection,
+    description: string,
+  ): Env => {
+    const ctx = this._ctx.select("withStringConnectionInput", {
+      name,
+      value,
+      description,
+    })
+    return new Env(ctx)
+  }
+
+  /**
+   * Declare a desired StringConnection output to be assigned in the environment
+   * @param name The name of the binding
+   * @param description A description of the desired value of the binding
+   */
+  withStringConnectionOutput = (name: string, description: string): Env => {
+    const ctx = this._ctx.select("withStringConnectionOutput", {
+      name,
+      description,
+    })
+    return new Env(ctx)
+  }
+
   /**
    * Provides a string input binding to the environment
    * @param name The name of the binding
@@ -6958,95 +7871,505 @@ export class Error extends BaseClient {
 
     const ctx = this._ctx.select("values").select("id")
 
-    const response: Awaited<values[]> = await ctx.execute()
+    const response: Awaited<values[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadErrorValueFromID(r.id),
+    )
+  }
+
+  /**
+   * Add a value to the error.
+   * @param name The name of the value.
+   * @param value The value to store on the error.
+   */
+  withValue = (name: string, value: JSON): Error => {
+    const ctx = this._ctx.select("withValue", { name, value })
+    return new Error(ctx)
+  }
+
+  /**
+   * Call the provided function with current Error.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: Error) => Error) => {
+    return arg(this)
+  }
+}
+
+export class ErrorValue extends BaseClient {
+  private readonly _id?: ErrorValueID = undefined
+  private readonly _name?: string = undefined
+  private readonly _value?: JSON = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: ErrorValueID,
+    _name?: string,
+    _value?: JSON,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._name = _name
+    this._value = _value
+  }
+
+  /**
+   * A unique identifier for this ErrorValue.
+   */
+  id = async (): Promise<ErrorValueID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<ErrorValueID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The name of the value.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const ctx = this._ctx.select("name")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The value.
+   */
+  value = async (): Promise<JSON> => {
+    if (this._value) {
+      return this._value
+    }
+
+    const ctx = this._ctx.select("value")
+
+    const response: Awaited<JSON> = await ctx.execute()
+
+    return response
+  }
+}
+
+/**
+ * An evaluation of an agent against a prompt and environment.
+ *
+ * Each model is given the prompt a number of times, and each attempt is checked against a set of assertions.
+ */
+export class Eval extends BaseClient {
+  private readonly _id?: EvalID = undefined
+  private readonly _attempts?: number = undefined
+  private readonly _maxAPICalls?: number = undefined
+  private readonly _prompt?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: EvalID,
+    _attempts?: number,
+    _maxAPICalls?: number,
+    _prompt?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._attempts = _attempts
+    this._maxAPICalls = _maxAPICalls
+    this._prompt = _prompt
+  }
+
+  /**
+   * A unique identifier for this Eval.
+   */
+  id = async (): Promise<EvalID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<EvalID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The number of attempts to run for each model.
+   */
+  attempts = async (): Promise<number> => {
+    if (this._attempts) {
+      return this._attempts
+    }
+
+    const ctx = this._ctx.select("attempts")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The maximum number of API calls per attempt, or 0 for no limit.
+   */
+  maxAPICalls = async (): Promise<number> => {
+    if (this._maxAPICalls) {
+      return this._maxAPICalls
+    }
+
+    const ctx = this._ctx.select("maxAPICalls")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The prompt given to the agent.
+   */
+  prompt = async (): Promise<string> => {
+    if (this._prompt) {
+      return this._prompt
+    }
+
+    const ctx = this._ctx.select("prompt")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Run every attempt for every model, and report the outcomes
+   */
+  run = (): EvalReport => {
+    const ctx = this._ctx.select("run")
+    return new EvalReport(ctx)
+  }
+
+  /**
+   * Set the number of attempts to run for each model
+   * @param attempts The number of attempts
+   */
+  withAttempts = (attempts: number): Eval => {
+    const ctx = this._ctx.select("withAttempts", { attempts })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Assert that the agent changed its environment's workspace
+   * @param opts.added Paths or glob patterns that must have been added
+   * @param opts.modified Paths or glob patterns that must have been modified
+   * @param opts.removed Paths or glob patterns that must have been removed
+   */
+  withChangesetAssertion = (opts?: EvalWithChangesetAssertionOpts): Eval => {
+    const ctx = this._ctx.select("withChangesetAssertion", { ...opts })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Run the agent in an environment
+   * @param env The environment to give the agent
+   */
+  withEnv = (env: Env): Eval => {
+    const ctx = this._ctx.select("withEnv", { env })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Cap the number of API calls for each attempt
+   * @param maxAPICalls The maximum number of API calls, or 0 for no limit
+   */
+  withMaxAPICalls = (maxAPICalls: number): Eval => {
+    const ctx = this._ctx.select("withMaxAPICalls", { maxAPICalls })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Add a model to evaluate. If no model is added, the default model is evaluated.
+   * @param model The model to evaluate
+   */
+  withModel = (model: string): Eval => {
+    const ctx = this._ctx.select("withModel", { model })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Assert that the agent saved an output in its environment.
+   *
+   * String outputs and the contents of File outputs may also be checked against a string and/or a pattern.
+   * @param name The name of the output
+   * @param opts.contains A string that the output must contain
+   * @param opts.matches A regular expression that the output must match
+   */
+  withOutputAssertion = (
+    name: string,
+    opts?: EvalWithOutputAssertionOpts,
+  ): Eval => {
+    const ctx = this._ctx.select("withOutputAssertion", { name, ...opts })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Assert that the agent's last reply contains a string and/or matches a pattern
+   * @param opts.contains A string that the reply must contain
+   * @param opts.matches A regular expression that the reply must match
+   */
+  withReplyAssertion = (opts?: EvalWithReplyAssertionOpts): Eval => {
+    const ctx = this._ctx.select("withReplyAssertion", { ...opts })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Call the provided function with current Eval.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: Eval) => Eval) => {
+    return arg(this)
+  }
+}
+
+/**
+ * The outcome of an evaluation for a single model.
+ */
+export class EvalModelReport extends BaseClient {
+  private readonly _id?: EvalModelReportID = undefined
+  private readonly _attempts?: number = undefined
+  private readonly _meanLatency?: float = undefined
+  private readonly _model?: string = undefined
+  private readonly _successRate?: float = undefined
+  private readonly _successes?: number = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: EvalModelReportID,
+    _attempts?: number,
+    _meanLatency?: float,
+    _model?: string,
+    _successRate?: float,
+    _successes?: number,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._attempts = _attempts
+    this._meanLatency = _meanLatency
+    this._model = _model
+    this._successRate = _successRate
+    this._successes = _successes
+  }
+
+  /**
+   * A unique identifier for this EvalModelReport.
+   */
+  id = async (): Promise<EvalModelReportID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<EvalModelReportID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The number of attempts.
+   */
+  attempts = async (): Promise<number> => {
+    if (this._attempts) {
+      return this._attempts
+    }
+
+    const ctx = this._ctx.select("attempts")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Why each failed attempt failed.
+   */
+  failures = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("failures")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The mean duration of an attempt, in seconds.
+   */
+  meanLatency = async (): Promise<float> => {
+    if (this._meanLatency) {
+      return this._meanLatency
+    }
+
+    const ctx = this._ctx.select("meanLatency")
+
+    const response: Awaited<float> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The model that was evaluated.
+   */
+  model = async (): Promise<string> => {
+    if (this._model) {
+      return this._model
+    }
+
+    const ctx = this._ctx.select("model")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The fraction of attempts that succeeded, between 0 and 1.
+   */
+  successRate = async (): Promise<float> => {
+    if (this._successRate) {
+      return this._successRate
+    }
+
+    const ctx = this._ctx.select("successRate")
+
+    const response: Awaited<float> = await ctx.execute()
 
-    return response.map((r) =>
-      new Client(ctx.copy()).loadErrorValueFromID(r.id),
-    )
+    return response
   }
 
   /**
-   * Add a value to the error.
-   * @param name The name of the value.
-   * @param value The value to store on the error.
+   * The number of attempts that satisfied every assertion.
    */
-  withValue = (name: string, value: JSON): Error => {
-    const ctx = this._ctx.select("withValue", { name, value })
-    return new Error(ctx)
+  successes = async (): Promise<number> => {
+    if (this._successes) {
+      return this._successes
+    }
+
+    const ctx = this._ctx.select("successes")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
   }
 
   /**
-   * Call the provided function with current Error.
-   *
-   * This is useful for reusability and readability by not breaking the calling chain.
+   * The tokens used across all attempts.
    */
-  with = (arg: (param: Error) => Error) => {
-    return arg(this)
+  tokenUsage = (): LLMTokenUsage => {
+    const ctx = this._ctx.select("tokenUsage")
+    return new LLMTokenUsage(ctx)
   }
 }
 
-export class ErrorValue extends BaseClient {
-  private readonly _id?: ErrorValueID = undefined
-  private readonly _name?: string = undefined
-  private readonly _value?: JSON = undefined
+/**
+ * The outcome of an evaluation, for each model.
+ */
+export class EvalReport extends BaseClient {
+  private readonly _id?: EvalReportID = undefined
+  private readonly _json?: JSON = undefined
+  private readonly _table?: string = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
   constructor(
     ctx?: Context,
-    _id?: ErrorValueID,
-    _name?: string,
-    _value?: JSON,
+    _id?: EvalReportID,
+    _json?: JSON,
+    _table?: string,
   ) {
     super(ctx)
 
     this._id = _id
-    this._name = _name
-    this._value = _value
+    this._json = _json
+    this._table = _table
   }
 
   /**
-   * A unique identifier for this ErrorValue.
+   * A unique identifier for this EvalReport.
    */
-  id = async (): Promise<ErrorValueID> => {
+  id = async (): Promise<EvalReportID> => {
     if (this._id) {
       return this._id
     }
 
     const ctx = this._ctx.select("id")
 
-    const response: Awaited<ErrorValueID> = await ctx.execute()
+    const response: Awaited<EvalReportID> = await ctx.execute()
 
     return response
   }
 
   /**
-   * The name of the value.
+   * Render the report as JSON
    */
-  name = async (): Promise<string> => {
-    if (this._name) {
-      return this._name
+  json = async (): Promise<JSON> => {
+    if (this._json) {
+      return this._json
     }
 
-    const ctx = this._ctx.select("name")
+    const ctx = this._ctx.select("json")
 
-    const response: Awaited<string> = await ctx.execute()
+    const response: Awaited<JSON> = await ctx.execute()
 
     return response
   }
 
   /**
-   * The value.
+   * The results for each model.
    */
-  value = async (): Promise<JSON> => {
-    if (this._value) {
-      return this._value
+  models = async (): Promise<EvalModelReport[]> => {
+    type models = {
+      id: EvalModelReportID
     }
 
-    const ctx = this._ctx.select("value")
+    const ctx = this._ctx.select("models").select("id")
 
-    const response: Awaited<JSON> = await ctx.execute()
+    const response: Awaited<models[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadEvalModelReportFromID(r.id),
+    )
+  }
+
+  /**
+   * Render the report as a markdown table
+   */
+  table = async (): Promise<string> => {
+    if (this._table) {
+      return this._table
+    }
+
+    const ctx = this._ctx.select("table")
+
+    const response: Awaited<string> = await ctx.execute()
 
     return response
   }
@@ -7616,6 +8939,26 @@ export class Function_ extends BaseClient {
     return new Function_(ctx)
   }
 
+  /**
+   * Returns the function with a flag indicating the list it returns can be selected in pages.
+   *
+   * A "<name>Connection" field is installed alongside the function, with first and after arguments selecting a page of the list.
+   */
+  withPaginated = (): Function_ => {
+    const ctx = this._ctx.select("withPaginated")
+    return new Function_(ctx)
+  }
+
+  /**
+   * Returns the function with a flag indicating it renders a prompt template.
+   *
+   * Prompt functions are exposed as prompts by MCP servers.
+   */
+  withPrompt = (): Function_ => {
+    const ctx = this._ctx.select("withPrompt")
+    return new Function_(ctx)
+  }
+
   /**
    * Returns the function with the given source map.
    * @param sourceMap The source map for the function definition.
@@ -8284,6 +9627,19 @@ export class GitRepository extends BaseClient {
     return response
   }
 
+  /**
+   * A page of the elements returned by tags, selected by a cursor.
+   * @param opts.patterns Glob patterns (e.g., "refs/tags/v*").
+   * @param opts.first The maximum number of elements to return. All remaining elements are returned if unset.
+   * @param opts.after Only return elements after the given cursor, as returned by endCursor.
+   */
+  tagsConnection = (
+    opts?: GitRepositoryTagsConnectionOpts,
+  ): StringConnection => {
+    const ctx = this._ctx.select("tagsConnection", { ...opts })
+    return new StringConnection(ctx)
+  }
+
   /**
    * Returns the changeset of uncommitted changes in the git repository.
    */
@@ -9039,6 +10395,27 @@ export class LLM extends BaseClient {
     return new LLM(ctx)
   }
 
+  /**
+   * Compact the message history automatically whenever it grows beyond a token budget
+   * @param maxTokens The maximum number of tokens to send to the model
+   * @param opts.strategy How to compact the message history
+   */
+  withContextPolicy = (
+    maxTokens: number,
+    opts?: LLMWithContextPolicyOpts,
+  ): LLM => {
+    const metadata = {
+      strategy: { is_enum: true, value_to_name: LLMContextStrategyValueToName },
+    }
+
+    const ctx = this._ctx.select("withContextPolicy", {
+      maxTokens,
+      ...opts,
+      __metadata: metadata,
+    })
+    return new LLM(ctx)
+  }
+
   /**
    * allow the LLM to interact with an environment via MCP
    */
@@ -9092,6 +10469,15 @@ export class LLM extends BaseClient {
     return new LLM(ctx)
   }
 
+  /**
+   * Allow the LLM to delegate tasks to sub-agents, each with its own environment and system prompt
+   * @param opts.model The model for sub-agents to use. Defaults to the LLM's current model.
+   */
+  withSubagents = (opts?: LLMWithSubagentsOpts): LLM => {
+    const ctx = this._ctx.select("withSubagents", { ...opts })
+    return new LLM(ctx)
+  }
+
   /**
    * Add a system prompt to the LLM's environment
    * @param prompt The system prompt to send
@@ -9135,6 +10521,130 @@ export class LLM extends BaseClient {
   }
 }
 
+/**
+ * A message added to an LLM's history while it runs.
+ */
+export class LLMEvent extends BaseClient {
+  private readonly _id?: LLMEventID = undefined
+  private readonly _content?: string = undefined
+  private readonly _role?: string = undefined
+  private readonly _toolCallId?: string = undefined
+  private readonly _toolCalls?: JSON = undefined
+  private readonly _toolErrored?: boolean = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: LLMEventID,
+    _content?: string,
+    _role?: string,
+    _toolCallId?: string,
+    _toolCalls?: JSON,
+    _toolErrored?: boolean,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._content = _content
+    this._role = _role
+    this._toolCallId = _toolCallId
+    this._toolCalls = _toolCalls
+    this._toolErrored = _toolErrored
+  }
+
+  /**
+   * A unique identifier for this LLMEvent.
+   */
+  id = async (): Promise<LLMEventID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<LLMEventID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The text content of the message.
+   */
+  content = async (): Promise<string> => {
+    if (this._content) {
+      return this._content
+    }
+
+    const ctx = this._ctx.select("content")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The role of the message's author: user, assistant, or system.
+   */
+  role = async (): Promise<string> => {
+    if (this._role) {
+      return this._role
+    }
+
+    const ctx = this._ctx.select("role")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The ID of the tool call that this message is the result of, if any.
+   */
+  toolCallId = async (): Promise<string> => {
+    if (this._toolCallId) {
+      return this._toolCallId
+    }
+
+    const ctx = this._ctx.select("toolCallId")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The tools called by the assistant, as a JSON array.
+   */
+  toolCalls = async (): Promise<JSON> => {
+    if (this._toolCalls) {
+      return this._toolCalls
+    }
+
+    const ctx = this._ctx.select("toolCalls")
+
+    const response: Awaited<JSON> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Whether the tool call that this message is the result of failed.
+   */
+  toolErrored = async (): Promise<boolean> => {
+    if (this._toolErrored) {
+      return this._toolErrored
+    }
+
+    const ctx = this._ctx.select("toolErrored")
+
+    const response: Awaited<boolean> = await ctx.execute()
+
+    return response
+  }
+}
+
 export class LLMTokenUsage extends BaseClient {
   private readonly _id?: LLMTokenUsageID = undefined
   private readonly _cachedTokenReads?: number = undefined
@@ -9310,32 +10820,143 @@ export class ListTypeDef extends BaseClient {
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
-  constructor(ctx?: Context, _id?: ListTypeDefID) {
+  constructor(ctx?: Context, _id?: ListTypeDefID) {
+    super(ctx)
+
+    this._id = _id
+  }
+
+  /**
+   * A unique identifier for this ListTypeDef.
+   */
+  id = async (): Promise<ListTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<ListTypeDefID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The type of the elements in the list.
+   */
+  elementTypeDef = (): TypeDef => {
+    const ctx = this._ctx.select("elementTypeDef")
+    return new TypeDef(ctx)
+  }
+}
+
+/**
+ * A chunk of output written by a process.
+ */
+export class LogEntry extends BaseClient {
+  private readonly _id?: LogEntryID = undefined
+  private readonly _data?: string = undefined
+  private readonly _stream?: LogStream = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: LogEntryID,
+    _data?: string,
+    _stream?: LogStream,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._data = _data
+    this._stream = _stream
+  }
+
+  /**
+   * A unique identifier for this LogEntry.
+   */
+  id = async (): Promise<LogEntryID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<LogEntryID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The output, which may include partial lines.
+   */
+  data = async (): Promise<string> => {
+    if (this._data) {
+      return this._data
+    }
+
+    const ctx = this._ctx.select("data")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The stream that the output was written to.
+   */
+  stream = async (): Promise<LogStream> => {
+    if (this._stream) {
+      return this._stream
+    }
+
+    const ctx = this._ctx.select("stream")
+
+    const response: Awaited<LogStream> = await ctx.execute()
+
+    return LogStreamNameToValue(response)
+  }
+}
+
+/**
+ * A definition of a map type in a Module.
+ *
+ * Maps have string keys, and are represented as JSON objects in the GraphQL schema.
+ */
+export class MapTypeDef extends BaseClient {
+  private readonly _id?: MapTypeDefID = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(ctx?: Context, _id?: MapTypeDefID) {
     super(ctx)
 
     this._id = _id
   }
 
   /**
-   * A unique identifier for this ListTypeDef.
+   * A unique identifier for this MapTypeDef.
    */
-  id = async (): Promise<ListTypeDefID> => {
+  id = async (): Promise<MapTypeDefID> => {
     if (this._id) {
       return this._id
     }
 
     const ctx = this._ctx.select("id")
 
-    const response: Awaited<ListTypeDefID> = await ctx.execute()
+    const response: Awaited<MapTypeDefID> = await ctx.execute()
 
     return response
   }
 
   /**
-   * The type of the elements in the list.
+   * The type of the values in the map. Keys are always strings.
    */
-  elementTypeDef = (): TypeDef => {
-    const ctx = this._ctx.select("elementTypeDef")
+  valueTypeDef = (): TypeDef => {
+    const ctx = this._ctx.select("valueTypeDef")
     return new TypeDef(ctx)
   }
 }
@@ -9566,6 +11187,21 @@ export class Module_ extends BaseClient {
     return new Client(ctx.copy()).loadModuleFromID(response)
   }
 
+  /**
+   * Unions served by this module.
+   */
+  unions = async (): Promise<TypeDef[]> => {
+    type unions = {
+      id: TypeDefID
+    }
+
+    const ctx = this._ctx.select("unions").select("id")
+
+    const response: Awaited<unions[]> = await ctx.execute()
+
+    return response.map((r) => new Client(ctx.copy()).loadTypeDefFromID(r.id))
+  }
+
   /**
    * User-defined default values, loaded from local .env files.
    */
@@ -9609,6 +11245,14 @@ export class Module_ extends BaseClient {
     return new Module_(ctx)
   }
 
+  /**
+   * This module plus the given Union type
+   */
+  withUnion = (union: TypeDef): Module_ => {
+    const ctx = this._ctx.select("withUnion", { union })
+    return new Module_(ctx)
+  }
+
   /**
    * Call the provided function with current Module.
    *
@@ -10786,6 +12430,17 @@ export class Client extends BaseClient {
     return new Error(ctx)
   }
 
+  /**
+   * Initialize an evaluation of an agent
+   * @param prompt The prompt to give the agent
+   * @param opts.attempts The number of attempts to run for each model
+   * @experimental
+   */
+  eval = (prompt: string, opts?: ClientEvalOpts): Eval => {
+    const ctx = this._ctx.select("eval", { prompt, ...opts })
+    return new Eval(ctx)
+  }
+
   /**
    * Creates a file with the specified contents.
    * @param name Name of the new file. Example: "foo.txt"
@@ -10955,6 +12610,16 @@ export class Client extends BaseClient {
     return new Directory(ctx)
   }
 
+  /**
+   * Load a EngineCacheEntryConnection from its ID.
+   */
+  loadEngineCacheEntryConnectionFromID = (
+    id: EngineCacheEntryConnectionID,
+  ): EngineCacheEntryConnection => {
+    const ctx = this._ctx.select("loadEngineCacheEntryConnectionFromID", { id })
+    return new EngineCacheEntryConnection(ctx)
+  }
+
   /**
    * Load a EngineCacheEntry from its ID.
    */
@@ -10981,6 +12646,14 @@ export class Client extends BaseClient {
     return new EngineCache(ctx)
   }
 
+  /**
+   * Load a EngineClient from its ID.
+   */
+  loadEngineClientFromID = (id: EngineClientID): EngineClient => {
+    const ctx = this._ctx.select("loadEngineClientFromID", { id })
+    return new EngineClient(ctx)
+  }
+
   /**
    * Load a Engine from its ID.
    */
@@ -11045,6 +12718,30 @@ export class Client extends BaseClient {
     return new ErrorValue(ctx)
   }
 
+  /**
+   * Load a Eval from its ID.
+   */
+  loadEvalFromID = (id: EvalID): Eval => {
+    const ctx = this._ctx.select("loadEvalFromID", { id })
+    return new Eval(ctx)
+  }
+
+  /**
+   * Load a EvalModelReport from its ID.
+   */
+  loadEvalModelReportFromID = (id: EvalModelReportID): EvalModelReport => {
+    const ctx = this._ctx.select("loadEvalModelReportFromID", { id })
+    return new EvalModelReport(ctx)
+  }
+
+  /**
+   * Load a EvalReport from its ID.
+   */
+  loadEvalReportFromID = (id: EvalReportID): EvalReport => {
+    const ctx = this._ctx.select("loadEvalReportFromID", { id })
+    return new EvalReport(ctx)
+  }
+
   /**
    * Load a FieldTypeDef from its ID.
    */
@@ -11151,6 +12848,14 @@ export class Client extends BaseClient {
     return new JSONValue(ctx)
   }
 
+  /**
+   * Load a LLMEvent from its ID.
+   */
+  loadLLMEventFromID = (id: LLMEventID): LLMEvent => {
+    const ctx = this._ctx.select("loadLLMEventFromID", { id })
+    return new LLMEvent(ctx)
+  }
+
   /**
    * Load a LLM from its ID.
    */
@@ -11183,6 +12888,22 @@ export class Client extends BaseClient {
     return new ListTypeDef(ctx)
   }
 
+  /**
+   * Load a LogEntry from its ID.
+   */
+  loadLogEntryFromID = (id: LogEntryID): LogEntry => {
+    const ctx = this._ctx.select("loadLogEntryFromID", { id })
+    return new LogEntry(ctx)
+  }
+
+  /**
+   * Load a MapTypeDef from its ID.
+   */
+  loadMapTypeDefFromID = (id: MapTypeDefID): MapTypeDef => {
+    const ctx = this._ctx.select("loadMapTypeDefFromID", { id })
+    return new MapTypeDef(ctx)
+  }
+
   /**
    * Load a ModuleConfigClient from its ID.
    */
@@ -11297,6 +13018,14 @@ export class Client extends BaseClient {
     return new Stat(ctx)
   }
 
+  /**
+   * Load a StringConnection from its ID.
+   */
+  loadStringConnectionFromID = (id: StringConnectionID): StringConnection => {
+    const ctx = this._ctx.select("loadStringConnectionFromID", { id })
+    return new StringConnection(ctx)
+  }
+
   /**
    * Load a Terminal from its ID.
    */
@@ -11313,6 +13042,14 @@ export class Client extends BaseClient {
     return new TypeDef(ctx)
   }
 
+  /**
+   * Load a UnionTypeDef from its ID.
+   */
+  loadUnionTypeDefFromID = (id: UnionTypeDefID): UnionTypeDef => {
+    const ctx = this._ctx.select("loadUnionTypeDefFromID", { id })
+    return new UnionTypeDef(ctx)
+  }
+
   /**
    * Create a new module.
    */
@@ -12295,6 +14032,107 @@ export class Stat extends BaseClient {
   }
 }
 
+/**
+ * A page of a list of String, selected by a cursor.
+ */
+export class StringConnection extends BaseClient {
+  private readonly _id?: StringConnectionID = undefined
+  private readonly _endCursor?: string = undefined
+  private readonly _hasNextPage?: boolean = undefined
+  private readonly _totalCount?: number = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: StringConnectionID,
+    _endCursor?: string,
+    _hasNextPage?: boolean,
+    _totalCount?: number,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._endCursor = _endCursor
+    this._hasNextPage = _hasNextPage
+    this._totalCount = _totalCount
+  }
+
+  /**
+   * A unique identifier for this StringConnection.
+   */
+  id = async (): Promise<StringConnectionID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<StringConnectionID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The cursor of the last element of the page, to pass as "after" to select the next page.
+   *
+   * Null if the page is empty.
+   */
+  endCursor = async (): Promise<string> => {
+    if (this._endCursor) {
+      return this._endCursor
+    }
+
+    const ctx = this._ctx.select("endCursor")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Whether there are elements after this page.
+   */
+  hasNextPage = async (): Promise<boolean> => {
+    if (this._hasNextPage) {
+      return this._hasNextPage
+    }
+
+    const ctx = this._ctx.select("hasNextPage")
+
+    const response: Awaited<boolean> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The elements of the page.
+   */
+  nodes = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("nodes")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The number of elements in the whole list.
+   */
+  totalCount = async (): Promise<number> => {
+    if (this._totalCount) {
+      return this._totalCount
+    }
+
+    const ctx = this._ctx.select("totalCount")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+}
+
 /**
  * An interactive terminal that clients can connect to.
  */
@@ -12412,6 +14250,14 @@ export class TypeDef extends BaseClient {
     return new ListTypeDef(ctx)
   }
 
+  /**
+   * If kind is MAP, the map-specific type definition. If kind is not MAP, this will be null.
+   */
+  asMap = (): MapTypeDef => {
+    const ctx = this._ctx.select("asMap")
+    return new MapTypeDef(ctx)
+  }
+
   /**
    * If kind is OBJECT, the object-specific type definition. If kind is not OBJECT, this will be null.
    */
@@ -12428,6 +14274,14 @@ export class TypeDef extends BaseClient {
     return new ScalarTypeDef(ctx)
   }
 
+  /**
+   * If kind is UNION, the union-specific type definition. If kind is not UNION, this will be null.
+   */
+  asUnion = (): UnionTypeDef => {
+    const ctx = this._ctx.select("asUnion")
+    return new UnionTypeDef(ctx)
+  }
+
   /**
    * The kind of type this is (e.g. primitive, list, object).
    */
@@ -12565,6 +14419,16 @@ export class TypeDef extends BaseClient {
     return new TypeDef(ctx)
   }
 
+  /**
+   * Returns a TypeDef of kind Map with string keys and the provided type for its values.
+   *
+   * Maps are represented as JSON objects, so their values can't be objects, interfaces or unions.
+   */
+  withMapOf = (valueType: TypeDef): TypeDef => {
+    const ctx = this._ctx.select("withMapOf", { valueType })
+    return new TypeDef(ctx)
+  }
+
   /**
    * Returns a TypeDef of kind Object with the provided name.
    *
@@ -12591,6 +14455,28 @@ export class TypeDef extends BaseClient {
     return new TypeDef(ctx)
   }
 
+  /**
+   * Returns a TypeDef of kind Union with the provided name.
+   *
+   * Note that a union's members may be omitted if the intent is only to refer to a union. This is how functions are able to return their own, or any other circular reference.
+   * @param name The name of the union
+   * @param opts.description A doc string for the union, if any
+   * @param opts.sourceMap The source map for the union definition.
+   */
+  withUnion = (name: string, opts?: TypeDefWithUnionOpts): TypeDef => {
+    const ctx = this._ctx.select("withUnion", { name, ...opts })
+    return new TypeDef(ctx)
+  }
+
+  /**
+   * Adds a member type to a Union TypeDef, failing if the type is not a union or the member is not an object.
+   * @param member The object type to add to the union
+   */
+  withUnionMember = (member: TypeDef): TypeDef => {
+    const ctx = this._ctx.select("withUnionMember", { member })
+    return new TypeDef(ctx)
+  }
+
   /**
    * Call the provided function with current TypeDef.
    *
@@ -12601,4 +14487,115 @@ export class TypeDef extends BaseClient {
   }
 }
 
+/**
+ * A definition of a custom union of object types defined in a Module.
+ */
+export class UnionTypeDef extends BaseClient {
+  private readonly _id?: UnionTypeDefID = undefined
+  private readonly _description?: string = undefined
+  private readonly _name?: string = undefined
+  private readonly _sourceModuleName?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: UnionTypeDefID,
+    _description?: string,
+    _name?: string,
+    _sourceModuleName?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._description = _description
+    this._name = _name
+    this._sourceModuleName = _sourceModuleName
+  }
+
+  /**
+   * A unique identifier for this UnionTypeDef.
+   */
+  id = async (): Promise<UnionTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<UnionTypeDefID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * A doc string for the union, if any.
+   */
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
+    }
+
+    const ctx = this._ctx.select("description")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The object types that a value of the union can be.
+   */
+  members = async (): Promise<TypeDef[]> => {
+    type members = {
+      id: TypeDefID
+    }
+
+    const ctx = this._ctx.select("members").select("id")
+
+    const response: Awaited<members[]> = await ctx.execute()
+
+    return response.map((r) => new Client(ctx.copy()).loadTypeDefFromID(r.id))
+  }
+
+  /**
+   * The name of the union.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const ctx = this._ctx.select("name")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The location of this union declaration.
+   */
+  sourceMap = (): SourceMap => {
+    const ctx = this._ctx.select("sourceMap")
+    return new SourceMap(ctx)
+  }
+
+  /**
+   * If this UnionTypeDef is associated with a Module, the name of the module. Unset otherwise.
+   */
+  sourceModuleName = async (): Promise<string> => {
+    if (this._sourceModuleName) {
+      return this._sourceModuleName
+    }
+
+    const ctx = this._ctx.select("sourceModuleName")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+}
+
 export const dag = new Client()
@@ -25,6 +25,13 @@ export const func = registry.func
  */
 export const check = registry.check
 
+/**
+ * The definition of @prompt decorator that marks a function as a prompt.
+ * Prompts are functions taking and returning strings, exposed as MCP prompts
+ * by `dagger mcp`.
+ */
+export const prompt = registry.prompt
+
 /**
  * The definition of @field decorator that should be on top of any
  * class' property that must be exposed to the Dagger API.
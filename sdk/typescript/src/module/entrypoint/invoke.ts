@@ -7,7 +7,9 @@ import {
   DaggerEnumBase,
   DaggerModule,
   DaggerObjectBase,
+  DaggerUnion,
 } from "../introspector/dagger_module/index.js"
+import { TypeDef } from "../introspector/typedef.js"
 import { registry } from "../registry.js"
 import { InvokeCtx } from "./context.js"
 import {
@@ -17,6 +19,7 @@ import {
   loadArgs,
   loadParentState,
   loadObjectReturnType,
+  loadMapResult,
 } from "./load.js"
 
 function isConstructor(method: Method | Constructor): method is Constructor {
@@ -81,7 +84,7 @@ export async function invoke(
   }
 
   if (result) {
-    let returnType: DaggerObjectBase | DaggerEnumBase
+    let returnType: DaggerObjectBase | DaggerEnumBase | DaggerUnion
 
     // Handle alias serialization by getting the return type to load
     // if the function called isn't a constructor.
@@ -97,6 +100,15 @@ export async function invoke(
         )
       }
 
+      // Maps are returned as plain objects, only their values need to be loaded.
+      if (method.returnType!.kind === TypeDefKind.MapKind) {
+        return await loadMapResult(
+          result,
+          module,
+          method.returnType as TypeDef<TypeDefKind.MapKind>,
+        )
+      }
+
       returnType = loadObjectReturnType(module, object, method)
     } else {
       returnType = object
@@ -13,6 +13,7 @@ import {
   DaggerObjectBase,
   DaggerTypeObject,
   DaggerEnumClass,
+  DaggerUnion,
 } from "../introspector/dagger_module/index.js"
 import { TypeDef } from "../introspector/typedef.js"
 import { InvokeCtx } from "./context.js"
@@ -164,6 +165,23 @@ export async function loadValue(
             ),
         ),
       )
+    case TypeDefKind.MapKind: {
+      const valueType = (type as TypeDef<TypeDefKind.MapKind>).typeDef
+      const map: Record<string, any> = {}
+      for (const [key, v] of Object.entries(value)) {
+        map[key] = await loadValue(executor, v, valueType)
+      }
+
+      return map
+    }
+    case TypeDefKind.UnionKind: {
+      // Unions are received as the value of one of their members, tagged with
+      // the name of its type.
+      return loadValue(executor, value.value, {
+        kind: TypeDefKind.ObjectKind,
+        name: value.typeName,
+      })
+    }
     case TypeDefKind.ObjectKind: {
       const objectType = (type as TypeDef<TypeDefKind.ObjectKind>).name
 
@@ -214,7 +232,7 @@ export function loadObjectReturnType(
   module: DaggerModule,
   object: DaggerObject,
   method: Method,
-): DaggerObjectBase | DaggerEnumBase {
+): DaggerObjectBase | DaggerEnumBase | DaggerUnion {
   const retType = method.returnType
   if (!retType) {
     throw new Error(`could not find return type for ${method.name}`)
@@ -233,12 +251,18 @@ export function loadObjectReturnType(
         return module.enums[(listType as TypeDef<TypeDefKind.EnumKind>).name]
       }
 
+      if (listType.kind === TypeDefKind.UnionKind) {
+        return module.unions[(listType as TypeDef<TypeDefKind.UnionKind>).name]
+      }
+
       return module.objects[(listType as TypeDef<TypeDefKind.ObjectKind>).name]
     }
     case TypeDefKind.ObjectKind:
       return module.objects[(retType as TypeDef<TypeDefKind.ObjectKind>).name]
     case TypeDefKind.EnumKind:
       return module.enums[(retType as TypeDef<TypeDefKind.EnumKind>).name]
+    case TypeDefKind.UnionKind:
+      return module.unions[(retType as TypeDef<TypeDefKind.UnionKind>).name]
     default:
       return object
   }
@@ -247,8 +271,36 @@ export function loadObjectReturnType(
 export async function loadResult(
   result: any,
   module: DaggerModule,
-  object: DaggerObjectBase | DaggerEnumBase,
+  object: DaggerObjectBase | DaggerEnumBase | DaggerUnion,
 ): Promise<any> {
+  // Handle arrays of unions
+  if (Array.isArray(result) && object instanceof DaggerUnion) {
+    return await Promise.all(
+      result.map(async (r) => await loadResult(r, module, object)),
+    )
+  }
+
+  // Handle unions, tagging the value with the name of its type
+  if (object instanceof DaggerUnion) {
+    const typeName = object.members.find(
+      (member) => result?.constructor?.name === member,
+    )
+    if (!typeName) {
+      throw new Error(
+        `could not find which member of union ${object.name} the result is`,
+      )
+    }
+
+    const member = module.objects[typeName]
+
+    return {
+      typeName,
+      value: member
+        ? await loadResult(result, module, member)
+        : await result.id(),
+    }
+  }
+
   // Handle IDable objects
   if (result && typeof result?.id === "function") {
     return await result.id()
@@ -282,8 +334,22 @@ export async function loadResult(
         throw new Error(`could not find type for result property ${key}`)
       }
 
-      let referencedObject: DaggerObjectBase | DaggerEnumBase | undefined =
-        undefined
+      // Handle maps
+      if (property.type.kind === TypeDefKind.MapKind) {
+        state[property.alias ?? property.name] = await loadMapResult(
+          value,
+          module,
+          property.type as TypeDef<TypeDefKind.MapKind>,
+        )
+
+        continue
+      }
+
+      let referencedObject:
+        | DaggerObjectBase
+        | DaggerEnumBase
+        | DaggerUnion
+        | undefined = undefined
 
       // Handle nested objects
       if (property.type.kind === TypeDefKind.ObjectKind) {
@@ -313,6 +379,12 @@ export async function loadResult(
           referencedObject =
             module.enums[(_property as TypeDef<TypeDefKind.EnumKind>).name]
         }
+
+        // If the original type is a union, we use it as the referenced object.
+        if (_property.kind === TypeDefKind.UnionKind) {
+          referencedObject =
+            module.unions[(_property as TypeDef<TypeDefKind.UnionKind>).name]
+        }
       }
 
       // Handle enums
@@ -321,6 +393,12 @@ export async function loadResult(
           module.enums[(property.type as TypeDef<TypeDefKind.EnumKind>).name]
       }
 
+      // Handle unions
+      if (property.type.kind === TypeDefKind.UnionKind) {
+        referencedObject =
+          module.unions[(property.type as TypeDef<TypeDefKind.UnionKind>).name]
+      }
+
       // If there's no referenced object, we use the current object.
       if (!referencedObject) {
         referencedObject = object
@@ -355,3 +433,41 @@ export async function loadResult(
   // Handle primitive types
   return result
 }
+
+/**
+ * Load the values of a map result.
+ *
+ * Maps can only contain primitive values, enums or lists of those, so only enum
+ * values need to be converted to the name of their member.
+ *
+ * @param result The map returned by the function.
+ * @param module The module to load the enum from.
+ * @param type The type of the map.
+ */
+export async function loadMapResult(
+  result: Record<string, any>,
+  module: DaggerModule,
+  type: TypeDef<TypeDefKind.MapKind>,
+): Promise<Record<string, any>> {
+  let valueType = type.typeDef
+  while (valueType.kind === TypeDefKind.ListKind) {
+    valueType = (valueType as TypeDef<TypeDefKind.ListKind>).typeDef
+  }
+
+  if (valueType.kind !== TypeDefKind.EnumKind) {
+    return result
+  }
+
+  const enumObj =
+    module.enums[(valueType as TypeDef<TypeDefKind.EnumKind>).name]
+  if (!enumObj) {
+    return result
+  }
+
+  const map: Record<string, any> = {}
+  for (const [key, value] of Object.entries(result)) {
+    map[key] = await loadResult(value, module, enumObj)
+  }
+
+  return map
+}
@@ -25,9 +25,11 @@ import {
   EnumTypeDef,
   InterfaceTypeDef,
   ListTypeDef,
+  MapTypeDef,
   ObjectTypeDef,
   ScalarTypeDef,
   TypeDef as ScannerTypeDef,
+  UnionTypeDef,
 } from "../introspector/typedef.js"
 
 export class Register {
@@ -122,6 +124,20 @@ export class Register {
       mod = mod.withInterface(typeDef)
     })
 
+    // Register all unions defined by this module
+    Object.values(this.module.unions).forEach((union) => {
+      let typeDef = dag.typeDef().withUnion(union.name, {
+        description: union.description,
+        sourceMap: addSourceMap(union),
+      })
+
+      union.members.forEach((member) => {
+        typeDef = typeDef.withUnionMember(dag.typeDef().withObject(member))
+      })
+
+      mod = mod.withUnion(typeDef)
+    })
+
     return await mod.id()
   }
 
@@ -168,6 +184,10 @@ export class Register {
       fnDef = fnDef.withCheck()
     }
 
+    if ((fct as Method).isPrompt) {
+      fnDef = fnDef.withPrompt()
+    }
+
     return fnDef
   }
 
@@ -281,12 +301,16 @@ function addTypeDef(type: ScannerTypeDef<TypeDefKind>): TypeDef {
       return dag.typeDef().withObject((type as ObjectTypeDef).name)
     case TypeDefKind.ListKind:
       return dag.typeDef().withListOf(addTypeDef((type as ListTypeDef).typeDef))
+    case TypeDefKind.MapKind:
+      return dag.typeDef().withMapOf(addTypeDef((type as MapTypeDef).typeDef))
     case TypeDefKind.VoidKind:
       return dag.typeDef().withKind(type.kind).withOptional(true)
     case TypeDefKind.EnumKind:
       return dag.typeDef().withEnum((type as EnumTypeDef).name)
     case TypeDefKind.InterfaceKind:
       return dag.typeDef().withInterface((type as InterfaceTypeDef).name)
+    case TypeDefKind.UnionKind:
+      return dag.typeDef().withUnion((type as UnionTypeDef).name)
     default:
       return dag.typeDef().withKind(type.kind)
   }
@@ -5,12 +5,14 @@ import {
   enumType,
   field,
   check,
+  prompt,
 } from "../../decorators.js"
 
 export type DaggerDecorators =
   | "object"
   | "func"
   | "check"
+  | "prompt"
   | "argument"
   | "enumType"
   | "field"
@@ -18,6 +20,7 @@ export type DaggerDecorators =
 export const OBJECT_DECORATOR = object.name as DaggerDecorators
 export const FUNCTION_DECORATOR = func.name as DaggerDecorators
 export const CHECK_DECORATOR = check.name as DaggerDecorators
+export const PROMPT_DECORATOR = prompt.name as DaggerDecorators
 export const FIELD_DECORATOR = field.name as DaggerDecorators
 export const ARGUMENT_DECORATOR = argument.name as DaggerDecorators
 export const ENUM_DECORATOR = enumType.name as DaggerDecorators
@@ -10,7 +10,11 @@ import {
   resolveTypeDef,
 } from "../typescript_module/index.js"
 import { DaggerArgument, DaggerArguments } from "./argument.js"
-import { CHECK_DECORATOR, FUNCTION_DECORATOR } from "./decorator.js"
+import {
+  CHECK_DECORATOR,
+  FUNCTION_DECORATOR,
+  PROMPT_DECORATOR,
+} from "./decorator.js"
 import { Locatable } from "./locatable.js"
 import { References } from "./reference.js"
 
@@ -26,6 +30,7 @@ export class DaggerFunction extends Locatable {
   public alias: string | undefined
   public cache: string | undefined
   public isCheck: boolean = false
+  public isPrompt: boolean = false
 
   private signature: ts.Signature
   private symbol: ts.Symbol
@@ -62,6 +67,11 @@ export class DaggerFunction extends Locatable {
       this.isCheck = true
     }
 
+    // Parse @prompt decorator
+    if (this.ast.isNodeDecoratedWith(this.node, PROMPT_DECORATOR)) {
+      this.isPrompt = true
+    }
+
     for (const parameter of this.node.parameters) {
       this.arguments[parameter.name.getText()] = new DaggerArgument(
         parameter,
@@ -12,3 +12,4 @@ export * from "./typeObject.js"
 export * from "./decorator.js"
 export * from "./locatable.js"
 export * from "./interface.js"
+export * from "./union.js"
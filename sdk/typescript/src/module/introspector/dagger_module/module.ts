@@ -17,6 +17,7 @@ import { DaggerObject } from "./object.js"
 import { DaggerObjectsBase } from "./objectBase.js"
 import { References } from "./reference.js"
 import { DaggerTypeObject } from "./typeObject.js"
+import { DaggerUnion, DaggerUnions } from "./union.js"
 
 /**
  * DaggerModule represents a TypeScript module with a set of files
@@ -77,6 +78,17 @@ export class DaggerModule {
    */
   public interfaces: DaggerInterfaces = {}
 
+  /**
+   * A union is declared using the `type` keyword with a union of objects.
+   * Type alias unions are resolved if referenced in the module.
+   *
+   * @example
+   * ```ts
+   * export type Pet = Cat | Dog
+   * ```
+   */
+  public unions: DaggerUnions = {}
+
   public description: string | undefined
 
   private references: References = {
@@ -266,6 +278,7 @@ export class DaggerModule {
    * - `type Example = number`
    * - `type Example = boolean`
    * - `type Example = void`
+   * - `type Example = ObjectA | ObjectB`
    *
    * If the reference is an object, we recursively resolve its references.
    * If the type cannot be resolved or is not supported, we throw an error.
@@ -300,6 +313,35 @@ export class DaggerModule {
       return
     }
 
+    // Unions are defined with a union of objects such as `type Pet = Cat | Dog`
+    if (
+      type.flags & ts.TypeFlags.Union &&
+      (type as ts.UnionType).types.every(
+        (t) =>
+          t.flags & ts.TypeFlags.Object &&
+          t.symbol &&
+          t.symbol.flags & ts.SymbolFlags.Class,
+      )
+    ) {
+      const daggerUnion = new DaggerUnion(typeAlias.node, this.ast)
+      this.unions[daggerUnion.name] = daggerUnion
+      this.references[daggerUnion.name] = {
+        kind: TypeDefKind.UnionKind,
+        name: daggerUnion.name,
+      }
+
+      this.resolveReferences(daggerUnion.members)
+      for (const member of daggerUnion.members) {
+        if (this.references[member]?.kind !== TypeDefKind.ObjectKind) {
+          throw new IntrospectionError(
+            `member ${member} of union ${daggerUnion.name} at ${AST.getNodePosition(typeAlias.node)} must be an object.`,
+          )
+        }
+      }
+
+      return
+    }
+
     // Scalar are defined with string intersection such as `type MyScalar = string & { __MyScalar: never }`
     if (
       type.flags & ts.TypeFlags.Intersection ||
@@ -409,6 +451,7 @@ export class DaggerModule {
       objects: this.objects,
       enums: this.enums,
       interfaces: this.interfaces,
+      unions: this.unions,
     }
   }
 }
@@ -0,0 +1,55 @@
+import ts from "typescript"
+
+import { IntrospectionError } from "../../../common/errors/index.js"
+import { AST } from "../typescript_module/index.js"
+import { Locatable } from "./locatable.js"
+
+export type DaggerUnions = { [name: string]: DaggerUnion }
+
+/**
+ * Represents a union of objects defined using the `type` keyword.
+ *
+ * Each member of the union must be an object, either a decorated class
+ * of the module or a type from the Dagger API.
+ *
+ * @example
+ * ```ts
+ * export type Pet = Cat | Dog
+ * ```
+ */
+export class DaggerUnion extends Locatable {
+  public name: string
+  public description: string
+  public members: string[] = []
+
+  private symbol: ts.Symbol
+
+  constructor(
+    private readonly node: ts.TypeAliasDeclaration,
+    private readonly ast: AST,
+  ) {
+    super(node)
+
+    this.name = this.node.name.getText()
+    this.symbol = this.ast.getSymbolOrThrow(this.node.name)
+    this.description = this.ast.getDocFromSymbol(this.symbol)
+
+    if (!ts.isUnionTypeNode(this.node.type)) {
+      throw new IntrospectionError(
+        `type ${this.name} at ${AST.getNodePosition(this.node)} is not a union.`,
+      )
+    }
+
+    for (const member of this.node.type.types) {
+      this.members.push(member.getText())
+    }
+  }
+
+  toJSON() {
+    return {
+      name: this.name,
+      description: this.description,
+      members: this.members,
+    }
+  }
+}
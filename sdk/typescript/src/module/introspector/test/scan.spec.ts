@@ -123,6 +123,10 @@ describe("scan by reference TypeScript", function () {
       name: "Should correctly scan interfaces",
       directory: "interface",
     },
+    {
+      name: "Should correctly scan maps and unions",
+      directory: "mapAndUnion",
+    },
   ]
 
   for (const test of testCases) {
@@ -154,6 +158,17 @@ ${jsonResult}
     })
   }
 
+  it("Should mark functions decorated with @prompt", async function () {
+    this.timeout(60000)
+
+    const files = await listFiles(`${rootDirectory}/prompt`)
+    const module = await scan(files, "prompt")
+    const methods = (module.objects["Prompts"] as any).methods
+
+    assert.equal(methods["review"].isPrompt, true)
+    assert.equal(methods["plain"].isPrompt, false)
+  })
+
   describe("Should throw error on invalid module", function () {
     it("Should throw an error when no files are provided", async function () {
       this.timeout(60000)
@@ -0,0 +1,44 @@
+import { func, object } from "../../../../decorators.js"
+
+@object()
+export class Cat {
+  @func()
+  meow(): string {
+    return "meow"
+  }
+}
+
+@object()
+export class Dog {
+  @func()
+  bark(): string {
+    return "woof"
+  }
+}
+
+/**
+ * A pet.
+ */
+export type Pet = Cat | Dog
+
+@object()
+export class MapAndUnion {
+  @func()
+  labels(labels: Record<string, string>): Record<string, string> {
+    return labels
+  }
+
+  @func()
+  total(counts: { [key: string]: number }): number {
+    return Object.values(counts).reduce((a, b) => a + b, 0)
+  }
+
+  @func()
+  adopt(barks: boolean): Pet {
+    if (barks) {
+      return new Dog()
+    }
+
+    return new Cat()
+  }
+}
@@ -0,0 +1,18 @@
+import { func, object, prompt } from "../../../../decorators.js"
+
+@object()
+export class Prompts {
+	/**
+	 * Review the given code
+	 */
+	@func()
+	@prompt()
+	review(code: string): string {
+		return `Review this code:\n${code}`
+	}
+
+	@func()
+	plain(): string {
+		return "plain"
+	}
+}
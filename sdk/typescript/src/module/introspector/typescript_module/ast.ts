@@ -340,6 +340,17 @@ export class AST {
     return type
   }
 
+  public unwrapTypeStringFromMap(type: string): string {
+    const match =
+      type.match(/^Record<string, (.+)>$/) ??
+      type.match(/^\{ \[\w+: string\]: (.+); \}$/)
+    if (match) {
+      return match[1]
+    }
+
+    return type
+  }
+
   public stringTypeToUnwrappedType(type: string): string {
     type = this.unwrapTypeStringFromPromise(type)
 
@@ -349,6 +360,11 @@ export class AST {
       return this.stringTypeToUnwrappedType(extractedTypeFromArray)
     }
 
+    const extractedTypeFromMap = this.unwrapTypeStringFromMap(type)
+    if (extractedTypeFromMap !== type) {
+      return this.stringTypeToUnwrappedType(extractedTypeFromMap)
+    }
+
     return type
   }
 
@@ -418,6 +434,19 @@ export class AST {
           }
         }
       }
+
+      // Objects that only have a string index signature, like `Record<string, T>`
+      // or `{ [key: string]: T }`, are maps.
+      const indexInfo = this.checker.getIndexInfoOfType(
+        type,
+        ts.IndexKind.String,
+      )
+      if (indexInfo && this.checker.getPropertiesOfType(type).length === 0) {
+        return {
+          kind: TypeDefKind.MapKind,
+          typeDef: this.tsTypeToTypeDef(node, indexInfo.type),
+        }
+      }
     }
   }
 
@@ -3,21 +3,22 @@ import { IntrospectionError } from "../../../common/errors/index.js"
 import { TypeDef } from "../typedef.js"
 
 export function isTypeDefResolved(typeDef: TypeDef<TypeDefKind>): boolean {
-  if (typeDef.kind !== TypeDefKind.ListKind) {
+  if (
+    typeDef.kind !== TypeDefKind.ListKind &&
+    typeDef.kind !== TypeDefKind.MapKind
+  ) {
     return true
   }
 
-  const arrayTypeDef = typeDef as TypeDef<TypeDefKind.ListKind>
+  const wrapperTypeDef = typeDef as
+    | TypeDef<TypeDefKind.ListKind>
+    | TypeDef<TypeDefKind.MapKind>
 
-  if (arrayTypeDef.typeDef === undefined) {
+  if (wrapperTypeDef.typeDef === undefined) {
     return false
   }
 
-  if (arrayTypeDef.typeDef.kind === TypeDefKind.ListKind) {
-    return isTypeDefResolved(arrayTypeDef.typeDef)
-  }
-
-  return true
+  return isTypeDefResolved(wrapperTypeDef.typeDef)
 }
 
 export function resolveTypeDef(
@@ -35,6 +36,13 @@ export function resolveTypeDef(
     return listTypeDef
   }
 
+  if (typeDef.kind === TypeDefKind.MapKind) {
+    const mapTypeDef = typeDef as TypeDef<TypeDefKind.MapKind>
+
+    mapTypeDef.typeDef = resolveTypeDef(mapTypeDef.typeDef, reference)
+    return mapTypeDef
+  }
+
   throw new IntrospectionError(
     `type ${JSON.stringify(typeDef)} has already been resolved, it should not be overwritten ; reference: ${JSON.stringify(reference)}`,
   )
@@ -140,6 +140,21 @@ export class Registry {
     ) => {}
   }
 
+  /**
+   * The definition of @prompt decorator that marks a function as a prompt.
+   */
+  prompt = (): ((
+    target: object,
+    propertyKey: string | symbol,
+    descriptor?: PropertyDescriptor,
+  ) => void) => {
+    return (
+      target: object,
+      propertyKey: string | symbol,
+      descriptor?: PropertyDescriptor,
+    ) => {}
+  }
+
   argument = (
     opts?: ArgumentOptions,
   ): ((